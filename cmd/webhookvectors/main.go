@@ -0,0 +1,46 @@
+// webhookvectors 生成或校验商户通知签名测试向量
+//
+//	go run ./cmd/webhookvectors > docs/webhook/signature_vectors.json
+//	go run ./cmd/webhookvectors -check docs/webhook/signature_vectors.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"inpayos/pkg/webhooksig"
+)
+
+func main() {
+	check := flag.String("check", "", "校验已有的测试向量文件")
+	flag.Parse()
+
+	if *check != "" {
+		data, err := os.ReadFile(*check)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		var vectors []*webhooksig.Vector
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := webhooksig.Check(vectors); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d vectors ok\n", len(vectors))
+		return
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(webhooksig.Vectors()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
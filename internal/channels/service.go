@@ -17,6 +17,23 @@ type ChannelOpenApi interface {
 	Query(in *ChannelTrxRequest) *protocol.ChannelResult
 }
 
+// AccountVerifyRequest 收款账户验证请求
+type AccountVerifyRequest struct {
+	Mid         string
+	RefID       string // 收款人ID，作为渠道请求参考号
+	Country     string
+	Ccy         string
+	AccountType string
+	AccountNo   string
+	AccountName string
+	BankCode    string
+}
+
+// AccountVerifier 支持小额打款验证（penny drop）的渠道可选实现该接口
+type AccountVerifier interface {
+	VerifyAccount(in *AccountVerifyRequest) *protocol.AccountVerifyResult
+}
+
 var channelAccountLib = make(map[string]func(*models.ChannelAccount) ChannelOpenApi)
 
 func RegisterOpenAiChannelService(channel_account string, svc func(*models.ChannelAccount) ChannelOpenApi) {
@@ -196,6 +196,31 @@ func (t *TestChannel) Query(in *ChannelTrxRequest) *protocol.ChannelResult {
 	return result
 }
 
+// VerifyAccount 模拟小额打款验证，账号以000结尾视为失败，以111结尾视为验证中，其余返回请求中的户名
+func (t *TestChannel) VerifyAccount(in *AccountVerifyRequest) *protocol.AccountVerifyResult {
+	result := &protocol.AccountVerifyResult{
+		Status:       protocol.VerifyStatusVerified,
+		VerifiedName: strings.ToUpper(in.AccountName),
+		ChannelTrxID: t.generateChannelTrxID(),
+		ResMsg:       "account verified",
+	}
+	switch {
+	case !t.isSupportedCurrency(in.Ccy):
+		result.Status = protocol.VerifyStatusFailed
+		result.VerifiedName = ""
+		result.ResMsg = fmt.Sprintf("Unsupported currency: %s", in.Ccy)
+	case strings.HasSuffix(in.AccountNo, "000"):
+		result.Status = protocol.VerifyStatusFailed
+		result.VerifiedName = ""
+		result.ResMsg = "account not found"
+	case strings.HasSuffix(in.AccountNo, "111"):
+		result.Status = protocol.VerifyStatusPending
+		result.VerifiedName = ""
+		result.ResMsg = "verification is processing"
+	}
+	return result
+}
+
 // isSupportedCurrency 检查是否支持该币种
 func (t *TestChannel) isSupportedCurrency(currency string) bool {
 	supportedCurrencies := []string{"USD", "INR", "EUR", "GBP", "JPY", "CNY", "SGD", "HKD"}
@@ -0,0 +1,23 @@
+package config
+
+import "github.com/shopspring/decimal"
+
+const (
+	DefaultCashierMarginCapacityMultiplier = 1.0 // 默认代收额度倍数
+)
+
+// CashierMarginConfig 车队保证金配置
+type CashierMarginConfig struct {
+	CapacityMultiplier float64 `mapstructure:"capacity_multiplier"` // 未结算代收金额上限 = 保证金余额 × 倍数
+}
+
+func (c *CashierMarginConfig) Validate() {
+	if c.CapacityMultiplier <= 0 {
+		c.CapacityMultiplier = DefaultCashierMarginCapacityMultiplier
+	}
+}
+
+// GetCapacityMultiplier 获取代收额度倍数
+func (c *CashierMarginConfig) GetCapacityMultiplier() decimal.Decimal {
+	return decimal.NewFromFloat(c.CapacityMultiplier)
+}
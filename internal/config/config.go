@@ -12,6 +12,12 @@ var (
 	config *Config
 )
 
+// secretEnvs 敏感配置项对应的环境变量，部署时由密钥管理注入，优先于配置文件
+var secretEnvs = map[string]string{
+	"export.sign_secret":    "INPAYOS_EXPORT_SIGN_SECRET",
+	"statement.sign_secret": "INPAYOS_STATEMENT_SIGN_SECRET",
+}
+
 type Config struct {
 	Debug            bool                    `mapstructure:"debug"`
 	Env              string                  `mapstructure:"env"`
@@ -29,6 +35,16 @@ type Config struct {
 	MerchantPayin    *MerchantPayinConfig    `mapstructure:"payin"`       // 支付配置
 	MerchantPayout   *MerchantPayoutConfig   `mapstructure:"payout"`      // 支付配置
 	MerchantCheckout *MerchantCheckoutConfig `mapstructure:"checkout"`    // 结账配置
+	Export           *ExportConfig           `mapstructure:"export"`      // 交易导出配置
+	Fx               *FxConfig               `mapstructure:"fx"`          // 汇率配置
+	Proof            *ProofConfig            `mapstructure:"proof"`       // 支付凭证配置
+	PaymentLink      *PaymentLinkConfig      `mapstructure:"paylink"`     // 支付链接配置
+	QRCode           *QRCodeConfig           `mapstructure:"qrcode"`      // 二维码配置
+	Webhook          *WebhookConfig          `mapstructure:"webhook"`     // 商户异步通知配置
+	Ledger           *LedgerConfig           `mapstructure:"ledger"`      // 账务核对配置
+
+	Statement     *StatementConfig     `mapstructure:"statement"`      // 账户对账单配置
+	CashierMargin *CashierMarginConfig `mapstructure:"cashier_margin"` // 车队保证金配置
 }
 
 // Get 获取配置单例
@@ -53,8 +69,8 @@ func (c *Config) ValidateDB() {
 	}
 }
 
-// Validate 验证并设置所有配置默认值
-func (c *Config) Validate() {
+// Validate 验证并设置所有配置默认值，必填项缺失时返回错误
+func (c *Config) Validate() error {
 	c.ValidateDB()
 	c.Env = strings.ToLower(c.Env)
 	if c.Env == "" || (c.Env != DevEnv && c.Env != ProdEnv) {
@@ -100,6 +116,47 @@ func (c *Config) Validate() {
 		c.MerchantCheckout = &MerchantCheckoutConfig{}
 	}
 	c.MerchantCheckout.Validate()
+	if c.Export == nil {
+		c.Export = &ExportConfig{}
+	}
+	if err := c.Export.Validate(); err != nil {
+		return err
+	}
+	if c.Fx == nil {
+		c.Fx = &FxConfig{}
+	}
+	c.Fx.Validate()
+	if c.Proof == nil {
+		c.Proof = &ProofConfig{}
+	}
+	c.Proof.Validate()
+	if c.PaymentLink == nil {
+		c.PaymentLink = &PaymentLinkConfig{}
+	}
+	c.PaymentLink.Validate()
+	if c.QRCode == nil {
+		c.QRCode = &QRCodeConfig{}
+	}
+	c.QRCode.Validate()
+	if c.Webhook == nil {
+		c.Webhook = &WebhookConfig{}
+	}
+	c.Webhook.Validate()
+	if c.Ledger == nil {
+		c.Ledger = &LedgerConfig{}
+	}
+	c.Ledger.Validate()
+	if c.Statement == nil {
+		c.Statement = &StatementConfig{}
+	}
+	if err := c.Statement.Validate(); err != nil {
+		return err
+	}
+	if c.CashierMargin == nil {
+		c.CashierMargin = &CashierMarginConfig{}
+	}
+	c.CashierMargin.Validate()
+	return nil
 }
 
 // LoadConfig 加载配置
@@ -114,11 +171,16 @@ func LoadConfig() (err error) {
 	if err = viper.ReadInConfig(); err != nil {
 		return
 	}
+	for key, env := range secretEnvs {
+		if err = viper.BindEnv(key, env); err != nil {
+			return
+		}
+	}
 	if err = viper.Unmarshal(&config); err != nil {
 		return
 	}
 	fmt.Println("Configuration loaded successfully")
 	// 验证并设置默认值
-	config.Validate()
+	err = config.Validate()
 	return
 }
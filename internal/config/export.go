@@ -0,0 +1,48 @@
+package config
+
+import "errors"
+
+const (
+	DefaultExportStorageDir        = "./data/exports"
+	DefaultExportLinkExpireMinutes = 30      // 默认下载链接有效期，单位：分钟
+	DefaultExportBatchSize         = 1000    // 默认每批读取记录数
+	DefaultExportRetentionDays     = 7       // 默认导出文件保留天数
+	DefaultExportMaxRows           = 1000000 // 默认单次导出最大行数
+)
+
+// ExportConfig 交易导出配置
+type ExportConfig struct {
+	StorageDir        string `mapstructure:"storage_dir"`         // 导出文件存储目录
+	DownloadURL       string `mapstructure:"download_url"`        // 下载地址，签名参数追加在其后
+	SignSecret        string `mapstructure:"sign_secret"`         // 下载链接签名密钥，必填，生产环境由环境变量INPAYOS_EXPORT_SIGN_SECRET注入
+	LinkExpireMinutes int    `mapstructure:"link_expire_minutes"` // 下载链接有效期，单位：分钟
+	BatchSize         int    `mapstructure:"batch_size"`          // 每批读取记录数
+	RetentionDays     int    `mapstructure:"retention_days"`      // 导出文件保留天数
+	MaxRows           int    `mapstructure:"max_rows"`            // 单次导出最大行数
+}
+
+func (c *ExportConfig) Validate() error {
+	if c.StorageDir == "" {
+		c.StorageDir = DefaultExportStorageDir
+	}
+	if c.DownloadURL == "" {
+		c.DownloadURL = "/exports/download"
+	}
+	// 下载接口无需登录，仅凭签名校验，密钥不能使用默认值
+	if c.SignSecret == "" {
+		return errors.New("export sign_secret is required, set INPAYOS_EXPORT_SIGN_SECRET")
+	}
+	if c.LinkExpireMinutes <= 0 {
+		c.LinkExpireMinutes = DefaultExportLinkExpireMinutes
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultExportBatchSize
+	}
+	if c.RetentionDays <= 0 {
+		c.RetentionDays = DefaultExportRetentionDays
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = DefaultExportMaxRows
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import "time"
+
+const (
+	DefaultFxFeedTimeoutSeconds = 10    // 默认行情源请求超时，单位：秒
+	DefaultFxMaxRateAgeHours    = 72    // 默认汇率最长可用时长，单位：小时
+	DefaultFxBackfillBatchSize  = 500   // 默认回填每批处理记录数
+	DefaultFxBackfillMaxRows    = 50000 // 默认回填单次最多处理记录数
+	DefaultFxQuoteExpirySeconds = 30    // 默认换汇报价有效期，单位：秒
+)
+
+// FxConfig 汇率配置
+type FxConfig struct {
+	Feed               string `mapstructure:"feed"`                 // 行情源名称，为空时仅使用后台上传的汇率
+	FeedURL            string `mapstructure:"feed_url"`             // 行情源地址
+	FeedAPIKey         string `mapstructure:"feed_api_key"`         // 行情源密钥
+	FeedTimeoutSeconds int    `mapstructure:"feed_timeout_seconds"` // 行情源请求超时，单位：秒
+	MaxRateAgeHours    int    `mapstructure:"max_rate_age_hours"`   // 交易时可用汇率的最长时效，超过则不快照
+	BackfillBatchSize  int    `mapstructure:"backfill_batch_size"`  // 回填每批处理记录数
+	BackfillMaxRows    int    `mapstructure:"backfill_max_rows"`    // 回填单次最多处理记录数
+	QuoteExpirySeconds int    `mapstructure:"quote_expiry_seconds"` // 换汇报价有效期，过期后需重新报价
+}
+
+func (c *FxConfig) Validate() {
+	if c.FeedTimeoutSeconds <= 0 {
+		c.FeedTimeoutSeconds = DefaultFxFeedTimeoutSeconds
+	}
+	if c.MaxRateAgeHours <= 0 {
+		c.MaxRateAgeHours = DefaultFxMaxRateAgeHours
+	}
+	if c.BackfillBatchSize <= 0 {
+		c.BackfillBatchSize = DefaultFxBackfillBatchSize
+	}
+	if c.BackfillMaxRows <= 0 {
+		c.BackfillMaxRows = DefaultFxBackfillMaxRows
+	}
+	if c.QuoteExpirySeconds <= 0 {
+		c.QuoteExpirySeconds = DefaultFxQuoteExpirySeconds
+	}
+}
+
+// GetQuoteExpiry 获取换汇报价有效期（毫秒）
+func (c *FxConfig) GetQuoteExpiry() int64 {
+	return int64(c.QuoteExpirySeconds) * time.Second.Milliseconds()
+}
+
+// GetFeedTimeout 获取行情源请求超时
+func (c *FxConfig) GetFeedTimeout() time.Duration {
+	return time.Duration(c.FeedTimeoutSeconds) * time.Second
+}
+
+// GetMaxRateAge 获取汇率最长可用时长（毫秒）
+func (c *FxConfig) GetMaxRateAge() int64 {
+	return int64(c.MaxRateAgeHours) * time.Hour.Milliseconds()
+}
@@ -0,0 +1,44 @@
+package config
+
+import "time"
+
+const (
+	DefaultLedgerCheckBatchSize     = 200  // 默认每批核对的账户数
+	DefaultLedgerCheckLookbackHours = 48   // 默认业务与流水交叉核对的回溯时长，单位：小时
+	DefaultLedgerMaxDiscrepancies   = 1000 // 默认单次核对最多保存的差异数
+	DefaultLedgerCheckTimeoutMins   = 60   // 默认后台发起的单次核对最长执行时间，单位：分钟
+)
+
+// LedgerConfig 账务核对配置
+type LedgerConfig struct {
+	CheckBatchSize     int      `mapstructure:"check_batch_size"`     // 每批核对的账户数
+	CheckLookbackHours int      `mapstructure:"check_lookback_hours"` // 定时核对时业务与流水交叉核对的回溯时长
+	MaxDiscrepancies   int      `mapstructure:"max_discrepancies"`    // 单次核对最多保存的差异数，超出只计数
+	AlertEmails        []string `mapstructure:"alert_emails"`         // 发现差异时告警的邮箱
+	CheckTimeoutMins   int      `mapstructure:"check_timeout_mins"`   // 后台发起的单次核对最长执行时间，超时置为失败
+}
+
+func (c *LedgerConfig) Validate() {
+	if c.CheckBatchSize <= 0 {
+		c.CheckBatchSize = DefaultLedgerCheckBatchSize
+	}
+	if c.CheckLookbackHours <= 0 {
+		c.CheckLookbackHours = DefaultLedgerCheckLookbackHours
+	}
+	if c.MaxDiscrepancies <= 0 {
+		c.MaxDiscrepancies = DefaultLedgerMaxDiscrepancies
+	}
+	if c.CheckTimeoutMins <= 0 {
+		c.CheckTimeoutMins = DefaultLedgerCheckTimeoutMins
+	}
+}
+
+// GetCheckLookback 获取交叉核对回溯时长（毫秒）
+func (c *LedgerConfig) GetCheckLookback() int64 {
+	return int64(c.CheckLookbackHours) * time.Hour.Milliseconds()
+}
+
+// GetCheckTimeout 获取后台发起核对的最长执行时间
+func (c *LedgerConfig) GetCheckTimeout() time.Duration {
+	return time.Duration(c.CheckTimeoutMins) * time.Minute
+}
@@ -1,15 +1,19 @@
 package config
 
+import "strings"
+
 const (
 	DefaultCheckoutExpiryMinutes = 30 // 默认支付订单过期时间，单位：分钟
 )
 
 type MerchantCheckoutConfig struct {
-	ExpiryMinutes int `mapstructure:"expiry_minutes"` // 支付订单过期时间，单位：分钟
+	ExpiryMinutes int    `mapstructure:"expiry_minutes"` // 支付订单过期时间，单位：分钟
+	PageURL       string `mapstructure:"page_url"`       // 收银台页面地址，会话地址为 {page_url}/{checkout_id}
 }
 
 func (c *MerchantCheckoutConfig) Validate() {
 	if c.ExpiryMinutes <= 0 {
 		c.ExpiryMinutes = DefaultMerchantPayoutExpiryMinutes
 	}
+	c.PageURL = strings.TrimRight(c.PageURL, "/")
 }
@@ -0,0 +1,12 @@
+package config
+
+import "strings"
+
+// PaymentLinkConfig 支付链接配置
+type PaymentLinkConfig struct {
+	PageURL string `mapstructure:"page_url"` // 支付链接落地页地址，链接地址为 {page_url}/{link_id}
+}
+
+func (c *PaymentLinkConfig) Validate() {
+	c.PageURL = strings.TrimRight(c.PageURL, "/")
+}
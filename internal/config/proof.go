@@ -0,0 +1,21 @@
+package config
+
+const (
+	DefaultProofStorageDir   = "./data/proofs"
+	DefaultProofMaxImageSize = 5 * 1024 * 1024 // 默认凭证图片大小上限，单位：字节
+)
+
+// ProofConfig 支付凭证配置
+type ProofConfig struct {
+	StorageDir   string `mapstructure:"storage_dir"`    // 凭证图片存储目录
+	MaxImageSize int64  `mapstructure:"max_image_size"` // 凭证图片大小上限，单位：字节
+}
+
+func (c *ProofConfig) Validate() {
+	if c.StorageDir == "" {
+		c.StorageDir = DefaultProofStorageDir
+	}
+	if c.MaxImageSize <= 0 {
+		c.MaxImageSize = DefaultProofMaxImageSize
+	}
+}
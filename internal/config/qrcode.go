@@ -0,0 +1,26 @@
+package config
+
+const (
+	DefaultQRCodeSize    = 256  // 默认二维码边长，单位：像素
+	DefaultQRCodeMinSize = 128  // 二维码最小边长
+	DefaultQRCodeMaxSize = 1024 // 二维码最大边长
+)
+
+// QRCodeConfig 二维码渲染配置
+type QRCodeConfig struct {
+	DefaultSize int `mapstructure:"default_size"` // 未指定尺寸时的边长，单位：像素
+	MinSize     int `mapstructure:"min_size"`     // 允许的最小边长
+	MaxSize     int `mapstructure:"max_size"`     // 允许的最大边长
+}
+
+func (c *QRCodeConfig) Validate() {
+	if c.MinSize <= 0 {
+		c.MinSize = DefaultQRCodeMinSize
+	}
+	if c.MaxSize < c.MinSize {
+		c.MaxSize = max(DefaultQRCodeMaxSize, c.MinSize)
+	}
+	if c.DefaultSize < c.MinSize || c.DefaultSize > c.MaxSize {
+		c.DefaultSize = min(max(DefaultQRCodeSize, c.MinSize), c.MaxSize)
+	}
+}
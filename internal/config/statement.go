@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultStatementMaxRows         = 100000 // 默认单份对账单最大流水条数
+	DefaultStatementMaxPeriodDays   = 366    // 默认单份对账单最长周期，单位：天
+	DefaultStatementLinkExpireHours = 24     // 默认对账单下载链接有效期，单位：小时
+	DefaultStatementBatchSize       = 1000   // 默认生成对账单时每批读取的流水条数
+)
+
+// StatementConfig 账户对账单配置
+type StatementConfig struct {
+	DownloadURL     string `mapstructure:"download_url"`      // 下载地址，签名参数追加在其后，开启邮件发送时须为https完整地址
+	SignSecret      string `mapstructure:"sign_secret"`       // 下载链接签名密钥，必填，与导出签名密钥相互独立，生产环境由环境变量INPAYOS_STATEMENT_SIGN_SECRET注入
+	EmailEnabled    bool   `mapstructure:"email_enabled"`     // 是否按月发送对账单邮件
+	MaxRows         int    `mapstructure:"max_rows"`          // 单份对账单最大流水条数
+	MaxPeriodDays   int    `mapstructure:"max_period_days"`   // 单份对账单最长周期
+	LinkExpireHours int    `mapstructure:"link_expire_hours"` // 下载链接有效期，邮件发送的链接同样适用
+	BatchSize       int    `mapstructure:"batch_size"`        // 生成对账单时每批读取的流水条数
+}
+
+func (c *StatementConfig) Validate() error {
+	if c.DownloadURL == "" {
+		c.DownloadURL = "/statements/download"
+	}
+	// 下载接口无需登录，仅凭签名校验，密钥不能使用默认值
+	if c.SignSecret == "" {
+		return errors.New("statement sign_secret is required, set INPAYOS_STATEMENT_SIGN_SECRET")
+	}
+	// 邮件中的链接在邮件客户端中打开，相对地址无法访问
+	if c.EmailEnabled {
+		u, err := url.Parse(c.DownloadURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.New("statement download_url must be an absolute https url when email_enabled is true")
+		}
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = DefaultStatementMaxRows
+	}
+	if c.MaxPeriodDays <= 0 {
+		c.MaxPeriodDays = DefaultStatementMaxPeriodDays
+	}
+	if c.LinkExpireHours <= 0 {
+		c.LinkExpireHours = DefaultStatementLinkExpireHours
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultStatementBatchSize
+	}
+	return nil
+}
+
+// GetLinkExpire 获取下载链接有效期
+func (c *StatementConfig) GetLinkExpire() time.Duration {
+	return time.Duration(c.LinkExpireHours) * time.Hour
+}
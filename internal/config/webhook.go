@@ -0,0 +1,63 @@
+package config
+
+import "time"
+
+const (
+	DefaultWebhookTimeoutSeconds   = 10    // 默认推送请求超时，单位：秒
+	DefaultWebhookMaxRetryTimes    = 8     // 默认最多推送次数
+	DefaultWebhookRetryBaseSeconds = 30    // 默认首次重试间隔，单位：秒
+	DefaultWebhookRetryMaxSeconds  = 21600 // 默认最长重试间隔，单位：秒
+	DefaultWebhookBatchSize        = 100   // 默认每批扫描的待推送记录数
+	DefaultWebhookWorkers          = 10    // 默认并发推送数
+	DefaultWebhookRotationHours    = 24    // 默认密钥轮换过渡期，单位：小时
+)
+
+// WebhookConfig 商户异步通知推送配置
+type WebhookConfig struct {
+	TimeoutSeconds   int `mapstructure:"timeout_seconds"`    // 推送请求超时，单位：秒
+	MaxRetryTimes    int `mapstructure:"max_retry_times"`    // 最多推送次数，达到后置为失败
+	RetryBaseSeconds int `mapstructure:"retry_base_seconds"` // 首次重试间隔，之后按指数退避
+	RetryMaxSeconds  int `mapstructure:"retry_max_seconds"`  // 最长重试间隔
+	BatchSize        int `mapstructure:"batch_size"`         // 每批扫描的待推送记录数
+	Workers          int `mapstructure:"workers"`            // 并发推送数
+	RotationHours    int `mapstructure:"rotation_hours"`     // 签名密钥轮换后旧密钥继续签名的小时数
+}
+
+func (c *WebhookConfig) Validate() {
+	if c.TimeoutSeconds <= 0 {
+		c.TimeoutSeconds = DefaultWebhookTimeoutSeconds
+	}
+	if c.MaxRetryTimes <= 0 {
+		c.MaxRetryTimes = DefaultWebhookMaxRetryTimes
+	}
+	if c.RetryBaseSeconds <= 0 {
+		c.RetryBaseSeconds = DefaultWebhookRetryBaseSeconds
+	}
+	if c.RetryMaxSeconds < c.RetryBaseSeconds {
+		c.RetryMaxSeconds = max(DefaultWebhookRetryMaxSeconds, c.RetryBaseSeconds)
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultWebhookBatchSize
+	}
+	if c.Workers <= 0 {
+		c.Workers = DefaultWebhookWorkers
+	}
+	if c.RotationHours <= 0 {
+		c.RotationHours = DefaultWebhookRotationHours
+	}
+}
+
+// GetTimeout 获取推送请求超时
+func (c *WebhookConfig) GetTimeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// GetRetryDelay 第times次推送失败后的重试间隔（毫秒），按指数退避并封顶
+func (c *WebhookConfig) GetRetryDelay(times int32) int64 {
+	delay := time.Duration(c.RetryBaseSeconds) * time.Second
+	maxDelay := time.Duration(c.RetryMaxSeconds) * time.Second
+	for i := int32(1); i < times && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay).Milliseconds()
+}
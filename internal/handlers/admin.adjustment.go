@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 申请修改交易状态
+// @Description 渠道线下确认等场景，发起将交易强制改为成功或失败的申请，需另一名管理员复核后生效；改为成功的代收由结算任务入账，代付改为失败时解冻、改为成功时扣款，已结算的交易及成功的代付不能改状态，余额修正请使用调账申请
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.TrxStatusOverrideRequest true "改状态申请"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /adjustments/status [post]
+func (a *Admin) RequestStatusOverride(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.TrxStatusOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetAdminAdjustmentService().RequestStatusOverride(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 申请账户调账
+// @Description 对任意账户发起加款或扣款申请，需另一名管理员复核后生效
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BalanceAdjustRequest true "调账申请"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /adjustments/balance [post]
+func (a *Admin) RequestBalanceAdjust(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BalanceAdjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetAdminAdjustmentService().RequestBalanceAdjust(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 人工调整申请列表
+// @Description 按业务类型（trx_status_override/balance_adjust/internal_transfer）查询人工调整申请
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ApprovalListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Approval}} "返回结果"
+// @Router /adjustments/list [post]
+func (a *Admin) ListAdjustments(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ApprovalListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetAdminAdjustmentService().List(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 复核通过人工调整
+// @Description 复核人使用自己的G2FA审批通过并立即执行调整
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.AdjustmentDecisionRequest true "审批信息"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /adjustments/approve [post]
+func (a *Admin) ApproveAdjustment(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.AdjustmentDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetAdminAdjustmentService().Approve(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 驳回人工调整
+// @Description 复核人使用自己的G2FA驳回申请，不做任何调整
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.AdjustmentDecisionRequest true "审批信息"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /adjustments/reject [post]
+func (a *Admin) RejectAdjustment(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.AdjustmentDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetAdminAdjustmentService().Reject(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 导入银行目录
+// @Description 上传CSV银行目录，type为banks时列顺序：country,bank_code,bank_name,short_name；
+// @Description ifsc：ifsc,bank_name,branch,address,city,state；swift：swift_code,bank_name,country,city,branch。
+// @Description 已存在的编码覆盖更新，导入后按目录规范化出纳员卡的银行编码
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param type formData string true "目录类型：banks, ifsc, swift"
+// @Param file formData file true "CSV文件"
+// @Success 200 {object} protocol.Result{data=protocol.BankImportResult} "返回结果"
+// @Router /banks/import [post]
+func (a *Admin) ImportBanks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.FileError, lang))
+		return
+	}
+	defer file.Close()
+	response, code := services.GetBankDirectoryService().Import(c.PostForm("type"), file)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 查询银行编码
+// @Description 按银行编码、IFSC或SWIFT/BIC查询银行目录
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankLookupRequest true "银行编码"
+// @Success 200 {object} protocol.Result{data=protocol.BankInfo} "返回结果"
+// @Router /banks/lookup [post]
+func (a *Admin) LookupBank(c *gin.Context) {
+	lookupBank(c)
+}
+
+// @Summary 搜索银行目录
+// @Description 按国家、名称搜索银行，或按银行编码、城市搜索IFSC与SWIFT分行
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankSearchRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BankInfo}} "返回结果"
+// @Router /banks/search [post]
+func (a *Admin) SearchBanks(c *gin.Context) {
+	searchBanks(c)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 保证金充值审核队列
+// @Description 按车队、状态等筛选全部车队的保证金充值
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginDepositListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.CashierMarginDeposit}} "返回结果"
+// @Router /cashier-margin/deposits/list [post]
+func (a *Admin) ListMarginDeposits(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetCashierMarginService().List("", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 保证金充值详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginDepositRequest true "充值ID"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /cashier-margin/deposits/detail [post]
+func (a *Admin) MarginDepositDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetCashierMarginService().Get("", req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 保证金转账凭证
+// @Description 查看车队上传的转账凭证图片
+// @Tags Admin
+// @Accept json
+// @Produce octet-stream
+// @Param data body protocol.MarginDepositRequest true "充值ID"
+// @Success 200 {file} file "凭证图片"
+// @Router /cashier-margin/deposits/image [post]
+func (a *Admin) MarginDepositImage(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	path, code := services.GetCashierMarginService().ImagePath("", req.TrxID)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.File(path)
+}
+
+// @Summary 确认保证金到账
+// @Description 核实银行到账后使用管理员G2FA确认，充值金额入账车队余额并转入保证金
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ConfirmMarginDepositRequest true "确认信息"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /cashier-margin/deposits/confirm [post]
+func (a *Admin) ConfirmMarginDeposit(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ConfirmMarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetCashierMarginService().Confirm(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 驳回保证金充值
+// @Description 未收到对应转账时驳回
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.RejectMarginDepositRequest true "驳回原因"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /cashier-margin/deposits/reject [post]
+func (a *Admin) RejectMarginDeposit(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.RejectMarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetCashierMarginService().Reject(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 车队代收额度
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginCapacityRequest true "车队ID及币种"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginCapacity} "返回结果"
+// @Router /cashier-margin/capacity [post]
+func (a *Admin) MarginCapacity(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Tid == "" {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetCashierMarginService().Capacity(req.Tid, req.Ccy)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 车队下线
+// @Description 使用管理员G2FA下线车队，下线后不再承接代收，未结算代收全部结算后自动释放保证金
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.OffboardCashierTeamRequest true "下线信息"
+// @Success 200 {object} protocol.Result{data=protocol.CashierTeam} "返回结果"
+// @Router /cashier-teams/offboard [post]
+func (a *Admin) OffboardCashierTeam(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.OffboardCashierTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetCashierMarginService().Offboard(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 登记争议
+// @Description 登记渠道退单或付款人投诉，冻结商户争议金额并通知商户；仅已结算入账的成功代收可登记
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateDisputeRequest true "争议信息"
+// @Success 200 {object} protocol.Result{data=protocol.Dispute} "返回结果"
+// @Router /disputes/create [post]
+func (a *Admin) CreateDispute(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetDisputeService().Create(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 争议队列
+// @Description 按商户、状态、截止时间等筛选全部争议
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.DisputeListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Dispute}} "返回结果"
+// @Router /disputes/list [post]
+func (a *Admin) ListDisputes(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.DisputeListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetDisputeService().List("", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 争议详情
+// @Description 获取争议详情及商户提交的证据
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.DisputeRequest true "争议ID"
+// @Success 200 {object} protocol.Result{data=protocol.Dispute} "返回结果"
+// @Router /disputes/detail [post]
+func (a *Admin) GetDispute(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.DisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetDisputeService().Get("", req.DisputeID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 裁决争议
+// @Description 胜诉解冻争议金额；败诉解冻后以退单类型扣款
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ResolveDisputeRequest true "裁决结果"
+// @Success 200 {object} protocol.Result{data=protocol.Dispute} "返回结果"
+// @Router /disputes/resolve [post]
+func (a *Admin) ResolveDispute(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetDisputeService().Resolve(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func adminExportOwner(c *gin.Context) *services.ExportOwner {
+	admin := middleware.GetAdminFromContext(c)
+	return &services.ExportOwner{
+		UserID:   admin.UserID,
+		UserType: protocol.UserTypeAdmin,
+		Email:    admin.GetEmail(),
+		Lang:     middleware.GetLanguage(c),
+	}
+}
+
+// CreateExport godoc
+// @Summary 创建交易导出任务
+// @Description 按交易筛选条件异步导出CSV/XLSX，可按商户或出纳团队筛选，完成后邮件通知
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.TransactionExportRequest true "导出条件"
+// @Success 200 {object} protocol.Result{data=protocol.ExportJob}
+// @Router /exports/create [post]
+func (a *Admin) CreateExport(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.TransactionExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	createExport(c, adminExportOwner(c), &req)
+}
+
+// ListExports godoc
+// @Summary 导出任务列表
+// @Description 分页获取当前管理员的导出任务
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ExportJobListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.ExportJob}}
+// @Router /exports/list [post]
+func (a *Admin) ListExports(c *gin.Context) {
+	listExports(c, adminExportOwner(c))
+}
+
+// ExportLink godoc
+// @Summary 获取导出文件下载链接
+// @Description 生成限时签名下载链接
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ExportJobRequest true "导出任务"
+// @Success 200 {object} protocol.Result{data=protocol.ExportDownloadLink}
+// @Router /exports/link [post]
+func (a *Admin) ExportLink(c *gin.Context) {
+	exportLink(c, adminExportOwner(c))
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 录入汇率
+// @Description 批量录入币种对汇率，1 base_ccy = rate quote_ccy，同一币种对同一生效时间重复录入时覆盖
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateFxRatesRequest true "汇率列表"
+// @Success 200 {object} protocol.Result{data=[]protocol.FxRate} "返回结果"
+// @Router /fx/rates/create [post]
+func (a *Admin) CreateFxRates(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateFxRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetFxRateService().Create(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 上传汇率文件
+// @Description 上传CSV汇率文件，列顺序：base_ccy,quote_ccy,rate,effective_at
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV文件"
+// @Success 200 {object} protocol.Result{data=[]protocol.FxRate} "返回结果"
+// @Router /fx/rates/upload [post]
+func (a *Admin) UploadFxRates(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.FileError, lang))
+		return
+	}
+	defer file.Close()
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetFxRateService().Upload(admin.UserID, file)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 汇率历史
+// @Description 按币种对、来源和生效时间查询汇率历史
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxRateListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.FxRate}} "返回结果"
+// @Router /fx/rates/list [post]
+func (a *Admin) ListFxRates(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxRateListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetFxRateService().List(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 查询汇率
+// @Description 查询指定时间点生效的汇率，支持反向及经美元交叉换算
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxRateQuoteRequest true "币种对及时间"
+// @Success 200 {object} protocol.Result{data=protocol.FxRate} "返回结果"
+// @Router /fx/rates/quote [post]
+func (a *Admin) QuoteFxRate(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxRateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetFxRateService().Quote(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 回填美元金额
+// @Description 按交易创建时间和完成时间回溯历史汇率，补齐缺失的美元金额及手续费美元汇率
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxUsdBackfillRequest true "回填范围"
+// @Success 200 {object} protocol.Result{data=protocol.FxUsdBackfillResult} "返回结果"
+// @Router /fx/backfill [post]
+func (a *Admin) BackfillUsdAmount(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxUsdBackfillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetFxRateService().Backfill(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 保存换汇加点
+// @Description 按商户及币种对配置换汇加点，商户为空时保存全部商户的默认加点
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.SaveFxMarkupRequest true "加点配置"
+// @Success 200 {object} protocol.Result{data=protocol.FxMarkup} "返回结果"
+// @Router /fx/markups/save [post]
+func (a *Admin) SaveFxMarkup(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.SaveFxMarkupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetFxConversionService().SaveMarkup(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 换汇加点列表
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxMarkupListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.FxMarkup}} "返回结果"
+// @Router /fx/markups/list [post]
+func (a *Admin) ListFxMarkups(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxMarkupListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetFxConversionService().ListMarkups(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 商户换汇记录
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.FxConversion}} "返回结果"
+// @Router /fx/conversions/list [post]
+func (a *Admin) ListFxConversions(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetFxConversionService().List("", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 商户换汇详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionRequest true "换汇单号"
+// @Success 200 {object} protocol.Result{data=protocol.FxConversion} "返回结果"
+// @Router /fx/conversions/detail [post]
+func (a *Admin) FxConversionDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetFxConversionService().Get("", req.ConversionID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"inpayos/internal/middleware"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 管理员绑定G2FA
+// @Description 绑定管理员的二次验证，人工调整的发起和复核均需G2FA
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body BindG2FAReq true "绑定信息"
+// @Success 200 {object} protocol.Result "返回结果"
+// @Router /g2fa/bind [post]
+func (a *Admin) BindG2FA(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req BindG2FAReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+
+	admin := middleware.GetAdminFromContext(c)
+	//已经绑定过，重新绑定
+	if admin.GetG2FA() != "" {
+		// 验证码校验
+		if !services.GetVerifyCodeService().VerifyEmailCode(protocol.VerifyCodeTypeResetG2FA, admin.GetEmail(), req.VerifyCode) {
+			c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+			return
+		}
+	}
+
+	// 从缓存获取待绑定的G2FA密钥
+	cacheKey := fmt.Sprintf(protocol.G2FABindingTpl, admin.UserID)
+	newG2FAKey, err := models.GetCache(cacheKey)
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.SystemError, lang))
+		return
+	}
+
+	// 使用待绑定的新密钥验证G2FA code
+	if !services.VerifyG2FACode(newG2FAKey, req.Code) {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidTwoFactorCode, lang))
+		return
+	}
+
+	if err := models.WriteDB.Model(admin).Updates(&models.AdminValues{G2FA: &newG2FAKey}).Error; err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.DatabaseError, lang))
+		return
+	}
+
+	// 删除缓存中的临时G2FA密钥
+	models.Delete(cacheKey)
+
+	c.JSON(http.StatusOK, protocol.NewSuccessResultWithLang(nil, lang))
+}
+
+// @Summary 生成新的G2FA密钥
+// @Description 为管理员生成新的G2FA密钥
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result "返回结果"
+// @Router /g2fa/new [post]
+func (a *Admin) NewG2FA(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	admin := middleware.GetAdminFromContext(c)
+	newG2FAKey := services.GenerateG2FAKey()
+	if newG2FAKey == "" {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.SystemError, lang))
+		return
+	}
+
+	// 将新生成的G2FA密钥存入缓存
+	cacheKey := fmt.Sprintf(protocol.G2FABindingTpl, admin.UserID)
+	if err := models.SetCache(cacheKey, newG2FAKey, protocol.G2FACacheExpiration); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.CacheError, lang))
+		return
+	}
+
+	response := G2FAResponse{
+		G2FAKey: newG2FAKey,
+		QRCode:  services.GenerateG2FAQRCode(admin.UserID, newG2FAKey),
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessResultWithLang(response, lang))
+}
@@ -85,9 +85,146 @@ func (a *Admin) SetupRouter() *gin.Engine {
 	// 添加Swagger文档路由
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName("admin")))
 
+	// 导出文件下载（签名链接，无需登录）
+	router.GET("/exports/download", DownloadExport)
+
 	// 需要JWT认证的端点
 	adminAPI := router.Group("/")
 	adminAPI.Use(middleware.AdminJWTAuth())
 	adminAPI.Use(middleware.PermissionCheck())
+
+	// G2FA相关路由
+	g2fa := adminAPI.Group("/g2fa")
+	{
+		g2fa.POST("/new", a.NewG2FA)   // 生成新的G2FA密钥
+		g2fa.POST("/bind", a.BindG2FA) // 绑定G2FA
+	}
+
+	// 人工调整相关路由（maker-checker）
+	adjustments := adminAPI.Group("/adjustments")
+	{
+		adjustments.POST("/status", a.RequestStatusOverride) // 申请修改交易状态
+		adjustments.POST("/balance", a.RequestBalanceAdjust) // 申请账户调账
+		adjustments.POST("/list", a.ListAdjustments)         // 申请列表
+		adjustments.POST("/approve", a.ApproveAdjustment)    // 复核通过
+		adjustments.POST("/reject", a.RejectAdjustment)      // 驳回
+	}
+
+	// 内部转账相关路由，复核通过 /adjustments/approve 完成
+	transfers := adminAPI.Group("/transfers")
+	{
+		transfers.POST("/create", a.RequestInternalTransfer) // 申请内部转账
+		transfers.POST("/list", a.ListInternalTransfers)     // 内部转账列表
+		transfers.POST("/detail", a.InternalTransferDetail)  // 内部转账详情
+	}
+
+	// 商户管理相关路由
+	merchants := adminAPI.Group("/merchants")
+	{
+		merchants.POST("/group", a.SetMerchantGroup) // 设置商户集团
+	}
+
+	// 结算准备金相关路由
+	reserves := adminAPI.Group("/reserves")
+	{
+		reserves.POST("/rules/save", a.SaveReserveRule)  // 保存准备金规则
+		reserves.POST("/rules/list", a.ListReserveRules) // 准备金规则列表
+		reserves.POST("/list", a.ListReserves)           // 准备金记录列表
+		reserves.POST("/detail", a.ReserveDetail)        // 准备金记录详情
+	}
+
+	// 争议相关路由
+	disputes := adminAPI.Group("/disputes")
+	{
+		disputes.POST("/create", a.CreateDispute)   // 登记争议
+		disputes.POST("/list", a.ListDisputes)      // 争议队列
+		disputes.POST("/detail", a.GetDispute)      // 争议详情
+		disputes.POST("/resolve", a.ResolveDispute) // 裁决争议
+	}
+
+	// 商户提现审核相关路由
+	withdraws := adminAPI.Group("/withdraws")
+	{
+		withdraws.POST("/list", a.ListWithdraws)        // 提现审核队列
+		withdraws.POST("/detail", a.WithdrawDetail)     // 提现详情
+		withdraws.POST("/approve", a.ApproveWithdraw)   // 审核通过并执行
+		withdraws.POST("/reject", a.RejectWithdraw)     // 驳回
+		withdraws.POST("/complete", a.CompleteWithdraw) // 登记人工转账完成
+		withdraws.POST("/fail", a.FailWithdraw)         // 置为失败
+	}
+
+	// 车队保证金相关路由
+	cashierMargin := adminAPI.Group("/cashier-margin")
+	{
+		cashierMargin.POST("/deposits/list", a.ListMarginDeposits)      // 保证金充值审核队列
+		cashierMargin.POST("/deposits/detail", a.MarginDepositDetail)   // 保证金充值详情
+		cashierMargin.POST("/deposits/image", a.MarginDepositImage)     // 转账凭证图片
+		cashierMargin.POST("/deposits/confirm", a.ConfirmMarginDeposit) // 确认到账
+		cashierMargin.POST("/deposits/reject", a.RejectMarginDeposit)   // 驳回
+		cashierMargin.POST("/capacity", a.MarginCapacity)               // 车队代收额度
+	}
+
+	// 车队管理相关路由
+	cashierTeams := adminAPI.Group("/cashier-teams")
+	{
+		cashierTeams.POST("/offboard", a.OffboardCashierTeam) // 车队下线并释放保证金
+	}
+
+	// 汇率相关路由
+	fx := adminAPI.Group("/fx")
+	{
+		fx.POST("/rates/create", a.CreateFxRates)            // 录入汇率
+		fx.POST("/rates/upload", a.UploadFxRates)            // 上传汇率文件
+		fx.POST("/rates/list", a.ListFxRates)                // 汇率历史
+		fx.POST("/rates/quote", a.QuoteFxRate)               // 查询指定时间汇率
+		fx.POST("/backfill", a.BackfillUsdAmount)            // 回填历史交易美元金额
+		fx.POST("/markups/save", a.SaveFxMarkup)             // 保存换汇加点
+		fx.POST("/markups/list", a.ListFxMarkups)            // 换汇加点列表
+		fx.POST("/conversions/list", a.ListFxConversions)    // 商户换汇记录
+		fx.POST("/conversions/detail", a.FxConversionDetail) // 商户换汇详情
+	}
+
+	// 支付凭证审核相关路由
+	proofs := adminAPI.Group("/proofs")
+	{
+		proofs.POST("/list", a.ListProofs)      // 凭证队列
+		proofs.POST("/detail", a.ProofDetail)   // 凭证详情
+		proofs.POST("/image", a.ProofImage)     // 凭证图片
+		proofs.POST("/approve", a.ApproveProof) // 审核通过
+		proofs.POST("/reject", a.RejectProof)   // 驳回
+	}
+
+	// 银行流水相关路由
+	statements := adminAPI.Group("/statements")
+	{
+		statements.POST("/import", a.ImportStatements) // 导入银行流水
+		statements.POST("/list", a.ListStatements)     // 银行流水列表
+	}
+
+	// 银行目录相关路由
+	banks := adminAPI.Group("/banks")
+	{
+		banks.POST("/import", a.ImportBanks) // 导入银行目录
+		banks.POST("/lookup", a.LookupBank)  // 查询银行编码
+		banks.POST("/search", a.SearchBanks) // 搜索银行目录
+	}
+
+	// 交易导出相关路由
+	exports := adminAPI.Group("/exports")
+	{
+		exports.POST("/create", a.CreateExport) // 创建导出任务
+		exports.POST("/list", a.ListExports)    // 导出任务列表
+		exports.POST("/link", a.ExportLink)     // 获取下载链接
+	}
+
+	// 账务核对相关路由
+	ledger := adminAPI.Group("/ledger")
+	{
+		ledger.POST("/checks/run", a.RunLedgerCheck)                    // 发起账务核对
+		ledger.POST("/checks/list", a.ListLedgerChecks)                 // 核对记录
+		ledger.POST("/checks/detail", a.LedgerCheckDetail)              // 核对详情
+		ledger.POST("/checks/discrepancies", a.ListLedgerDiscrepancies) // 差异报告
+		ledger.POST("/accounts/replay", a.ReplayLedgerAccount)          // 重放单个账户流水
+	}
 	return router
 }
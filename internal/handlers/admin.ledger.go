@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 发起账务核对
+// @Description 异步重放全部账户流水，并交叉核对时间范围内的结算、已结算交易、争议、代付复核和调账等业务流水，时间范围为空时按配置回溯，超过配置的最长执行时间置为失败
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.LedgerCheckRunRequest true "交叉核对时间范围"
+// @Success 200 {object} protocol.Result{data=protocol.LedgerCheck} "返回结果"
+// @Router /ledger/checks/run [post]
+func (a *Admin) RunLedgerCheck(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.LedgerCheckRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetLedgerService().RunCheck(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 账务核对记录
+// @Description 按状态和创建时间查询账务核对记录
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.LedgerCheckListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.LedgerCheck}} "返回结果"
+// @Router /ledger/checks/list [post]
+func (a *Admin) ListLedgerChecks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.LedgerCheckListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetLedgerService().ListChecks(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 账务核对详情
+// @Description 获取账务核对的进度和统计
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.LedgerCheckRequest true "核对ID"
+// @Success 200 {object} protocol.Result{data=protocol.LedgerCheck} "返回结果"
+// @Router /ledger/checks/detail [post]
+func (a *Admin) LedgerCheckDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.LedgerCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetLedgerService().GetCheck(req.CheckID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 账务核对差异报告
+// @Description 按差异类型和账户查询账务核对发现的差异
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.LedgerDiscrepancyListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.LedgerDiscrepancy}} "返回结果"
+// @Router /ledger/checks/discrepancies [post]
+func (a *Admin) ListLedgerDiscrepancies(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.LedgerDiscrepancyListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetLedgerService().ListDiscrepancies(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 重放账户流水
+// @Description 按版本号顺序重放单个账户的流水，返回重放资产、当前资产和发现的差异，不保存结果
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.LedgerReplayRequest true "账户ID"
+// @Success 200 {object} protocol.Result{data=protocol.LedgerReplayResult} "返回结果"
+// @Router /ledger/accounts/replay [post]
+func (a *Admin) ReplayLedgerAccount(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.LedgerReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetLedgerService().ReplayAccount(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 支付凭证队列
+// @Description 查询付款人提交的UTR凭证，按状态筛选待审核队列
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.PaymentProof}} "返回结果"
+// @Router /proofs/list [post]
+func (a *Admin) ListProofs(c *gin.Context) {
+	listProofs(c, "")
+}
+
+// @Summary 支付凭证详情
+// @Description 获取支付凭证详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofDetailRequest true "凭证ID"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof} "返回结果"
+// @Router /proofs/detail [post]
+func (a *Admin) ProofDetail(c *gin.Context) {
+	proofDetail(c, "")
+}
+
+// @Summary 支付凭证图片
+// @Description 查看付款人上传的付款截图
+// @Tags Admin
+// @Accept json
+// @Produce octet-stream
+// @Param data body protocol.ProofDetailRequest true "凭证ID"
+// @Success 200 {file} file "凭证图片"
+// @Router /proofs/image [post]
+func (a *Admin) ProofImage(c *gin.Context) {
+	proofImage(c, "")
+}
+
+// @Summary 审核通过支付凭证
+// @Description 确认到账，交易置为成功并通知商户
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofReviewRequest true "审核信息"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof} "返回结果"
+// @Router /proofs/approve [post]
+func (a *Admin) ApproveProof(c *gin.Context) {
+	reviewProof(c, middleware.GetAdminFromContext(c).UserID, "", true)
+}
+
+// @Summary 驳回支付凭证
+// @Description 驳回后交易保持待支付，付款人可重新提交
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofReviewRequest true "审核信息"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof} "返回结果"
+// @Router /proofs/reject [post]
+func (a *Admin) RejectProof(c *gin.Context) {
+	reviewProof(c, middleware.GetAdminFromContext(c).UserID, "", false)
+}
+
+// @Summary 导入银行流水
+// @Description 上传CSV银行流水并按UTR自动匹配待审核凭证，列顺序：utr,amount,ccy,txn_at,account_no,narration
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV文件"
+// @Success 200 {object} protocol.Result{data=protocol.BankStatementImportResult} "返回结果"
+// @Router /statements/import [post]
+func (a *Admin) ImportStatements(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.FileError, lang))
+		return
+	}
+	defer file.Close()
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetPaymentProofService().ImportStatements(admin.UserID, file)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 银行流水列表
+// @Description 按导入批次、UTR、匹配状态查询银行流水
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankStatementListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BankStatement}} "返回结果"
+// @Router /statements/list [post]
+func (a *Admin) ListStatements(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BankStatementListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetPaymentProofService().ListStatements(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 保存准备金规则
+// @Description 设置商户某结算币种的准备金扣留比例、最低扣留金额及持有天数，同一商户同一币种重复保存时覆盖
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.SaveReserveRuleRequest true "准备金规则"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantReserveRule} "返回结果"
+// @Router /reserves/rules/save [post]
+func (a *Admin) SaveReserveRule(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.SaveReserveRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetMerchantReserveService().SaveRule(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 准备金规则列表
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ReserveRuleListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.MerchantReserveRule}} "返回结果"
+// @Router /reserves/rules/list [post]
+func (a *Admin) ListReserveRules(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ReserveRuleListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetMerchantReserveService().ListRules(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 准备金记录列表
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ReserveListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.MerchantReserve}} "返回结果"
+// @Router /reserves/list [post]
+func (a *Admin) ListReserves(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ReserveListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetMerchantReserveService().List("", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 准备金记录详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ReserveRequest true "准备金编号"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantReserve} "返回结果"
+// @Router /reserves/detail [post]
+func (a *Admin) ReserveDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ReserveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetMerchantReserveService().Get("", req.ReserveID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
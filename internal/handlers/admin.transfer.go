@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 申请内部转账
+// @Description 在任意两个同币种的平台账户间发起转账申请，需另一名管理员通过 /adjustments/approve 复核后执行
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateInternalTransferRequest true "转账申请"
+// @Success 200 {object} protocol.Result{data=protocol.InternalTransfer} "返回结果"
+// @Router /transfers/create [post]
+func (a *Admin) RequestInternalTransfer(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateInternalTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetInternalTransferService().Request(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 内部转账列表
+// @Description 按用户查询时包含该用户转出和转入的记录
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.InternalTransferListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.InternalTransfer}} "返回结果"
+// @Router /transfers/list [post]
+func (a *Admin) ListInternalTransfers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.InternalTransferListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetInternalTransferService().List("", "", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 内部转账详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.InternalTransferRequest true "转账单号"
+// @Success 200 {object} protocol.Result{data=protocol.InternalTransfer} "返回结果"
+// @Router /transfers/detail [post]
+func (a *Admin) InternalTransferDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.InternalTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetInternalTransferService().Get("", "", req.TransferID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 设置商户集团
+// @Description 同一集团的商户之间可由商户自行发起内部转账，集团ID为空时移出集团
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.SetMerchantGroupRequest true "商户及集团ID"
+// @Success 200 {object} protocol.Result{data=protocol.Merchant} "返回结果"
+// @Router /merchants/group [post]
+func (a *Admin) SetMerchantGroup(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.SetMerchantGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetInternalTransferService().SetMerchantGroup(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
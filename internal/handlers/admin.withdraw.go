@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 提现审核队列
+// @Description 按商户、状态、执行方式等筛选全部商户提现
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.MerchantWithdraw}} "返回结果"
+// @Router /withdraws/list [post]
+func (a *Admin) ListWithdraws(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetMerchantWithdrawService().List("", &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 提现详情
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawRequest true "提现ID"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /withdraws/detail [post]
+func (a *Admin) WithdrawDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetMerchantWithdrawService().Get("", req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 审核通过提现
+// @Description 使用管理员G2FA审核通过；channel方式立即请求代付渠道出款，manual方式线下转账后登记完成
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ApproveWithdrawRequest true "审核信息"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /withdraws/approve [post]
+func (a *Admin) ApproveWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ApproveWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Approve(c.Request.Context(), admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 驳回提现
+// @Description 驳回待审核的提现并解冻商户资金
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.RejectWithdrawRequest true "驳回原因"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /withdraws/reject [post]
+func (a *Admin) RejectWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.RejectWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Reject(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 登记人工转账完成
+// @Description 人工方式的提现线下转账后登记银行流水号，需管理员G2FA确认
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.CompleteWithdrawRequest true "转账信息"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /withdraws/complete [post]
+func (a *Admin) CompleteWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CompleteWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Complete(admin, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 提现置为失败
+// @Description 人工转账失败或渠道线下确认失败时，将处理中的提现置为失败并解冻商户资金
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param data body protocol.FailWithdrawRequest true "失败原因"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /withdraws/fail [post]
+func (a *Admin) FailWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FailWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	admin := middleware.GetAdminFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Fail(admin.UserID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
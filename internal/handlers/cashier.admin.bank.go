@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// LookupBank godoc
+// @Summary 查询银行编码
+// @Description 按银行编码、IFSC或SWIFT/BIC查询银行目录
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankLookupRequest true "银行编码"
+// @Success 200 {object} protocol.Result{data=protocol.BankInfo}
+// @Router /banks/lookup [post]
+func (t *CashierAdmin) LookupBank(c *gin.Context) {
+	lookupBank(c)
+}
+
+// SearchBanks godoc
+// @Summary 搜索银行目录
+// @Description 按国家、名称搜索银行，或按银行编码、城市搜索IFSC与SWIFT分行
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankSearchRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BankInfo}}
+// @Router /banks/search [post]
+func (t *CashierAdmin) SearchBanks(c *gin.Context) {
+	searchBanks(c)
+}
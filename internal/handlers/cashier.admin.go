@@ -76,6 +76,9 @@ func (t *CashierAdmin) SetupRouter() *gin.Engine {
 	api.POST("/verifycode/verify", VerifyCode)   // 验证验证码
 	api.POST("/register", t.Register)            // 注册商户
 	api.POST("/password/reset", t.ResetPassword) // 重置密码
+	api.GET("/exports/download", DownloadExport) // 导出文件下载（签名链接）
+	// 对账单下载（签名链接）
+	api.GET("/statements/download", DownloadStatement)
 	// 注册JWT中间件
 	api.Use(middleware.CashierTeamJWTAuth())
 	api.POST("/info", t.Info)                      // 商户信息
@@ -96,6 +99,52 @@ func (t *CashierAdmin) SetupRouter() *gin.Engine {
 		transactions.POST("/today-stats", t.GetTransactionTodayStats) // 今日统计
 	}
 
+	// 交易导出相关路由
+	exports := api.Group("/exports")
+	{
+		exports.POST("/create", t.CreateExport) // 创建导出任务
+		exports.POST("/list", t.ListExports)    // 导出任务列表
+		exports.POST("/link", t.ExportLink)     // 获取下载链接
+	}
+
+	// 对账单相关路由
+	statements := api.Group("/statements")
+	{
+		statements.POST("/summary", t.StatementSummary)                        // 对账单摘要
+		statements.POST("/link", t.StatementLink)                              // 获取对账单下载链接
+		statements.POST("/subscription", t.StatementSubscription)              // 对账单邮件订阅
+		statements.POST("/subscription/update", t.UpdateStatementSubscription) // 修改对账单邮件订阅
+	}
+
+	// 支付凭证审核相关路由
+	proofs := api.Group("/proofs")
+	{
+		proofs.POST("/list", t.ListProofs)      // 凭证队列
+		proofs.POST("/detail", t.ProofDetail)   // 凭证详情
+		proofs.POST("/image", t.ProofImage)     // 凭证图片
+		proofs.POST("/approve", t.ApproveProof) // 审核通过
+		proofs.POST("/reject", t.RejectProof)   // 驳回
+	}
+
+	// 银行目录相关路由
+	banks := api.Group("/banks")
+	{
+		banks.POST("/lookup", t.LookupBank)  // 查询银行编码
+		banks.POST("/search", t.SearchBanks) // 搜索银行目录
+	}
+
+	// 异步通知相关路由
+	webhooks := api.Group("/webhooks")
+	{
+		webhooks.POST("/notify-url", t.UpdateWebhookNotifyURL) // 修改通知地址
+		webhooks.POST("/secret", t.WebhookSecret)              // 通知签名密钥
+		webhooks.POST("/secret/rotate", t.RotateWebhookSecret) // 轮换通知签名密钥
+		webhooks.POST("/list", t.ListWebhooks)                 // 通知推送记录
+		webhooks.POST("/detail", t.WebhookDetail)              // 通知推送详情
+		webhooks.POST("/resend", t.ResendWebhooks)             // 重推通知
+		webhooks.POST("/ping", t.PingWebhook)                  // 测试通知
+	}
+
 	// 出纳员相关路由
 	cashiers := api.Group("/cashiers")
 	{
@@ -117,5 +166,15 @@ func (t *CashierAdmin) SetupRouter() *gin.Engine {
 		account.GET("/list", t.AccountList)           // 账户列表
 		account.POST("/flow/list", t.AccountFlowList) // 账户流水列表
 	}
+
+	// 保证金相关路由
+	margin := api.Group("/margin")
+	{
+		margin.POST("/deposits/submit", t.SubmitMarginDeposit) // 提交保证金充值
+		margin.POST("/deposits/list", t.ListMarginDeposits)    // 保证金充值列表
+		margin.POST("/deposits/detail", t.MarginDepositDetail) // 保证金充值详情
+		margin.POST("/deposits/cancel", t.CancelMarginDeposit) // 撤销保证金充值
+		margin.POST("/capacity", t.MarginCapacity)             // 代收额度
+	}
 	return router
 }
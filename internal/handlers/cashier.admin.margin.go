@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 提交保证金充值
+// @Description 线下转账后提交转账流水号及转账凭证，管理员确认到账后计入保证金
+// @Tags CashierAdmin
+// @Accept multipart/form-data
+// @Produce json
+// @Param ccy formData string true "币种"
+// @Param amount formData string true "转账金额"
+// @Param transfer_ref formData string true "银行转账流水号"
+// @Param remark formData string false "备注"
+// @Param proof formData file true "转账凭证（jpg/png/webp）"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /margin/deposits/submit [post]
+func (t *CashierAdmin) SubmitMarginDeposit(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.SubmitMarginDepositRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	image, err := c.FormFile("proof")
+	if err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.ProofImageInvalid, lang))
+		return
+	}
+	team := middleware.GetCashierTeamFromContext(c)
+	response, code := services.GetCashierMarginService().Submit(team, &req, image)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 保证金充值列表
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginDepositListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.CashierMarginDeposit}} "返回结果"
+// @Router /margin/deposits/list [post]
+func (t *CashierAdmin) ListMarginDeposits(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	list, total, code := services.GetCashierMarginService().List(tid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 保证金充值详情
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginDepositRequest true "充值ID"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /margin/deposits/detail [post]
+func (t *CashierAdmin) MarginDepositDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetCashierMarginService().Get(tid, req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 撤销保证金充值
+// @Description 撤销待确认的保证金充值
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginDepositRequest true "充值ID"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginDeposit} "返回结果"
+// @Router /margin/deposits/cancel [post]
+func (t *CashierAdmin) CancelMarginDeposit(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetCashierMarginService().Cancel(tid, req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 代收额度
+// @Description 代收额度为保证金余额乘以配置倍数，扣除处理中及成功未结算的代收金额后为可用额度
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.MarginCapacityRequest true "币种"
+// @Success 200 {object} protocol.Result{data=protocol.CashierMarginCapacity} "返回结果"
+// @Router /margin/capacity [post]
+func (t *CashierAdmin) MarginCapacity(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MarginCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetCashierMarginService().Capacity(tid, req.Ccy)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProofs godoc
+// @Summary 支付凭证队列
+// @Description 本团队受理交易的付款凭证审核队列
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.PaymentProof}}
+// @Router /proofs/list [post]
+func (t *CashierAdmin) ListProofs(c *gin.Context) {
+	listProofs(c, middleware.GetTidFromContext(c))
+}
+
+// ProofDetail godoc
+// @Summary 支付凭证详情
+// @Description 获取本团队的支付凭证详情
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofDetailRequest true "凭证ID"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof}
+// @Router /proofs/detail [post]
+func (t *CashierAdmin) ProofDetail(c *gin.Context) {
+	proofDetail(c, middleware.GetTidFromContext(c))
+}
+
+// ProofImage godoc
+// @Summary 支付凭证图片
+// @Description 查看付款人上传的付款截图
+// @Tags CashierAdmin
+// @Accept json
+// @Produce octet-stream
+// @Param data body protocol.ProofDetailRequest true "凭证ID"
+// @Success 200 {file} file "凭证图片"
+// @Router /proofs/image [post]
+func (t *CashierAdmin) ProofImage(c *gin.Context) {
+	proofImage(c, middleware.GetTidFromContext(c))
+}
+
+// ApproveProof godoc
+// @Summary 审核通过支付凭证
+// @Description 确认已收到付款，交易置为成功
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofReviewRequest true "审核信息"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof}
+// @Router /proofs/approve [post]
+func (t *CashierAdmin) ApproveProof(c *gin.Context) {
+	tid := middleware.GetTidFromContext(c)
+	reviewProof(c, tid, tid, true)
+}
+
+// RejectProof godoc
+// @Summary 驳回支付凭证
+// @Description 未收到对应付款时驳回，付款人可重新提交
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.ProofReviewRequest true "审核信息"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof}
+// @Router /proofs/reject [post]
+func (t *CashierAdmin) RejectProof(c *gin.Context) {
+	tid := middleware.GetTidFromContext(c)
+	reviewProof(c, tid, tid, false)
+}
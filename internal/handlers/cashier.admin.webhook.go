@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 修改通知地址
+// @Description 修改收银团队的通知地址，团队将收到订单分配、订单完成及结算完成通知，为空表示不再接收通知
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.UpdateWebhookNotifyURLRequest true "通知地址"
+// @Success 200 {object} protocol.Result "返回结果"
+// @Router /webhooks/notify-url [post]
+func (t *CashierAdmin) UpdateWebhookNotifyURL(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.UpdateWebhookNotifyURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	team := middleware.GetCashierTeamFromContext(c)
+	code := services.GetCashierAdminService().UpdateNotifyURL(team, req.NotifyURL)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, nil, lang))
+}
+
+// @Summary 通知签名密钥
+// @Description 获取当前参与签名的通知密钥，首次查询时生成，签名规则与商户通知一致
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=protocol.WebhookSecretInfo} "返回结果"
+// @Router /webhooks/secret [post]
+func (t *CashierAdmin) WebhookSecret(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetWebhookSecretService().Info(protocol.UserTypeCashierTeam, tid, "")
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 轮换通知签名密钥
+// @Description 生成新的通知签名密钥，旧密钥在过渡期内继续签名，已绑定G2FA时需提供验证码
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.RotateWebhookSecretRequest true "轮换参数"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookSecretInfo} "返回结果"
+// @Router /webhooks/secret/rotate [post]
+func (t *CashierAdmin) RotateWebhookSecret(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.RotateWebhookSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetWebhookSecretService().Rotate(protocol.UserTypeCashierTeam, tid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 通知推送记录
+// @Description 查询收银团队的通知推送记录，可按通知ID、交易号、事件、推送状态及创建时间筛选
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.WebhookDelivery}} "返回结果"
+// @Router /webhooks/list [post]
+func (t *CashierAdmin) ListWebhooks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	list, total, code := services.GetWebhookService().ListDeliveries(protocol.UserTypeCashierTeam, tid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 通知推送详情
+// @Description 获取通知推送详情，包含推送内容及每次推送的请求头、响应码与响应体
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookRequest true "通知ID"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookDelivery} "返回结果"
+// @Router /webhooks/detail [post]
+func (t *CashierAdmin) WebhookDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetWebhookService().GetDelivery(protocol.UserTypeCashierTeam, tid, req.WebhookID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 重推通知
+// @Description 手动重推一条或多条通知，单次最多100条，由投递任务立即推送
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookResendRequest true "通知ID列表"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookResendResult} "返回结果"
+// @Router /webhooks/resend [post]
+func (t *CashierAdmin) ResendWebhooks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookResendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetWebhookService().Resend(protocol.UserTypeCashierTeam, tid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 测试通知
+// @Description 向团队的通知地址发送一条event为ping的签名通知，同步返回响应状态码与是否成功（不含响应体），不保存也不重试
+// @Tags CashierAdmin
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=protocol.WebhookPingResult} "返回结果"
+// @Router /webhooks/ping [post]
+func (t *CashierAdmin) PingWebhook(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	tid := middleware.GetTidFromContext(c)
+	response, code := services.GetWebhookService().Ping(c.Request.Context(), protocol.UserTypeCashierTeam, tid, "")
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadExport godoc
+// @Summary 下载导出文件
+// @Description 通过限时签名链接下载导出文件，无需登录
+// @Tags Export
+// @Produce octet-stream
+// @Param job_id query string true "导出任务ID"
+// @Param expires query int true "链接过期时间(秒)"
+// @Param sign query string true "签名"
+// @Success 200 {file} file "导出文件"
+// @Router /exports/download [get]
+func DownloadExport(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ExportDownloadRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	job, code := services.GetTransactionExportService().ResolveDownload(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.FileAttachment(job.GetFilePath(), job.GetFileName())
+}
+
+// createExport 创建导出任务的公共处理
+func createExport(c *gin.Context, owner *services.ExportOwner, req *protocol.TransactionExportRequest) {
+	lang := middleware.GetLanguage(c)
+	query := services.NewExportTrxQuery(req)
+	job, code := services.GetTransactionExportService().Create(owner, query, req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, job, lang))
+}
+
+// listExports 导出任务列表的公共处理
+func listExports(c *gin.Context, owner *services.ExportOwner) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ExportJobListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	list, total, code := services.GetTransactionExportService().List(owner, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// exportLink 获取导出文件下载链接的公共处理
+func exportLink(c *gin.Context, owner *services.ExportOwner) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ExportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	link, code := services.GetTransactionExportService().GetDownloadLink(owner, req.JobID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, link, lang))
+}
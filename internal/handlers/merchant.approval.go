@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 复核列表
+// @Description 获取待复核及已处理的审批记录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.ApprovalListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Approval}} "返回结果"
+// @Router /merchant/approvals/list [post]
+func (t *MerchantAdmin) ListApprovals(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ApprovalListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetMerchantApprovalService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 复核通过
+// @Description 复核员以子账号登录并使用自己的G2FA审批通过，代付解冻后提交渠道
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.ApprovalDecisionRequest true "审批信息"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /merchant/approvals/approve [post]
+func (t *MerchantAdmin) ApproveApproval(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	approverID := middleware.GetOperatorIDFromContext(c)
+	response, code := services.GetMerchantApprovalService().Approve(c, mid, approverID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 复核驳回
+// @Description 复核员以子账号登录并使用自己的G2FA驳回，代付解冻并置为失败
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.ApprovalDecisionRequest true "审批信息"
+// @Success 200 {object} protocol.Result{data=protocol.Approval} "返回结果"
+// @Router /merchant/approvals/reject [post]
+func (t *MerchantAdmin) RejectApproval(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	approverID := middleware.GetOperatorIDFromContext(c)
+	response, code := services.GetMerchantApprovalService().Reject(mid, approverID, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 查询银行编码
+// @Description 按银行编码、IFSC或SWIFT/BIC查询银行目录，供商户后台与收银台使用，无需登录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankLookupRequest true "银行编码"
+// @Success 200 {object} protocol.Result{data=protocol.BankInfo} "返回结果"
+// @Router /merchant/banks/lookup [post]
+func (t *MerchantAdmin) LookupBank(c *gin.Context) {
+	lookupBank(c)
+}
+
+// @Summary 搜索银行目录
+// @Description 按国家、名称搜索银行，或按银行编码、城市搜索IFSC与SWIFT分行，无需登录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BankSearchRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BankInfo}} "返回结果"
+// @Router /merchant/banks/search [post]
+func (t *MerchantAdmin) SearchBanks(c *gin.Context) {
+	searchBanks(c)
+}
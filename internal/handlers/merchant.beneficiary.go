@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 创建收款人
+// @Description 保存代付收款账户，按国家校验IFSC、IBAN、ABA、Sort Code等格式，可选创建后立即进行小额打款验证
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateBeneficiaryRequest true "收款人信息"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "返回结果"
+// @Router /merchant/beneficiaries/create [post]
+func (t *MerchantAdmin) CreateBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Create(mid, protocol.BeneficiarySourcePortal, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 收款人列表
+// @Description 获取商户的收款人，可按状态、验证状态、币种及名称筛选
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BeneficiaryListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Beneficiary}} "返回结果"
+// @Router /merchant/beneficiaries/list [post]
+func (t *MerchantAdmin) ListBeneficiaries(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetBeneficiaryService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 收款人详情
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BeneficiaryRequest true "收款人ID"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "返回结果"
+// @Router /merchant/beneficiaries/detail [post]
+func (t *MerchantAdmin) BeneficiaryDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Detail(mid, req.BeneficiaryID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 修改收款人
+// @Description 仅可修改备注名与联系方式，账户信息变更需停用后重新创建
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.UpdateBeneficiaryRequest true "修改内容"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "返回结果"
+// @Router /merchant/beneficiaries/update [post]
+func (t *MerchantAdmin) UpdateBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.UpdateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Update(mid, protocol.BeneficiarySourcePortal, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 停用收款人
+// @Description 停用后不能再用于代付
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BeneficiaryRequest true "收款人ID"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "返回结果"
+// @Router /merchant/beneficiaries/deactivate [post]
+func (t *MerchantAdmin) DeactivateBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Deactivate(mid, protocol.BeneficiarySourcePortal, req.BeneficiaryID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 验证收款人
+// @Description 通过支持账户验证的代付渠道进行小额打款验证，渠道返回户名需与收款人姓名一致
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BeneficiaryRequest true "收款人ID"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "返回结果"
+// @Router /merchant/beneficiaries/verify [post]
+func (t *MerchantAdmin) VerifyBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Verify(mid, protocol.BeneficiarySourcePortal, req.BeneficiaryID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 收款人变更记录
+// @Description 查询收款人的创建、修改、验证与停用记录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.BeneficiaryAuditListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BeneficiaryAudit}} "返回结果"
+// @Router /merchant/beneficiaries/audits [post]
+func (t *MerchantAdmin) BeneficiaryAudits(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryAuditListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetBeneficiaryService().ListAudits(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
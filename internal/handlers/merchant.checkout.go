@@ -3,6 +3,7 @@ package handlers
 import (
 	"inpayos/internal/middleware"
 	"inpayos/internal/protocol"
+	"inpayos/internal/services"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -166,3 +167,37 @@ func (a *MerchantAdmin) CancelCheckout(c *gin.Context) {
 	response, code := a.Checkout.Cancel(req.CheckoutID)
 	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
 }
+
+// SubmitCheckoutProof 提交支付凭证
+// @Summary 提交支付凭证
+// @Description 付款人完成银行转账/UPI付款后提交UTR及可选的付款截图，自动匹配银行流水或进入人工审核
+// @Tags Merchant
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param checkout_id formData string true "收银台会话ID"
+// @Param trx_id formData string true "交易ID"
+// @Param utr formData string true "UTR/银行参考号"
+// @Param image formData file false "付款截图（jpg/png/webp）"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentProof} "提交成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Failure 500 {object} protocol.Result "服务器错误"
+// @Router /checkout/proof [post]
+func (a *MerchantAdmin) SubmitCheckoutProof(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.SubmitProofRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Mid = middleware.GetMidFromContext(c)
+	// 截图为可选项
+	image, err := c.FormFile("image")
+	if err != nil && err != http.ErrMissingFile && err != http.ErrNotMultipart {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.ProofImageInvalid, lang))
+		return
+	}
+	response, code := services.GetPaymentProofService().Submit(&req, image)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -98,3 +98,17 @@ func (m *MerchantAdmin) GetAccountBalance(c *gin.Context) {
 
 	c.JSON(http.StatusOK, protocol.NewSuccessResult(balances))
 }
+
+// @Summary 获取准备金释放计划
+// @Description 获取商户各币种扣留中的结算准备金及按到期日汇总的释放计划
+// @Tags Dashboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=[]protocol.ReserveSchedule}
+// @Router /dashboard/reserve-schedule [post]
+func (m *MerchantAdmin) GetReserveSchedule(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	mid := middleware.GetMidFromContext(c)
+	schedules, code := services.GetMerchantReserveService().Schedule(mid)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, schedules, lang))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 争议列表
+// @Description 获取商户的退单和投诉争议
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.DisputeListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Dispute}} "返回结果"
+// @Router /merchant/disputes/list [post]
+func (t *MerchantAdmin) ListDisputes(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.DisputeListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetDisputeService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 争议详情
+// @Description 获取争议详情及已提交的证据
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.DisputeRequest true "争议ID"
+// @Success 200 {object} protocol.Result{data=protocol.Dispute} "返回结果"
+// @Router /merchant/disputes/detail [post]
+func (t *MerchantAdmin) GetDispute(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.DisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetDisputeService().Get(mid, req.DisputeID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 响应争议
+// @Description 截止时间前提交证据附件抗辩，或接受退单（立即扣除冻结金额）
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.DisputeRespondRequest true "响应内容"
+// @Success 200 {object} protocol.Result{data=protocol.Dispute} "返回结果"
+// @Router /merchant/disputes/respond [post]
+func (t *MerchantAdmin) RespondDispute(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.DisputeRespondRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetDisputeService().Respond(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
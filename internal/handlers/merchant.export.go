@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func merchantExportOwner(c *gin.Context) *services.ExportOwner {
+	merchant := middleware.GetMerchantFromContext(c)
+	return &services.ExportOwner{
+		UserID:   merchant.Mid,
+		UserType: protocol.UserTypeMerchant,
+		Email:    merchant.GetEmail(),
+		Lang:     middleware.GetLanguage(c),
+	}
+}
+
+// CreateExport godoc
+// @Summary 创建交易导出任务
+// @Description 按交易列表筛选条件异步导出CSV/XLSX，完成后邮件通知
+// @Tags 交易管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.TransactionExportRequest true "导出条件"
+// @Success 200 {object} protocol.Result{data=protocol.ExportJob}
+// @Router /exports/create [post]
+func (t *MerchantAdmin) CreateExport(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.TransactionExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	// 商户只能导出自己的交易
+	req.Mid = middleware.GetMidFromContext(c)
+	createExport(c, merchantExportOwner(c), &req)
+}
+
+// ListExports godoc
+// @Summary 导出任务列表
+// @Description 分页获取当前商户的导出任务
+// @Tags 交易管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.ExportJobListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.ExportJob}}
+// @Router /exports/list [post]
+func (t *MerchantAdmin) ListExports(c *gin.Context) {
+	listExports(c, merchantExportOwner(c))
+}
+
+// ExportLink godoc
+// @Summary 获取导出文件下载链接
+// @Description 生成限时签名下载链接
+// @Tags 交易管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.ExportJobRequest true "导出任务"
+// @Success 200 {object} protocol.Result{data=protocol.ExportDownloadLink}
+// @Router /exports/link [post]
+func (t *MerchantAdmin) ExportLink(c *gin.Context) {
+	exportLink(c, merchantExportOwner(c))
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 换汇报价
+// @Description 按中间价及加点报价，成交汇率与入账金额在有效期内锁定
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionQuoteRequest true "币种对及转出金额"
+// @Success 200 {object} protocol.Result{data=protocol.FxConversion} "返回结果"
+// @Router /merchant/fx/conversions/quote [post]
+func (t *MerchantAdmin) QuoteFxConversion(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetFxConversionService().Quote(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 执行换汇
+// @Description 执行有效期内的报价，从转出币种账户扣款并入账转入币种账户
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionRequest true "换汇单号"
+// @Success 200 {object} protocol.Result{data=protocol.FxConversion} "返回结果"
+// @Router /merchant/fx/conversions/execute [post]
+func (t *MerchantAdmin) ExecuteFxConversion(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetFxConversionService().Execute(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 换汇记录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.FxConversion}} "返回结果"
+// @Router /merchant/fx/conversions/list [post]
+func (t *MerchantAdmin) ListFxConversions(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetFxConversionService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 换汇详情
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.FxConversionRequest true "换汇单号"
+// @Success 200 {object} protocol.Result{data=protocol.FxConversion} "返回结果"
+// @Router /merchant/fx/conversions/detail [post]
+func (t *MerchantAdmin) FxConversionDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.FxConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetFxConversionService().Get(mid, req.ConversionID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
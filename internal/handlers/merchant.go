@@ -78,8 +78,28 @@ func (t *MerchantAdmin) SetupRouter() *gin.Engine {
 	api.POST("/verifycode/send", SendVerifyCode) // 发送验证码
 	api.POST("/verifycode/verify", VerifyCode)   // 验证验证码
 	api.POST("/register", t.Register)            // 注册商户
+	api.GET("/exports/download", DownloadExport) // 导出文件下载（签名链接）
+	api.POST("/links/info", t.PaymentLinkInfo)   // 付款人查看支付链接
+	api.POST("/links/visit", t.VisitPaymentLink) // 付款人访问支付链接，创建收银台会话
+	api.POST("/banks/lookup", t.LookupBank)      // 查询银行编码，供收银台使用
+	api.POST("/banks/search", t.SearchBanks)     // 搜索银行目录，供收银台使用
+	// 对账单下载（签名链接）
+	api.GET("/statements/download", DownloadStatement)
 
-	// 注册JWT中间件
+	// 子账号可访问的路由，按角色开放，须在主账号JWT中间件之前注册
+	operatorAuth := middleware.MerchantJWTAuth(protocol.MerchantUserRoleOperator)
+	api.POST("/transactions/payout", operatorAuth, t.CreatePayout) // 门户提交代付
+
+	// 代付复核相关路由
+	approverAuth := middleware.MerchantJWTAuth(protocol.MerchantUserRoleApprover)
+	approvals := api.Group("/approvals", approverAuth)
+	{
+		approvals.POST("/list", t.ListApprovals)      // 复核列表
+		approvals.POST("/approve", t.ApproveApproval) // 复核通过
+		approvals.POST("/reject", t.RejectApproval)   // 复核驳回
+	}
+
+	// 注册JWT中间件，以下路由仅商户主账号可访问
 	api.Use(middleware.MerchantJWTAuth())
 	api.POST("/info", t.Info)                      // 商户信息
 	api.POST("/password/change", t.ChangePassword) // 修改密码
@@ -104,9 +124,10 @@ func (t *MerchantAdmin) SetupRouter() *gin.Engine {
 	// Dashboard相关路由
 	dashboard := api.Group("/dashboard")
 	{
-		dashboard.POST("/today-stats", t.GetTodayStats)         // 今日统计
-		dashboard.POST("/overview", t.GetDashboardOverview)     // Dashboard概览
-		dashboard.POST("/account-balance", t.GetAccountBalance) // 账户余额
+		dashboard.POST("/today-stats", t.GetTodayStats)           // 今日统计
+		dashboard.POST("/overview", t.GetDashboardOverview)       // Dashboard概览
+		dashboard.POST("/account-balance", t.GetAccountBalance)   // 账户余额
+		dashboard.POST("/reserve-schedule", t.GetReserveSchedule) // 准备金释放计划
 	}
 
 	// 账户相关路由
@@ -115,6 +136,16 @@ func (t *MerchantAdmin) SetupRouter() *gin.Engine {
 		account.GET("/list", t.AccountList)           // 账户列表
 		account.POST("/flow/list", t.AccountFlowList) // 账户流水列表
 	}
+
+	// 对账单相关路由
+	statements := api.Group("/statements")
+	{
+		statements.POST("/summary", t.StatementSummary)                        // 对账单摘要
+		statements.POST("/link", t.StatementLink)                              // 获取对账单下载链接
+		statements.POST("/subscription", t.StatementSubscription)              // 对账单邮件订阅
+		statements.POST("/subscription/update", t.UpdateStatementSubscription) // 修改对账单邮件订阅
+	}
+
 	checkout := api.Group("/checkout")
 	{
 		checkout.POST("/create", t.CreateCheckout)
@@ -122,6 +153,104 @@ func (t *MerchantAdmin) SetupRouter() *gin.Engine {
 		checkout.POST("/services", t.CheckoutServices)
 		checkout.POST("/confirm", t.ConfirmCheckout)
 		checkout.POST("/cancel", t.CancelCheckout)
+		checkout.POST("/proof", t.SubmitCheckoutProof) // 提交支付凭证（UTR/截图）
+	}
+
+	// 支付链接相关路由
+	links := api.Group("/links")
+	{
+		links.POST("/create", t.CreatePaymentLink)   // 创建支付链接
+		links.POST("/list", t.ListPaymentLinks)      // 支付链接列表
+		links.POST("/disable", t.DisablePaymentLink) // 停用支付链接
+		links.POST("/stats", t.PaymentLinkStats)     // 支付链接统计
+	}
+
+	// 收款人相关路由
+	beneficiaries := api.Group("/beneficiaries")
+	{
+		beneficiaries.POST("/create", t.CreateBeneficiary)         // 创建收款人
+		beneficiaries.POST("/list", t.ListBeneficiaries)           // 收款人列表
+		beneficiaries.POST("/detail", t.BeneficiaryDetail)         // 收款人详情
+		beneficiaries.POST("/update", t.UpdateBeneficiary)         // 修改备注名与联系方式
+		beneficiaries.POST("/deactivate", t.DeactivateBeneficiary) // 停用收款人
+		beneficiaries.POST("/verify", t.VerifyBeneficiary)         // 小额打款验证
+		beneficiaries.POST("/audits", t.BeneficiaryAudits)         // 变更记录
+	}
+
+	// 异步通知相关路由
+	webhooks := api.Group("/webhooks")
+	{
+		webhooks.POST("/secret", t.WebhookSecret)              // 通知签名密钥
+		webhooks.POST("/secret/rotate", t.RotateWebhookSecret) // 轮换通知签名密钥
+		webhooks.POST("/list", t.ListWebhooks)                 // 通知推送记录
+		webhooks.POST("/detail", t.WebhookDetail)              // 通知推送详情
+		webhooks.POST("/resend", t.ResendWebhooks)             // 重推通知
+		webhooks.POST("/ping", t.PingWebhook)                  // 测试通知
+
+		webhooks.POST("/endpoints/list", t.ListWebhookEndpoints)    // 通知地址列表
+		webhooks.POST("/endpoints/create", t.CreateWebhookEndpoint) // 创建通知地址
+		webhooks.POST("/endpoints/detail", t.WebhookEndpointDetail) // 通知地址详情
+		webhooks.POST("/endpoints/update", t.UpdateWebhookEndpoint) // 修改通知地址及启停
+		webhooks.POST("/endpoints/ping", t.PingWebhookEndpoint)     // 通知地址测试通知
+	}
+
+	// 交易导出相关路由
+	exports := api.Group("/exports")
+	{
+		exports.POST("/create", t.CreateExport) // 创建导出任务
+		exports.POST("/list", t.ListExports)    // 导出任务列表
+		exports.POST("/link", t.ExportLink)     // 获取下载链接
+	}
+
+	// 子账号相关路由
+	users := api.Group("/users")
+	{
+		users.POST("/list", t.ListMerchantUsers)          // 子账号列表
+		users.POST("/create", t.CreateMerchantUser)       // 创建子账号
+		users.POST("/status", t.UpdateMerchantUserStatus) // 启用/停用子账号
+	}
+
+	// 提现相关路由
+	withdraws := api.Group("/withdraws")
+	{
+		withdraws.POST("/fee", t.WithdrawFee)       // 提现手续费试算
+		withdraws.POST("/create", t.CreateWithdraw) // 申请提现
+		withdraws.POST("/list", t.ListWithdraws)    // 提现列表
+		withdraws.POST("/detail", t.WithdrawDetail) // 提现详情
+		withdraws.POST("/cancel", t.CancelWithdraw) // 撤销提现
+	}
+
+	// 内部转账相关路由
+	transfers := api.Group("/transfers")
+	{
+		transfers.POST("/targets", t.TransferTargets) // 可转入商户
+		transfers.POST("/create", t.CreateTransfer)   // 内部转账
+		transfers.POST("/list", t.ListTransfers)      // 内部转账列表
+		transfers.POST("/detail", t.TransferDetail)   // 内部转账详情
+	}
+
+	// 换汇相关路由
+	fx := api.Group("/fx")
+	{
+		fx.POST("/conversions/quote", t.QuoteFxConversion)     // 换汇报价
+		fx.POST("/conversions/execute", t.ExecuteFxConversion) // 执行换汇
+		fx.POST("/conversions/list", t.ListFxConversions)      // 换汇记录
+		fx.POST("/conversions/detail", t.FxConversionDetail)   // 换汇详情
+	}
+
+	// 结算准备金相关路由
+	reserves := api.Group("/reserves")
+	{
+		reserves.POST("/list", t.ListReserves)    // 准备金记录
+		reserves.POST("/detail", t.ReserveDetail) // 准备金详情
+	}
+
+	// 争议相关路由
+	disputes := api.Group("/disputes")
+	{
+		disputes.POST("/list", t.ListDisputes)      // 争议列表
+		disputes.POST("/detail", t.GetDispute)      // 争议详情
+		disputes.POST("/respond", t.RespondDispute) // 提交证据或接受退单
 	}
 
 	return router
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 创建支付链接
+// @Description 创建可分享的收款链接，支持固定金额或开放金额、过期时间与使用次数限制
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreatePaymentLinkRequest true "支付链接参数"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentLink} "返回结果"
+// @Router /merchant/links/create [post]
+func (t *MerchantAdmin) CreatePaymentLink(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreatePaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Mid = middleware.GetMidFromContext(c)
+	response, code := services.GetPaymentLinkService().Create(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 支付链接列表
+// @Description 获取商户的支付链接，状态可按active、disabled、expired筛选
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.PaymentLinkListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.PaymentLink}} "返回结果"
+// @Router /merchant/links/list [post]
+func (t *MerchantAdmin) ListPaymentLinks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.PaymentLinkListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetPaymentLinkService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 停用支付链接
+// @Description 停用后不能再访问创建会话，已创建的会话也不能再确认支付
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.PaymentLinkRequest true "支付链接ID"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentLink} "返回结果"
+// @Router /merchant/links/disable [post]
+func (t *MerchantAdmin) DisablePaymentLink(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.PaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetPaymentLinkService().Disable(mid, req.LinkID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 支付链接统计
+// @Description 统计支付链接的访问会话数、成功笔数与金额、剩余可用次数
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.PaymentLinkRequest true "支付链接ID"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentLinkStats} "返回结果"
+// @Router /merchant/links/stats [post]
+func (t *MerchantAdmin) PaymentLinkStats(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.PaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetPaymentLinkService().Stats(mid, req.LinkID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 支付链接信息
+// @Description 付款人打开支付链接时获取金额与说明，无需登录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.PaymentLinkRequest true "支付链接ID"
+// @Success 200 {object} protocol.Result{data=protocol.PaymentLink} "返回结果"
+// @Router /merchant/links/info [post]
+func (t *MerchantAdmin) PaymentLinkInfo(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.PaymentLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetPaymentLinkService().Info(req.LinkID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 访问支付链接
+// @Description 付款人访问支付链接，创建收银台会话并返回会话令牌，开放金额链接需传入金额，无需登录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.PaymentLinkVisitRequest true "访问参数"
+// @Success 200 {object} protocol.Result{data=protocol.Checkout} "返回结果"
+// @Router /merchant/links/visit [post]
+func (t *MerchantAdmin) VisitPaymentLink(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.PaymentLinkVisitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetPaymentLinkService().Visit(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
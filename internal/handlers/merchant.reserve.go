@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 准备金记录
+// @Description 结算入账时扣留的准备金，到期后自动释放到可用余额
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.ReserveListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.MerchantReserve}} "返回结果"
+// @Router /merchant/reserves/list [post]
+func (t *MerchantAdmin) ListReserves(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ReserveListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetMerchantReserveService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 准备金详情
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.ReserveRequest true "准备金编号"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantReserve} "返回结果"
+// @Router /merchant/reserves/detail [post]
+func (t *MerchantAdmin) ReserveDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ReserveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetMerchantReserveService().Get(mid, req.ReserveID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
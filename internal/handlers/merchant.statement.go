@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementSummary godoc
+// @Summary 账户对账单摘要
+// @Description 按自然月或日期区间获取账户期初余额、期末余额及按业务类型汇总
+// @Tags 账户管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.StatementRequest true "对账单周期"
+// @Success 200 {object} protocol.Result{data=protocol.Statement}
+// @Router /statements/summary [post]
+func (t *MerchantAdmin) StatementSummary(c *gin.Context) {
+	statementSummary(c, middleware.GetMidFromContext(c), protocol.UserTypeMerchant)
+}
+
+// StatementLink godoc
+// @Summary 获取对账单下载链接
+// @Description 生成CSV/PDF对账单限时签名下载链接
+// @Tags 账户管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.StatementRequest true "对账单周期及格式"
+// @Success 200 {object} protocol.Result{data=protocol.StatementLink}
+// @Router /statements/link [post]
+func (t *MerchantAdmin) StatementLink(c *gin.Context) {
+	statementLink(c, middleware.GetMidFromContext(c), protocol.UserTypeMerchant)
+}
+
+// StatementSubscription godoc
+// @Summary 对账单邮件订阅
+// @Description 获取每月对账单邮件发送配置
+// @Tags 账户管理
+// @Produce json
+// @Success 200 {object} protocol.Result{data=protocol.StatementSubscription}
+// @Router /statements/subscription [post]
+func (t *MerchantAdmin) StatementSubscription(c *gin.Context) {
+	statementSubscription(c, middleware.GetMidFromContext(c), protocol.UserTypeMerchant)
+}
+
+// UpdateStatementSubscription godoc
+// @Summary 修改对账单邮件订阅
+// @Description 开启后每月初按订阅时区发送上月各币种账户对账单下载链接
+// @Tags 账户管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.StatementSubscriptionRequest true "订阅配置"
+// @Success 200 {object} protocol.Result{data=protocol.StatementSubscription}
+// @Router /statements/subscription/update [post]
+func (t *MerchantAdmin) UpdateStatementSubscription(c *gin.Context) {
+	mid := middleware.GetMidFromContext(c)
+	updateStatementSubscription(c, mid, protocol.UserTypeMerchant, mid)
+}
@@ -196,3 +196,24 @@ func (t *MerchantAdmin) GetTransactionTodayStats(c *gin.Context) {
 	// 返回成功结果
 	c.JSON(http.StatusOK, protocol.NewSuccessResultWithLang(stats, lang))
 }
+
+// CreatePayout godoc
+// @Summary 门户提交代付
+// @Description 商户子账号在门户提交代付，命中复核规则时进入待复核，提交人不能复核自己提交的代付
+// @Tags 交易管理
+// @Accept json
+// @Produce json
+// @Param data body protocol.MerchantPayoutRequest true "代付订单请求参数"
+// @Success 200 {object} protocol.Result{data=protocol.Transaction}
+// @Router /transactions/payout [post]
+func (t *MerchantAdmin) CreatePayout(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MerchantPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Mid = middleware.GetMidFromContext(c)
+	response, code := services.GetMerchantTransactionService().CreatePayout(c, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
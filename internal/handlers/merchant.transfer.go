@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 可转入商户
+// @Description 与当前商户同属一个商户集团的其他商户
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=[]protocol.Merchant} "返回结果"
+// @Router /merchant/transfers/targets [post]
+func (t *MerchantAdmin) TransferTargets(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	merchant := middleware.GetMerchantFromContext(c)
+	response, code := services.GetInternalTransferService().GroupMerchants(merchant)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 内部转账
+// @Description 向同一商户集团的商户转账，需商户G2FA确认，转出与转入同时记账
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.MerchantTransferRequest true "转账信息"
+// @Success 200 {object} protocol.Result{data=protocol.InternalTransfer} "返回结果"
+// @Router /merchant/transfers/create [post]
+func (t *MerchantAdmin) CreateTransfer(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.MerchantTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	merchant := middleware.GetMerchantFromContext(c)
+	response, code := services.GetInternalTransferService().MerchantTransfer(merchant, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 内部转账列表
+// @Description 包含当前商户转出和转入的记录
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.InternalTransferListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.InternalTransfer}} "返回结果"
+// @Router /merchant/transfers/list [post]
+func (t *MerchantAdmin) ListTransfers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.InternalTransferListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetInternalTransferService().List(mid, protocol.UserTypeMerchant, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 内部转账详情
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.InternalTransferRequest true "转账单号"
+// @Success 200 {object} protocol.Result{data=protocol.InternalTransfer} "返回结果"
+// @Router /merchant/transfers/detail [post]
+func (t *MerchantAdmin) TransferDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.InternalTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetInternalTransferService().Get(mid, protocol.UserTypeMerchant, req.TransferID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
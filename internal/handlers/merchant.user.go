@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 子账号列表
+// @Description 获取商户的复核员、操作员子账号
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=[]protocol.MerchantUser} "返回结果"
+// @Router /merchant/users/list [post]
+func (t *MerchantAdmin) ListMerchantUsers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	mid := middleware.GetMidFromContext(c)
+	users, code := services.GetMerchantUserService().List(mid)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, users, lang))
+}
+
+// @Summary 创建子账号
+// @Description 创建复核员或操作员子账号并设置登录密码，需商户G2FA确认，返回子账号G2FA密钥
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateMerchantUserRequest true "子账号信息"
+// @Success 200 {object} protocol.Result{data=protocol.CreateMerchantUserResponse} "返回结果"
+// @Router /merchant/users/create [post]
+func (t *MerchantAdmin) CreateMerchantUser(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateMerchantUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	merchant := middleware.GetMerchantFromContext(c)
+	response, code := services.GetMerchantUserService().Create(merchant, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 启用/停用子账号
+// @Description 修改子账号状态，需商户G2FA确认
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.UpdateMerchantUserStatusRequest true "状态信息"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantUser} "返回结果"
+// @Router /merchant/users/status [post]
+func (t *MerchantAdmin) UpdateMerchantUserStatus(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.UpdateMerchantUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	merchant := middleware.GetMerchantFromContext(c)
+	response, code := services.GetMerchantUserService().UpdateStatus(merchant, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 通知签名密钥
+// @Description 获取当前参与签名的通知密钥，首次查询时生成。通知请求头X-Webhook-Signature格式为t={时间戳},v1={签名}，签名为HMAC-SHA256(密钥, "{时间戳}.{原始请求体}")，轮换过渡期内携带新旧两个签名
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=protocol.WebhookSecretInfo} "返回结果"
+// @Router /merchant/webhooks/secret [post]
+func (t *MerchantAdmin) WebhookSecret(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookSecretService().Info(protocol.UserTypeMerchant, mid, "")
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 轮换通知签名密钥
+// @Description 生成新的通知签名密钥，旧密钥在过渡期内继续签名，已绑定G2FA时需提供验证码
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.RotateWebhookSecretRequest true "轮换参数"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookSecretInfo} "返回结果"
+// @Router /merchant/webhooks/secret/rotate [post]
+func (t *MerchantAdmin) RotateWebhookSecret(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.RotateWebhookSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookSecretService().Rotate(protocol.UserTypeMerchant, mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 通知推送记录
+// @Description 查询商户的异步通知推送记录，可按通知ID、交易号、事件类型、推送状态及创建时间筛选
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.WebhookDelivery}} "返回结果"
+// @Router /merchant/webhooks/list [post]
+func (t *MerchantAdmin) ListWebhooks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetWebhookService().ListDeliveries(protocol.UserTypeMerchant, mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 通知推送详情
+// @Description 获取通知推送详情，包含推送内容及每次推送的请求头、商户响应码与响应体
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookRequest true "通知ID"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookDelivery} "返回结果"
+// @Router /merchant/webhooks/detail [post]
+func (t *MerchantAdmin) WebhookDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookService().GetDelivery(protocol.UserTypeMerchant, mid, req.WebhookID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 重推通知
+// @Description 手动重推一条或多条通知，单次最多100条，由投递任务立即推送
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookResendRequest true "通知ID列表"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookResendResult} "返回结果"
+// @Router /merchant/webhooks/resend [post]
+func (t *MerchantAdmin) ResendWebhooks(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookResendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookService().Resend(protocol.UserTypeMerchant, mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 测试通知
+// @Description 向商户配置的默认通知地址发送一条event为ping的签名通知，同步返回响应状态码与是否成功（不含响应体），不保存也不重试
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=protocol.WebhookPingResult} "返回结果"
+// @Router /merchant/webhooks/ping [post]
+func (t *MerchantAdmin) PingWebhook(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookService().Ping(c.Request.Context(), protocol.UserTypeMerchant, mid, "")
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 通知地址列表
+// @Description 获取商户配置的全部通知地址，通知地址与默认通知地址（商户配置或订单指定的notify_url）并行推送
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Success 200 {object} protocol.Result{data=[]protocol.WebhookEndpoint} "返回结果"
+// @Router /merchant/webhooks/endpoints/list [post]
+func (t *MerchantAdmin) ListWebhookEndpoints(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookEndpointService().List(mid)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 创建通知地址
+// @Description 创建通知地址并生成独立的签名密钥，可按事件类型订阅并指定推送内容版本，未指定事件时订阅全部
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateWebhookEndpointRequest true "通知地址"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookEndpoint} "返回结果"
+// @Router /merchant/webhooks/endpoints/create [post]
+func (t *MerchantAdmin) CreateWebhookEndpoint(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookEndpointService().Create(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 通知地址详情
+// @Description 获取通知地址配置及其当前参与签名的密钥
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookEndpointRequest true "通知地址ID"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookEndpoint} "返回结果"
+// @Router /merchant/webhooks/endpoints/detail [post]
+func (t *MerchantAdmin) WebhookEndpointDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookEndpointService().Detail(mid, req.EndpointID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 修改通知地址
+// @Description 修改通知地址、订阅事件、推送内容版本或启停状态，停用后不再接收新事件，未完成的推送不再重试
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.UpdateWebhookEndpointRequest true "修改内容"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookEndpoint} "返回结果"
+// @Router /merchant/webhooks/endpoints/update [post]
+func (t *MerchantAdmin) UpdateWebhookEndpoint(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.UpdateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookEndpointService().Update(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 通知地址测试通知
+// @Description 按通知地址的密钥与版本发送一条event为ping的签名通知，同步返回响应状态码与是否成功（不含响应体），不保存也不重试
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WebhookEndpointRequest true "通知地址ID"
+// @Success 200 {object} protocol.Result{data=protocol.WebhookPingResult} "返回结果"
+// @Router /merchant/webhooks/endpoints/ping [post]
+func (t *MerchantAdmin) PingWebhookEndpoint(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetWebhookService().Ping(c.Request.Context(), protocol.UserTypeMerchant, mid, req.EndpointID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary 提现手续费试算
+// @Description 按商户提现费率配置计算手续费，冻结金额为提现金额与手续费之和
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawFeeRequest true "币种及金额"
+// @Success 200 {object} protocol.Result{data=protocol.WithdrawFee} "返回结果"
+// @Router /merchant/withdraws/fee [post]
+func (t *MerchantAdmin) WithdrawFee(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawFeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetMerchantWithdrawService().QuoteFee(mid, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 申请提现
+// @Description 将结算余额提现到已验证的收款人账户，需商户G2FA确认；提现金额及手续费立即冻结，管理员审核后出款
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.CreateWithdrawRequest true "提现申请"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /merchant/withdraws/create [post]
+func (t *MerchantAdmin) CreateWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateWithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	merchant := middleware.GetMerchantFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Create(merchant, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 提现列表
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.MerchantWithdraw}} "返回结果"
+// @Router /merchant/withdraws/list [post]
+func (t *MerchantAdmin) ListWithdraws(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetMerchantWithdrawService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// @Summary 提现详情
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawRequest true "提现ID"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /merchant/withdraws/detail [post]
+func (t *MerchantAdmin) WithdrawDetail(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Get(mid, req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// @Summary 撤销提现
+// @Description 撤销待审核的提现并解冻资金
+// @Tags Merchant
+// @Accept json
+// @Produce json
+// @Param data body protocol.WithdrawRequest true "提现ID"
+// @Success 200 {object} protocol.Result{data=protocol.MerchantWithdraw} "返回结果"
+// @Router /merchant/withdraws/cancel [post]
+func (t *MerchantAdmin) CancelWithdraw(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.WithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetMerchantWithdrawService().Cancel(mid, req.TrxID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// =============================================================================
+// 银行目录接口
+// =============================================================================
+
+// LookupBank 查询银行编码
+// @Summary 查询银行编码
+// @Description 按银行编码、IFSC或SWIFT/BIC查询银行目录，可用于代付前校验收款银行
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.BankLookupRequest true "银行编码"
+// @Success 200 {object} protocol.Result{data=protocol.BankInfo} "查询成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /banks/lookup [post]
+func (a *OpenApi) LookupBank(c *gin.Context) {
+	lookupBank(c)
+}
+
+// SearchBanks 搜索银行目录
+// @Summary 搜索银行目录
+// @Description 按国家、名称搜索银行，或按银行编码、城市搜索IFSC与SWIFT分行
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.BankSearchRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.BankInfo}} "查询成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /banks/search [post]
+func (a *OpenApi) SearchBanks(c *gin.Context) {
+	searchBanks(c)
+}
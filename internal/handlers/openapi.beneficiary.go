@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =============================================================================
+// 收款人接口
+// =============================================================================
+
+// CreateBeneficiary 创建收款人
+// @Summary 创建收款人
+// @Description 保存代付收款账户，代付时可通过beneficiary_id引用，可选创建后立即进行小额打款验证
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.CreateBeneficiaryRequest true "收款人信息"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "创建成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /beneficiaries [post]
+func (a *OpenApi) CreateBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.CreateBeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Create(mid, protocol.BeneficiarySourceOpenAPI, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// ListBeneficiaries 收款人列表
+// @Summary 收款人列表
+// @Description 分页查询商户的收款人，账号以掩码返回
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.BeneficiaryListRequest true "查询条件"
+// @Success 200 {object} protocol.Result{data=protocol.PageResult{records=[]protocol.Beneficiary}} "查询成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /beneficiaries/list [post]
+func (a *OpenApi) ListBeneficiaries(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	list, total, code := services.GetBeneficiaryService().List(mid, &req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+// DeactivateBeneficiary 停用收款人
+// @Summary 停用收款人
+// @Description 停用后不能再用于代付
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.BeneficiaryRequest true "收款人ID"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "停用成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /beneficiaries/deactivate [post]
+func (a *OpenApi) DeactivateBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Deactivate(mid, protocol.BeneficiarySourceOpenAPI, req.BeneficiaryID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// VerifyBeneficiary 验证收款人
+// @Summary 验证收款人
+// @Description 通过支持账户验证的代付渠道进行小额打款验证
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.BeneficiaryRequest true "收款人ID"
+// @Success 200 {object} protocol.Result{data=protocol.Beneficiary} "验证结果"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /beneficiaries/verify [post]
+func (a *OpenApi) VerifyBeneficiary(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.BeneficiaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	mid := middleware.GetMidFromContext(c)
+	response, code := services.GetBeneficiaryService().Verify(mid, protocol.BeneficiarySourceOpenAPI, req.BeneficiaryID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -105,6 +105,23 @@ func (a *OpenApi) SetupRouter() *gin.Engine {
 			checkout.POST("", a.CreateCheckout)
 		}
 
+		// 二维码与UPI意图接口
+		apiGroup.POST("/qrcode", a.QRCode)
+		apiGroup.POST("/upi/intent", a.UpiIntent)
+
+		// 收款人接口
+		beneficiaries := apiGroup.Group("/beneficiaries")
+		{
+			beneficiaries.POST("", a.CreateBeneficiary)
+			beneficiaries.POST("/list", a.ListBeneficiaries)
+			beneficiaries.POST("/deactivate", a.DeactivateBeneficiary)
+			beneficiaries.POST("/verify", a.VerifyBeneficiary)
+		}
+
+		// 银行目录接口
+		apiGroup.POST("/banks/lookup", a.LookupBank)
+		apiGroup.POST("/banks/search", a.SearchBanks)
+
 		// 查询接口
 		apiGroup.POST("/balance", a.Balance)
 		apiGroup.POST("/query", a.Query)
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// =============================================================================
+// 二维码与UPI意图接口
+// =============================================================================
+
+// QRCode 生成交易或收银台二维码
+// @Summary 生成支付二维码
+// @Description 渲染代收交易的支付链接或收银台地址二维码，支持PNG与SVG，返回data URI
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.QRCodeRequest true "二维码请求参数"
+// @Success 200 {object} protocol.Result{data=protocol.QRCode} "生成成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /qrcode [post]
+func (a *OpenApi) QRCode(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.QRCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Mid = middleware.GetMidFromContext(c)
+	response, code := services.GetQRCodeService().TrxQRCode(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// UpiIntent 生成UPI收款意图
+// @Summary 生成UPI意图
+// @Description 按VPA、金额与参考号生成 upi://pay 意图串，可选同时返回二维码
+// @Tags OpenAPI
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body protocol.UpiIntentRequest true "UPI意图请求参数"
+// @Success 200 {object} protocol.Result{data=protocol.UpiIntent} "生成成功"
+// @Failure 400 {object} protocol.Result "请求参数错误"
+// @Failure 401 {object} protocol.Result "认证失败"
+// @Router /upi/intent [post]
+func (a *OpenApi) UpiIntent(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.UpiIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Mid = middleware.GetMidFromContext(c)
+	response, code := services.GetQRCodeService().UpiIntent(&req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listProofs 凭证审核队列，tid不为空时仅返回该收银团队的凭证
+func listProofs(c *gin.Context, tid string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ProofListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	req.Tid = tid
+	list, total, code := services.GetPaymentProofService().List(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.JSON(http.StatusOK, protocol.NewSuccessPageResult(list, total, &protocol.Pagination{
+		Page: req.Page,
+		Size: req.Size,
+	}))
+}
+
+func proofDetail(c *gin.Context, tid string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ProofDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	response, code := services.GetPaymentProofService().Detail(tid, req.ProofID)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
+
+// proofImage 返回凭证图片文件
+func proofImage(c *gin.Context, tid string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ProofDetailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	path, code := services.GetPaymentProofService().ImagePath(tid, req.ProofID)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	c.File(path)
+}
+
+// reviewProof 审核凭证，approve为true时确认到账，否则驳回
+func reviewProof(c *gin.Context, operator, tid string, approve bool) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.ProofReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	service := services.GetPaymentProofService()
+	var (
+		response *protocol.PaymentProof
+		code     protocol.ErrorCode
+	)
+	if approve {
+		response, code = service.Approve(operator, tid, &req)
+	} else {
+		response, code = service.Reject(operator, tid, &req)
+	}
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, response, lang))
+}
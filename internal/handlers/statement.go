@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"inpayos/internal/log"
+	"inpayos/internal/middleware"
+	"inpayos/internal/protocol"
+	"inpayos/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadStatement godoc
+// @Summary 下载账户对账单
+// @Description 通过限时签名链接下载CSV/PDF对账单，无需登录
+// @Tags Statement
+// @Produce octet-stream
+// @Param account_id query string true "账户ID"
+// @Param start query int true "周期开始时间(毫秒)"
+// @Param end query int true "周期结束时间(毫秒)"
+// @Param timezone query string true "时区"
+// @Param format query string true "文件格式：csv, pdf"
+// @Param expires query int true "链接过期时间(秒)"
+// @Param sign query string true "签名"
+// @Success 200 {file} file "对账单文件"
+// @Router /statements/download [get]
+func DownloadStatement(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.StatementDownloadRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	service := services.GetStatementService()
+	account, period, code := service.ResolveDownload(&req)
+	if code != protocol.Success {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(code, lang))
+		return
+	}
+	contentType := "text/csv; charset=utf-8"
+	if req.Format == protocol.StatementFormatPDF {
+		contentType = "application/pdf"
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+service.FileName(account, period, req.Format)+`"`)
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	if err := service.Write(c.Writer, account, period, req.Format); err != nil {
+		// 响应已开始输出，只能记录日志
+		log.Get().Errorf("Write statement of account %s failed: %v", account.AccountID, err)
+	}
+}
+
+// statementSummary 对账单摘要的公共处理
+func statementSummary(c *gin.Context, userID, userType string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.StatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	statement, code := services.GetStatementService().Summary(userID, userType, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, statement, lang))
+}
+
+// statementLink 获取对账单下载链接的公共处理
+func statementLink(c *gin.Context, userID, userType string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.StatementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	link, code := services.GetStatementService().GetDownloadLink(userID, userType, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, link, lang))
+}
+
+// statementSubscription 获取对账单邮件订阅的公共处理
+func statementSubscription(c *gin.Context, userID, userType string) {
+	lang := middleware.GetLanguage(c)
+	subscription, code := services.GetStatementService().GetSubscription(userID, userType)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, subscription, lang))
+}
+
+// updateStatementSubscription 修改对账单邮件订阅的公共处理
+func updateStatementSubscription(c *gin.Context, userID, userType, operator string) {
+	lang := middleware.GetLanguage(c)
+	var req protocol.StatementSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusOK, protocol.NewErrorResultWithCode(protocol.InvalidParams, lang))
+		return
+	}
+	subscription, code := services.GetStatementService().UpdateSubscription(userID, userType, operator, &req)
+	c.JSON(http.StatusOK, protocol.HandleServiceResult(code, subscription, lang))
+}
@@ -13,6 +13,8 @@ const (
 	UserKey     = "user"
 	UserIDKey   = "user_id"
 	MerchantKey = "merchant"
+
+	OperatorIDKey = "operator_id" // 商户子账号ID
 )
 
 func GetAdminIdFromContext(c *gin.Context) string {
@@ -27,6 +27,8 @@ type JWTClaims struct {
 	UserType string `json:"user_type"` // merchant, admin, cashier
 	Email    string `json:"email"`
 	Role     string `json:"role"` // 角色权限
+
+	OperatorID string `json:"operator_id,omitempty"` // 商户子账号ID，子账号登录时设置
 	jwt.RegisteredClaims
 }
 
@@ -112,8 +114,15 @@ func GetTokenFromRequest(c *gin.Context) string {
 
 // GenerateToken 生成JWT Token
 func GenerateToken(userID string, expiresAt time.Time, jwtSecret string) (string, error) {
+	return GenerateOperatorToken(userID, "", "", expiresAt, jwtSecret)
+}
+
+// GenerateOperatorToken 生成携带商户子账号ID及角色的JWT Token
+func GenerateOperatorToken(userID, operatorID, role string, expiresAt time.Time, jwtSecret string) (string, error) {
 	claims := &JWTClaims{
-		UserID: userID,
+		UserID:     userID,
+		Role:       role,
+		OperatorID: operatorID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
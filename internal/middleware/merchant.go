@@ -5,6 +5,7 @@ import (
 	"inpayos/internal/models"
 	"inpayos/internal/protocol"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,8 +28,19 @@ func GetMerchantFromContext(c *gin.Context) *models.Merchant {
 	return nil
 }
 
-// MerchantJWTAuth JWT认证中间件
-func MerchantJWTAuth() gin.HandlerFunc {
+// GetOperatorIDFromContext 获取登录的商户子账号ID，商户主账号登录时为空
+func GetOperatorIDFromContext(c *gin.Context) string {
+	if _v, exists := c.Get(OperatorIDKey); exists {
+		if v, ok := _v.(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// MerchantJWTAuth JWT认证中间件，商户主账号可访问全部接口；
+// 子账号token仅可访问roles中列出角色的接口，未列出角色时只允许主账号访问
+func MerchantJWTAuth(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := ValidToken(c, []byte(config.Get().Server.Merchant.Jwt.Secret))
 		if token == nil || !token.Valid {
@@ -53,6 +65,21 @@ func MerchantJWTAuth() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		// 子账号登录时校验接口对该角色开放，且子账号仍属于该商户、未停用、角色未变更
+		if claims.OperatorID != "" {
+			if !slices.Contains(roles, claims.Role) {
+				c.JSON(http.StatusForbidden, protocol.NewBusinessErrorResult("Insufficient permissions"))
+				c.Abort()
+				return
+			}
+			user := models.GetMerchantUser(merchant.Mid, claims.OperatorID)
+			if user == nil || !user.IsActive() || user.GetRole() != claims.Role {
+				c.JSON(http.StatusUnauthorized, protocol.NewAuthErrorResult())
+				c.Abort()
+				return
+			}
+			c.Set(OperatorIDKey, user.UserID)
+		}
 		// 临时设置用户信息
 		c.Set(UserIDKey, merchant.Mid)
 		c.Set(UserKey, merchant)
@@ -53,6 +53,7 @@ type Asset struct {
 	MarginBalance          decimal.Decimal `json:"margin_balance"`           // 保证金余额
 	AvailableMarginBalance decimal.Decimal `json:"available_margin_balance"` // 可用保证金余额
 	FrozenMarginBalance    decimal.Decimal `json:"frozen_margin_balance"`    // 冻结保证金余额
+	ReserveBalance         decimal.Decimal `json:"reserve_balance"`          // 结算准备金余额，到期后释放到余额
 	Ccy                    string          `json:"ccy"`                      // 币种
 	UpdatedAt              int64           `json:"updated_at"`               // 更新时间
 }
@@ -75,6 +76,7 @@ func (t *Asset) Protocol() *protocol.Assert {
 		MarginBalance:          t.MarginBalance.String(),
 		AvailableMarginBalance: t.MarginBalance.Sub(t.FrozenMarginBalance).String(),
 		FrozenMarginBalance:    t.FrozenMarginBalance.String(),
+		ReserveBalance:         t.ReserveBalance.String(),
 		Ccy:                    t.Ccy,
 		UpdatedAt:              t.UpdatedAt,
 	}
@@ -183,7 +185,7 @@ func GetAccountByAccountID(accountID string) (*Account, error) {
 
 func GetAccountByUserIDAndCurrency(userID, userType, currency string) (*Account, error) {
 	var account Account
-	err := WriteDB.Where("user_id = ? AND user_type = ? AND currency = ?", userID, userType, currency).First(&account).Error
+	err := WriteDB.Where("user_id = ? AND user_type = ? AND ccy = ?", userID, userType, currency).First(&account).Error
 	if err != nil {
 		return nil, err
 	}
@@ -193,7 +195,7 @@ func GetAccountByUserIDAndCurrency(userID, userType, currency string) (*Account,
 func GetAccountForUpdate(tx *gorm.DB, userID, userType, currency string) (*Account, error) {
 	var account Account
 	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("user_id = ? AND user_type = ? AND currency = ?", userID, userType, currency).
+		Where("user_id = ? AND user_type = ? AND ccy = ?", userID, userType, currency).
 		First(&account).Error
 	if err != nil {
 		return nil, err
@@ -218,3 +220,15 @@ func GetAccountsByIDs(ids []string) ([]*Account, error) {
 	}
 	return accounts, nil
 }
+
+// CreateAccountIfNotExists 创建账户，同一用户同一币种的账户已存在时忽略，用于系统账户自动开户
+func CreateAccountIfNotExists(tx *gorm.DB, account *Account) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(account).Error
+}
+
+// ListAccountsAfterID 按主键顺序分批获取账户，用于全量核对
+func ListAccountsAfterID(db *gorm.DB, afterID uint64, limit int) ([]*Account, error) {
+	var accounts []*Account
+	err := db.Where("id > ?", afterID).Order("id asc").Limit(limit).Find(&accounts).Error
+	return accounts, err
+}
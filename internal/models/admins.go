@@ -21,6 +21,7 @@ type AdminValues struct {
 	Role     *string `json:"role" gorm:"column:role;type:varchar(50);index"`
 	Status   *string `json:"status" gorm:"column:status;type:varchar(32);index;default:'active'"`
 	Password *string `json:"password" gorm:"column:password;type:varchar(128);not null"`
+	G2FA     *string `json:"g2fa" gorm:"column:g2fa;type:varchar(256)"`
 }
 
 func (Admin) TableName() string {
@@ -62,6 +63,13 @@ func (av *AdminValues) GetRole() string {
 	return *av.Role
 }
 
+func (av *AdminValues) GetG2FA() string {
+	if av.G2FA == nil {
+		return ""
+	}
+	return *av.G2FA
+}
+
 func (av *AdminValues) GetStatus() string {
 	if av.Status == nil {
 		return protocol.StatusActive
@@ -85,6 +93,11 @@ func (av *AdminValues) SetRole(value string) *AdminValues {
 	return av
 }
 
+func (av *AdminValues) SetG2FA(value string) *AdminValues {
+	av.G2FA = &value
+	return av
+}
+
 func (av *AdminValues) SetStatus(value string) *AdminValues {
 	av.Status = &value
 	return av
@@ -207,6 +220,9 @@ func (a *Admin) SetValues(values *AdminValues) *Admin {
 	if values.Status != nil {
 		a.AdminValues.SetStatus(*values.Status)
 	}
+	if values.G2FA != nil {
+		a.AdminValues.SetG2FA(*values.G2FA)
+	}
 
 	return a
 }
@@ -0,0 +1,291 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Approval 复核审批表（maker-checker），记录发起人、复核人和审批意见
+type Approval struct {
+	ID         int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ApprovalID string `json:"approval_id" gorm:"column:approval_id;type:varchar(64);uniqueIndex"`
+	Mid        string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	BizType    string `json:"biz_type" gorm:"column:biz_type;type:varchar(32);index"` // 业务类型: payout
+	BizID      string `json:"biz_id" gorm:"column:biz_id;type:varchar(64);index"`     // 业务ID，如交易ID
+	*ApprovalValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type ApprovalValues struct {
+	Status         *string           `json:"status" gorm:"column:status;type:varchar(32);index"` // pending, approved, rejected
+	Ccy            *string           `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount         *decimal.Decimal  `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
+	RiskFlags      []string          `json:"risk_flags" gorm:"column:risk_flags;type:json;serializer:json"`
+	Payload        *protocol.MapData `json:"payload" gorm:"column:payload;type:json;serializer:json"` // 待执行操作的参数
+	RequestedBy    *string           `json:"requested_by" gorm:"column:requested_by;type:varchar(64)"`
+	RequestReason  *string           `json:"request_reason" gorm:"column:request_reason;type:varchar(512)"`
+	ApprovedBy     *string           `json:"approved_by" gorm:"column:approved_by;type:varchar(64)"`
+	DecisionReason *string           `json:"decision_reason" gorm:"column:decision_reason;type:varchar(512)"`
+	DecidedAt      *int64            `json:"decided_at" gorm:"column:decided_at;type:bigint"`
+}
+
+func (Approval) TableName() string {
+	return "t_approvals"
+}
+
+func (v *ApprovalValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *ApprovalValues) GetCcy() string {
+	if v.Ccy == nil {
+		return ""
+	}
+	return *v.Ccy
+}
+
+func (v *ApprovalValues) GetAmount() decimal.Decimal {
+	if v.Amount == nil {
+		return decimal.Zero
+	}
+	return *v.Amount
+}
+
+func (v *ApprovalValues) GetPayload() protocol.MapData {
+	if v.Payload == nil {
+		return protocol.MapData{}
+	}
+	return *v.Payload
+}
+
+func (v *ApprovalValues) GetRequestedBy() string {
+	if v.RequestedBy == nil {
+		return ""
+	}
+	return *v.RequestedBy
+}
+
+func (v *ApprovalValues) GetRequestReason() string {
+	if v.RequestReason == nil {
+		return ""
+	}
+	return *v.RequestReason
+}
+
+func (v *ApprovalValues) GetApprovedBy() string {
+	if v.ApprovedBy == nil {
+		return ""
+	}
+	return *v.ApprovedBy
+}
+
+func (v *ApprovalValues) GetDecisionReason() string {
+	if v.DecisionReason == nil {
+		return ""
+	}
+	return *v.DecisionReason
+}
+
+func (v *ApprovalValues) GetDecidedAt() int64 {
+	if v.DecidedAt == nil {
+		return 0
+	}
+	return *v.DecidedAt
+}
+
+func (v *ApprovalValues) SetStatus(value string) *ApprovalValues {
+	v.Status = &value
+	return v
+}
+
+func (v *ApprovalValues) SetCcy(value string) *ApprovalValues {
+	v.Ccy = &value
+	return v
+}
+
+func (v *ApprovalValues) SetAmount(value decimal.Decimal) *ApprovalValues {
+	v.Amount = &value
+	return v
+}
+
+func (v *ApprovalValues) SetRiskFlags(value []string) *ApprovalValues {
+	v.RiskFlags = value
+	return v
+}
+
+func (v *ApprovalValues) SetPayload(value protocol.MapData) *ApprovalValues {
+	v.Payload = &value
+	return v
+}
+
+func (v *ApprovalValues) SetRequestedBy(value string) *ApprovalValues {
+	v.RequestedBy = &value
+	return v
+}
+
+func (v *ApprovalValues) SetRequestReason(value string) *ApprovalValues {
+	v.RequestReason = &value
+	return v
+}
+
+func (v *ApprovalValues) SetApprovedBy(value string) *ApprovalValues {
+	v.ApprovedBy = &value
+	return v
+}
+
+func (v *ApprovalValues) SetDecisionReason(value string) *ApprovalValues {
+	v.DecisionReason = &value
+	return v
+}
+
+func (v *ApprovalValues) SetDecidedAt(value int64) *ApprovalValues {
+	v.DecidedAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (a *Approval) SetValues(values *ApprovalValues) *Approval {
+	if values == nil {
+		return a
+	}
+	if a.ApprovalValues == nil {
+		a.ApprovalValues = &ApprovalValues{}
+	}
+	if values.Status != nil {
+		a.SetStatus(*values.Status)
+	}
+	if values.Ccy != nil {
+		a.SetCcy(*values.Ccy)
+	}
+	if values.Amount != nil {
+		a.SetAmount(*values.Amount)
+	}
+	if values.RiskFlags != nil {
+		a.SetRiskFlags(values.RiskFlags)
+	}
+	if values.Payload != nil {
+		a.SetPayload(*values.Payload)
+	}
+	if values.RequestedBy != nil {
+		a.SetRequestedBy(*values.RequestedBy)
+	}
+	if values.RequestReason != nil {
+		a.SetRequestReason(*values.RequestReason)
+	}
+	if values.ApprovedBy != nil {
+		a.SetApprovedBy(*values.ApprovedBy)
+	}
+	if values.DecisionReason != nil {
+		a.SetDecisionReason(*values.DecisionReason)
+	}
+	if values.DecidedAt != nil {
+		a.SetDecidedAt(*values.DecidedAt)
+	}
+	return a
+}
+
+func (a *Approval) Protocol() *protocol.Approval {
+	return &protocol.Approval{
+		ApprovalID:     a.ApprovalID,
+		Mid:            a.Mid,
+		BizType:        a.BizType,
+		BizID:          a.BizID,
+		Status:         a.GetStatus(),
+		Ccy:            a.GetCcy(),
+		Amount:         a.GetAmount().String(),
+		RiskFlags:      a.RiskFlags,
+		RequestedBy:    a.GetRequestedBy(),
+		RequestReason:  a.GetRequestReason(),
+		ApprovedBy:     a.GetApprovedBy(),
+		DecisionReason: a.GetDecisionReason(),
+		DecidedAt:      a.GetDecidedAt(),
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
+
+// GetApprovalByID 获取审批记录，mid为空时不限制商户
+func GetApprovalByID(mid, approvalID string) *Approval {
+	var approval Approval
+	db := ReadDB.Where("approval_id = ?", approvalID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&approval).Error; err != nil {
+		return nil
+	}
+	return &approval
+}
+
+// DecideApproval 以待审批状态为条件更新审批结果，避免重复审批
+func DecideApproval(tx *gorm.DB, approval *Approval, values *ApprovalValues) (bool, error) {
+	result := tx.Model(&Approval{}).
+		Where("approval_id = ? AND status = ?", approval.ApprovalID, protocol.StatusPending).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	approval.SetValues(values)
+	return true, nil
+}
+
+// ApprovalQuery 审批查询参数
+type ApprovalQuery struct {
+	Mid            string
+	BizType        string
+	BizID          string
+	Status         string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListApprovalByQuery 分页查询审批记录
+func ListApprovalByQuery(q *ApprovalQuery) ([]*Approval, int64, error) {
+	db := ReadDB.Model(&Approval{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.BizType != "" {
+		db = db.Where("biz_type = ?", q.BizType)
+	}
+	if q.BizID != "" {
+		db = db.Where("biz_id = ?", q.BizID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*Approval
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListApprovalsCreatedBetween 按主键顺序分批获取创建时间在范围内的指定业务类型审批
+func ListApprovalsCreatedBetween(db *gorm.DB, bizTypes []string, start, end, afterID int64, limit int) ([]*Approval, error) {
+	var list []*Approval
+	err := db.Where("biz_type IN ? AND created_at >= ? AND created_at <= ? AND id > ?", bizTypes, start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
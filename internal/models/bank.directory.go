@@ -0,0 +1,196 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bankImportBatchSize 银行目录批量写入的单批条数
+const bankImportBatchSize = 1000
+
+// Bank 银行目录表，按国家维护本地银行编码
+type Bank struct {
+	ID        int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Country   string `json:"country" gorm:"column:country;type:varchar(8);uniqueIndex:idx_bank_country_code"`
+	BankCode  string `json:"bank_code" gorm:"column:bank_code;type:varchar(32);uniqueIndex:idx_bank_country_code"`
+	BankName  string `json:"bank_name" gorm:"column:bank_name;type:varchar(128);index"`
+	ShortName string `json:"short_name" gorm:"column:short_name;type:varchar(64)"`
+	CreatedAt int64  `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (Bank) TableName() string {
+	return "t_banks"
+}
+
+func (b *Bank) Protocol() *protocol.BankInfo {
+	return &protocol.BankInfo{
+		CodeType:  protocol.BankDirectoryBanks,
+		Code:      b.BankCode,
+		Country:   b.Country,
+		BankCode:  b.BankCode,
+		BankName:  b.BankName,
+		ShortName: b.ShortName,
+	}
+}
+
+// BankBranch 银行分行编码表，保存印度IFSC与SWIFT/BIC
+type BankBranch struct {
+	ID        int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	CodeType  string `json:"code_type" gorm:"column:code_type;type:varchar(16);uniqueIndex:idx_bank_branch_code"` // ifsc, swift
+	Code      string `json:"code" gorm:"column:code;type:varchar(16);uniqueIndex:idx_bank_branch_code"`
+	Country   string `json:"country" gorm:"column:country;type:varchar(8);index"`
+	BankCode  string `json:"bank_code" gorm:"column:bank_code;type:varchar(32);index"` // IFSC与SWIFT的前4位
+	BankName  string `json:"bank_name" gorm:"column:bank_name;type:varchar(128)"`
+	Branch    string `json:"branch" gorm:"column:branch;type:varchar(128)"`
+	Address   string `json:"address" gorm:"column:address;type:varchar(512)"`
+	City      string `json:"city" gorm:"column:city;type:varchar(64);index"`
+	State     string `json:"state" gorm:"column:state;type:varchar(64)"`
+	CreatedAt int64  `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64  `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (BankBranch) TableName() string {
+	return "t_bank_branches"
+}
+
+func (b *BankBranch) Protocol() *protocol.BankInfo {
+	return &protocol.BankInfo{
+		CodeType: b.CodeType,
+		Code:     b.Code,
+		Country:  b.Country,
+		BankCode: b.BankCode,
+		BankName: b.BankName,
+		Branch:   b.Branch,
+		Address:  b.Address,
+		City:     b.City,
+		State:    b.State,
+	}
+}
+
+// SaveBanks 批量写入银行目录，已存在的编码更新名称
+func SaveBanks(db *gorm.DB, list []*Bank) (int64, error) {
+	if len(list) == 0 {
+		return 0, nil
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "country"}, {Name: "bank_code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"bank_name", "short_name", "updated_at"}),
+	}).CreateInBatches(&list, bankImportBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+// SaveBankBranches 批量写入分行编码，已存在的编码更新分行信息
+func SaveBankBranches(db *gorm.DB, list []*BankBranch) (int64, error) {
+	if len(list) == 0 {
+		return 0, nil
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code_type"}, {Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"country", "bank_code", "bank_name", "branch", "address", "city", "state", "updated_at"}),
+	}).CreateInBatches(&list, bankImportBatchSize)
+	return result.RowsAffected, result.Error
+}
+
+// GetBankByCode 按国家与银行编码获取银行
+func GetBankByCode(countries []string, bankCode string) *Bank {
+	var bank Bank
+	if err := ReadDB.Where("country IN ? AND bank_code = ?", countries, bankCode).First(&bank).Error; err != nil {
+		return nil
+	}
+	return &bank
+}
+
+// GetBankBranchByCode 按编码类型与编码获取分行
+func GetBankBranchByCode(codeType string, codes ...string) *BankBranch {
+	var branch BankBranch
+	err := ReadDB.Where("code_type = ? AND code IN ?", codeType, codes).
+		Order("code asc").First(&branch).Error
+	if err != nil {
+		return nil
+	}
+	return &branch
+}
+
+// HasBanks 目录中是否已收录这些国家的银行编码
+func HasBanks(countries []string) bool {
+	var found int
+	ReadDB.Model(&Bank{}).Select("1").Where("country IN ?", countries).Limit(1).Scan(&found)
+	return found == 1
+}
+
+// HasBankBranches 目录中是否已收录该类型的分行编码，country为空时不限国家
+func HasBankBranches(codeType, country string) bool {
+	var found int
+	db := ReadDB.Model(&BankBranch{}).Select("1").Where("code_type = ?", codeType)
+	if country != "" {
+		db = db.Where("country = ?", country)
+	}
+	db.Limit(1).Scan(&found)
+	return found == 1
+}
+
+// BankQuery 银行目录查询参数
+type BankQuery struct {
+	Country string
+	Keyword string
+	Page    int
+	Size    int
+}
+
+// ListBankByQuery 分页查询银行目录
+func ListBankByQuery(q *BankQuery) ([]*Bank, int64, error) {
+	db := ReadDB.Model(&Bank{})
+	if q.Country != "" {
+		db = db.Where("country = ?", q.Country)
+	}
+	if q.Keyword != "" {
+		keyword := "%" + q.Keyword + "%"
+		db = db.Where("bank_name ILIKE ? OR short_name ILIKE ? OR bank_code ILIKE ?", keyword, keyword, keyword)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*Bank
+	err := db.Order("bank_name asc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// BankBranchQuery 分行编码查询参数
+type BankBranchQuery struct {
+	CodeType string
+	Country  string
+	BankCode string
+	City     string
+	Keyword  string
+	Page     int
+	Size     int
+}
+
+// ListBankBranchByQuery 分页查询分行编码
+func ListBankBranchByQuery(q *BankBranchQuery) ([]*BankBranch, int64, error) {
+	db := ReadDB.Model(&BankBranch{}).Where("code_type = ?", q.CodeType)
+	if q.Country != "" {
+		db = db.Where("country = ?", q.Country)
+	}
+	if q.BankCode != "" {
+		db = db.Where("bank_code = ?", q.BankCode)
+	}
+	if q.City != "" {
+		db = db.Where("city ILIKE ?", q.City)
+	}
+	if q.Keyword != "" {
+		keyword := "%" + q.Keyword + "%"
+		db = db.Where("bank_name ILIKE ? OR branch ILIKE ? OR code ILIKE ?", keyword, keyword, keyword)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*BankBranch
+	err := db.Order("code asc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
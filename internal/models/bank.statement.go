@@ -0,0 +1,187 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BankStatement 银行流水表，由运营导入收款账户流水，按UTR与支付凭证自动匹配
+type BankStatement struct {
+	ID          int64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	StatementID string          `json:"statement_id" gorm:"column:statement_id;type:varchar(64);uniqueIndex"`
+	BatchID     string          `json:"batch_id" gorm:"column:batch_id;type:varchar(64);index"` // 导入批次
+	AccountNo   string          `json:"account_no" gorm:"column:account_no;type:varchar(64);uniqueIndex:uk_bank_statement_utr,priority:1"`
+	Utr         string          `json:"utr" gorm:"column:utr;type:varchar(64);uniqueIndex:uk_bank_statement_utr,priority:2;index"`
+	Ccy         string          `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount      decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
+	TxnAt       int64           `json:"txn_at" gorm:"column:txn_at;index"` // 银行入账时间（毫秒）
+	Narration   string          `json:"narration" gorm:"column:narration;type:varchar(512)"`
+	ImportedBy  string          `json:"imported_by" gorm:"column:imported_by;type:varchar(64)"`
+	*BankStatementValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type BankStatementValues struct {
+	Status       *string `json:"status" gorm:"column:status;type:varchar(32);index"` // unmatched, matched
+	MatchedTrxID *string `json:"matched_trx_id" gorm:"column:matched_trx_id;type:varchar(64);index"`
+	MatchedAt    *int64  `json:"matched_at" gorm:"column:matched_at"`
+}
+
+func (BankStatement) TableName() string {
+	return "t_bank_statements"
+}
+
+func (v *BankStatementValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *BankStatementValues) GetMatchedTrxID() string {
+	if v.MatchedTrxID == nil {
+		return ""
+	}
+	return *v.MatchedTrxID
+}
+
+func (v *BankStatementValues) GetMatchedAt() int64 {
+	if v.MatchedAt == nil {
+		return 0
+	}
+	return *v.MatchedAt
+}
+
+func (v *BankStatementValues) SetStatus(value string) *BankStatementValues {
+	v.Status = &value
+	return v
+}
+
+func (v *BankStatementValues) SetMatchedTrxID(value string) *BankStatementValues {
+	v.MatchedTrxID = &value
+	return v
+}
+
+func (v *BankStatementValues) SetMatchedAt(value int64) *BankStatementValues {
+	v.MatchedAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (s *BankStatement) SetValues(values *BankStatementValues) *BankStatement {
+	if values == nil {
+		return s
+	}
+	if s.BankStatementValues == nil {
+		s.BankStatementValues = &BankStatementValues{}
+	}
+	if values.Status != nil {
+		s.SetStatus(*values.Status)
+	}
+	if values.MatchedTrxID != nil {
+		s.SetMatchedTrxID(*values.MatchedTrxID)
+	}
+	if values.MatchedAt != nil {
+		s.SetMatchedAt(*values.MatchedAt)
+	}
+	return s
+}
+
+func (s *BankStatement) Protocol() *protocol.BankStatement {
+	return &protocol.BankStatement{
+		StatementID:  s.StatementID,
+		BatchID:      s.BatchID,
+		AccountNo:    s.AccountNo,
+		Utr:          s.Utr,
+		Ccy:          s.Ccy,
+		Amount:       s.Amount.String(),
+		TxnAt:        s.TxnAt,
+		Narration:    s.Narration,
+		Status:       s.GetStatus(),
+		MatchedTrxID: s.GetMatchedTrxID(),
+		MatchedAt:    s.GetMatchedAt(),
+		ImportedBy:   s.ImportedBy,
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
+// SaveBankStatements 批量导入银行流水，同一账户同一UTR重复导入时忽略，返回实际写入行数
+func SaveBankStatements(db *gorm.DB, list []*BankStatement) (int64, error) {
+	if len(list) == 0 {
+		return 0, nil
+	}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_no"}, {Name: "utr"}},
+		DoNothing: true,
+	}).Create(&list)
+	return result.RowsAffected, result.Error
+}
+
+// ListUnmatchedStatementsByUtr 获取该UTR下未匹配的银行流水
+func ListUnmatchedStatementsByUtr(utr string) ([]*BankStatement, error) {
+	var list []*BankStatement
+	err := ReadDB.Where("utr = ? AND status = ?", utr, protocol.StatementStatusUnmatched).
+		Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// UpdateBankStatementValues 以当前状态为条件更新流水，避免同一笔流水匹配多笔交易
+func UpdateBankStatementValues(tx *gorm.DB, statement *BankStatement, fromStatus []string, values *BankStatementValues) (bool, error) {
+	result := tx.Model(&BankStatement{}).
+		Where("statement_id = ? AND status IN ?", statement.StatementID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	statement.SetValues(values)
+	return true, nil
+}
+
+// BankStatementQuery 银行流水查询参数
+type BankStatementQuery struct {
+	BatchID    string
+	AccountNo  string
+	Utr        string
+	Status     string
+	TxnAtStart int64
+	TxnAtEnd   int64
+	Page       int
+	Size       int
+}
+
+// ListBankStatementByQuery 分页查询银行流水
+func ListBankStatementByQuery(q *BankStatementQuery) ([]*BankStatement, int64, error) {
+	db := ReadDB.Model(&BankStatement{})
+	if q.BatchID != "" {
+		db = db.Where("batch_id = ?", q.BatchID)
+	}
+	if q.AccountNo != "" {
+		db = db.Where("account_no = ?", q.AccountNo)
+	}
+	if q.Utr != "" {
+		db = db.Where("utr = ?", q.Utr)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.TxnAtStart > 0 {
+		db = db.Where("txn_at >= ?", q.TxnAtStart)
+	}
+	if q.TxnAtEnd > 0 {
+		db = db.Where("txn_at <= ?", q.TxnAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*BankStatement
+	err := db.Order("txn_at desc, id desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
@@ -1,6 +1,11 @@
 package models
 
-import "github.com/shopspring/decimal"
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
 
 // CashierDeposit 充值记录表
 type CashierDeposit struct {
@@ -8,6 +13,7 @@ type CashierDeposit struct {
 	TrxID     string `json:"trx_id" gorm:"column:trx_id;type:varchar(64);uniqueIndex"`
 	Tid       string `json:"tid" gorm:"column:tid;type:varchar(32);index"`
 	AccountID string `json:"account_id" gorm:"column:account_id;type:varchar(64);index"`
+	ImagePath string `json:"-" gorm:"column:image_path;type:varchar(256)"` // 转账凭证图片存储路径
 	*CashierDepositValues
 	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
@@ -25,6 +31,10 @@ type CashierDepositValues struct {
 	CompletedAt *int64           `json:"completed_at" gorm:"column:completed_at"`
 	ExpiredAt   *int64           `json:"expired_at" gorm:"column:expired_at"`
 	ConfirmedAt *int64           `json:"confirmed_at" gorm:"column:confirmed_at"`
+	TransferRef *string          `json:"transfer_ref" gorm:"column:transfer_ref;type:varchar(128);index"` // 银行转账流水号
+	Remark      *string          `json:"remark" gorm:"column:remark;type:varchar(512)"`
+	Reason      *string          `json:"reason" gorm:"column:reason;type:varchar(512)"` // 驳回原因
+	ReviewedBy  *string          `json:"reviewed_by" gorm:"column:reviewed_by;type:varchar(64)"`
 }
 
 func (CashierDeposit) TableName() string {
@@ -109,6 +119,34 @@ func (cdv *CashierDepositValues) GetConfirmedAt() int64 {
 	return *cdv.ConfirmedAt
 }
 
+func (cdv *CashierDepositValues) GetTransferRef() string {
+	if cdv.TransferRef == nil {
+		return ""
+	}
+	return *cdv.TransferRef
+}
+
+func (cdv *CashierDepositValues) GetRemark() string {
+	if cdv.Remark == nil {
+		return ""
+	}
+	return *cdv.Remark
+}
+
+func (cdv *CashierDepositValues) GetReason() string {
+	if cdv.Reason == nil {
+		return ""
+	}
+	return *cdv.Reason
+}
+
+func (cdv *CashierDepositValues) GetReviewedBy() string {
+	if cdv.ReviewedBy == nil {
+		return ""
+	}
+	return *cdv.ReviewedBy
+}
+
 // Setter methods for CashierDepositValues (支持链式调用)
 func (cdv *CashierDepositValues) SetStatus(status string) *CashierDepositValues {
 	cdv.Status = &status
@@ -165,6 +203,26 @@ func (cdv *CashierDepositValues) SetConfirmedAt(confirmedAt int64) *CashierDepos
 	return cdv
 }
 
+func (cdv *CashierDepositValues) SetTransferRef(transferRef string) *CashierDepositValues {
+	cdv.TransferRef = &transferRef
+	return cdv
+}
+
+func (cdv *CashierDepositValues) SetRemark(remark string) *CashierDepositValues {
+	cdv.Remark = &remark
+	return cdv
+}
+
+func (cdv *CashierDepositValues) SetReason(reason string) *CashierDepositValues {
+	cdv.Reason = &reason
+	return cdv
+}
+
+func (cdv *CashierDepositValues) SetReviewedBy(reviewedBy string) *CashierDepositValues {
+	cdv.ReviewedBy = &reviewedBy
+	return cdv
+}
+
 // SetValues 为CashierDeposit设置CashierDepositValues
 func (cd *CashierDeposit) SetValues(values *CashierDepositValues) *CashierDeposit {
 	if values == nil {
@@ -208,6 +266,128 @@ func (cd *CashierDeposit) SetValues(values *CashierDepositValues) *CashierDeposi
 	if values.ConfirmedAt != nil {
 		cd.CashierDepositValues.SetConfirmedAt(*values.ConfirmedAt)
 	}
+	if values.TransferRef != nil {
+		cd.CashierDepositValues.SetTransferRef(*values.TransferRef)
+	}
+	if values.Remark != nil {
+		cd.CashierDepositValues.SetRemark(*values.Remark)
+	}
+	if values.Reason != nil {
+		cd.CashierDepositValues.SetReason(*values.Reason)
+	}
+	if values.ReviewedBy != nil {
+		cd.CashierDepositValues.SetReviewedBy(*values.ReviewedBy)
+	}
 
 	return cd
 }
+
+func (cd *CashierDeposit) Protocol() *protocol.CashierMarginDeposit {
+	return &protocol.CashierMarginDeposit{
+		TrxID:       cd.TrxID,
+		Tid:         cd.Tid,
+		AccountID:   cd.AccountID,
+		Status:      cd.GetStatus(),
+		Ccy:         cd.GetCcy(),
+		Amount:      cd.GetAmount().String(),
+		TransferRef: cd.GetTransferRef(),
+		HasImage:    cd.ImagePath != "",
+		Remark:      cd.GetRemark(),
+		Reason:      cd.GetReason(),
+		ReviewedBy:  cd.GetReviewedBy(),
+		ConfirmedAt: cd.GetConfirmedAt(),
+		CanceledAt:  cd.GetCanceledAt(),
+		CompletedAt: cd.GetCompletedAt(),
+		CreatedAt:   cd.CreatedAt,
+		UpdatedAt:   cd.UpdatedAt,
+	}
+}
+
+// GetCashierDeposit 获取保证金充值记录，tid为空时不限制车队
+func GetCashierDeposit(tid, trxID string) *CashierDeposit {
+	var deposit CashierDeposit
+	db := ReadDB.Where("trx_id = ?", trxID)
+	if tid != "" {
+		db = db.Where("tid = ?", tid)
+	}
+	if err := db.First(&deposit).Error; err != nil {
+		return nil
+	}
+	return &deposit
+}
+
+// CountActiveCashierDepositByTransferRef 统计使用同一转账流水号的待确认及已确认充值，防止重复入账
+func CountActiveCashierDepositByTransferRef(transferRef string) int64 {
+	var count int64
+	ReadDB.Model(&CashierDeposit{}).
+		Where("transfer_ref = ? AND status IN ?", transferRef, []string{protocol.StatusPending, protocol.StatusSuccess}).
+		Count(&count)
+	return count
+}
+
+// UpdateCashierDepositValues 以当前状态为条件更新保证金充值记录，避免并发重复处理
+func UpdateCashierDepositValues(tx *gorm.DB, deposit *CashierDeposit, fromStatus []string, values *CashierDepositValues) (bool, error) {
+	result := tx.Model(&CashierDeposit{}).
+		Where("trx_id = ? AND status IN ?", deposit.TrxID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	deposit.SetValues(values)
+	return true, nil
+}
+
+// CashierDepositQuery 保证金充值查询参数
+type CashierDepositQuery struct {
+	Tid            string
+	TrxID          string
+	Status         string
+	Ccy            string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListCashierDepositByQuery 分页查询保证金充值记录
+func ListCashierDepositByQuery(q *CashierDepositQuery) ([]*CashierDeposit, int64, error) {
+	db := ReadDB.Model(&CashierDeposit{})
+	if q.Tid != "" {
+		db = db.Where("tid = ?", q.Tid)
+	}
+	if q.TrxID != "" {
+		db = db.Where("trx_id = ?", q.TrxID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*CashierDeposit
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListCashierDepositsConfirmedBetween 按主键顺序分批获取确认时间在范围内的已到账保证金充值
+func ListCashierDepositsConfirmedBetween(db *gorm.DB, start, end int64, afterID uint64, limit int) ([]*CashierDeposit, error) {
+	var list []*CashierDeposit
+	err := db.Where("status = ? AND confirmed_at >= ? AND confirmed_at <= ? AND id > ?", protocol.StatusSuccess, start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
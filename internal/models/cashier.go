@@ -4,6 +4,8 @@ import (
 	"inpayos/internal/protocol"
 	"inpayos/internal/utils"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Cashier 出纳员/收银员表（区分公户和私户）
@@ -314,6 +316,19 @@ func (v *CashierValues) IsActive() bool {
 	return v.GetStatus() == protocol.StatusActive
 }
 
+// GetPayinVpa 获取UPI收款VPA及收款方名称，配置于收款配置的vpa、payee_name字段，未配置名称时使用持卡人姓名
+func (v *CashierValues) GetPayinVpa() (vpa, payeeName string) {
+	cfg := v.GetPayinConfig()
+	if cfg == nil {
+		return "", ""
+	}
+	vpa, payeeName = cfg.Get("vpa"), cfg.Get("payee_name")
+	if payeeName == "" {
+		payeeName = v.GetHolderName()
+	}
+	return vpa, payeeName
+}
+
 // IsExpired 检查是否已过期
 func (v *CashierValues) IsExpired() bool {
 	if v.GetExpireAt() == 0 {
@@ -396,3 +411,29 @@ func (c *Cashier) SetValues(values *CashierValues) *Cashier {
 
 	return c
 }
+
+// GetCashierByCashierID 根据出纳员ID获取出纳员
+func GetCashierByCashierID(cashierID string) *Cashier {
+	var cashier Cashier
+	if err := ReadDB.Where("cashier_id = ?", cashierID).First(&cashier).Error; err != nil {
+		return nil
+	}
+	return &cashier
+}
+
+// ListCashiersWithBankCode 按ID顺序分批获取填写了银行编码的出纳员
+func ListCashiersWithBankCode(afterID uint64, limit int) ([]*Cashier, error) {
+	var list []*Cashier
+	err := ReadDB.Where("id > ? AND bank_code IS NOT NULL AND bank_code <> ''", afterID).
+		Order("id asc").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// UpdateCashierValues 更新出纳员非空字段
+func UpdateCashierValues(db *gorm.DB, cashier *Cashier, values *CashierValues) error {
+	if err := db.Model(&Cashier{}).Where("cashier_id = ?", cashier.CashierID).UpdateColumns(values).Error; err != nil {
+		return err
+	}
+	cashier.SetValues(values)
+	return nil
+}
@@ -405,3 +405,28 @@ func (q *CashierTeamQuery) BuildQuery() *gorm.DB {
 
 	return query
 }
+
+// UpdateCashierTeamStatus 更新车队状态，状态未变化时返回false
+func UpdateCashierTeamStatus(tx *gorm.DB, team *CashierTeam, status string) (bool, error) {
+	result := tx.Model(&CashierTeam{}).
+		Where("tid = ? AND (status IS NULL OR status <> ?)", team.Tid, status).
+		UpdateColumn("status", status)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	team.SetStatus(status)
+	return true, nil
+}
+
+// ListCashierTeamsByStatus 按主键顺序分批获取指定状态的车队
+func ListCashierTeamsByStatus(status string, afterID int64, limit int) ([]*CashierTeam, error) {
+	var list []*CashierTeam
+	err := ReadDB.Where("status = ? AND id > ?", status, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
@@ -75,6 +75,7 @@ func AutoMigrate() error {
 		&Account{},
 		&Admin{},
 		&Merchant{},
+		&MerchantUser{},
 		&MerchantSecret{},
 		&Cashier{},
 		&CashierTeam{},
@@ -85,8 +86,25 @@ func AutoMigrate() error {
 		&MerchantCheckout{},
 		&Deposit{},
 		&Withdraw{},
+		&MerchantWithdraw{},
 		&CashierPayin{},
 		&CashierPayout{},
+		&CashierDeposit{},
+		&Approval{},
+		&Dispute{},
+		&FxRate{},
+		&FxMarkup{},
+		&FxConversion{},
+		&InternalTransfer{},
+		&MerchantReserveRule{},
+		&MerchantReserve{},
+		&PaymentProof{},
+		&BankStatement{},
+		&PaymentLink{},
+		&MerchantBeneficiary{},
+		&BeneficiaryAudit{},
+		&Bank{},
+		&BankBranch{},
 
 		//渠道相关
 		&ChannelAccount{},
@@ -95,6 +113,11 @@ func AutoMigrate() error {
 		// 资金和流水
 		&FundFlow{},
 		&TrxHistory{},
+		&LedgerEntry{},
+		&LedgerLine{},
+		&LedgerCheck{},
+		&LedgerDiscrepancy{},
+		&StatementSubscription{},
 
 		// 配置相关
 		&MerchantConfig{},
@@ -116,11 +139,15 @@ func AutoMigrate() error {
 
 		// 通知和消息
 		&Webhook{},
+		&WebhookAttempt{},
+		&WebhookSecret{},
+		&WebhookEndpoint{},
 		&MessageTemplate{},
 		&FCMToken{},
 
 		// 统计和系统
 		&SummaryStats{},
 		&Task{},
+		&ExportJob{},
 	)
 }
@@ -0,0 +1,361 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Dispute 争议/退单表，按交易ID关联原交易，争议金额在商户账户冻结直至裁决
+type Dispute struct {
+	ID            int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	DisputeID     string `json:"dispute_id" gorm:"column:dispute_id;type:varchar(64);uniqueIndex"`
+	Mid           string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	TrxID         string `json:"trx_id" gorm:"column:trx_id;type:varchar(64);index"`
+	TrxType       string `json:"trx_type" gorm:"column:trx_type;type:varchar(32)"`
+	Type          string `json:"type" gorm:"column:type;type:varchar(32);index"` // chargeback, complaint
+	ReasonCode    string `json:"reason_code" gorm:"column:reason_code;type:varchar(32);index"`
+	ReasonMsg     string `json:"reason_msg" gorm:"column:reason_msg;type:varchar(512)"`
+	ChannelCaseID string `json:"channel_case_id" gorm:"column:channel_case_id;type:varchar(64);index"`
+	Ccy           string `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	CreatedBy     string `json:"created_by" gorm:"column:created_by;type:varchar(64)"`
+	*DisputeValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type DisputeValues struct {
+	Status         *string                     `json:"status" gorm:"column:status;type:varchar(32);index"` // open, evidence_submitted, won, lost
+	Amount         *decimal.Decimal            `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
+	FrozenAmount   *decimal.Decimal            `json:"frozen_amount" gorm:"column:frozen_amount;type:decimal(20,8)"` // 登记时实际冻结金额，余额不足时小于争议金额
+	Shortfall      *decimal.Decimal            `json:"shortfall" gorm:"column:shortfall;type:decimal(20,8)"`         // 未冻结的差额，败诉后为未能扣回的金额
+	Deadline       *int64                      `json:"deadline" gorm:"column:deadline;index"`                        // 商户响应截止时间
+	Evidence       []*protocol.DisputeEvidence `json:"evidence" gorm:"column:evidence;type:json;serializer:json"`
+	MerchantRemark *string                     `json:"merchant_remark" gorm:"column:merchant_remark;type:varchar(1024)"`
+	ResolveRemark  *string                     `json:"resolve_remark" gorm:"column:resolve_remark;type:varchar(512)"`
+	ResolvedBy     *string                     `json:"resolved_by" gorm:"column:resolved_by;type:varchar(64)"`
+	RespondedAt    *int64                      `json:"responded_at" gorm:"column:responded_at"`
+	ResolvedAt     *int64                      `json:"resolved_at" gorm:"column:resolved_at"`
+}
+
+func (Dispute) TableName() string {
+	return "t_disputes"
+}
+
+func (v *DisputeValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *DisputeValues) GetAmount() decimal.Decimal {
+	if v.Amount == nil {
+		return decimal.Zero
+	}
+	return *v.Amount
+}
+
+// GetFrozenAmount 实际冻结金额，未记录时为全额冻结
+func (v *DisputeValues) GetFrozenAmount() decimal.Decimal {
+	if v.FrozenAmount == nil {
+		return v.GetAmount()
+	}
+	return *v.FrozenAmount
+}
+
+func (v *DisputeValues) GetShortfall() decimal.Decimal {
+	if v.Shortfall == nil {
+		return decimal.Zero
+	}
+	return *v.Shortfall
+}
+
+func (v *DisputeValues) GetDeadline() int64 {
+	if v.Deadline == nil {
+		return 0
+	}
+	return *v.Deadline
+}
+
+func (v *DisputeValues) GetMerchantRemark() string {
+	if v.MerchantRemark == nil {
+		return ""
+	}
+	return *v.MerchantRemark
+}
+
+func (v *DisputeValues) GetResolveRemark() string {
+	if v.ResolveRemark == nil {
+		return ""
+	}
+	return *v.ResolveRemark
+}
+
+func (v *DisputeValues) GetResolvedBy() string {
+	if v.ResolvedBy == nil {
+		return ""
+	}
+	return *v.ResolvedBy
+}
+
+func (v *DisputeValues) GetRespondedAt() int64 {
+	if v.RespondedAt == nil {
+		return 0
+	}
+	return *v.RespondedAt
+}
+
+func (v *DisputeValues) GetResolvedAt() int64 {
+	if v.ResolvedAt == nil {
+		return 0
+	}
+	return *v.ResolvedAt
+}
+
+func (v *DisputeValues) SetStatus(value string) *DisputeValues {
+	v.Status = &value
+	return v
+}
+
+func (v *DisputeValues) SetAmount(value decimal.Decimal) *DisputeValues {
+	v.Amount = &value
+	return v
+}
+
+func (v *DisputeValues) SetFrozenAmount(value decimal.Decimal) *DisputeValues {
+	v.FrozenAmount = &value
+	return v
+}
+
+func (v *DisputeValues) SetShortfall(value decimal.Decimal) *DisputeValues {
+	v.Shortfall = &value
+	return v
+}
+
+func (v *DisputeValues) SetDeadline(value int64) *DisputeValues {
+	v.Deadline = &value
+	return v
+}
+
+func (v *DisputeValues) SetEvidence(value []*protocol.DisputeEvidence) *DisputeValues {
+	v.Evidence = value
+	return v
+}
+
+func (v *DisputeValues) SetMerchantRemark(value string) *DisputeValues {
+	v.MerchantRemark = &value
+	return v
+}
+
+func (v *DisputeValues) SetResolveRemark(value string) *DisputeValues {
+	v.ResolveRemark = &value
+	return v
+}
+
+func (v *DisputeValues) SetResolvedBy(value string) *DisputeValues {
+	v.ResolvedBy = &value
+	return v
+}
+
+func (v *DisputeValues) SetRespondedAt(value int64) *DisputeValues {
+	v.RespondedAt = &value
+	return v
+}
+
+func (v *DisputeValues) SetResolvedAt(value int64) *DisputeValues {
+	v.ResolvedAt = &value
+	return v
+}
+
+// IsResolved 是否已裁决
+func (v *DisputeValues) IsResolved() bool {
+	status := v.GetStatus()
+	return status == protocol.DisputeStatusWon || status == protocol.DisputeStatusLost
+}
+
+// SetValues 合并非空字段
+func (d *Dispute) SetValues(values *DisputeValues) *Dispute {
+	if values == nil {
+		return d
+	}
+	if d.DisputeValues == nil {
+		d.DisputeValues = &DisputeValues{}
+	}
+	if values.Status != nil {
+		d.SetStatus(*values.Status)
+	}
+	if values.Amount != nil {
+		d.SetAmount(*values.Amount)
+	}
+	if values.FrozenAmount != nil {
+		d.SetFrozenAmount(*values.FrozenAmount)
+	}
+	if values.Shortfall != nil {
+		d.SetShortfall(*values.Shortfall)
+	}
+	if values.Deadline != nil {
+		d.SetDeadline(*values.Deadline)
+	}
+	if values.Evidence != nil {
+		d.SetEvidence(values.Evidence)
+	}
+	if values.MerchantRemark != nil {
+		d.SetMerchantRemark(*values.MerchantRemark)
+	}
+	if values.ResolveRemark != nil {
+		d.SetResolveRemark(*values.ResolveRemark)
+	}
+	if values.ResolvedBy != nil {
+		d.SetResolvedBy(*values.ResolvedBy)
+	}
+	if values.RespondedAt != nil {
+		d.SetRespondedAt(*values.RespondedAt)
+	}
+	if values.ResolvedAt != nil {
+		d.SetResolvedAt(*values.ResolvedAt)
+	}
+	return d
+}
+
+func (d *Dispute) Protocol() *protocol.Dispute {
+	evidence := d.Evidence
+	if evidence == nil {
+		evidence = []*protocol.DisputeEvidence{}
+	}
+	return &protocol.Dispute{
+		DisputeID:      d.DisputeID,
+		Mid:            d.Mid,
+		TrxID:          d.TrxID,
+		TrxType:        d.TrxType,
+		Type:           d.Type,
+		Status:         d.GetStatus(),
+		ReasonCode:     d.ReasonCode,
+		ReasonMsg:      d.ReasonMsg,
+		ChannelCaseID:  d.ChannelCaseID,
+		Ccy:            d.Ccy,
+		Amount:         d.GetAmount().String(),
+		FrozenAmount:   d.GetFrozenAmount().String(),
+		Shortfall:      d.GetShortfall().String(),
+		Deadline:       d.GetDeadline(),
+		Evidence:       evidence,
+		MerchantRemark: d.GetMerchantRemark(),
+		ResolveRemark:  d.GetResolveRemark(),
+		CreatedBy:      d.CreatedBy,
+		ResolvedBy:     d.GetResolvedBy(),
+		RespondedAt:    d.GetRespondedAt(),
+		ResolvedAt:     d.GetResolvedAt(),
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// GetDisputeByID 获取争议，mid为空时不限制商户
+func GetDisputeByID(mid, disputeID string) *Dispute {
+	var dispute Dispute
+	db := ReadDB.Where("dispute_id = ?", disputeID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&dispute).Error; err != nil {
+		return nil
+	}
+	return &dispute
+}
+
+// CountActiveDisputeByTrxID 统计交易未裁决的争议数
+func CountActiveDisputeByTrxID(trxID string) int64 {
+	var count int64
+	ReadDB.Model(&Dispute{}).
+		Where("trx_id = ? AND status IN ?", trxID, []string{protocol.DisputeStatusOpen, protocol.DisputeStatusEvidenceSubmitted}).
+		Count(&count)
+	return count
+}
+
+// UpdateDisputeValues 以当前状态为条件更新争议，避免并发重复处理
+func UpdateDisputeValues(tx *gorm.DB, dispute *Dispute, fromStatus []string, values *DisputeValues) (bool, error) {
+	result := tx.Model(&Dispute{}).
+		Where("dispute_id = ? AND status IN ?", dispute.DisputeID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	dispute.SetValues(values)
+	return true, nil
+}
+
+// ListExpiredDisputes 获取超过响应期限仍未响应的争议
+func ListExpiredDisputes(now int64, limit int) ([]*Dispute, error) {
+	var list []*Dispute
+	err := ReadDB.Where("status = ? AND deadline > 0 AND deadline < ?", protocol.DisputeStatusOpen, now).
+		Order("deadline asc").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// DisputeQuery 争议查询参数
+type DisputeQuery struct {
+	Mid            string
+	TrxID          string
+	DisputeID      string
+	Type           string
+	Status         string
+	ReasonCode     string
+	DeadlineBefore int64
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListDisputeByQuery 分页查询争议
+func ListDisputeByQuery(q *DisputeQuery) ([]*Dispute, int64, error) {
+	db := ReadDB.Model(&Dispute{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.TrxID != "" {
+		db = db.Where("trx_id = ?", q.TrxID)
+	}
+	if q.DisputeID != "" {
+		db = db.Where("dispute_id = ?", q.DisputeID)
+	}
+	if q.Type != "" {
+		db = db.Where("type = ?", q.Type)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.ReasonCode != "" {
+		db = db.Where("reason_code = ?", q.ReasonCode)
+	}
+	if q.DeadlineBefore > 0 {
+		db = db.Where("deadline > 0 AND deadline <= ?", q.DeadlineBefore)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*Dispute
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListDisputesCreatedBetween 按主键顺序分批获取创建时间在范围内的争议
+func ListDisputesCreatedBetween(db *gorm.DB, start, end, afterID int64, limit int) ([]*Dispute, error) {
+	var list []*Dispute
+	err := db.Where("created_at >= ? AND created_at <= ? AND id > ?", start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
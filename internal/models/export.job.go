@@ -0,0 +1,284 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"gorm.io/gorm"
+)
+
+// ExportJob 异步交易导出任务
+type ExportJob struct {
+	ID       int64     `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	JobID    string    `json:"job_id" gorm:"column:job_id;type:varchar(64);uniqueIndex"`
+	UserID   string    `json:"user_id" gorm:"column:user_id;type:varchar(64);index"`     // 发起人ID：商户ID、出纳团队ID或管理员ID
+	UserType string    `json:"user_type" gorm:"column:user_type;type:varchar(32);index"` // merchant, cashier_team, admin
+	TrxType  string    `json:"trx_type" gorm:"column:trx_type;type:varchar(32)"`
+	Format   string    `json:"format" gorm:"column:format;type:varchar(16)"`
+	Columns  []string  `json:"columns" gorm:"column:columns;type:json;serializer:json"`
+	Timezone string    `json:"timezone" gorm:"column:timezone;type:varchar(64)"`
+	Query    *TrxQuery `json:"query" gorm:"column:query;type:json;serializer:json"`
+	Email    string    `json:"email" gorm:"column:email;type:varchar(255)"` // 完成通知邮箱
+	Lang     string    `json:"lang" gorm:"column:lang;type:varchar(16)"`
+	*ExportJobValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type ExportJobValues struct {
+	Status      *string `json:"status" gorm:"column:status;type:varchar(32);index"`
+	FileName    *string `json:"file_name" gorm:"column:file_name;type:varchar(255)"`
+	FilePath    *string `json:"file_path" gorm:"column:file_path;type:varchar(512)"`
+	FileSize    *int64  `json:"file_size" gorm:"column:file_size"`
+	RowCount    *int64  `json:"row_count" gorm:"column:row_count"`
+	Error       *string `json:"error" gorm:"column:error;type:varchar(512)"`
+	StartedAt   *int64  `json:"started_at" gorm:"column:started_at"`
+	CompletedAt *int64  `json:"completed_at" gorm:"column:completed_at"`
+	ExpiredAt   *int64  `json:"expired_at" gorm:"column:expired_at;index"`
+}
+
+func (ExportJob) TableName() string {
+	return "t_export_jobs"
+}
+
+func (v *ExportJobValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *ExportJobValues) GetFileName() string {
+	if v.FileName == nil {
+		return ""
+	}
+	return *v.FileName
+}
+
+func (v *ExportJobValues) GetFilePath() string {
+	if v.FilePath == nil {
+		return ""
+	}
+	return *v.FilePath
+}
+
+func (v *ExportJobValues) GetFileSize() int64 {
+	if v.FileSize == nil {
+		return 0
+	}
+	return *v.FileSize
+}
+
+func (v *ExportJobValues) GetRowCount() int64 {
+	if v.RowCount == nil {
+		return 0
+	}
+	return *v.RowCount
+}
+
+func (v *ExportJobValues) GetError() string {
+	if v.Error == nil {
+		return ""
+	}
+	return *v.Error
+}
+
+func (v *ExportJobValues) GetStartedAt() int64 {
+	if v.StartedAt == nil {
+		return 0
+	}
+	return *v.StartedAt
+}
+
+func (v *ExportJobValues) GetCompletedAt() int64 {
+	if v.CompletedAt == nil {
+		return 0
+	}
+	return *v.CompletedAt
+}
+
+func (v *ExportJobValues) GetExpiredAt() int64 {
+	if v.ExpiredAt == nil {
+		return 0
+	}
+	return *v.ExpiredAt
+}
+
+func (v *ExportJobValues) SetStatus(value string) *ExportJobValues {
+	v.Status = &value
+	return v
+}
+
+func (v *ExportJobValues) SetFileName(value string) *ExportJobValues {
+	v.FileName = &value
+	return v
+}
+
+func (v *ExportJobValues) SetFilePath(value string) *ExportJobValues {
+	v.FilePath = &value
+	return v
+}
+
+func (v *ExportJobValues) SetFileSize(value int64) *ExportJobValues {
+	v.FileSize = &value
+	return v
+}
+
+func (v *ExportJobValues) SetRowCount(value int64) *ExportJobValues {
+	v.RowCount = &value
+	return v
+}
+
+func (v *ExportJobValues) SetError(value string) *ExportJobValues {
+	v.Error = &value
+	return v
+}
+
+func (v *ExportJobValues) SetStartedAt(value int64) *ExportJobValues {
+	v.StartedAt = &value
+	return v
+}
+
+func (v *ExportJobValues) SetCompletedAt(value int64) *ExportJobValues {
+	v.CompletedAt = &value
+	return v
+}
+
+func (v *ExportJobValues) SetExpiredAt(value int64) *ExportJobValues {
+	v.ExpiredAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (j *ExportJob) SetValues(values *ExportJobValues) *ExportJob {
+	if values == nil {
+		return j
+	}
+	if j.ExportJobValues == nil {
+		j.ExportJobValues = &ExportJobValues{}
+	}
+	if values.Status != nil {
+		j.SetStatus(*values.Status)
+	}
+	if values.FileName != nil {
+		j.SetFileName(*values.FileName)
+	}
+	if values.FilePath != nil {
+		j.SetFilePath(*values.FilePath)
+	}
+	if values.FileSize != nil {
+		j.SetFileSize(*values.FileSize)
+	}
+	if values.RowCount != nil {
+		j.SetRowCount(*values.RowCount)
+	}
+	if values.Error != nil {
+		j.SetError(*values.Error)
+	}
+	if values.StartedAt != nil {
+		j.SetStartedAt(*values.StartedAt)
+	}
+	if values.CompletedAt != nil {
+		j.SetCompletedAt(*values.CompletedAt)
+	}
+	if values.ExpiredAt != nil {
+		j.SetExpiredAt(*values.ExpiredAt)
+	}
+	return j
+}
+
+func (j *ExportJob) Protocol() *protocol.ExportJob {
+	return &protocol.ExportJob{
+		JobID:       j.JobID,
+		UserID:      j.UserID,
+		UserType:    j.UserType,
+		TrxType:     j.TrxType,
+		Format:      j.Format,
+		Columns:     j.Columns,
+		Timezone:    j.Timezone,
+		Status:      j.GetStatus(),
+		FileName:    j.GetFileName(),
+		FileSize:    j.GetFileSize(),
+		RowCount:    j.GetRowCount(),
+		Error:       j.GetError(),
+		CompletedAt: j.GetCompletedAt(),
+		ExpiredAt:   j.GetExpiredAt(),
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+	}
+}
+
+// GetExportJob 获取导出任务，userID为空时不限制发起人
+func GetExportJob(userID, userType, jobID string) *ExportJob {
+	var job ExportJob
+	db := ReadDB.Where("job_id = ?", jobID)
+	if userID != "" {
+		db = db.Where("user_id = ? AND user_type = ?", userID, userType)
+	}
+	if err := db.First(&job).Error; err != nil {
+		return nil
+	}
+	return &job
+}
+
+// UpdateExportJobStatus 以当前状态为条件更新任务，用于抢占任务避免重复执行
+func UpdateExportJobStatus(job *ExportJob, fromStatus string, values *ExportJobValues) (bool, error) {
+	result := WriteDB.Model(&ExportJob{}).
+		Where("job_id = ? AND status = ?", job.JobID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.SetValues(values)
+	return true, nil
+}
+
+// ListExportJobs 分页查询用户的导出任务
+func ListExportJobs(userID, userType, status string, page, size int) ([]*ExportJob, int64, error) {
+	db := ReadDB.Model(&ExportJob{}).Where("user_id = ? AND user_type = ?", userID, userType)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*ExportJob
+	err := db.Order("created_at desc").Offset((page - 1) * size).Limit(size).Find(&list).Error
+	return list, total, err
+}
+
+// ListExportJobsByStatus 按状态获取导出任务
+func ListExportJobsByStatus(status string, createdBefore int64, limit int) ([]*ExportJob, error) {
+	var list []*ExportJob
+	err := ReadDB.Where("status = ? AND created_at <= ?", status, createdBefore).
+		Order("created_at asc").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ListExpiredExportJobs 获取文件已过期的导出任务
+func ListExpiredExportJobs(now int64, limit int) ([]*ExportJob, error) {
+	var list []*ExportJob
+	err := ReadDB.Where("status = ? AND expired_at > 0 AND expired_at <= ?", protocol.StatusSuccess, now).
+		Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ListTransactionBatchByQuery 按ID游标分批读取交易，适用于大批量导出
+func ListTransactionBatchByQuery(query *TrxQuery, afterID int64, limit int) ([]*Transaction, error) {
+	var transactions []*Transaction
+	db := query.BuildQuery(GetTransactionQueryByType(query.TrxType))
+	err := db.Where("id > ?", afterID).Order("id asc").Limit(limit).Find(&transactions).Error
+	return transactions, err
+}
+
+// SaveExportJobValues 保存导出任务字段
+func SaveExportJobValues(db *gorm.DB, job *ExportJob, values *ExportJobValues) error {
+	if err := db.Model(&ExportJob{}).Where("job_id = ?", job.JobID).UpdateColumns(values).Error; err != nil {
+		return err
+	}
+	job.SetValues(values)
+	return nil
+}
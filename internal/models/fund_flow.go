@@ -4,6 +4,7 @@ import (
 	"inpayos/internal/protocol"
 
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 // FundFlow 资金流水表
@@ -27,6 +28,8 @@ type FundFlow struct {
 	OperatorId     string           `gorm:"type:varchar(32)" json:"operator_id"`                               // 操作人ID
 	CreatedAt      int64            `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt      int64            `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"` // 更新时间 (毫秒时间戳)
+
+	EntryID string `gorm:"type:varchar(32);index" json:"entry_id"` // 生成该流水的记账凭证ID
 }
 
 func (t FundFlow) TableName() string {
@@ -63,5 +66,70 @@ func (f *FundFlow) Protocol() *protocol.FundFlow {
 		OperatorId:     f.OperatorId,
 		CreatedAt:      f.CreatedAt,
 		UpdatedAt:      f.UpdatedAt,
+		EntryID:        f.EntryID,
+	}
+}
+
+// ListAccountFundFlows 按账户版本号顺序分批获取版本号小于beforeVersion的账户流水，游标为上一批最后一条的版本号和主键
+func ListAccountFundFlows(db *gorm.DB, accountID string, beforeVersion, afterVersion int64, afterID uint64, limit int) ([]*FundFlow, error) {
+	var flows []*FundFlow
+	err := db.Where("account_id = ? AND account_version < ? AND (account_version > ? OR (account_version = ? AND id > ?))",
+		accountID, beforeVersion, afterVersion, afterVersion, afterID).
+		Order("account_version asc, id asc").
+		Limit(limit).
+		Find(&flows).Error
+	return flows, err
+}
+
+// ListFundFlowsByTrxIDs 获取业务单据关联的用户流水，不含系统账户
+func ListFundFlowsByTrxIDs(db *gorm.DB, trxIDs []string) ([]*FundFlow, error) {
+	var flows []*FundFlow
+	if len(trxIDs) == 0 {
+		return flows, nil
+	}
+	err := db.Where("trx_id IN ? AND user_type <> ?", trxIDs, protocol.System).Find(&flows).Error
+	return flows, err
+}
+
+// GetUserFundFlowByTrx 获取业务单据在用户账户上的指定类型流水
+func GetUserFundFlowByTrx(db *gorm.DB, trxID, trxType, userID, userType string) *FundFlow {
+	var flow FundFlow
+	err := db.Where("trx_id = ? AND trx_type = ? AND user_id = ? AND user_type = ?", trxID, trxType, userID, userType).
+		First(&flow).Error
+	if err != nil {
+		return nil
+	}
+	return &flow
+}
+
+// ListAccountFundFlowsBetween 按账户版本号顺序分批获取时间区间[start, end)内的账户流水，游标为上一批最后一条的版本号和主键
+func ListAccountFundFlowsBetween(db *gorm.DB, accountID string, start, end, afterVersion int64, afterID uint64, limit int) ([]*FundFlow, error) {
+	var flows []*FundFlow
+	err := db.Where("account_id = ? AND created_at >= ? AND created_at < ? AND (account_version > ? OR (account_version = ? AND id > ?))",
+		accountID, start, end, afterVersion, afterVersion, afterID).
+		Order("account_version asc, id asc").
+		Limit(limit).
+		Find(&flows).Error
+	return flows, err
+}
+
+// GetLastAccountFundFlowBefore 获取账户在指定时间之前的最后一条流水
+func GetLastAccountFundFlowBefore(db *gorm.DB, accountID string, before int64) *FundFlow {
+	var flow FundFlow
+	err := db.Where("account_id = ? AND created_at < ?", accountID, before).
+		Order("account_version desc, id desc").
+		First(&flow).Error
+	if err != nil {
+		return nil
 	}
+	return &flow
+}
+
+// CountAccountFundFlowsBetween 统计账户在时间区间[start, end)内的流水条数
+func CountAccountFundFlowsBetween(db *gorm.DB, accountID string, start, end int64) (int64, error) {
+	var count int64
+	err := db.Model(&FundFlow{}).
+		Where("account_id = ? AND created_at >= ? AND created_at < ?", accountID, start, end).
+		Count(&count).Error
+	return count, err
 }
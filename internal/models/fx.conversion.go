@@ -0,0 +1,230 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// FxConversion 商户换汇记录表，报价时锁定汇率与加点，有效期内执行时在两个币种账户间原子划转
+type FxConversion struct {
+	ID           uint64          `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ConversionID string          `json:"conversion_id" gorm:"column:conversion_id;type:varchar(64);uniqueIndex"`
+	Mid          string          `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	FromCcy      string          `json:"from_ccy" gorm:"column:from_ccy;type:varchar(16)"`
+	ToCcy        string          `json:"to_ccy" gorm:"column:to_ccy;type:varchar(16)"`
+	FromAmount   decimal.Decimal `json:"from_amount" gorm:"column:from_amount;type:decimal(36,18)"`
+	ToAmount     decimal.Decimal `json:"to_amount" gorm:"column:to_amount;type:decimal(36,18)"`
+	MidRate      decimal.Decimal `json:"mid_rate" gorm:"column:mid_rate;type:decimal(28,12)"`
+	Markup       decimal.Decimal `json:"markup" gorm:"column:markup;type:decimal(10,6)"`
+	Rate         decimal.Decimal `json:"rate" gorm:"column:rate;type:decimal(28,12)"`
+	MarkupAmount decimal.Decimal `json:"markup_amount" gorm:"column:markup_amount;type:decimal(36,18)"`
+	ExpiredAt    int64           `json:"expired_at" gorm:"column:expired_at"`
+	*FxConversionValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type FxConversionValues struct {
+	Status     *string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	OutFlowNo  *string `json:"out_flow_no" gorm:"column:out_flow_no;type:varchar(64)"`
+	InFlowNo   *string `json:"in_flow_no" gorm:"column:in_flow_no;type:varchar(64)"`
+	ExecutedAt *int64  `json:"executed_at" gorm:"column:executed_at;index"`
+}
+
+func (FxConversion) TableName() string {
+	return "t_fx_conversions"
+}
+
+// Getter methods for FxConversionValues
+func (fcv *FxConversionValues) GetStatus() string {
+	if fcv.Status == nil {
+		return ""
+	}
+	return *fcv.Status
+}
+
+func (fcv *FxConversionValues) GetOutFlowNo() string {
+	if fcv.OutFlowNo == nil {
+		return ""
+	}
+	return *fcv.OutFlowNo
+}
+
+func (fcv *FxConversionValues) GetInFlowNo() string {
+	if fcv.InFlowNo == nil {
+		return ""
+	}
+	return *fcv.InFlowNo
+}
+
+func (fcv *FxConversionValues) GetExecutedAt() int64 {
+	if fcv.ExecutedAt == nil {
+		return 0
+	}
+	return *fcv.ExecutedAt
+}
+
+// Setter methods for FxConversionValues (支持链式调用)
+func (fcv *FxConversionValues) SetStatus(status string) *FxConversionValues {
+	fcv.Status = &status
+	return fcv
+}
+
+func (fcv *FxConversionValues) SetOutFlowNo(outFlowNo string) *FxConversionValues {
+	fcv.OutFlowNo = &outFlowNo
+	return fcv
+}
+
+func (fcv *FxConversionValues) SetInFlowNo(inFlowNo string) *FxConversionValues {
+	fcv.InFlowNo = &inFlowNo
+	return fcv
+}
+
+func (fcv *FxConversionValues) SetExecutedAt(executedAt int64) *FxConversionValues {
+	fcv.ExecutedAt = &executedAt
+	return fcv
+}
+
+// SetValues 为FxConversion设置FxConversionValues
+func (fc *FxConversion) SetValues(values *FxConversionValues) *FxConversion {
+	if values == nil {
+		return fc
+	}
+
+	if fc.FxConversionValues == nil {
+		fc.FxConversionValues = &FxConversionValues{}
+	}
+
+	if values.Status != nil {
+		fc.FxConversionValues.SetStatus(*values.Status)
+	}
+	if values.OutFlowNo != nil {
+		fc.FxConversionValues.SetOutFlowNo(*values.OutFlowNo)
+	}
+	if values.InFlowNo != nil {
+		fc.FxConversionValues.SetInFlowNo(*values.InFlowNo)
+	}
+	if values.ExecutedAt != nil {
+		fc.FxConversionValues.SetExecutedAt(*values.ExecutedAt)
+	}
+
+	return fc
+}
+
+func (fc *FxConversion) Protocol() *protocol.FxConversion {
+	return &protocol.FxConversion{
+		ConversionID: fc.ConversionID,
+		Mid:          fc.Mid,
+		FromCcy:      fc.FromCcy,
+		ToCcy:        fc.ToCcy,
+		FromAmount:   fc.FromAmount.String(),
+		ToAmount:     fc.ToAmount.String(),
+		MidRate:      fc.MidRate.String(),
+		Markup:       fc.Markup.String(),
+		Rate:         fc.Rate.String(),
+		MarkupAmount: fc.MarkupAmount.String(),
+		Status:       fc.GetStatus(),
+		ExpiredAt:    fc.ExpiredAt,
+		OutFlowNo:    fc.GetOutFlowNo(),
+		InFlowNo:     fc.GetInFlowNo(),
+		ExecutedAt:   fc.GetExecutedAt(),
+		CreatedAt:    fc.CreatedAt,
+		UpdatedAt:    fc.UpdatedAt,
+	}
+}
+
+// GetFxConversion 获取换汇记录，mid为空时不限制商户
+func GetFxConversion(mid, conversionID string) *FxConversion {
+	var conversion FxConversion
+	db := ReadDB.Where("conversion_id = ?", conversionID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&conversion).Error; err != nil {
+		return nil
+	}
+	return &conversion
+}
+
+// UpdateFxConversionValues 以当前状态为条件更新换汇记录，避免同一报价重复执行
+func UpdateFxConversionValues(tx *gorm.DB, conversion *FxConversion, fromStatus []string, values *FxConversionValues) (bool, error) {
+	result := tx.Model(&FxConversion{}).
+		Where("conversion_id = ? AND status IN ?", conversion.ConversionID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	conversion.SetValues(values)
+	return true, nil
+}
+
+// SumExecutedFxConversions 统计商户自某时刻起已执行换汇的转出金额，用于每日限额校验
+func SumExecutedFxConversions(mid, fromCcy string, since int64) decimal.Decimal {
+	var total decimal.NullDecimal
+	ReadDB.Model(&FxConversion{}).
+		Where("mid = ? AND from_ccy = ? AND status = ? AND executed_at >= ?", mid, fromCcy, protocol.StatusSuccess, since).
+		Select("SUM(from_amount)").
+		Scan(&total)
+	return total.Decimal
+}
+
+// FxConversionQuery 换汇记录查询参数
+type FxConversionQuery struct {
+	Mid            string
+	ConversionID   string
+	Status         string
+	FromCcy        string
+	ToCcy          string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListFxConversionByQuery 分页查询换汇记录
+func ListFxConversionByQuery(q *FxConversionQuery) ([]*FxConversion, int64, error) {
+	db := ReadDB.Model(&FxConversion{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.ConversionID != "" {
+		db = db.Where("conversion_id = ?", q.ConversionID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.FromCcy != "" {
+		db = db.Where("from_ccy = ?", q.FromCcy)
+	}
+	if q.ToCcy != "" {
+		db = db.Where("to_ccy = ?", q.ToCcy)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*FxConversion
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListFxConversionsExecutedBetween 按主键顺序分批获取执行时间在范围内的换汇
+func ListFxConversionsExecutedBetween(db *gorm.DB, start, end int64, afterID uint64, limit int) ([]*FxConversion, error) {
+	var list []*FxConversion
+	err := db.Where("status = ? AND executed_at >= ? AND executed_at <= ? AND id > ?", protocol.StatusSuccess, start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
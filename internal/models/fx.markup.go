@@ -0,0 +1,95 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FxMarkup 换汇加点表，Mid为*时为全部商户的默认加点，商户单独配置优先
+type FxMarkup struct {
+	ID        int64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Mid       string          `json:"mid" gorm:"column:mid;type:varchar(64);uniqueIndex:uk_fx_markup_pair,priority:1"`
+	FromCcy   string          `json:"from_ccy" gorm:"column:from_ccy;type:varchar(16);uniqueIndex:uk_fx_markup_pair,priority:2"`
+	ToCcy     string          `json:"to_ccy" gorm:"column:to_ccy;type:varchar(16);uniqueIndex:uk_fx_markup_pair,priority:3"`
+	Markup    decimal.Decimal `json:"markup" gorm:"column:markup;type:decimal(10,6)"` // 加点比例
+	Status    string          `json:"status" gorm:"column:status;type:varchar(16)"`
+	UpdatedBy string          `json:"updated_by" gorm:"column:updated_by;type:varchar(64)"`
+	CreatedAt int64           `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64           `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (FxMarkup) TableName() string {
+	return "t_fx_markups"
+}
+
+func (m *FxMarkup) Protocol() *protocol.FxMarkup {
+	return &protocol.FxMarkup{
+		ID:        m.ID,
+		Mid:       m.Mid,
+		FromCcy:   m.FromCcy,
+		ToCcy:     m.ToCcy,
+		Markup:    m.Markup.String(),
+		Status:    m.Status,
+		UpdatedBy: m.UpdatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// SaveFxMarkup 保存换汇加点，同一商户同一币种对重复写入时覆盖原值
+func SaveFxMarkup(db *gorm.DB, markup *FxMarkup) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mid"}, {Name: "from_ccy"}, {Name: "to_ccy"}},
+		DoUpdates: clause.AssignmentColumns([]string{"markup", "status", "updated_by", "updated_at"}),
+	}).Create(markup).Error
+}
+
+// GetFxMarkup 获取商户币种对的换汇加点，商户未单独配置时取默认加点
+func GetFxMarkup(mid, fromCcy, toCcy string) *FxMarkup {
+	var list []*FxMarkup
+	err := ReadDB.Where("mid IN ? AND from_ccy = ? AND to_ccy = ?", []string{mid, GlobalMerchantID}, fromCcy, toCcy).
+		Find(&list).Error
+	if err != nil || len(list) == 0 {
+		return nil
+	}
+	for _, markup := range list {
+		if markup.Mid == mid {
+			return markup
+		}
+	}
+	return list[0]
+}
+
+// FxMarkupQuery 换汇加点查询参数
+type FxMarkupQuery struct {
+	Mid     string
+	FromCcy string
+	ToCcy   string
+	Page    int
+	Size    int
+}
+
+// ListFxMarkupByQuery 分页查询换汇加点
+func ListFxMarkupByQuery(q *FxMarkupQuery) ([]*FxMarkup, int64, error) {
+	db := ReadDB.Model(&FxMarkup{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.FromCcy != "" {
+		db = db.Where("from_ccy = ?", q.FromCcy)
+	}
+	if q.ToCcy != "" {
+		db = db.Where("to_ccy = ?", q.ToCcy)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*FxMarkup
+	err := db.Order("mid asc, from_ccy asc, to_ccy asc").
+		Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FxRate 汇率表，1 BaseCcy = Rate QuoteCcy，按生效时间保留历史以便回溯
+type FxRate struct {
+	ID          int64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	BaseCcy     string          `json:"base_ccy" gorm:"column:base_ccy;type:varchar(16);uniqueIndex:uk_fx_rate_pair_time,priority:1"`
+	QuoteCcy    string          `json:"quote_ccy" gorm:"column:quote_ccy;type:varchar(16);uniqueIndex:uk_fx_rate_pair_time,priority:2"`
+	EffectiveAt int64           `json:"effective_at" gorm:"column:effective_at;uniqueIndex:uk_fx_rate_pair_time,priority:3"` // 生效时间（毫秒）
+	Rate        decimal.Decimal `json:"rate" gorm:"column:rate;type:decimal(28,12)"`
+	Source      string          `json:"source" gorm:"column:source;type:varchar(32);index"` // manual, upload 或行情源名称
+	CreatedBy   string          `json:"created_by" gorm:"column:created_by;type:varchar(64)"`
+	CreatedAt   int64           `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt   int64           `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (FxRate) TableName() string {
+	return "t_fx_rates"
+}
+
+func (r *FxRate) Protocol() *protocol.FxRate {
+	return &protocol.FxRate{
+		BaseCcy:     r.BaseCcy,
+		QuoteCcy:    r.QuoteCcy,
+		Rate:        r.Rate.String(),
+		Source:      r.Source,
+		EffectiveAt: r.EffectiveAt,
+		CreatedBy:   r.CreatedBy,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// SaveFxRates 批量保存汇率，同一币种对同一生效时间重复写入时覆盖原值
+func SaveFxRates(db *gorm.DB, rates []*FxRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_ccy"}, {Name: "quote_ccy"}, {Name: "effective_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "created_by", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+// GetFxRateAt 获取指定时间点生效的汇率，即生效时间不晚于at的最新一条
+func GetFxRateAt(base, quote string, at int64) *FxRate {
+	var rate FxRate
+	err := ReadDB.Where("base_ccy = ? AND quote_ccy = ? AND effective_at <= ?", base, quote, at).
+		Order("effective_at desc").First(&rate).Error
+	if err != nil {
+		return nil
+	}
+	return &rate
+}
+
+// ListFxRatesByPair 获取币种对全部历史汇率，按生效时间升序，用于批量回填时在内存中回溯
+func ListFxRatesByPair(base, quote string) ([]*FxRate, error) {
+	var list []*FxRate
+	err := ReadDB.Where("base_ccy = ? AND quote_ccy = ?", base, quote).
+		Order("effective_at asc").Find(&list).Error
+	return list, err
+}
+
+// FxRateQuery 汇率查询参数
+type FxRateQuery struct {
+	BaseCcy          string
+	QuoteCcy         string
+	Source           string
+	EffectiveAtStart int64
+	EffectiveAtEnd   int64
+	Page             int
+	Size             int
+}
+
+// ListFxRateByQuery 分页查询汇率
+func ListFxRateByQuery(q *FxRateQuery) ([]*FxRate, int64, error) {
+	db := ReadDB.Model(&FxRate{})
+	if q.BaseCcy != "" {
+		db = db.Where("base_ccy = ?", q.BaseCcy)
+	}
+	if q.QuoteCcy != "" {
+		db = db.Where("quote_ccy = ?", q.QuoteCcy)
+	}
+	if q.Source != "" {
+		db = db.Where("source = ?", q.Source)
+	}
+	if q.EffectiveAtStart > 0 {
+		db = db.Where("effective_at >= ?", q.EffectiveAtStart)
+	}
+	if q.EffectiveAtEnd > 0 {
+		db = db.Where("effective_at <= ?", q.EffectiveAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*FxRate
+	err := db.Order("effective_at desc, base_ccy asc, quote_ccy asc").
+		Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// UsdBackfillRow 待回填美元金额的交易行
+type UsdBackfillRow struct {
+	ID                int64            `gorm:"column:id"`
+	TrxID             string           `gorm:"column:trx_id"`
+	Ccy               string           `gorm:"column:ccy"`
+	Amount            *decimal.Decimal `gorm:"column:amount"`
+	UsdAmount         *decimal.Decimal `gorm:"column:usd_amount"`
+	UsdRate           *decimal.Decimal `gorm:"column:usd_rate"`
+	FeeCcy            *string          `gorm:"column:fee_ccy"`
+	FeeAmount         *decimal.Decimal `gorm:"column:fee_amount"`
+	FeeUsdRate        *decimal.Decimal `gorm:"column:fee_usd_rate"`
+	ChannelFeeCcy     *string          `gorm:"column:channel_fee_ccy"`
+	ChannelFeeAmount  *decimal.Decimal `gorm:"column:channel_fee_amount"`
+	ChannelFeeUsdRate *decimal.Decimal `gorm:"column:channel_fee_usd_rate"`
+	CompletedAt       *int64           `gorm:"column:completed_at"`
+	CreatedAt         int64            `gorm:"column:created_at"`
+}
+
+// UsdBackfillQuery 美元金额回填查询参数
+type UsdBackfillQuery struct {
+	TrxType        string
+	AfterID        int64 // 游标，按ID递增扫描
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Limit          int
+}
+
+// ListUsdBackfillRows 查询缺少美元金额或手续费美元汇率的交易，仅支持商户代收付表
+func ListUsdBackfillRows(q *UsdBackfillQuery) ([]*UsdBackfillRow, error) {
+	if q.TrxType != protocol.TrxTypePayin && q.TrxType != protocol.TrxTypePayout {
+		return nil, nil
+	}
+	db := ReadDB.Table(TrxTypeTableMap[q.TrxType]).
+		Where("id > ?", q.AfterID).
+		Where("(usd_amount IS NULL OR usd_rate IS NULL OR (completed_at > 0 AND ((fee_amount IS NOT NULL AND fee_usd_rate IS NULL) OR (channel_fee_amount IS NOT NULL AND channel_fee_usd_rate IS NULL))))")
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var rows []*UsdBackfillRow
+	err := db.Select("id, trx_id, ccy, amount, usd_amount, usd_rate, fee_ccy, fee_amount, fee_usd_rate, channel_fee_ccy, channel_fee_amount, channel_fee_usd_rate, completed_at, created_at").
+		Order("id asc").Limit(q.Limit).Find(&rows).Error
+	return rows, err
+}
+
+// UpdateUsdBackfillRow 回填美元金额，usd_amount为仅创建写入字段，需使用map按列更新
+func UpdateUsdBackfillRow(trxType string, id int64, updates map[string]any) error {
+	table, ok := TrxTypeTableMap[trxType]
+	if !ok || len(updates) == 0 {
+		return nil
+	}
+	return WriteDB.Table(table).Where("id = ?", id).UpdateColumns(updates).Error
+}
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// InternalTransfer 平台内部转账表，同币种账户间划转，转出、转入在同一记账凭证内完成
+type InternalTransfer struct {
+	ID           uint64          `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	TransferID   string          `json:"transfer_id" gorm:"column:transfer_id;type:varchar(64);uniqueIndex"`
+	Source       string          `json:"source" gorm:"column:source;type:varchar(16)"`
+	FromUserID   string          `json:"from_user_id" gorm:"column:from_user_id;type:varchar(64);index"`
+	FromUserType string          `json:"from_user_type" gorm:"column:from_user_type;type:varchar(32)"`
+	ToUserID     string          `json:"to_user_id" gorm:"column:to_user_id;type:varchar(64);index"`
+	ToUserType   string          `json:"to_user_type" gorm:"column:to_user_type;type:varchar(32)"`
+	Ccy          string          `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount       decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(36,18)"`
+	*InternalTransferValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type InternalTransferValues struct {
+	Status      *string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	ApprovalID  *string `json:"approval_id" gorm:"column:approval_id;type:varchar(64)"`
+	RequestedBy *string `json:"requested_by" gorm:"column:requested_by;type:varchar(64)"`
+	ApprovedBy  *string `json:"approved_by" gorm:"column:approved_by;type:varchar(64)"`
+	Remark      *string `json:"remark" gorm:"column:remark;type:varchar(512)"`
+	EntryID     *string `json:"entry_id" gorm:"column:entry_id;type:varchar(64)"`
+	ExecutedAt  *int64  `json:"executed_at" gorm:"column:executed_at;index"`
+}
+
+func (InternalTransfer) TableName() string {
+	return "t_internal_transfers"
+}
+
+// Getter methods for InternalTransferValues
+func (itv *InternalTransferValues) GetStatus() string {
+	if itv.Status == nil {
+		return ""
+	}
+	return *itv.Status
+}
+
+func (itv *InternalTransferValues) GetApprovalID() string {
+	if itv.ApprovalID == nil {
+		return ""
+	}
+	return *itv.ApprovalID
+}
+
+func (itv *InternalTransferValues) GetRequestedBy() string {
+	if itv.RequestedBy == nil {
+		return ""
+	}
+	return *itv.RequestedBy
+}
+
+func (itv *InternalTransferValues) GetApprovedBy() string {
+	if itv.ApprovedBy == nil {
+		return ""
+	}
+	return *itv.ApprovedBy
+}
+
+func (itv *InternalTransferValues) GetRemark() string {
+	if itv.Remark == nil {
+		return ""
+	}
+	return *itv.Remark
+}
+
+func (itv *InternalTransferValues) GetEntryID() string {
+	if itv.EntryID == nil {
+		return ""
+	}
+	return *itv.EntryID
+}
+
+func (itv *InternalTransferValues) GetExecutedAt() int64 {
+	if itv.ExecutedAt == nil {
+		return 0
+	}
+	return *itv.ExecutedAt
+}
+
+// Setter methods for InternalTransferValues (支持链式调用)
+func (itv *InternalTransferValues) SetStatus(status string) *InternalTransferValues {
+	itv.Status = &status
+	return itv
+}
+
+func (itv *InternalTransferValues) SetApprovalID(approvalID string) *InternalTransferValues {
+	itv.ApprovalID = &approvalID
+	return itv
+}
+
+func (itv *InternalTransferValues) SetRequestedBy(requestedBy string) *InternalTransferValues {
+	itv.RequestedBy = &requestedBy
+	return itv
+}
+
+func (itv *InternalTransferValues) SetApprovedBy(approvedBy string) *InternalTransferValues {
+	itv.ApprovedBy = &approvedBy
+	return itv
+}
+
+func (itv *InternalTransferValues) SetRemark(remark string) *InternalTransferValues {
+	itv.Remark = &remark
+	return itv
+}
+
+func (itv *InternalTransferValues) SetEntryID(entryID string) *InternalTransferValues {
+	itv.EntryID = &entryID
+	return itv
+}
+
+func (itv *InternalTransferValues) SetExecutedAt(executedAt int64) *InternalTransferValues {
+	itv.ExecutedAt = &executedAt
+	return itv
+}
+
+// SetValues 为InternalTransfer设置InternalTransferValues
+func (it *InternalTransfer) SetValues(values *InternalTransferValues) *InternalTransfer {
+	if values == nil {
+		return it
+	}
+
+	if it.InternalTransferValues == nil {
+		it.InternalTransferValues = &InternalTransferValues{}
+	}
+
+	if values.Status != nil {
+		it.InternalTransferValues.SetStatus(*values.Status)
+	}
+	if values.ApprovalID != nil {
+		it.InternalTransferValues.SetApprovalID(*values.ApprovalID)
+	}
+	if values.RequestedBy != nil {
+		it.InternalTransferValues.SetRequestedBy(*values.RequestedBy)
+	}
+	if values.ApprovedBy != nil {
+		it.InternalTransferValues.SetApprovedBy(*values.ApprovedBy)
+	}
+	if values.Remark != nil {
+		it.InternalTransferValues.SetRemark(*values.Remark)
+	}
+	if values.EntryID != nil {
+		it.InternalTransferValues.SetEntryID(*values.EntryID)
+	}
+	if values.ExecutedAt != nil {
+		it.InternalTransferValues.SetExecutedAt(*values.ExecutedAt)
+	}
+
+	return it
+}
+
+func (it *InternalTransfer) Protocol() *protocol.InternalTransfer {
+	return &protocol.InternalTransfer{
+		TransferID:   it.TransferID,
+		Source:       it.Source,
+		FromUserID:   it.FromUserID,
+		FromUserType: it.FromUserType,
+		ToUserID:     it.ToUserID,
+		ToUserType:   it.ToUserType,
+		Ccy:          it.Ccy,
+		Amount:       it.Amount.String(),
+		Status:       it.GetStatus(),
+		ApprovalID:   it.GetApprovalID(),
+		RequestedBy:  it.GetRequestedBy(),
+		ApprovedBy:   it.GetApprovedBy(),
+		Remark:       it.GetRemark(),
+		EntryID:      it.GetEntryID(),
+		ExecutedAt:   it.GetExecutedAt(),
+		CreatedAt:    it.CreatedAt,
+		UpdatedAt:    it.UpdatedAt,
+	}
+}
+
+// GetInternalTransfer 获取内部转账，userID不为空时仅返回该用户转出或转入的记录
+func GetInternalTransfer(userID, userType, transferID string) *InternalTransfer {
+	var transfer InternalTransfer
+	db := ReadDB.Where("transfer_id = ?", transferID)
+	if userID != "" {
+		db = db.Where("(from_user_id = ? AND from_user_type = ?) OR (to_user_id = ? AND to_user_type = ?)", userID, userType, userID, userType)
+	}
+	if err := db.First(&transfer).Error; err != nil {
+		return nil
+	}
+	return &transfer
+}
+
+// UpdateInternalTransferValues 以当前状态为条件更新内部转账，避免重复执行
+func UpdateInternalTransferValues(tx *gorm.DB, transfer *InternalTransfer, fromStatus []string, values *InternalTransferValues) (bool, error) {
+	result := tx.Model(&InternalTransfer{}).
+		Where("transfer_id = ? AND status IN ?", transfer.TransferID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	transfer.SetValues(values)
+	return true, nil
+}
+
+// InternalTransferQuery 内部转账查询参数
+type InternalTransferQuery struct {
+	UserID         string
+	UserType       string
+	TransferID     string
+	Source         string
+	Status         string
+	Ccy            string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListInternalTransferByQuery 分页查询内部转账，按用户查询时转出、转入均包含
+func ListInternalTransferByQuery(q *InternalTransferQuery) ([]*InternalTransfer, int64, error) {
+	db := ReadDB.Model(&InternalTransfer{})
+	if q.UserID != "" {
+		if q.UserType != "" {
+			db = db.Where("(from_user_id = ? AND from_user_type = ?) OR (to_user_id = ? AND to_user_type = ?)", q.UserID, q.UserType, q.UserID, q.UserType)
+		} else {
+			db = db.Where("from_user_id = ? OR to_user_id = ?", q.UserID, q.UserID)
+		}
+	}
+	if q.TransferID != "" {
+		db = db.Where("transfer_id = ?", q.TransferID)
+	}
+	if q.Source != "" {
+		db = db.Where("source = ?", q.Source)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*InternalTransfer
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListInternalTransfersExecutedBetween 按主键顺序分批获取执行时间在范围内的内部转账
+func ListInternalTransfersExecutedBetween(db *gorm.DB, start, end int64, afterID uint64, limit int) ([]*InternalTransfer, error) {
+	var list []*InternalTransfer
+	err := db.Where("status = ? AND executed_at >= ? AND executed_at <= ? AND id > ?", protocol.StatusSuccess, start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
@@ -0,0 +1,294 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"gorm.io/gorm"
+)
+
+// LedgerCheck 账务核对记录，按流水重放账户资产并与业务单据交叉核对
+type LedgerCheck struct {
+	ID             int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	CheckID        string `json:"check_id" gorm:"column:check_id;type:varchar(64);uniqueIndex"`
+	TriggeredBy    string `json:"triggered_by" gorm:"column:triggered_by;type:varchar(64)"` // 发起人，定时任务为system
+	CreatedAtStart int64  `json:"created_at_start" gorm:"column:created_at_start"`          // 交叉核对的业务单据时间范围
+	CreatedAtEnd   int64  `json:"created_at_end" gorm:"column:created_at_end"`
+	*LedgerCheckValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type LedgerCheckValues struct {
+	Status           *string `json:"status" gorm:"column:status;type:varchar(32);index"` // processing, completed, failed
+	AccountCount     *int64  `json:"account_count" gorm:"column:account_count"`
+	FlowCount        *int64  `json:"flow_count" gorm:"column:flow_count"`
+	TrxCount         *int64  `json:"trx_count" gorm:"column:trx_count"`
+	DiscrepancyCount *int64  `json:"discrepancy_count" gorm:"column:discrepancy_count"`
+	Error            *string `json:"error" gorm:"column:error;type:varchar(512)"`
+	StartedAt        *int64  `json:"started_at" gorm:"column:started_at"`
+	CompletedAt      *int64  `json:"completed_at" gorm:"column:completed_at"`
+}
+
+func (LedgerCheck) TableName() string {
+	return "t_ledger_checks"
+}
+
+func (v *LedgerCheckValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *LedgerCheckValues) GetAccountCount() int64 {
+	if v.AccountCount == nil {
+		return 0
+	}
+	return *v.AccountCount
+}
+
+func (v *LedgerCheckValues) GetFlowCount() int64 {
+	if v.FlowCount == nil {
+		return 0
+	}
+	return *v.FlowCount
+}
+
+func (v *LedgerCheckValues) GetTrxCount() int64 {
+	if v.TrxCount == nil {
+		return 0
+	}
+	return *v.TrxCount
+}
+
+func (v *LedgerCheckValues) GetDiscrepancyCount() int64 {
+	if v.DiscrepancyCount == nil {
+		return 0
+	}
+	return *v.DiscrepancyCount
+}
+
+func (v *LedgerCheckValues) GetError() string {
+	if v.Error == nil {
+		return ""
+	}
+	return *v.Error
+}
+
+func (v *LedgerCheckValues) GetStartedAt() int64 {
+	if v.StartedAt == nil {
+		return 0
+	}
+	return *v.StartedAt
+}
+
+func (v *LedgerCheckValues) GetCompletedAt() int64 {
+	if v.CompletedAt == nil {
+		return 0
+	}
+	return *v.CompletedAt
+}
+
+func (v *LedgerCheckValues) SetStatus(value string) *LedgerCheckValues {
+	v.Status = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetAccountCount(value int64) *LedgerCheckValues {
+	v.AccountCount = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetFlowCount(value int64) *LedgerCheckValues {
+	v.FlowCount = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetTrxCount(value int64) *LedgerCheckValues {
+	v.TrxCount = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetDiscrepancyCount(value int64) *LedgerCheckValues {
+	v.DiscrepancyCount = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetError(value string) *LedgerCheckValues {
+	v.Error = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetStartedAt(value int64) *LedgerCheckValues {
+	v.StartedAt = &value
+	return v
+}
+
+func (v *LedgerCheckValues) SetCompletedAt(value int64) *LedgerCheckValues {
+	v.CompletedAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (c *LedgerCheck) SetValues(values *LedgerCheckValues) *LedgerCheck {
+	if values == nil {
+		return c
+	}
+	if c.LedgerCheckValues == nil {
+		c.LedgerCheckValues = &LedgerCheckValues{}
+	}
+	if values.Status != nil {
+		c.Status = values.Status
+	}
+	if values.AccountCount != nil {
+		c.AccountCount = values.AccountCount
+	}
+	if values.FlowCount != nil {
+		c.FlowCount = values.FlowCount
+	}
+	if values.TrxCount != nil {
+		c.TrxCount = values.TrxCount
+	}
+	if values.DiscrepancyCount != nil {
+		c.DiscrepancyCount = values.DiscrepancyCount
+	}
+	if values.Error != nil {
+		c.Error = values.Error
+	}
+	if values.StartedAt != nil {
+		c.StartedAt = values.StartedAt
+	}
+	if values.CompletedAt != nil {
+		c.CompletedAt = values.CompletedAt
+	}
+	return c
+}
+
+func (c *LedgerCheck) Protocol() *protocol.LedgerCheck {
+	return &protocol.LedgerCheck{
+		CheckID:          c.CheckID,
+		Status:           c.GetStatus(),
+		TriggeredBy:      c.TriggeredBy,
+		CreatedAtStart:   c.CreatedAtStart,
+		CreatedAtEnd:     c.CreatedAtEnd,
+		AccountCount:     c.GetAccountCount(),
+		FlowCount:        c.GetFlowCount(),
+		TrxCount:         c.GetTrxCount(),
+		DiscrepancyCount: c.GetDiscrepancyCount(),
+		Error:            c.GetError(),
+		StartedAt:        c.GetStartedAt(),
+		CompletedAt:      c.GetCompletedAt(),
+		CreatedAt:        c.CreatedAt,
+	}
+}
+
+// LedgerDiscrepancy 账务核对差异明细
+type LedgerDiscrepancy struct {
+	ID        int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	CheckID   string `json:"check_id" gorm:"column:check_id;type:varchar(64);index"`
+	Type      string `json:"type" gorm:"column:type;type:varchar(32);index"`
+	AccountID string `json:"account_id" gorm:"column:account_id;type:varchar(32);index"`
+	UserID    string `json:"user_id" gorm:"column:user_id;type:varchar(32)"`
+	UserType  string `json:"user_type" gorm:"column:user_type;type:varchar(16)"`
+	Ccy       string `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	FlowNo    string `json:"flow_no" gorm:"column:flow_no;type:varchar(64)"`
+	TrxID     string `json:"trx_id" gorm:"column:trx_id;type:varchar(64)"`
+	TrxType   string `json:"trx_type" gorm:"column:trx_type;type:varchar(20)"`
+	Expected  string `json:"expected" gorm:"column:expected;type:varchar(512)"`
+	Actual    string `json:"actual" gorm:"column:actual;type:varchar(512)"`
+	CreatedAt int64  `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (LedgerDiscrepancy) TableName() string {
+	return "t_ledger_discrepancies"
+}
+
+func (d *LedgerDiscrepancy) Protocol() *protocol.LedgerDiscrepancy {
+	return &protocol.LedgerDiscrepancy{
+		CheckID:   d.CheckID,
+		Type:      d.Type,
+		AccountID: d.AccountID,
+		UserID:    d.UserID,
+		UserType:  d.UserType,
+		Ccy:       d.Ccy,
+		FlowNo:    d.FlowNo,
+		TrxID:     d.TrxID,
+		TrxType:   d.TrxType,
+		Expected:  d.Expected,
+		Actual:    d.Actual,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+// GetLedgerCheck 按核对ID获取核对记录
+func GetLedgerCheck(checkID string) *LedgerCheck {
+	var check LedgerCheck
+	if err := ReadDB.Where("check_id = ?", checkID).First(&check).Error; err != nil {
+		return nil
+	}
+	return &check
+}
+
+// UpdateLedgerCheckValues 更新核对记录
+func UpdateLedgerCheckValues(db *gorm.DB, check *LedgerCheck, values *LedgerCheckValues) error {
+	if err := db.Model(&LedgerCheck{}).Where("check_id = ?", check.CheckID).Updates(values).Error; err != nil {
+		return err
+	}
+	check.SetValues(values)
+	return nil
+}
+
+type LedgerCheckQuery struct {
+	Status         string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListLedgerCheckByQuery 分页查询核对记录
+func ListLedgerCheckByQuery(q *LedgerCheckQuery) ([]*LedgerCheck, int64, error) {
+	db := ReadDB.Model(&LedgerCheck{})
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*LedgerCheck
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+type LedgerDiscrepancyQuery struct {
+	CheckID   string
+	Type      string
+	AccountID string
+	Page      int
+	Size      int
+}
+
+// ListLedgerDiscrepancyByQuery 分页查询核对差异
+func ListLedgerDiscrepancyByQuery(q *LedgerDiscrepancyQuery) ([]*LedgerDiscrepancy, int64, error) {
+	db := ReadDB.Model(&LedgerDiscrepancy{}).Where("check_id = ?", q.CheckID)
+	if q.Type != "" {
+		db = db.Where("type = ?", q.Type)
+	}
+	if q.AccountID != "" {
+		db = db.Where("account_id = ?", q.AccountID)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*LedgerDiscrepancy
+	err := db.Order("id asc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
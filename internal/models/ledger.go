@@ -0,0 +1,82 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// LedgerEntry 复式记账凭证，凭证下借贷分录金额相等
+// 凭证是资金的唯一来源，账户余额与资金流水均为凭证的投影
+type LedgerEntry struct {
+	ID         uint64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EntryID    string           `json:"entry_id" gorm:"column:entry_id;type:varchar(32);uniqueIndex"`
+	TrxID      string           `json:"trx_id" gorm:"column:trx_id;type:varchar(64);index"` // 关联业务ID
+	TrxType    string           `json:"trx_type" gorm:"column:trx_type;type:varchar(20)"`   // 业务类型
+	Ccy        string           `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount     *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"` // 借方合计，等于贷方合计
+	Remark     string           `json:"remark" gorm:"column:remark;type:varchar(255)"`
+	OperatorID string           `json:"operator_id" gorm:"column:operator_id;type:varchar(32)"`
+	CreatedAt  int64            `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+}
+
+func (LedgerEntry) TableName() string {
+	return "t_ledger_entries"
+}
+
+// LedgerLine 凭证分录，一条分录只影响一个账户的一个资金属性
+type LedgerLine struct {
+	ID        uint64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EntryID   string           `json:"entry_id" gorm:"column:entry_id;type:varchar(32);index"`
+	Seq       int              `json:"seq" gorm:"column:seq"` // 凭证内序号，从1开始
+	AccountID string           `json:"account_id" gorm:"column:account_id;type:varchar(32);index"`
+	UserID    string           `json:"user_id" gorm:"column:user_id;type:varchar(32)"`
+	UserType  string           `json:"user_type" gorm:"column:user_type;type:varchar(16)"`
+	Ccy       string           `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Bucket    string           `json:"bucket" gorm:"column:bucket;type:varchar(16)"` // balance, frozen, margin, reserve
+	Side      string           `json:"side" gorm:"column:side;type:varchar(8)"`      // debit, credit
+	Amount    *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
+	CreatedAt int64            `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+}
+
+func (LedgerLine) TableName() string {
+	return "t_ledger_lines"
+}
+
+// GetAmount 获取分录金额
+func (l *LedgerLine) GetAmount() decimal.Decimal {
+	if l.Amount == nil {
+		return decimal.Zero
+	}
+	return *l.Amount
+}
+
+// ListLedgerEntriesByTrxID 获取业务关联的全部凭证，按过账顺序
+func ListLedgerEntriesByTrxID(db *gorm.DB, trxID string) ([]*LedgerEntry, error) {
+	var list []*LedgerEntry
+	err := db.Where("trx_id = ?", trxID).Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// ListLedgerLinesByEntryIDs 获取凭证下的全部分录
+func ListLedgerLinesByEntryIDs(db *gorm.DB, entryIDs []string) ([]*LedgerLine, error) {
+	var list []*LedgerLine
+	if len(entryIDs) == 0 {
+		return list, nil
+	}
+	err := db.Where("entry_id IN ?", entryIDs).Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// ListUnbalancedLedgerEntryIDs 获取时间范围内借贷不平的凭证ID
+func ListUnbalancedLedgerEntryIDs(db *gorm.DB, start, end int64) ([]string, error) {
+	var ids []string
+	err := db.Model(&LedgerLine{}).
+		Select("entry_id").
+		Where("created_at >= ? AND created_at <= ?", start, end).
+		Group("entry_id").
+		Having("SUM(CASE WHEN side = ? THEN amount ELSE -amount END) <> 0", protocol.LedgerSideDebit).
+		Pluck("entry_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,375 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// MerchantBeneficiary 商户收款人表，保存已校验的代付收款账户，账户信息创建后不可修改
+type MerchantBeneficiary struct {
+	ID            int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	BeneficiaryID string `json:"beneficiary_id" gorm:"column:beneficiary_id;type:varchar(64);uniqueIndex"`
+	Mid           string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	Country       string `json:"country" gorm:"column:country;type:varchar(8)"`
+	Ccy           string `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	AccountType   string `json:"account_type" gorm:"column:account_type;type:varchar(32)"`
+	AccountNo     string `json:"account_no" gorm:"column:account_no;type:varchar(64)"`
+	AccountName   string `json:"account_name" gorm:"column:account_name;type:varchar(128)"`
+	BankCode      string `json:"bank_code" gorm:"column:bank_code;type:varchar(32)"`
+	BankName      string `json:"bank_name" gorm:"column:bank_name;type:varchar(128)"`
+	Fingerprint   string `json:"fingerprint" gorm:"column:fingerprint;type:varchar(64);index"` // 账户指纹，用于识别重复账户
+	*MerchantBeneficiaryValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type MerchantBeneficiaryValues struct {
+	Alias         *string `json:"alias" gorm:"column:alias;type:varchar(64)"`
+	Email         *string `json:"email" gorm:"column:email;type:varchar(128)"`
+	Phone         *string `json:"phone" gorm:"column:phone;type:varchar(32)"`
+	Status        *string `json:"status" gorm:"column:status;type:varchar(32);index"`               // active, inactive
+	VerifyStatus  *string `json:"verify_status" gorm:"column:verify_status;type:varchar(32);index"` // unverified, pending, verified, failed
+	VerifiedName  *string `json:"verified_name" gorm:"column:verified_name;type:varchar(128)"`      // 渠道验证返回的户名
+	VerifyChannel *string `json:"verify_channel" gorm:"column:verify_channel;type:varchar(64)"`     // 验证渠道账户
+	VerifyTrxID   *string `json:"verify_trx_id" gorm:"column:verify_trx_id;type:varchar(128)"`      // 渠道验证流水号
+	VerifyMsg     *string `json:"verify_msg" gorm:"column:verify_msg;type:varchar(256)"`
+	VerifiedAt    *int64  `json:"verified_at" gorm:"column:verified_at"`
+	LastUsedAt    *int64  `json:"last_used_at" gorm:"column:last_used_at"`
+	DeactivatedAt *int64  `json:"deactivated_at" gorm:"column:deactivated_at"`
+}
+
+func (MerchantBeneficiary) TableName() string {
+	return "t_merchant_beneficiaries"
+}
+
+func (v *MerchantBeneficiaryValues) GetAlias() string {
+	if v.Alias == nil {
+		return ""
+	}
+	return *v.Alias
+}
+
+func (v *MerchantBeneficiaryValues) GetEmail() string {
+	if v.Email == nil {
+		return ""
+	}
+	return *v.Email
+}
+
+func (v *MerchantBeneficiaryValues) GetPhone() string {
+	if v.Phone == nil {
+		return ""
+	}
+	return *v.Phone
+}
+
+func (v *MerchantBeneficiaryValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifyStatus() string {
+	if v.VerifyStatus == nil {
+		return ""
+	}
+	return *v.VerifyStatus
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifiedName() string {
+	if v.VerifiedName == nil {
+		return ""
+	}
+	return *v.VerifiedName
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifyChannel() string {
+	if v.VerifyChannel == nil {
+		return ""
+	}
+	return *v.VerifyChannel
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifyTrxID() string {
+	if v.VerifyTrxID == nil {
+		return ""
+	}
+	return *v.VerifyTrxID
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifyMsg() string {
+	if v.VerifyMsg == nil {
+		return ""
+	}
+	return *v.VerifyMsg
+}
+
+func (v *MerchantBeneficiaryValues) GetVerifiedAt() int64 {
+	if v.VerifiedAt == nil {
+		return 0
+	}
+	return *v.VerifiedAt
+}
+
+func (v *MerchantBeneficiaryValues) GetLastUsedAt() int64 {
+	if v.LastUsedAt == nil {
+		return 0
+	}
+	return *v.LastUsedAt
+}
+
+func (v *MerchantBeneficiaryValues) GetDeactivatedAt() int64 {
+	if v.DeactivatedAt == nil {
+		return 0
+	}
+	return *v.DeactivatedAt
+}
+
+func (v *MerchantBeneficiaryValues) SetAlias(value string) *MerchantBeneficiaryValues {
+	v.Alias = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetEmail(value string) *MerchantBeneficiaryValues {
+	v.Email = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetPhone(value string) *MerchantBeneficiaryValues {
+	v.Phone = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetStatus(value string) *MerchantBeneficiaryValues {
+	v.Status = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifyStatus(value string) *MerchantBeneficiaryValues {
+	v.VerifyStatus = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifiedName(value string) *MerchantBeneficiaryValues {
+	v.VerifiedName = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifyChannel(value string) *MerchantBeneficiaryValues {
+	v.VerifyChannel = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifyTrxID(value string) *MerchantBeneficiaryValues {
+	v.VerifyTrxID = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifyMsg(value string) *MerchantBeneficiaryValues {
+	v.VerifyMsg = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetVerifiedAt(value int64) *MerchantBeneficiaryValues {
+	v.VerifiedAt = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetLastUsedAt(value int64) *MerchantBeneficiaryValues {
+	v.LastUsedAt = &value
+	return v
+}
+
+func (v *MerchantBeneficiaryValues) SetDeactivatedAt(value int64) *MerchantBeneficiaryValues {
+	v.DeactivatedAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (b *MerchantBeneficiary) SetValues(values *MerchantBeneficiaryValues) *MerchantBeneficiary {
+	if values == nil {
+		return b
+	}
+	if b.MerchantBeneficiaryValues == nil {
+		b.MerchantBeneficiaryValues = &MerchantBeneficiaryValues{}
+	}
+	if values.Alias != nil {
+		b.SetAlias(*values.Alias)
+	}
+	if values.Email != nil {
+		b.SetEmail(*values.Email)
+	}
+	if values.Phone != nil {
+		b.SetPhone(*values.Phone)
+	}
+	if values.Status != nil {
+		b.SetStatus(*values.Status)
+	}
+	if values.VerifyStatus != nil {
+		b.SetVerifyStatus(*values.VerifyStatus)
+	}
+	if values.VerifiedName != nil {
+		b.SetVerifiedName(*values.VerifiedName)
+	}
+	if values.VerifyChannel != nil {
+		b.SetVerifyChannel(*values.VerifyChannel)
+	}
+	if values.VerifyTrxID != nil {
+		b.SetVerifyTrxID(*values.VerifyTrxID)
+	}
+	if values.VerifyMsg != nil {
+		b.SetVerifyMsg(*values.VerifyMsg)
+	}
+	if values.VerifiedAt != nil {
+		b.SetVerifiedAt(*values.VerifiedAt)
+	}
+	if values.LastUsedAt != nil {
+		b.SetLastUsedAt(*values.LastUsedAt)
+	}
+	if values.DeactivatedAt != nil {
+		b.SetDeactivatedAt(*values.DeactivatedAt)
+	}
+	return b
+}
+
+func (b *MerchantBeneficiary) Protocol() *protocol.Beneficiary {
+	return &protocol.Beneficiary{
+		BeneficiaryID: b.BeneficiaryID,
+		Mid:           b.Mid,
+		Alias:         b.GetAlias(),
+		Country:       b.Country,
+		Ccy:           b.Ccy,
+		AccountType:   b.AccountType,
+		AccountNo:     utils.MaskAccountNo(b.AccountNo),
+		AccountName:   b.AccountName,
+		BankCode:      b.BankCode,
+		BankName:      b.BankName,
+		Email:         b.GetEmail(),
+		Phone:         b.GetPhone(),
+		Status:        b.GetStatus(),
+		VerifyStatus:  b.GetVerifyStatus(),
+		VerifiedName:  b.GetVerifiedName(),
+		VerifyMsg:     b.GetVerifyMsg(),
+		VerifiedAt:    b.GetVerifiedAt(),
+		LastUsedAt:    b.GetLastUsedAt(),
+		DeactivatedAt: b.GetDeactivatedAt(),
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+}
+
+// GetMerchantBeneficiaryByID 获取商户收款人
+func GetMerchantBeneficiaryByID(mid, beneficiaryID string) *MerchantBeneficiary {
+	var beneficiary MerchantBeneficiary
+	err := ReadDB.Where("mid = ? AND beneficiary_id = ?", mid, beneficiaryID).First(&beneficiary).Error
+	if err != nil {
+		return nil
+	}
+	return &beneficiary
+}
+
+// GetActiveBeneficiaryByFingerprint 按账户指纹获取商户可用的收款人
+func GetActiveBeneficiaryByFingerprint(mid, fingerprint string) *MerchantBeneficiary {
+	var beneficiary MerchantBeneficiary
+	err := ReadDB.Where("mid = ? AND fingerprint = ? AND status = ?", mid, fingerprint, protocol.StatusActive).
+		Order("created_at desc").First(&beneficiary).Error
+	if err != nil {
+		return nil
+	}
+	return &beneficiary
+}
+
+// BeneficiaryQuery 收款人查询参数
+type BeneficiaryQuery struct {
+	Mid          string
+	Status       string
+	VerifyStatus string
+	Ccy          string
+	Keyword      string
+	Page         int
+	Size         int
+}
+
+// ListBeneficiaryByQuery 分页查询商户收款人
+func ListBeneficiaryByQuery(q *BeneficiaryQuery) ([]*MerchantBeneficiary, int64, error) {
+	db := ReadDB.Model(&MerchantBeneficiary{}).Where("mid = ?", q.Mid)
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.VerifyStatus != "" {
+		db = db.Where("verify_status = ?", q.VerifyStatus)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.Keyword != "" {
+		keyword := "%" + q.Keyword + "%"
+		db = db.Where("alias ILIKE ? OR account_name ILIKE ?", keyword, keyword)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*MerchantBeneficiary
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// UpdateBeneficiaryValues 以当前状态为条件更新收款人
+func UpdateBeneficiaryValues(tx *gorm.DB, beneficiary *MerchantBeneficiary, fromStatus []string, values *MerchantBeneficiaryValues) (bool, error) {
+	result := tx.Model(&MerchantBeneficiary{}).
+		Where("beneficiary_id = ? AND status IN ?", beneficiary.BeneficiaryID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	beneficiary.SetValues(values)
+	return true, nil
+}
+
+// BeneficiaryAudit 收款人变更审计表，记录创建、修改、验证与停用
+type BeneficiaryAudit struct {
+	ID            int64            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	BeneficiaryID string           `json:"beneficiary_id" gorm:"column:beneficiary_id;type:varchar(64);index"`
+	Mid           string           `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	Action        string           `json:"action" gorm:"column:action;type:varchar(32)"`
+	Operator      string           `json:"operator" gorm:"column:operator;type:varchar(64)"`
+	Source        string           `json:"source" gorm:"column:source;type:varchar(32)"`
+	Changes       protocol.MapData `json:"changes" gorm:"column:changes;type:json;serializer:json"` // 变更字段，格式为 {field: {from, to}}
+	Remark        string           `json:"remark" gorm:"column:remark;type:varchar(256)"`
+	CreatedAt     int64            `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+}
+
+func (BeneficiaryAudit) TableName() string {
+	return "t_beneficiary_audits"
+}
+
+func (a *BeneficiaryAudit) Protocol() *protocol.BeneficiaryAudit {
+	return &protocol.BeneficiaryAudit{
+		BeneficiaryID: a.BeneficiaryID,
+		Action:        a.Action,
+		Operator:      a.Operator,
+		Source:        a.Source,
+		Changes:       a.Changes,
+		Remark:        a.Remark,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+// ListBeneficiaryAudits 分页查询收款人变更记录，按时间倒序
+func ListBeneficiaryAudits(mid, beneficiaryID string, page, size int) ([]*BeneficiaryAudit, int64, error) {
+	db := ReadDB.Model(&BeneficiaryAudit{}).Where("mid = ? AND beneficiary_id = ?", mid, beneficiaryID)
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*BeneficiaryAudit
+	err := db.Order("created_at desc, id desc").Offset((page - 1) * size).Limit(size).Find(&list).Error
+	return list, total, err
+}
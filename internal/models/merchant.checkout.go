@@ -15,6 +15,7 @@ type MerchantCheckout struct {
 	Mid        string `gorm:"column:mid;type:varchar(64);not null;index" json:"mid"`
 	ReqID      string `gorm:"column:req_id;type:varchar(64);index" json:"req_id"`
 	TrxType    string `gorm:"column:trx_type;type:varchar(32);index" json:"trx_type"` // 交易类型: payin-代收, payout-代付
+	LinkID     string `gorm:"column:link_id;type:varchar(64);index" json:"link_id"`   // 来源支付链接ID
 	*MerchantCheckoutValues
 	CreatedAt int64 `gorm:"column:created_at;type:bigint;autoCreateTime:milli" json:"created_at"`
 	UpdatedAt int64 `gorm:"column:updated_at;type:bigint;autoUpdateTime:milli" json:"updated_at"`
@@ -489,6 +490,7 @@ func (c *MerchantCheckout) Protocol() *protocol.Checkout {
 		CheckoutID:  c.CheckoutID,
 		Mid:         c.Mid,
 		ReqID:       c.ReqID,
+		LinkID:      c.LinkID,
 		TrxID:       c.GetTrxID(),
 		Amount:      c.GetAmount().String(),
 		Ccy:         c.GetCcy(),
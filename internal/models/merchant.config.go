@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"inpayos/internal/protocol"
+	"slices"
 
 	"github.com/shopspring/decimal"
 )
@@ -29,6 +30,17 @@ type TrxConfig struct {
 	AutoConfirm   string            `json:"auto_confirm,omitempty"`   // 自动确认：on, off
 	NotifyURL     string            `json:"notify_url,omitempty"`     // 通知地址
 	TimeoutMinute int               `json:"timeout_minute,omitempty"` // 超时时间（分钟）
+
+	ApprovalThreshold map[string]string `json:"approval_threshold,omitempty"`  // 复核阈值，超过该金额需审批
+	ApprovalRiskFlags []string          `json:"approval_risk_flags,omitempty"` // 命中即需审批的风险标记
+
+	UnderpaidAction    string            `json:"underpaid_action,omitempty"`     // 少付处理：accept, reject, review
+	OverpaidAction     string            `json:"overpaid_action,omitempty"`      // 多付处理：accept, reject, review
+	AmountTolerance    map[string]string `json:"amount_tolerance,omitempty"`     // 按币种的金额差异容差，容差内按实收金额自动接受
+	AmountTolerancePct string            `json:"amount_tolerance_pct,omitempty"` // 金额差异容差百分比，与按币种容差取较大者
+
+	UpiVpa       string `json:"upi_vpa,omitempty"`        // 商户UPI收款VPA，用于生成upi://pay意图
+	UpiPayeeName string `json:"upi_payee_name,omitempty"` // UPI收款方展示名称
 }
 
 // 表名
@@ -150,6 +162,48 @@ func (c *TrxConfig) Copy(source *TrxConfig) {
 	if source.TimeoutMinute > 0 {
 		c.TimeoutMinute = source.TimeoutMinute
 	}
+
+	if source.ApprovalThreshold != nil {
+		if c.ApprovalThreshold == nil {
+			c.ApprovalThreshold = make(map[string]string)
+		}
+		for k, v := range source.ApprovalThreshold {
+			c.ApprovalThreshold[k] = v
+		}
+	}
+
+	if source.ApprovalRiskFlags != nil {
+		c.ApprovalRiskFlags = source.ApprovalRiskFlags
+	}
+
+	if source.UnderpaidAction != "" {
+		c.UnderpaidAction = source.UnderpaidAction
+	}
+
+	if source.OverpaidAction != "" {
+		c.OverpaidAction = source.OverpaidAction
+	}
+
+	if source.AmountTolerance != nil {
+		if c.AmountTolerance == nil {
+			c.AmountTolerance = make(map[string]string)
+		}
+		for k, v := range source.AmountTolerance {
+			c.AmountTolerance[k] = v
+		}
+	}
+
+	if source.AmountTolerancePct != "" {
+		c.AmountTolerancePct = source.AmountTolerancePct
+	}
+
+	if source.UpiVpa != "" {
+		c.UpiVpa = source.UpiVpa
+	}
+
+	if source.UpiPayeeName != "" {
+		c.UpiPayeeName = source.UpiPayeeName
+	}
 }
 
 // GetMinAmount 获取最小金额
@@ -197,6 +251,73 @@ func (c *TrxConfig) GetFeeRate(currency string) decimal.Decimal {
 	return decimal.Zero
 }
 
+// GetDailyLimit 获取每日限额
+func (c *TrxConfig) GetDailyLimit(currency string) decimal.Decimal {
+	if c.DailyLimit == nil {
+		return decimal.Zero
+	}
+
+	if amount, exists := c.DailyLimit[currency]; exists {
+		if amt, err := decimal.NewFromString(amount); err == nil {
+			return amt
+		}
+	}
+
+	return decimal.Zero
+}
+
+// GetApprovalThreshold 获取复核阈值，0表示不需要复核
+func (c *TrxConfig) GetApprovalThreshold(currency string) decimal.Decimal {
+	if c.ApprovalThreshold == nil {
+		return decimal.Zero
+	}
+
+	if amount, exists := c.ApprovalThreshold[currency]; exists {
+		if amt, err := decimal.NewFromString(amount); err == nil {
+			return amt
+		}
+	}
+
+	return decimal.Zero
+}
+
+// GetUnderpaidAction 获取少付处理方式，未配置时转人工审核
+func (c *TrxConfig) GetUnderpaidAction() string {
+	if c.UnderpaidAction == "" {
+		return protocol.AmountActionReview
+	}
+	return c.UnderpaidAction
+}
+
+// GetOverpaidAction 获取多付处理方式，未配置时转人工审核
+func (c *TrxConfig) GetOverpaidAction() string {
+	if c.OverpaidAction == "" {
+		return protocol.AmountActionReview
+	}
+	return c.OverpaidAction
+}
+
+// GetAmountTolerance 获取金额差异容差，取按币种容差与订单金额百分比容差的较大者
+func (c *TrxConfig) GetAmountTolerance(currency string, amount decimal.Decimal) decimal.Decimal {
+	tolerance := decimal.Zero
+	if value, exists := c.AmountTolerance[currency]; exists {
+		if amt, err := decimal.NewFromString(value); err == nil {
+			tolerance = amt
+		}
+	}
+	if c.AmountTolerancePct != "" {
+		if pct, err := decimal.NewFromString(c.AmountTolerancePct); err == nil {
+			tolerance = decimal.Max(tolerance, amount.Mul(pct).Div(decimal.NewFromInt(100)))
+		}
+	}
+	return tolerance
+}
+
+// HasApprovalRiskFlag 检查是否启用指定的风险标记
+func (c *TrxConfig) HasApprovalRiskFlag(flag string) bool {
+	return slices.Contains(c.ApprovalRiskFlags, flag)
+}
+
 // IsEnabled 检查是否启用
 func (c *TrxConfig) IsEnabled() bool {
 	return c.Status == "on"
@@ -214,6 +335,7 @@ const (
 	TrxTypeDeposit  = "deposit"  // 充值
 	TrxTypeWithdraw = "withdraw" // 提现
 	TrxTypeRefund   = "refund"   // 退款
+	TrxTypeConvert  = "convert"  // 换汇
 )
 
 // 配置状态常量
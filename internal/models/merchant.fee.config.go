@@ -1,12 +1,12 @@
 package models
 
-// 移除未使用的import
+import "inpayos/internal/protocol"
 
 // MerchantFeeConfig 费率配置表
 type MerchantFeeConfig struct {
 	ID      uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
 	Mid     string `gorm:"column:mid;type:varchar(64);not null;index" json:"mid"`
-	TrxType string `gorm:"column:trx_type;type:varchar(32);not null" json:"trx_type"` // receipt, payment, deposit, withdraw
+	TrxType string `gorm:"column:trx_type;type:varchar(32);not null" json:"trx_type"` // payin, payout
 	*FeeConfigValues
 	CreatedAt int64 `gorm:"column:created_at;type:bigint;autoCreateTime:milli" json:"created_at"`
 	UpdatedAt int64 `gorm:"column:updated_at;type:bigint;autoUpdateTime:milli" json:"updated_at"`
@@ -179,3 +179,34 @@ func (fc *MerchantFeeConfig) SetValues(values *FeeConfigValues) *MerchantFeeConf
 
 	return fc
 }
+
+// MatchScore 计算费率配置与交易条件的匹配度，国家、支付方式、币种为空视为通配
+// 任一已配置条件不匹配时返回-1，否则返回已配置条件的个数，数值越大越精确
+func (fc *MerchantFeeConfig) MatchScore(country, paymentMethod, ccy string) int {
+	score := 0
+	for _, cond := range [][2]string{
+		{fc.GetCountry(), country},
+		{fc.GetPaymentMethod(), paymentMethod},
+		{fc.GetCcy(), ccy},
+	} {
+		if cond[0] == "" {
+			continue
+		}
+		if cond[0] != cond[1] {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// ListActiveMerchantFeeConfigs 获取商户指定交易类型的有效费率配置
+func ListActiveMerchantFeeConfigs(mid, trxType string) []*MerchantFeeConfig {
+	var list []*MerchantFeeConfig
+	err := ReadDB.Where("mid = ? AND trx_type = ? AND status = ?", mid, trxType, protocol.StatusActive).
+		Order("updated_at desc").Find(&list).Error
+	if err != nil {
+		return nil
+	}
+	return list
+}
@@ -27,6 +27,8 @@ type MerchantValues struct {
 	G2FA      *string `json:"g2fa" gorm:"column:g2fa;type:varchar(256)"`
 	NotifyURL *string `json:"notify_url" gorm:"column:notify_url;type:varchar(1024)"`
 	RegIP     *string `json:"reg_ip" gorm:"column:reg_ip;type:varchar(64)"` // 注册IP
+
+	GroupID *string `json:"group_id" gorm:"column:group_id;type:varchar(64);index"` // 商户集团ID，同一集团的商户之间可内部转账
 }
 
 func (t *Merchant) TableName() string {
@@ -193,6 +195,18 @@ func (m *MerchantValues) SetRegIP(ip string) *MerchantValues {
 	return m
 }
 
+func (m *MerchantValues) GetGroupID() string {
+	if m.GroupID == nil {
+		return ""
+	}
+	return *m.GroupID
+}
+
+func (m *MerchantValues) SetGroupID(groupID string) *MerchantValues {
+	m.GroupID = &groupID
+	return m
+}
+
 // Protocol converts model.Merchant to protocol.MerchantInfo
 func (t *Merchant) Protocol() *protocol.Merchant {
 	var region, avatar string
@@ -213,6 +227,7 @@ func (t *Merchant) Protocol() *protocol.Merchant {
 		Region:  region,
 		Avatar:  avatar,
 		HasG2FA: t.GetG2FA() != "",
+		GroupID: t.GetGroupID(),
 	}
 }
 
@@ -254,6 +269,19 @@ func GetActiveMerchants() ([]*Merchant, error) {
 	return GetMerchantsByStatus(protocol.StatusActive)
 }
 
+// ListMerchantsByGroupID 获取同一商户集团下的商户
+func ListMerchantsByGroupID(groupID string) ([]*Merchant, error) {
+	var merchants []*Merchant
+	err := ReadDB.Where("group_id = ?", groupID).Order("id asc").Find(&merchants).Error
+	return merchants, err
+}
+
+// UpdateMerchantGroupID 设置商户所属集团，groupID为空时移出集团
+func UpdateMerchantGroupID(mid, groupID string) (bool, error) {
+	result := WriteDB.Model(&Merchant{}).Where("mid = ?", mid).Update("group_id", groupID)
+	return result.RowsAffected > 0, result.Error
+}
+
 // GetMerchantsByStatus 获取指定状态的商户列表
 func GetMerchantsByStatus(status string) ([]*Merchant, error) {
 	var merchants []*Merchant
@@ -344,6 +372,9 @@ func (m *Merchant) SetValues(values *MerchantValues) *Merchant {
 	if values.RegIP != nil {
 		m.MerchantValues.SetRegIP(*values.RegIP)
 	}
+	if values.GroupID != nil {
+		m.MerchantValues.SetGroupID(*values.GroupID)
+	}
 
 	return m
 }
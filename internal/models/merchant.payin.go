@@ -29,12 +29,14 @@ type MerchantPayin struct {
 	Ccy                  string           `json:"ccy" gorm:"column:ccy;<-:create"`
 	Amount               *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(19,4);<-:create"`
 	UsdAmount            *decimal.Decimal `json:"usd_amount" gorm:"column:usd_amount;type:decimal(19,4);<-:create"`
+	UsdRate              *decimal.Decimal `json:"usd_rate" gorm:"column:usd_rate;type:decimal(28,12);<-:create"` // 创建时的币种兑美元汇率
 	AccountNo            string           `json:"account_no" gorm:"column:account_no;<-:create"`
 	AccountName          string           `json:"account_name" gorm:"column:account_name;<-:create"`
 	AccountType          string           `json:"account_type" gorm:"column:account_type;<-:create"`
 	BankCode             string           `json:"bank_code" gorm:"column:bank_code;<-:create"`
 	BankName             string           `json:"bank_name" gorm:"column:bank_name;<-:create"`
 	ReturnURL            string           `json:"return_url" gorm:"column:return_url;<-:create"`
+	LinkID               string           `json:"link_id" gorm:"column:link_id;type:varchar(64);index;<-:create"` // 来源支付链接ID
 	*MerchantPayinValues `gorm:"embedded"`
 	CreatedAt            int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt            int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"` // 更新时间 (毫秒时间戳)
@@ -61,14 +63,18 @@ type MerchantPayinValues struct {
 	Status    *string        `json:"status" gorm:"column:status;index"`
 	Reason    *string        `json:"reason" gorm:"column:reason"`
 	Link      *string        `json:"link" gorm:"column:link"`
+	ProofID   *string        `json:"proof_id" gorm:"column:proof_id;type:varchar(64);index"` // 支付凭证ID/UTR
 	Detail    map[string]any `json:"detail" gorm:"column:detail;serializer:json;type:json"`
 	NotifyURL *string        `json:"notify_url" gorm:"column:notify_url"`
 
+	// 实收金额，付款人实际转账金额与订单金额不一致时记录，手续费与结算按实收金额计算
+	ReceivedAmount *decimal.Decimal `json:"received_amount" gorm:"column:received_amount;type:decimal(19,4)"`
+
 	// Fee related fields
 	FeeCcy       *string          `json:"fee_ccy" gorm:"column:fee_ccy"`
 	FeeAmount    *decimal.Decimal `json:"fee_amount" gorm:"column:fee_amount;type:decimal(19,4)"`
 	FeeUsdAmount *decimal.Decimal `json:"fee_usd_amount" gorm:"column:fee_usd_amount;type:decimal(19,4)"`
-	FeeUsdRate   *decimal.Decimal `json:"fee_usd_rate" gorm:"column:fee_usd_rate;type:decimal(19,4)"`
+	FeeUsdRate   *decimal.Decimal `json:"fee_usd_rate" gorm:"column:fee_usd_rate;type:decimal(28,12)"`
 
 	// Channel related fields
 	ChannelStatus       *string          `json:"channel_status" gorm:"column:channel_status"`
@@ -81,7 +87,7 @@ type MerchantPayinValues struct {
 	ChannelFeeCcy       *string          `json:"channel_fee_ccy" gorm:"column:channel_fee_ccy"`
 	ChannelFeeAmount    *decimal.Decimal `json:"channel_fee_amount" gorm:"column:channel_fee_amount;type:decimal(19,4)"`
 	ChannelFeeUsdAmount *decimal.Decimal `json:"channel_fee_usd_amount" gorm:"column:channel_fee_usd_amount;type:decimal(19,4)"`
-	ChannelFeeUsdRate   *decimal.Decimal `json:"channel_fee_usd_rate" gorm:"column:channel_fee_usd_rate;type:decimal(19,4)"`
+	ChannelFeeUsdRate   *decimal.Decimal `json:"channel_fee_usd_rate" gorm:"column:channel_fee_usd_rate;type:decimal(28,12)"`
 
 	// Timing fields
 	ConfirmedAt        *int64  `json:"confirmed_at" gorm:"column:confirmed_at"`
@@ -265,6 +271,22 @@ func (pv *MerchantPayinValues) GetLink() string {
 	return *pv.Link
 }
 
+// GetProofID returns the ProofID value
+func (pv *MerchantPayinValues) GetProofID() string {
+	if pv.ProofID == nil {
+		return ""
+	}
+	return *pv.ProofID
+}
+
+// GetReceivedAmount returns the ReceivedAmount value
+func (pv *MerchantPayinValues) GetReceivedAmount() decimal.Decimal {
+	if pv.ReceivedAmount == nil {
+		return decimal.Zero
+	}
+	return *pv.ReceivedAmount
+}
+
 // GetFeeCcy returns the FeeCcy value
 func (pv *MerchantPayinValues) GetFeeCcy() string {
 	if pv.FeeCcy == nil {
@@ -521,6 +543,18 @@ func (pv *MerchantPayinValues) SetLink(value string) *MerchantPayinValues {
 	return pv
 }
 
+// SetProofID sets the ProofID value
+func (pv *MerchantPayinValues) SetProofID(value string) *MerchantPayinValues {
+	pv.ProofID = &value
+	return pv
+}
+
+// SetReceivedAmount sets the ReceivedAmount value
+func (pv *MerchantPayinValues) SetReceivedAmount(value decimal.Decimal) *MerchantPayinValues {
+	pv.ReceivedAmount = &value
+	return pv
+}
+
 // SetFeeCcy sets the FeeCcy value
 func (pv *MerchantPayinValues) SetFeeCcy(value string) *MerchantPayinValues {
 	pv.FeeCcy = &value
@@ -697,6 +731,12 @@ func (p *MerchantPayin) SetValues(values *MerchantPayinValues) *MerchantPayin {
 	if values.Link != nil {
 		p.MerchantPayinValues.SetLink(*values.Link)
 	}
+	if values.ProofID != nil {
+		p.MerchantPayinValues.SetProofID(*values.ProofID)
+	}
+	if values.ReceivedAmount != nil {
+		p.MerchantPayinValues.SetReceivedAmount(*values.ReceivedAmount)
+	}
 	if values.FeeCcy != nil {
 		p.MerchantPayinValues.SetFeeCcy(*values.FeeCcy)
 	}
@@ -780,12 +820,14 @@ func (p *MerchantPayin) ToTransaction() *Transaction {
 		Ccy:         p.Ccy,
 		Amount:      p.Amount,
 		UsdAmount:   p.UsdAmount,
+		UsdRate:     p.UsdRate,
 		AccountNo:   p.AccountNo,
 		AccountName: p.AccountName,
 		AccountType: p.AccountType,
 		BankCode:    p.BankCode,
 		BankName:    p.BankName,
 		ReturnURL:   p.ReturnURL,
+		LinkID:      p.LinkID,
 		TransactionValues: &TransactionValues{
 			MetaData:            p.MerchantPayinValues.MetaData,
 			RefundedCount:       p.MerchantPayinValues.RefundedCount,
@@ -801,6 +843,8 @@ func (p *MerchantPayin) ToTransaction() *Transaction {
 			Status:              p.MerchantPayinValues.Status,
 			Reason:              p.MerchantPayinValues.Reason,
 			Link:                p.MerchantPayinValues.Link,
+			ProofID:             p.MerchantPayinValues.ProofID,
+			ReceivedAmount:      p.MerchantPayinValues.ReceivedAmount,
 			Detail:              p.MerchantPayinValues.Detail,
 			NotifyURL:           p.MerchantPayinValues.NotifyURL,
 			FeeCcy:              p.MerchantPayinValues.FeeCcy,
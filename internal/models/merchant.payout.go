@@ -29,12 +29,14 @@ type MerchantPayout struct {
 	Ccy                   string           `json:"ccy" gorm:"column:ccy;<-:create"`
 	Amount                *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(19,4);<-:create"`
 	UsdAmount             *decimal.Decimal `json:"usd_amount" gorm:"column:usd_amount;type:decimal(19,4);<-:create"`
+	UsdRate               *decimal.Decimal `json:"usd_rate" gorm:"column:usd_rate;type:decimal(28,12);<-:create"` // 创建时的币种兑美元汇率
 	AccountNo             string           `json:"account_no" gorm:"column:account_no;<-:create"`
 	AccountName           string           `json:"account_name" gorm:"column:account_name;<-:create"`
 	AccountType           string           `json:"account_type" gorm:"column:account_type;<-:create"`
 	BankCode              string           `json:"bank_code" gorm:"column:bank_code;<-:create"`
 	BankName              string           `json:"bank_name" gorm:"column:bank_name;<-:create"`
 	ReturnURL             string           `json:"return_url" gorm:"column:return_url;<-:create"`
+	BeneficiaryID         string           `json:"beneficiary_id" gorm:"column:beneficiary_id;type:varchar(64);index;<-:create"` // 引用的收款人ID
 	*MerchantPayoutValues `gorm:"embedded"`
 	CreatedAt             int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt             int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"` // 更新时间 (毫秒时间戳)
@@ -67,7 +69,7 @@ type MerchantPayoutValues struct {
 	FeeCcy       *string          `json:"fee_ccy" gorm:"column:fee_ccy"`
 	FeeAmount    *decimal.Decimal `json:"fee_amount" gorm:"column:fee_amount;type:decimal(19,4)"`
 	FeeUsdAmount *decimal.Decimal `json:"fee_usd_amount" gorm:"column:fee_usd_amount;type:decimal(19,4)"`
-	FeeUsdRate   *decimal.Decimal `json:"fee_usd_rate" gorm:"column:fee_usd_rate;type:decimal(19,4)"`
+	FeeUsdRate   *decimal.Decimal `json:"fee_usd_rate" gorm:"column:fee_usd_rate;type:decimal(28,12)"`
 
 	// Channel related fields
 	ChannelStatus       *string          `json:"channel_status" gorm:"column:channel_status"`
@@ -81,7 +83,7 @@ type MerchantPayoutValues struct {
 	ChannelFeeCcy       *string          `json:"channel_fee_ccy" gorm:"column:channel_fee_ccy"`
 	ChannelFeeAmount    *decimal.Decimal `json:"channel_fee_amount" gorm:"column:channel_fee_amount;type:decimal(19,4)"`
 	ChannelFeeUsdAmount *decimal.Decimal `json:"channel_fee_usd_amount" gorm:"column:channel_fee_usd_amount;type:decimal(19,4)"`
-	ChannelFeeUsdRate   *decimal.Decimal `json:"channel_fee_usd_rate" gorm:"column:channel_fee_usd_rate;type:decimal(19,4)"`
+	ChannelFeeUsdRate   *decimal.Decimal `json:"channel_fee_usd_rate" gorm:"column:channel_fee_usd_rate;type:decimal(28,12)"`
 
 	// Timing fields
 	ConfirmedAt        *int64  `json:"confirmed_at" gorm:"column:confirmed_at"`
@@ -796,6 +798,7 @@ func (p *MerchantPayout) ToTransaction() *Transaction {
 		Ccy:         p.Ccy,
 		Amount:      p.Amount,
 		UsdAmount:   p.UsdAmount,
+		UsdRate:     p.UsdRate,
 		AccountNo:   p.AccountNo,
 		AccountName: p.AccountName,
 		AccountType: p.AccountType,
@@ -846,5 +849,6 @@ func (p *MerchantPayout) ToTransaction() *Transaction {
 		UpdatedAt: p.UpdatedAt,
 	}
 
+	transaction.BeneficiaryID = p.BeneficiaryID
 	return transaction
 }
@@ -0,0 +1,276 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MerchantReserveRule 商户结算准备金规则，每个商户每个结算币种一条
+// 每笔结算按比例扣留准备金，不低于固定下限（不超过结算金额），扣留的准备金在持有天数后释放
+type MerchantReserveRule struct {
+	ID         int64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Mid        string          `json:"mid" gorm:"column:mid;type:varchar(64);uniqueIndex:uk_reserve_rule_mid_ccy,priority:1"`
+	Ccy        string          `json:"ccy" gorm:"column:ccy;type:varchar(16);uniqueIndex:uk_reserve_rule_mid_ccy,priority:2"`
+	Percentage decimal.Decimal `json:"percentage" gorm:"column:percentage;type:decimal(10,6)"`    // 扣留比例
+	FixedFloor decimal.Decimal `json:"fixed_floor" gorm:"column:fixed_floor;type:decimal(36,18)"` // 每笔结算最低扣留金额
+	HoldDays   int             `json:"hold_days" gorm:"column:hold_days"`                         // 持有天数
+	Status     string          `json:"status" gorm:"column:status;type:varchar(16)"`
+	UpdatedBy  string          `json:"updated_by" gorm:"column:updated_by;type:varchar(64)"`
+	CreatedAt  int64           `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt  int64           `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+func (MerchantReserveRule) TableName() string {
+	return "t_merchant_reserve_rules"
+}
+
+func (r *MerchantReserveRule) Protocol() *protocol.MerchantReserveRule {
+	return &protocol.MerchantReserveRule{
+		ID:         r.ID,
+		Mid:        r.Mid,
+		Ccy:        r.Ccy,
+		Percentage: r.Percentage.String(),
+		FixedFloor: r.FixedFloor.String(),
+		HoldDays:   r.HoldDays,
+		Status:     r.Status,
+		UpdatedBy:  r.UpdatedBy,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+// SaveMerchantReserveRule 保存准备金规则，同一商户同一币种重复写入时覆盖原值
+func SaveMerchantReserveRule(db *gorm.DB, rule *MerchantReserveRule) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "mid"}, {Name: "ccy"}},
+		DoUpdates: clause.AssignmentColumns([]string{"percentage", "fixed_floor", "hold_days", "status", "updated_by", "updated_at"}),
+	}).Create(rule).Error
+}
+
+// GetActiveMerchantReserveRule 获取商户该币种生效的准备金规则
+func GetActiveMerchantReserveRule(mid, ccy string) *MerchantReserveRule {
+	var rule MerchantReserveRule
+	err := ReadDB.Where("mid = ? AND ccy = ? AND status = ?", mid, ccy, protocol.StatusActive).First(&rule).Error
+	if err != nil {
+		return nil
+	}
+	return &rule
+}
+
+// MerchantReserveRuleQuery 准备金规则查询参数
+type MerchantReserveRuleQuery struct {
+	Mid    string
+	Ccy    string
+	Status string
+	Page   int
+	Size   int
+}
+
+// ListMerchantReserveRuleByQuery 分页查询准备金规则
+func ListMerchantReserveRuleByQuery(q *MerchantReserveRuleQuery) ([]*MerchantReserveRule, int64, error) {
+	db := ReadDB.Model(&MerchantReserveRule{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*MerchantReserveRule
+	err := db.Order("mid asc, ccy asc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// MerchantReserve 结算准备金分笔记录，每笔结算扣留一笔，到期释放
+type MerchantReserve struct {
+	ID        uint64          `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ReserveID string          `json:"reserve_id" gorm:"column:reserve_id;type:varchar(64);uniqueIndex"`
+	Mid       string          `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	SettleID  string          `json:"settle_id" gorm:"column:settle_id;type:varchar(64);uniqueIndex"`
+	Ccy       string          `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount    decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(36,18)"`
+	HoldDays  int             `json:"hold_days" gorm:"column:hold_days"`
+	ReleaseAt int64           `json:"release_at" gorm:"column:release_at;index"` // 到期释放时间
+	*MerchantReserveValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type MerchantReserveValues struct {
+	Status     *string `json:"status" gorm:"column:status;type:varchar(16);index"`
+	ReleasedAt *int64  `json:"released_at" gorm:"column:released_at;index"`
+}
+
+func (MerchantReserve) TableName() string {
+	return "t_merchant_reserves"
+}
+
+// Getter methods for MerchantReserveValues
+func (mrv *MerchantReserveValues) GetStatus() string {
+	if mrv.Status == nil {
+		return ""
+	}
+	return *mrv.Status
+}
+
+func (mrv *MerchantReserveValues) GetReleasedAt() int64 {
+	if mrv.ReleasedAt == nil {
+		return 0
+	}
+	return *mrv.ReleasedAt
+}
+
+// Setter methods for MerchantReserveValues (支持链式调用)
+func (mrv *MerchantReserveValues) SetStatus(status string) *MerchantReserveValues {
+	mrv.Status = &status
+	return mrv
+}
+
+func (mrv *MerchantReserveValues) SetReleasedAt(releasedAt int64) *MerchantReserveValues {
+	mrv.ReleasedAt = &releasedAt
+	return mrv
+}
+
+// SetValues 为MerchantReserve设置MerchantReserveValues
+func (mr *MerchantReserve) SetValues(values *MerchantReserveValues) *MerchantReserve {
+	if values == nil {
+		return mr
+	}
+
+	if mr.MerchantReserveValues == nil {
+		mr.MerchantReserveValues = &MerchantReserveValues{}
+	}
+
+	if values.Status != nil {
+		mr.MerchantReserveValues.SetStatus(*values.Status)
+	}
+	if values.ReleasedAt != nil {
+		mr.MerchantReserveValues.SetReleasedAt(*values.ReleasedAt)
+	}
+
+	return mr
+}
+
+func (mr *MerchantReserve) Protocol() *protocol.MerchantReserve {
+	return &protocol.MerchantReserve{
+		ReserveID:  mr.ReserveID,
+		Mid:        mr.Mid,
+		SettleID:   mr.SettleID,
+		Ccy:        mr.Ccy,
+		Amount:     mr.Amount.String(),
+		HoldDays:   mr.HoldDays,
+		ReleaseAt:  mr.ReleaseAt,
+		Status:     mr.GetStatus(),
+		ReleasedAt: mr.GetReleasedAt(),
+		CreatedAt:  mr.CreatedAt,
+		UpdatedAt:  mr.UpdatedAt,
+	}
+}
+
+// GetMerchantReserve 获取准备金记录，mid为空时不限制商户
+func GetMerchantReserve(mid, reserveID string) *MerchantReserve {
+	var reserve MerchantReserve
+	db := ReadDB.Where("reserve_id = ?", reserveID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&reserve).Error; err != nil {
+		return nil
+	}
+	return &reserve
+}
+
+// UpdateMerchantReserveValues 以当前状态为条件更新准备金记录，避免重复释放
+func UpdateMerchantReserveValues(tx *gorm.DB, reserve *MerchantReserve, fromStatus string, values *MerchantReserveValues) (bool, error) {
+	result := tx.Model(&MerchantReserve{}).
+		Where("reserve_id = ? AND status = ?", reserve.ReserveID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	reserve.SetValues(values)
+	return true, nil
+}
+
+// ListMaturedMerchantReserves 按主键顺序分批获取已到期未释放的准备金
+func ListMaturedMerchantReserves(now int64, afterID uint64, limit int) ([]*MerchantReserve, error) {
+	var list []*MerchantReserve
+	err := ReadDB.Where("status = ? AND release_at <= ? AND id > ?", protocol.ReserveStatusHeld, now, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// ListHeldMerchantReserves 获取商户全部未释放的准备金，按到期时间排序
+func ListHeldMerchantReserves(mid string) ([]*MerchantReserve, error) {
+	var list []*MerchantReserve
+	err := ReadDB.Where("mid = ? AND status = ?", mid, protocol.ReserveStatusHeld).
+		Order("release_at asc, id asc").
+		Find(&list).Error
+	return list, err
+}
+
+// MerchantReserveQuery 准备金记录查询参数
+type MerchantReserveQuery struct {
+	Mid            string
+	SettleID       string
+	Status         string
+	Ccy            string
+	ReleaseAtStart int64
+	ReleaseAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListMerchantReserveByQuery 分页查询准备金记录，按到期时间排序
+func ListMerchantReserveByQuery(q *MerchantReserveQuery) ([]*MerchantReserve, int64, error) {
+	db := ReadDB.Model(&MerchantReserve{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.SettleID != "" {
+		db = db.Where("settle_id = ?", q.SettleID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.ReleaseAtStart > 0 {
+		db = db.Where("release_at >= ?", q.ReleaseAtStart)
+	}
+	if q.ReleaseAtEnd > 0 {
+		db = db.Where("release_at <= ?", q.ReleaseAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*MerchantReserve
+	err := db.Order("release_at asc, id asc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListMerchantReservesCreatedBetween 按主键顺序分批获取创建时间在范围内的准备金，用于账务核对
+func ListMerchantReservesCreatedBetween(db *gorm.DB, start, end int64, afterID uint64, limit int) ([]*MerchantReserve, error) {
+	var list []*MerchantReserve
+	err := db.Where("created_at >= ? AND created_at <= ? AND id > ?", start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
@@ -381,3 +381,23 @@ func GetPendingAccountingSettleLogs(currentTime int64) ([]*MerchantSettle, error
 
 	return settleLogs, nil
 }
+
+// ListMerchantSettlesBySettleIDs 按结算ID批量获取结算记录
+func ListMerchantSettlesBySettleIDs(db *gorm.DB, settleIDs []string) ([]*MerchantSettle, error) {
+	var list []*MerchantSettle
+	if len(settleIDs) == 0 {
+		return list, nil
+	}
+	err := db.Where("settle_id IN ?", settleIDs).Find(&list).Error
+	return list, err
+}
+
+// ListAccountedMerchantSettles 按主键顺序分批获取记账完成时间在范围内的结算记录
+func ListAccountedMerchantSettles(db *gorm.DB, start, end, afterID int64, limit int) ([]*MerchantSettle, error) {
+	var list []*MerchantSettle
+	err := db.Where("completed_at >= ? AND completed_at <= ? AND id > ?", start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
@@ -38,6 +38,9 @@ type MerchantSettleTransactionValues struct {
 	SettleStrategy  *protocol.SettleStrategy `json:"settle_strategy" gorm:"column:settle_strategy;type:json;serializer:json"` // SettleStrategy 结算策略
 	SettleRule      *protocol.SettleRule     `json:"settle_rule" gorm:"column:settle_rule;type:json;serializer:json"`         // SettleRule 结算规则
 	Status          *string                  `json:"status" gorm:"column:status;index"`                                       // Status 状态
+	PreFee          *decimal.Decimal         `json:"pre_fee" gorm:"column:pre_fee"`                                           // PreFee 交易创建时预计算的手续费
+	FeeDiff         *decimal.Decimal         `json:"fee_diff" gorm:"column:fee_diff"`                                         // FeeDiff 结算手续费与预计算手续费差额
+	FeeMismatch     *bool                    `json:"fee_mismatch" gorm:"column:fee_mismatch;index"`                           // FeeMismatch 手续费是否不一致
 }
 
 func (t MerchantSettleTransaction) TableName() string {
@@ -204,6 +207,36 @@ func (v *MerchantSettleTransactionValues) SetSettleRule(rule *protocol.SettleRul
 	v.SettleRule = rule
 	return v
 }
+func (v *MerchantSettleTransactionValues) GetPreFee() decimal.Decimal {
+	if v.PreFee == nil {
+		return decimal.Zero
+	}
+	return *v.PreFee
+}
+func (v *MerchantSettleTransactionValues) GetFeeDiff() decimal.Decimal {
+	if v.FeeDiff == nil {
+		return decimal.Zero
+	}
+	return *v.FeeDiff
+}
+func (v *MerchantSettleTransactionValues) GetFeeMismatch() bool {
+	if v.FeeMismatch == nil {
+		return false
+	}
+	return *v.FeeMismatch
+}
+func (v *MerchantSettleTransactionValues) SetPreFee(preFee decimal.Decimal) *MerchantSettleTransactionValues {
+	v.PreFee = &preFee
+	return v
+}
+func (v *MerchantSettleTransactionValues) SetFeeDiff(feeDiff decimal.Decimal) *MerchantSettleTransactionValues {
+	v.FeeDiff = &feeDiff
+	return v
+}
+func (v *MerchantSettleTransactionValues) SetFeeMismatch(mismatch bool) *MerchantSettleTransactionValues {
+	v.FeeMismatch = &mismatch
+	return v
+}
 
 // SetSettleLogID sets the settle log ID
 func (t *MerchantSettleTransactionValues) SetSettleLogID(settleLogID string) *MerchantSettleTransactionValues {
@@ -271,6 +304,15 @@ func (t *MerchantSettleTransaction) SetValues(values *MerchantSettleTransactionV
 	if values.Status != nil {
 		t.Status = values.Status
 	}
+	if values.PreFee != nil {
+		t.PreFee = values.PreFee
+	}
+	if values.FeeDiff != nil {
+		t.FeeDiff = values.FeeDiff
+	}
+	if values.FeeMismatch != nil {
+		t.FeeMismatch = values.FeeMismatch
+	}
 }
 
 // GetExistingSettleRecord 获取现有的结算记录
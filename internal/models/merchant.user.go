@@ -0,0 +1,198 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+)
+
+// MerchantUser 商户子账号表（复核员、操作员等）
+type MerchantUser struct {
+	ID     int64  `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	UserID string `json:"user_id" gorm:"column:user_id;type:varchar(64);uniqueIndex"`
+	Mid    string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	*MerchantUserValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type MerchantUserValues struct {
+	Name     *string `json:"name" gorm:"column:name;type:varchar(64)"`
+	Email    *string `json:"email" gorm:"column:email;type:varchar(128);index"`
+	Password *string `json:"-" gorm:"column:password;type:varchar(128)"` // 登录密码哈希，以UserID为盐
+	Role     *string `json:"role" gorm:"column:role;type:varchar(32);index"`
+	Status   *string `json:"status" gorm:"column:status;type:varchar(32);default:'active'"`
+	G2FA     *string `json:"g2fa" gorm:"column:g2fa;type:varchar(256)"`
+}
+
+func (MerchantUser) TableName() string {
+	return "t_merchant_users"
+}
+
+// NewMerchantUser 创建新的商户子账号
+func NewMerchantUser(mid string) *MerchantUser {
+	return &MerchantUser{
+		UserID: utils.GenerateMerchantUserID(),
+		Mid:    mid,
+		MerchantUserValues: &MerchantUserValues{
+			Status: utils.StringPtr(protocol.StatusActive),
+		},
+	}
+}
+
+func (v *MerchantUserValues) GetName() string {
+	if v.Name == nil {
+		return ""
+	}
+	return *v.Name
+}
+
+func (v *MerchantUserValues) GetEmail() string {
+	if v.Email == nil {
+		return ""
+	}
+	return *v.Email
+}
+
+func (v *MerchantUserValues) GetPassword() string {
+	if v.Password == nil {
+		return ""
+	}
+	return *v.Password
+}
+
+func (v *MerchantUserValues) GetRole() string {
+	if v.Role == nil {
+		return ""
+	}
+	return *v.Role
+}
+
+func (v *MerchantUserValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *MerchantUserValues) GetG2FA() string {
+	if v.G2FA == nil {
+		return ""
+	}
+	return *v.G2FA
+}
+
+func (v *MerchantUserValues) SetName(value string) *MerchantUserValues {
+	v.Name = &value
+	return v
+}
+
+func (v *MerchantUserValues) SetEmail(value string) *MerchantUserValues {
+	v.Email = &value
+	return v
+}
+
+func (v *MerchantUserValues) SetPassword(value string) *MerchantUserValues {
+	v.Password = &value
+	return v
+}
+
+func (v *MerchantUserValues) SetRole(value string) *MerchantUserValues {
+	v.Role = &value
+	return v
+}
+
+func (v *MerchantUserValues) SetStatus(value string) *MerchantUserValues {
+	v.Status = &value
+	return v
+}
+
+func (v *MerchantUserValues) SetG2FA(value string) *MerchantUserValues {
+	v.G2FA = &value
+	return v
+}
+
+// IsActive 子账号是否可用
+func (v *MerchantUserValues) IsActive() bool {
+	return v.GetStatus() == protocol.StatusActive
+}
+
+// IsApprover 子账号是否具备复核权限
+func (v *MerchantUserValues) IsApprover() bool {
+	return v.GetRole() == protocol.MerchantUserRoleApprover
+}
+
+// IsPasswordValid 校验子账号登录密码，未设置密码的子账号一律不通过
+func (u *MerchantUser) IsPasswordValid(password string) bool {
+	if password == "" || u.GetPassword() == "" {
+		return false
+	}
+	return utils.VerifyPassword(password, u.UserID, u.GetPassword())
+}
+
+// SetValues 合并非空字段
+func (u *MerchantUser) SetValues(values *MerchantUserValues) *MerchantUser {
+	if values == nil {
+		return u
+	}
+	if u.MerchantUserValues == nil {
+		u.MerchantUserValues = &MerchantUserValues{}
+	}
+	if values.Name != nil {
+		u.SetName(*values.Name)
+	}
+	if values.Email != nil {
+		u.SetEmail(*values.Email)
+	}
+	if values.Password != nil {
+		u.SetPassword(*values.Password)
+	}
+	if values.Role != nil {
+		u.SetRole(*values.Role)
+	}
+	if values.Status != nil {
+		u.SetStatus(*values.Status)
+	}
+	if values.G2FA != nil {
+		u.SetG2FA(*values.G2FA)
+	}
+	return u
+}
+
+func (u *MerchantUser) Protocol() *protocol.MerchantUser {
+	return &protocol.MerchantUser{
+		UserID:    u.UserID,
+		Mid:       u.Mid,
+		Name:      u.GetName(),
+		Email:     u.GetEmail(),
+		Role:      u.GetRole(),
+		Status:    u.GetStatus(),
+		HasG2FA:   u.GetG2FA() != "",
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+// GetMerchantUser 获取商户下的子账号
+func GetMerchantUser(mid, userID string) *MerchantUser {
+	var user MerchantUser
+	if err := ReadDB.Where("mid = ? AND user_id = ?", mid, userID).First(&user).Error; err != nil {
+		return nil
+	}
+	return &user
+}
+
+// GetMerchantUserByEmail 根据邮箱获取商户下的子账号
+func GetMerchantUserByEmail(mid, email string) *MerchantUser {
+	var user MerchantUser
+	if err := ReadDB.Where("mid = ? AND email = ?", mid, email).First(&user).Error; err != nil {
+		return nil
+	}
+	return &user
+}
+
+// ListMerchantUsers 获取商户全部子账号
+func ListMerchantUsers(mid string) ([]*MerchantUser, error) {
+	var users []*MerchantUser
+	err := ReadDB.Where("mid = ?", mid).Order("created_at desc").Find(&users).Error
+	return users, err
+}
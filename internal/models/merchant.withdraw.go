@@ -1,15 +1,24 @@
 package models
 
 import (
+	"inpayos/internal/protocol"
+
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
-// MerchantWithdraw 提现记录表
+// MerchantWithdraw 提现记录表，商户将结算余额提取到已验证的收款账户，收款账户信息在申请时快照
 type MerchantWithdraw struct {
-	ID        uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	TrxID     string `json:"trx_id" gorm:"column:trx_id;type:varchar(64);uniqueIndex"`
-	Mid       string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
-	AccountID string `json:"account_id" gorm:"column:account_id;type:varchar(64);index"`
+	ID            uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	TrxID         string `json:"trx_id" gorm:"column:trx_id;type:varchar(64);uniqueIndex"`
+	Mid           string `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	AccountID     string `json:"account_id" gorm:"column:account_id;type:varchar(64);index"`
+	BeneficiaryID string `json:"beneficiary_id" gorm:"column:beneficiary_id;type:varchar(64);index"`
+	AccountType   string `json:"account_type" gorm:"column:account_type;type:varchar(32)"`
+	AccountNo     string `json:"account_no" gorm:"column:account_no;type:varchar(64)"`
+	AccountName   string `json:"account_name" gorm:"column:account_name;type:varchar(128)"`
+	BankCode      string `json:"bank_code" gorm:"column:bank_code;type:varchar(32)"`
+	BankName      string `json:"bank_name" gorm:"column:bank_name;type:varchar(128)"`
 	*MerchantWithdrawValues
 	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
@@ -26,7 +35,18 @@ type MerchantWithdrawValues struct {
 	CanceledAt  *int64           `json:"canceled_at" gorm:"column:canceled_at"`
 	CompletedAt *int64           `json:"completed_at" gorm:"column:completed_at"`
 	ExpiredAt   *int64           `json:"expired_at" gorm:"column:expired_at"`
-	ConfirmedAt *int64           `json:"confirmed_at" gorm:"column:confirmed_at"`
+	ConfirmedAt *int64           `json:"confirmed_at" gorm:"column:confirmed_at"` // 审核时间
+
+	ExecMethod     *string `json:"exec_method" gorm:"column:exec_method;type:varchar(16)"`         // 执行方式: channel, manual
+	ChannelAccount *string `json:"channel_account" gorm:"column:channel_account;type:varchar(64)"` // 渠道出款使用的渠道账户
+	ChannelTrxID   *string `json:"channel_trx_id" gorm:"column:channel_trx_id;type:varchar(128)"`
+	TransferRef    *string `json:"transfer_ref" gorm:"column:transfer_ref;type:varchar(128)"` // 人工转账的银行流水号
+	ResCode        *string `json:"res_code" gorm:"column:res_code;type:varchar(64)"`
+	ResMsg         *string `json:"res_msg" gorm:"column:res_msg;type:varchar(512)"`
+	Remark         *string `json:"remark" gorm:"column:remark;type:varchar(512)"` // 商户申请备注
+	Reason         *string `json:"reason" gorm:"column:reason;type:varchar(512)"` // 驳回、失败原因
+	RequestedBy    *string `json:"requested_by" gorm:"column:requested_by;type:varchar(64)"`
+	ReviewedBy     *string `json:"reviewed_by" gorm:"column:reviewed_by;type:varchar(64)"`
 }
 
 func (MerchantWithdraw) TableName() string {
@@ -111,6 +131,76 @@ func (mwv *MerchantWithdrawValues) GetConfirmedAt() int64 {
 	return *mwv.ConfirmedAt
 }
 
+func (mwv *MerchantWithdrawValues) GetExecMethod() string {
+	if mwv.ExecMethod == nil {
+		return ""
+	}
+	return *mwv.ExecMethod
+}
+
+func (mwv *MerchantWithdrawValues) GetChannelAccount() string {
+	if mwv.ChannelAccount == nil {
+		return ""
+	}
+	return *mwv.ChannelAccount
+}
+
+func (mwv *MerchantWithdrawValues) GetChannelTrxID() string {
+	if mwv.ChannelTrxID == nil {
+		return ""
+	}
+	return *mwv.ChannelTrxID
+}
+
+func (mwv *MerchantWithdrawValues) GetTransferRef() string {
+	if mwv.TransferRef == nil {
+		return ""
+	}
+	return *mwv.TransferRef
+}
+
+func (mwv *MerchantWithdrawValues) GetResCode() string {
+	if mwv.ResCode == nil {
+		return ""
+	}
+	return *mwv.ResCode
+}
+
+func (mwv *MerchantWithdrawValues) GetResMsg() string {
+	if mwv.ResMsg == nil {
+		return ""
+	}
+	return *mwv.ResMsg
+}
+
+func (mwv *MerchantWithdrawValues) GetRemark() string {
+	if mwv.Remark == nil {
+		return ""
+	}
+	return *mwv.Remark
+}
+
+func (mwv *MerchantWithdrawValues) GetReason() string {
+	if mwv.Reason == nil {
+		return ""
+	}
+	return *mwv.Reason
+}
+
+func (mwv *MerchantWithdrawValues) GetRequestedBy() string {
+	if mwv.RequestedBy == nil {
+		return ""
+	}
+	return *mwv.RequestedBy
+}
+
+func (mwv *MerchantWithdrawValues) GetReviewedBy() string {
+	if mwv.ReviewedBy == nil {
+		return ""
+	}
+	return *mwv.ReviewedBy
+}
+
 // Setter methods for MerchantWithdrawValues (支持链式调用)
 func (mwv *MerchantWithdrawValues) SetStatus(status string) *MerchantWithdrawValues {
 	mwv.Status = &status
@@ -167,6 +257,56 @@ func (mwv *MerchantWithdrawValues) SetConfirmedAt(confirmedAt int64) *MerchantWi
 	return mwv
 }
 
+func (mwv *MerchantWithdrawValues) SetExecMethod(execMethod string) *MerchantWithdrawValues {
+	mwv.ExecMethod = &execMethod
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetChannelAccount(channelAccount string) *MerchantWithdrawValues {
+	mwv.ChannelAccount = &channelAccount
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetChannelTrxID(channelTrxID string) *MerchantWithdrawValues {
+	mwv.ChannelTrxID = &channelTrxID
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetTransferRef(transferRef string) *MerchantWithdrawValues {
+	mwv.TransferRef = &transferRef
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetResCode(resCode string) *MerchantWithdrawValues {
+	mwv.ResCode = &resCode
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetResMsg(resMsg string) *MerchantWithdrawValues {
+	mwv.ResMsg = &resMsg
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetRemark(remark string) *MerchantWithdrawValues {
+	mwv.Remark = &remark
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetReason(reason string) *MerchantWithdrawValues {
+	mwv.Reason = &reason
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetRequestedBy(requestedBy string) *MerchantWithdrawValues {
+	mwv.RequestedBy = &requestedBy
+	return mwv
+}
+
+func (mwv *MerchantWithdrawValues) SetReviewedBy(reviewedBy string) *MerchantWithdrawValues {
+	mwv.ReviewedBy = &reviewedBy
+	return mwv
+}
+
 // SetValues 为MerchantWithdraw设置MerchantWithdrawValues
 func (mw *MerchantWithdraw) SetValues(values *MerchantWithdrawValues) *MerchantWithdraw {
 	if values == nil {
@@ -210,6 +350,167 @@ func (mw *MerchantWithdraw) SetValues(values *MerchantWithdrawValues) *MerchantW
 	if values.ConfirmedAt != nil {
 		mw.MerchantWithdrawValues.SetConfirmedAt(*values.ConfirmedAt)
 	}
+	if values.ExecMethod != nil {
+		mw.MerchantWithdrawValues.SetExecMethod(*values.ExecMethod)
+	}
+	if values.ChannelAccount != nil {
+		mw.MerchantWithdrawValues.SetChannelAccount(*values.ChannelAccount)
+	}
+	if values.ChannelTrxID != nil {
+		mw.MerchantWithdrawValues.SetChannelTrxID(*values.ChannelTrxID)
+	}
+	if values.TransferRef != nil {
+		mw.MerchantWithdrawValues.SetTransferRef(*values.TransferRef)
+	}
+	if values.ResCode != nil {
+		mw.MerchantWithdrawValues.SetResCode(*values.ResCode)
+	}
+	if values.ResMsg != nil {
+		mw.MerchantWithdrawValues.SetResMsg(*values.ResMsg)
+	}
+	if values.Remark != nil {
+		mw.MerchantWithdrawValues.SetRemark(*values.Remark)
+	}
+	if values.Reason != nil {
+		mw.MerchantWithdrawValues.SetReason(*values.Reason)
+	}
+	if values.RequestedBy != nil {
+		mw.MerchantWithdrawValues.SetRequestedBy(*values.RequestedBy)
+	}
+	if values.ReviewedBy != nil {
+		mw.MerchantWithdrawValues.SetReviewedBy(*values.ReviewedBy)
+	}
 
 	return mw
 }
+
+func (mw *MerchantWithdraw) Protocol() *protocol.MerchantWithdraw {
+	return &protocol.MerchantWithdraw{
+		TrxID:         mw.TrxID,
+		Mid:           mw.Mid,
+		AccountID:     mw.AccountID,
+		BeneficiaryID: mw.BeneficiaryID,
+		AccountType:   mw.AccountType,
+		AccountNo:     mw.AccountNo,
+		AccountName:   mw.AccountName,
+		BankCode:      mw.BankCode,
+		BankName:      mw.BankName,
+		Country:       mw.GetCountry(),
+		Status:        mw.GetStatus(),
+		Ccy:           mw.GetCcy(),
+		Amount:        mw.GetAmount().String(),
+		Fee:           mw.GetFee().String(),
+		ExecMethod:    mw.GetExecMethod(),
+		ChannelCode:   mw.GetChannelCode(),
+		ChannelTrxID:  mw.GetChannelTrxID(),
+		TransferRef:   mw.GetTransferRef(),
+		ResCode:       mw.GetResCode(),
+		ResMsg:        mw.GetResMsg(),
+		Remark:        mw.GetRemark(),
+		Reason:        mw.GetReason(),
+		RequestedBy:   mw.GetRequestedBy(),
+		ReviewedBy:    mw.GetReviewedBy(),
+		ConfirmedAt:   mw.GetConfirmedAt(),
+		CanceledAt:    mw.GetCanceledAt(),
+		CompletedAt:   mw.GetCompletedAt(),
+		CreatedAt:     mw.CreatedAt,
+		UpdatedAt:     mw.UpdatedAt,
+	}
+}
+
+// GetFrozenAmount 申请时冻结的金额，为提现金额与手续费之和
+func (mw *MerchantWithdraw) GetFrozenAmount() decimal.Decimal {
+	return mw.GetAmount().Add(mw.GetFee())
+}
+
+// GetMerchantWithdraw 获取提现记录，mid为空时不限制商户
+func GetMerchantWithdraw(mid, trxID string) *MerchantWithdraw {
+	var withdraw MerchantWithdraw
+	db := ReadDB.Where("trx_id = ?", trxID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&withdraw).Error; err != nil {
+		return nil
+	}
+	return &withdraw
+}
+
+// UpdateMerchantWithdrawValues 以当前状态为条件更新提现记录，避免并发重复处理
+func UpdateMerchantWithdrawValues(tx *gorm.DB, withdraw *MerchantWithdraw, fromStatus []string, values *MerchantWithdrawValues) (bool, error) {
+	result := tx.Model(&MerchantWithdraw{}).
+		Where("trx_id = ? AND status IN ?", withdraw.TrxID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	withdraw.SetValues(values)
+	return true, nil
+}
+
+// MerchantWithdrawQuery 提现查询参数
+type MerchantWithdrawQuery struct {
+	Mid            string
+	TrxID          string
+	Status         string
+	Ccy            string
+	ExecMethod     string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListMerchantWithdrawByQuery 分页查询提现记录
+func ListMerchantWithdrawByQuery(q *MerchantWithdrawQuery) ([]*MerchantWithdraw, int64, error) {
+	db := ReadDB.Model(&MerchantWithdraw{})
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.TrxID != "" {
+		db = db.Where("trx_id = ?", q.TrxID)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	if q.ExecMethod != "" {
+		db = db.Where("exec_method = ?", q.ExecMethod)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*MerchantWithdraw
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// ListProcessingChannelWithdraws 获取渠道出款中的提现，用于向渠道同步结果
+func ListProcessingChannelWithdraws(limit int) ([]*MerchantWithdraw, error) {
+	var list []*MerchantWithdraw
+	err := ReadDB.Where("status = ? AND exec_method = ?", protocol.StatusProcessing, protocol.WithdrawMethodChannel).
+		Order("id asc").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ListMerchantWithdrawsCreatedBetween 按主键顺序分批获取创建时间在范围内的提现
+func ListMerchantWithdrawsCreatedBetween(db *gorm.DB, start, end int64, afterID uint64, limit int) ([]*MerchantWithdraw, error) {
+	var list []*MerchantWithdraw
+	err := db.Where("created_at >= ? AND created_at <= ? AND id > ?", start, end, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
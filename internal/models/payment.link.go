@@ -0,0 +1,252 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentLink 支付链接表，商户无需集成即可分享链接收款，每次访问创建一个收银台会话
+type PaymentLink struct {
+	ID          int64            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	LinkID      string           `json:"link_id" gorm:"column:link_id;type:varchar(64);uniqueIndex"`
+	Mid         string           `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	Ccy         string           `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount      *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"`         // 固定金额，为空时由付款人输入
+	MinAmount   *decimal.Decimal `json:"min_amount" gorm:"column:min_amount;type:decimal(20,8)"` // 开放金额下限
+	MaxAmount   *decimal.Decimal `json:"max_amount" gorm:"column:max_amount;type:decimal(20,8)"` // 开放金额上限
+	Description string           `json:"description" gorm:"column:description;type:varchar(256)"`
+	ReqIDPrefix string           `json:"req_id_prefix" gorm:"column:req_id_prefix;type:varchar(32)"`
+	MaxUses     int              `json:"max_uses" gorm:"column:max_uses"` // 最大支付次数（含支付中），0为不限
+	ExpiredAt   int64            `json:"expired_at" gorm:"column:expired_at"`
+	NotifyURL   string           `json:"notify_url" gorm:"column:notify_url;type:varchar(1024)"`
+	ReturnURL   string           `json:"return_url" gorm:"column:return_url;type:varchar(1024)"`
+	*PaymentLinkValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type PaymentLinkValues struct {
+	Status     *string `json:"status" gorm:"column:status;type:varchar(32);index"` // active, disabled
+	DisabledAt *int64  `json:"disabled_at" gorm:"column:disabled_at"`
+}
+
+func (PaymentLink) TableName() string {
+	return "t_payment_links"
+}
+
+func (v *PaymentLinkValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *PaymentLinkValues) GetDisabledAt() int64 {
+	if v.DisabledAt == nil {
+		return 0
+	}
+	return *v.DisabledAt
+}
+
+func (v *PaymentLinkValues) SetStatus(value string) *PaymentLinkValues {
+	v.Status = &value
+	return v
+}
+
+func (v *PaymentLinkValues) SetDisabledAt(value int64) *PaymentLinkValues {
+	v.DisabledAt = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (l *PaymentLink) SetValues(values *PaymentLinkValues) *PaymentLink {
+	if values == nil {
+		return l
+	}
+	if l.PaymentLinkValues == nil {
+		l.PaymentLinkValues = &PaymentLinkValues{}
+	}
+	if values.Status != nil {
+		l.SetStatus(*values.Status)
+	}
+	if values.DisabledAt != nil {
+		l.SetDisabledAt(*values.DisabledAt)
+	}
+	return l
+}
+
+// IsOpenAmount 是否为开放金额链接
+func (l *PaymentLink) IsOpenAmount() bool {
+	return l.Amount == nil
+}
+
+// IsExpired 是否已过期
+func (l *PaymentLink) IsExpired() bool {
+	return l.ExpiredAt > 0 && l.ExpiredAt <= utils.TimeNowMilli()
+}
+
+// DisplayStatus 展示状态，可用但已过期的链接展示为expired
+func (l *PaymentLink) DisplayStatus() string {
+	status := l.GetStatus()
+	if status == protocol.StatusActive && l.IsExpired() {
+		return protocol.StatusExpired
+	}
+	return status
+}
+
+func (l *PaymentLink) Protocol() *protocol.PaymentLink {
+	info := &protocol.PaymentLink{
+		LinkID:      l.LinkID,
+		Mid:         l.Mid,
+		Ccy:         l.Ccy,
+		Description: l.Description,
+		ReqIDPrefix: l.ReqIDPrefix,
+		MaxUses:     l.MaxUses,
+		Status:      l.DisplayStatus(),
+		ExpiredAt:   l.ExpiredAt,
+		NotifyURL:   l.NotifyURL,
+		ReturnURL:   l.ReturnURL,
+		DisabledAt:  l.GetDisabledAt(),
+		CreatedAt:   l.CreatedAt,
+		UpdatedAt:   l.UpdatedAt,
+	}
+	if l.Amount != nil {
+		info.Amount = l.Amount.String()
+	}
+	if l.MinAmount != nil {
+		info.MinAmount = l.MinAmount.String()
+	}
+	if l.MaxAmount != nil {
+		info.MaxAmount = l.MaxAmount.String()
+	}
+	return info
+}
+
+// GetPaymentLinkByID 获取支付链接，mid为空时不限制商户
+func GetPaymentLinkByID(mid, linkID string) *PaymentLink {
+	var link PaymentLink
+	db := ReadDB.Where("link_id = ?", linkID)
+	if mid != "" {
+		db = db.Where("mid = ?", mid)
+	}
+	if err := db.First(&link).Error; err != nil {
+		return nil
+	}
+	return &link
+}
+
+// LockPaymentLink 在事务内锁定支付链接，用于串行校验使用次数
+func LockPaymentLink(tx *gorm.DB, linkID string) *PaymentLink {
+	var link PaymentLink
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("link_id = ?", linkID).First(&link).Error
+	if err != nil {
+		return nil
+	}
+	return &link
+}
+
+// PaymentLinkQuery 支付链接查询参数
+type PaymentLinkQuery struct {
+	Mid    string
+	Status string
+	Ccy    string
+	Page   int
+	Size   int
+}
+
+// ListPaymentLinkByQuery 分页查询支付链接，expired状态按过期时间筛选
+func ListPaymentLinkByQuery(q *PaymentLinkQuery) ([]*PaymentLink, int64, error) {
+	db := ReadDB.Model(&PaymentLink{}).Where("mid = ?", q.Mid)
+	now := utils.TimeNowMilli()
+	switch q.Status {
+	case "":
+	case protocol.StatusActive:
+		db = db.Where("status = ? AND (expired_at = 0 OR expired_at > ?)", protocol.StatusActive, now)
+	case protocol.StatusExpired:
+		db = db.Where("status = ? AND expired_at > 0 AND expired_at <= ?", protocol.StatusActive, now)
+	default:
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.Ccy != "" {
+		db = db.Where("ccy = ?", q.Ccy)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*PaymentLink
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// UpdatePaymentLinkValues 以当前状态为条件更新支付链接
+func UpdatePaymentLinkValues(tx *gorm.DB, link *PaymentLink, fromStatus []string, values *PaymentLinkValues) (bool, error) {
+	result := tx.Model(&PaymentLink{}).
+		Where("link_id = ? AND status IN ?", link.LinkID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	link.SetValues(values)
+	return true, nil
+}
+
+// PaymentLinkCheckoutStats 支付链接收银台会话统计
+type PaymentLinkCheckoutStats struct {
+	VisitCount   int64 `gorm:"column:visit_count"`
+	ConfirmCount int64 `gorm:"column:confirm_count"`
+}
+
+// GetPaymentLinkCheckoutStats 统计支付链接创建的收银台会话
+func GetPaymentLinkCheckoutStats(linkID string) (*PaymentLinkCheckoutStats, error) {
+	var stats PaymentLinkCheckoutStats
+	err := ReadDB.Model(&MerchantCheckout{}).
+		Select("COUNT(*) AS visit_count, COUNT(*) FILTER (WHERE trx_id IS NOT NULL AND trx_id <> '') AS confirm_count").
+		Where("link_id = ?", linkID).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+// PaymentLinkPayinStats 支付链接代收统计
+type PaymentLinkPayinStats struct {
+	PaidCount    int64           `gorm:"column:paid_count"`
+	PaidAmount   decimal.Decimal `gorm:"column:paid_amount"`
+	PendingCount int64           `gorm:"column:pending_count"`
+	FailedCount  int64           `gorm:"column:failed_count"`
+	LastPaidAt   int64           `gorm:"column:last_paid_at"`
+}
+
+// PaymentLinkClosedStatuses 不占用链接支付次数的代收状态
+var PaymentLinkClosedStatuses = []string{protocol.StatusFailed, protocol.StatusCancelled, protocol.StatusExpired}
+
+// GetPaymentLinkPayinStats 统计支付链接产生的代收交易，成功金额按实收金额累计
+func GetPaymentLinkPayinStats(linkID string) (*PaymentLinkPayinStats, error) {
+	var stats PaymentLinkPayinStats
+	err := ReadDB.Table(TrxTypeTableMap[protocol.TrxTypePayin]).
+		Select(`COUNT(*) FILTER (WHERE status = @success) AS paid_count,
+			COALESCE(SUM(COALESCE(received_amount, amount)) FILTER (WHERE status = @success), 0) AS paid_amount,
+			COUNT(*) FILTER (WHERE status NOT IN @closed AND status <> @success) AS pending_count,
+			COUNT(*) FILTER (WHERE status IN @closed) AS failed_count,
+			COALESCE(MAX(completed_at) FILTER (WHERE status = @success), 0) AS last_paid_at`,
+			map[string]any{"success": protocol.StatusSuccess, "closed": PaymentLinkClosedStatuses}).
+		Where("link_id = ?", linkID).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+// CountPaymentLinkUses 统计占用支付链接次数的代收交易数（成功及支付中）
+func CountPaymentLinkUses(tx *gorm.DB, linkID string) int64 {
+	var count int64
+	tx.Table(TrxTypeTableMap[protocol.TrxTypePayin]).
+		Where("link_id = ? AND status NOT IN ?", linkID, PaymentLinkClosedStatuses).
+		Count(&count)
+	return count
+}
@@ -0,0 +1,281 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PaymentProof 支付凭证表，记录付款人提交的UTR与付款截图，按UTR与银行流水匹配或进入人工审核
+type PaymentProof struct {
+	ID         int64           `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ProofID    string          `json:"proof_id" gorm:"column:proof_id;type:varchar(64);uniqueIndex"`
+	TrxID      string          `json:"trx_id" gorm:"column:trx_id;type:varchar(64);index"`
+	TrxType    string          `json:"trx_type" gorm:"column:trx_type;type:varchar(32)"`
+	Mid        string          `json:"mid" gorm:"column:mid;type:varchar(64);index"`
+	CheckoutID string          `json:"checkout_id" gorm:"column:checkout_id;type:varchar(64)"`
+	Tid        string          `json:"tid" gorm:"column:tid;type:varchar(32);index"` // 受理交易的收银团队，为空时由运营审核
+	Utr        string          `json:"utr" gorm:"column:utr;type:varchar(64);index"`
+	Ccy        string          `json:"ccy" gorm:"column:ccy;type:varchar(16)"`
+	Amount     decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
+	ImagePath  string          `json:"image_path" gorm:"column:image_path;type:varchar(512)"`
+	*PaymentProofValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli;index"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type PaymentProofValues struct {
+	Status       *string `json:"status" gorm:"column:status;type:varchar(32);index"` // pending, matched, approved, rejected
+	StatementID  *string `json:"statement_id" gorm:"column:statement_id;type:varchar(64)"`
+	ReviewedBy   *string `json:"reviewed_by" gorm:"column:reviewed_by;type:varchar(64)"`
+	ReviewRemark *string `json:"review_remark" gorm:"column:review_remark;type:varchar(512)"`
+	ReviewedAt   *int64  `json:"reviewed_at" gorm:"column:reviewed_at"`
+
+	ReceivedAmount *decimal.Decimal `json:"received_amount" gorm:"column:received_amount;type:decimal(20,8)"` // 银行流水实收金额
+}
+
+func (PaymentProof) TableName() string {
+	return "t_payment_proofs"
+}
+
+func (v *PaymentProofValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *PaymentProofValues) GetStatementID() string {
+	if v.StatementID == nil {
+		return ""
+	}
+	return *v.StatementID
+}
+
+func (v *PaymentProofValues) GetReviewedBy() string {
+	if v.ReviewedBy == nil {
+		return ""
+	}
+	return *v.ReviewedBy
+}
+
+func (v *PaymentProofValues) GetReviewRemark() string {
+	if v.ReviewRemark == nil {
+		return ""
+	}
+	return *v.ReviewRemark
+}
+
+func (v *PaymentProofValues) GetReviewedAt() int64 {
+	if v.ReviewedAt == nil {
+		return 0
+	}
+	return *v.ReviewedAt
+}
+
+// GetReceivedAmount 实收金额，未关联流水时与订单金额一致
+func (p *PaymentProof) GetReceivedAmount() decimal.Decimal {
+	if p.PaymentProofValues == nil || p.ReceivedAmount == nil {
+		return p.Amount
+	}
+	return *p.ReceivedAmount
+}
+
+func (v *PaymentProofValues) SetStatus(value string) *PaymentProofValues {
+	v.Status = &value
+	return v
+}
+
+func (v *PaymentProofValues) SetStatementID(value string) *PaymentProofValues {
+	v.StatementID = &value
+	return v
+}
+
+func (v *PaymentProofValues) SetReviewedBy(value string) *PaymentProofValues {
+	v.ReviewedBy = &value
+	return v
+}
+
+func (v *PaymentProofValues) SetReviewRemark(value string) *PaymentProofValues {
+	v.ReviewRemark = &value
+	return v
+}
+
+func (v *PaymentProofValues) SetReviewedAt(value int64) *PaymentProofValues {
+	v.ReviewedAt = &value
+	return v
+}
+
+func (v *PaymentProofValues) SetReceivedAmount(value decimal.Decimal) *PaymentProofValues {
+	v.ReceivedAmount = &value
+	return v
+}
+
+// SetValues 合并非空字段
+func (p *PaymentProof) SetValues(values *PaymentProofValues) *PaymentProof {
+	if values == nil {
+		return p
+	}
+	if p.PaymentProofValues == nil {
+		p.PaymentProofValues = &PaymentProofValues{}
+	}
+	if values.Status != nil {
+		p.SetStatus(*values.Status)
+	}
+	if values.StatementID != nil {
+		p.SetStatementID(*values.StatementID)
+	}
+	if values.ReviewedBy != nil {
+		p.SetReviewedBy(*values.ReviewedBy)
+	}
+	if values.ReviewRemark != nil {
+		p.SetReviewRemark(*values.ReviewRemark)
+	}
+	if values.ReviewedAt != nil {
+		p.SetReviewedAt(*values.ReviewedAt)
+	}
+	if values.ReceivedAmount != nil {
+		p.SetReceivedAmount(*values.ReceivedAmount)
+	}
+	return p
+}
+
+func (p *PaymentProof) Protocol() *protocol.PaymentProof {
+	info := &protocol.PaymentProof{
+		ProofID:      p.ProofID,
+		TrxID:        p.TrxID,
+		TrxType:      p.TrxType,
+		Mid:          p.Mid,
+		CheckoutID:   p.CheckoutID,
+		Tid:          p.Tid,
+		Utr:          p.Utr,
+		Ccy:          p.Ccy,
+		Amount:       p.Amount.String(),
+		HasImage:     p.ImagePath != "",
+		Status:       p.GetStatus(),
+		StatementID:  p.GetStatementID(),
+		ReviewedBy:   p.GetReviewedBy(),
+		ReviewRemark: p.GetReviewRemark(),
+		ReviewedAt:   p.GetReviewedAt(),
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+	if p.PaymentProofValues != nil && p.ReceivedAmount != nil {
+		info.ReceivedAmount = p.ReceivedAmount.String()
+	}
+	return info
+}
+
+// ActiveProofStatuses 占用UTR的凭证状态，驳回的凭证不占用
+var ActiveProofStatuses = []string{protocol.ProofStatusPending, protocol.ProofStatusMatched, protocol.ProofStatusApproved}
+
+// GetPaymentProofByID 获取支付凭证，tid为空时不限制收银团队
+func GetPaymentProofByID(tid, proofID string) *PaymentProof {
+	var proof PaymentProof
+	db := ReadDB.Where("proof_id = ?", proofID)
+	if tid != "" {
+		db = db.Where("tid = ?", tid)
+	}
+	if err := db.First(&proof).Error; err != nil {
+		return nil
+	}
+	return &proof
+}
+
+// CountActiveProofByUtr 统计其他交易占用该UTR的有效凭证数
+func CountActiveProofByUtr(utr, excludeTrxID string) int64 {
+	var count int64
+	ReadDB.Model(&PaymentProof{}).
+		Where("utr = ? AND trx_id <> ? AND status IN ?", utr, excludeTrxID, ActiveProofStatuses).
+		Count(&count)
+	return count
+}
+
+// CountPendingProofByTrxID 统计交易待审核的凭证数
+func CountPendingProofByTrxID(trxID string) int64 {
+	var count int64
+	ReadDB.Model(&PaymentProof{}).
+		Where("trx_id = ? AND status = ?", trxID, protocol.ProofStatusPending).
+		Count(&count)
+	return count
+}
+
+// ListPendingProofsByUtr 获取该UTR下待审核的凭证
+func ListPendingProofsByUtr(utr string) ([]*PaymentProof, error) {
+	var list []*PaymentProof
+	err := ReadDB.Where("utr = ? AND status = ?", utr, protocol.ProofStatusPending).
+		Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// ListPendingProofsWithStatement 获取存在未匹配银行流水的待审核凭证，已因金额差异挂起的凭证除外
+func ListPendingProofsWithStatement(afterID int64, limit int) ([]*PaymentProof, error) {
+	var list []*PaymentProof
+	err := ReadDB.Where("id > ? AND status = ?", afterID, protocol.ProofStatusPending).
+		Where("(statement_id IS NULL OR statement_id = '')").
+		Where("EXISTS (SELECT 1 FROM t_bank_statements s WHERE s.utr = t_payment_proofs.utr AND s.status = ?)", protocol.StatementStatusUnmatched).
+		Order("id asc").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// PaymentProofQuery 支付凭证查询参数
+type PaymentProofQuery struct {
+	Tid            string
+	Mid            string
+	TrxID          string
+	Utr            string
+	Status         string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+}
+
+// ListPaymentProofByQuery 分页查询支付凭证
+func ListPaymentProofByQuery(q *PaymentProofQuery) ([]*PaymentProof, int64, error) {
+	db := ReadDB.Model(&PaymentProof{})
+	if q.Tid != "" {
+		db = db.Where("tid = ?", q.Tid)
+	}
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if q.TrxID != "" {
+		db = db.Where("trx_id = ?", q.TrxID)
+	}
+	if q.Utr != "" {
+		db = db.Where("utr = ?", q.Utr)
+	}
+	if q.Status != "" {
+		db = db.Where("status = ?", q.Status)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*PaymentProof
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// UpdatePaymentProofValues 以当前状态为条件更新凭证，避免重复审核
+func UpdatePaymentProofValues(tx *gorm.DB, proof *PaymentProof, fromStatus []string, values *PaymentProofValues) (bool, error) {
+	result := tx.Model(&PaymentProof{}).
+		Where("proof_id = ? AND status IN ?", proof.ProofID, fromStatus).
+		UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	proof.SetValues(values)
+	return true, nil
+}
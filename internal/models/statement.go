@@ -0,0 +1,167 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"gorm.io/gorm"
+)
+
+// StatementSubscription 对账单邮件订阅表，每个商户/出纳团队一条，每月初发送上月各币种账户对账单
+type StatementSubscription struct {
+	ID       uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	UserID   string `json:"user_id" gorm:"column:user_id;type:varchar(32);uniqueIndex:uk_statement_subscription_user"`
+	UserType string `json:"user_type" gorm:"column:user_type;type:varchar(16);uniqueIndex:uk_statement_subscription_user"`
+	*StatementSubscriptionValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type StatementSubscriptionValues struct {
+	Status     *string   `json:"status" gorm:"column:status;type:varchar(16);index;default:'active'"` // active, inactive
+	Emails     *[]string `json:"emails" gorm:"column:emails;type:json;serializer:json"`               // 收件邮箱，为空时发送到账号邮箱
+	Formats    *[]string `json:"formats" gorm:"column:formats;type:json;serializer:json"`             // 文件格式，为空时为csv
+	Timezone   *string   `json:"timezone" gorm:"column:timezone;type:varchar(64)"`                    // 周期时区，默认UTC
+	LastPeriod *string   `json:"last_period" gorm:"column:last_period;type:varchar(8)"`               // 最近一次发送的月份，如 2026-09
+	UpdatedBy  *string   `json:"updated_by" gorm:"column:updated_by;type:varchar(64)"`
+}
+
+func (StatementSubscription) TableName() string {
+	return "t_statement_subscriptions"
+}
+
+func (v *StatementSubscriptionValues) SetStatus(status string) *StatementSubscriptionValues {
+	v.Status = &status
+	return v
+}
+
+func (v *StatementSubscriptionValues) SetEmails(emails []string) *StatementSubscriptionValues {
+	v.Emails = &emails
+	return v
+}
+
+func (v *StatementSubscriptionValues) SetFormats(formats []string) *StatementSubscriptionValues {
+	v.Formats = &formats
+	return v
+}
+
+func (v *StatementSubscriptionValues) SetTimezone(timezone string) *StatementSubscriptionValues {
+	v.Timezone = &timezone
+	return v
+}
+
+func (v *StatementSubscriptionValues) SetLastPeriod(period string) *StatementSubscriptionValues {
+	v.LastPeriod = &period
+	return v
+}
+
+func (v *StatementSubscriptionValues) SetUpdatedBy(operator string) *StatementSubscriptionValues {
+	v.UpdatedBy = &operator
+	return v
+}
+
+func (v *StatementSubscriptionValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *StatementSubscriptionValues) GetEmails() []string {
+	if v.Emails == nil {
+		return nil
+	}
+	return *v.Emails
+}
+
+func (v *StatementSubscriptionValues) GetFormats() []string {
+	if v.Formats == nil || len(*v.Formats) == 0 {
+		return []string{protocol.StatementFormatCSV}
+	}
+	return *v.Formats
+}
+
+func (v *StatementSubscriptionValues) GetTimezone() string {
+	if v.Timezone == nil || *v.Timezone == "" {
+		return "UTC"
+	}
+	return *v.Timezone
+}
+
+func (v *StatementSubscriptionValues) GetLastPeriod() string {
+	if v.LastPeriod == nil {
+		return ""
+	}
+	return *v.LastPeriod
+}
+
+// SetValues 合并非空字段
+func (s *StatementSubscription) SetValues(values *StatementSubscriptionValues) *StatementSubscription {
+	if values == nil {
+		return s
+	}
+	if s.StatementSubscriptionValues == nil {
+		s.StatementSubscriptionValues = &StatementSubscriptionValues{}
+	}
+	if values.Status != nil {
+		s.SetStatus(*values.Status)
+	}
+	if values.Emails != nil {
+		s.SetEmails(*values.Emails)
+	}
+	if values.Formats != nil {
+		s.SetFormats(*values.Formats)
+	}
+	if values.Timezone != nil {
+		s.SetTimezone(*values.Timezone)
+	}
+	if values.LastPeriod != nil {
+		s.SetLastPeriod(*values.LastPeriod)
+	}
+	if values.UpdatedBy != nil {
+		s.SetUpdatedBy(*values.UpdatedBy)
+	}
+	return s
+}
+
+func (s *StatementSubscription) Protocol() *protocol.StatementSubscription {
+	emails := s.GetEmails()
+	if emails == nil {
+		emails = []string{}
+	}
+	return &protocol.StatementSubscription{
+		Status:     s.GetStatus(),
+		Emails:     emails,
+		Formats:    s.GetFormats(),
+		Timezone:   s.GetTimezone(),
+		LastPeriod: s.GetLastPeriod(),
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+// GetStatementSubscription 获取对账单邮件订阅
+func GetStatementSubscription(db *gorm.DB, userType, userID string) *StatementSubscription {
+	var subscription StatementSubscription
+	if err := db.Where("user_type = ? AND user_id = ?", userType, userID).First(&subscription).Error; err != nil {
+		return nil
+	}
+	return &subscription
+}
+
+// ListActiveStatementSubscriptions 按主键游标分批获取启用的对账单邮件订阅
+func ListActiveStatementSubscriptions(db *gorm.DB, afterID uint64, limit int) ([]*StatementSubscription, error) {
+	var list []*StatementSubscription
+	err := db.Where("status = ? AND id > ?", protocol.StatusActive, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// UpdateStatementSubscriptionValues 更新对账单邮件订阅
+func UpdateStatementSubscriptionValues(db *gorm.DB, subscription *StatementSubscription, values *StatementSubscriptionValues) error {
+	if err := db.Model(&StatementSubscription{}).Where("id = ?", subscription.ID).Updates(values).Error; err != nil {
+		return err
+	}
+	subscription.SetValues(values)
+	return nil
+}
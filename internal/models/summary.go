@@ -114,21 +114,21 @@ func CalculatePaymentStatistics(db *gorm.DB, targetType string, startTime, endTi
 		return nil, fmt.Errorf("unknown target type: %s", targetType)
 	}
 
-	query = query.Where("created_at BETWEEN ? AND ?", startTime.Unix(), endTime.Unix())
+	query = query.Where("created_at BETWEEN ? AND ?", startTime.UnixMilli(), endTime.UnixMilli())
 
 	err := query.Select(fmt.Sprintf(`
 		%s as target,
 		COUNT(*) as %s,
 		COALESCE(SUM(usd_amount), 0) as %s,
-		COUNT(CASE WHEN status = 1 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 1 THEN usd_amount ELSE 0 END), 0) as %s,
-		COUNT(CASE WHEN status = 2 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 2 THEN usd_amount ELSE 0 END), 0) as %s,
-		COUNT(CASE WHEN status = 0 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 0 THEN usd_amount ELSE 0 END), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 1 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 2 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 0 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s
+		COUNT(CASE WHEN status = 'success' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'success' THEN usd_amount ELSE 0 END), 0) as %s,
+		COUNT(CASE WHEN status = 'failed' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'failed' THEN usd_amount ELSE 0 END), 0) as %s,
+		COUNT(CASE WHEN status = 'pending' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'pending' THEN usd_amount ELSE 0 END), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'success' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'failed' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'pending' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s
 	`, groupByField,
 		protocol.STAT_IDX_TOTAL_COUNT,
 		protocol.STAT_IDX_TOTAL_USD_AMOUNT,
@@ -167,21 +167,21 @@ func CalculateWithdrawStatistics(db *gorm.DB, targetType string, startTime, endT
 		return nil, fmt.Errorf("unknown target type: %s", targetType)
 	}
 
-	query = query.Where("created_at BETWEEN ? AND ?", startTime.Unix(), endTime.Unix())
+	query = query.Where("created_at BETWEEN ? AND ?", startTime.UnixMilli(), endTime.UnixMilli())
 
 	err := query.Select(fmt.Sprintf(`
 		%s as target,
 		COUNT(*) as %s,
 		COALESCE(SUM(usd_amount), 0) as %s,
-		COUNT(CASE WHEN status = 1 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 1 THEN usd_amount ELSE 0 END), 0) as %s,
-		COUNT(CASE WHEN status = 2 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 2 THEN usd_amount ELSE 0 END), 0) as %s,
-		COUNT(CASE WHEN status = 0 THEN 1 END) as %s,
-		COALESCE(SUM(CASE WHEN status = 0 THEN usd_amount ELSE 0 END), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 1 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 2 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
-		CAST(COUNT(CASE WHEN status = 0 THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s
+		COUNT(CASE WHEN status = 'success' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'success' THEN usd_amount ELSE 0 END), 0) as %s,
+		COUNT(CASE WHEN status = 'failed' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'failed' THEN usd_amount ELSE 0 END), 0) as %s,
+		COUNT(CASE WHEN status = 'pending' THEN 1 END) as %s,
+		COALESCE(SUM(CASE WHEN status = 'pending' THEN usd_amount ELSE 0 END), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'success' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'failed' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s,
+		CAST(COUNT(CASE WHEN status = 'pending' THEN 1 END) AS DECIMAL(20,8)) / NULLIF(COUNT(*), 0) as %s
 	`, groupByField,
 		protocol.STAT_IDX_TOTAL_COUNT,
 		protocol.STAT_IDX_TOTAL_USD_AMOUNT,
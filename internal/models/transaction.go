@@ -34,12 +34,15 @@ type Transaction struct {
 	Ccy                string           `json:"ccy" gorm:"column:ccy;<-:create"`
 	Amount             *decimal.Decimal `json:"amount" gorm:"column:amount;<-:create"`
 	UsdAmount          *decimal.Decimal `json:"usd_amount" gorm:"column:usd_amount;<-:create"`
+	UsdRate            *decimal.Decimal `json:"usd_rate" gorm:"column:usd_rate;<-:create"` // 创建时的币种兑美元汇率
 	AccountNo          string           `json:"account_no" gorm:"column:account_no;<-:create"`
 	AccountName        string           `json:"account_name" gorm:"column:account_name;<-:create"`
 	AccountType        string           `json:"account_type" gorm:"column:account_type;<-:create"`
 	BankCode           string           `json:"bank_code" gorm:"column:bank_code;<-:create"`
 	BankName           string           `json:"bank_name" gorm:"column:bank_name;<-:create"`
 	ReturnURL          string           `json:"return_url" gorm:"column:return_url;<-:create"`
+	LinkID             string           `json:"link_id" gorm:"column:link_id;<-:create"`               // 来源支付链接ID，仅商户代收
+	BeneficiaryID      string           `json:"beneficiary_id" gorm:"column:beneficiary_id;<-:create"` // 引用的收款人ID，仅商户代付
 	*TransactionValues `gorm:"embedded"`
 	CreatedAt          int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
 	UpdatedAt          int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"` // 更新时间 (毫秒时间戳)
@@ -72,7 +75,8 @@ type TransactionValues struct {
 	Detail        map[string]any `json:"detail" gorm:"column:detail;serializer:json;type:json"`
 	NotifyURL     *string        `json:"notify_url" gorm:"column:notify_url"`
 
-	ProofID *string `json:"proof_id" gorm:"column:proof_id"` // 支付凭证ID/UTR
+	ProofID        *string          `json:"proof_id" gorm:"column:proof_id"`               // 支付凭证ID/UTR
+	ReceivedAmount *decimal.Decimal `json:"received_amount" gorm:"column:received_amount"` // 实收金额，为空时与订单金额一致
 
 	// Fee related fields
 	FeeCcy       *string          `json:"fee_ccy" gorm:"column:fee_ccy"`
@@ -173,7 +177,12 @@ func (q *TrxQuery) GetLimit() int {
 
 // BuildQuery 构建查询条件
 func (q *TrxQuery) BuildQuery(db *gorm.DB) *gorm.DB {
-	db = db.Where("mid = ?", q.Mid)
+	if q.Mid != "" {
+		db = db.Where("mid = ?", q.Mid)
+	}
+	if len(q.MidList) > 0 {
+		db = db.Where("mid IN ?", q.MidList)
+	}
 	if q.CashierID != "" {
 		db = db.Where("cashier_id = ?", q.CashierID)
 	}
@@ -225,6 +234,54 @@ func (q *TrxQuery) BuildQuery(db *gorm.DB) *gorm.DB {
 	if q.CompletedAtEnd > 0 {
 		db = db.Where("completed_at <= ?", q.CompletedAtEnd)
 	}
+	if q.TrxMethod != "" {
+		db = db.Where("trx_method = ?", q.TrxMethod)
+	}
+	if len(q.TrxMethodList) > 0 {
+		db = db.Where("trx_method IN ?", q.TrxMethodList)
+	}
+	if q.TrxMode != "" {
+		db = db.Where("trx_mode = ?", q.TrxMode)
+	}
+	if len(q.TrxModeList) > 0 {
+		db = db.Where("trx_mode IN ?", q.TrxModeList)
+	}
+	if q.FlowNo != "" {
+		db = db.Where("flow_no = ?", q.FlowNo)
+	}
+	if len(q.FlowNoList) > 0 {
+		db = db.Where("flow_no IN ?", q.FlowNoList)
+	}
+	if len(q.TrxIDList) > 0 {
+		db = db.Where("trx_id IN ?", q.TrxIDList)
+	}
+	if len(q.ReqIDList) > 0 {
+		db = db.Where("req_id IN ?", q.ReqIDList)
+	}
+	if q.ChannelCode != "" {
+		db = db.Where("channel_code = ?", q.ChannelCode)
+	}
+	if len(q.ChannelCodeList) > 0 {
+		db = db.Where("channel_code IN ?", q.ChannelCodeList)
+	}
+	if q.ChannelAccount != "" {
+		db = db.Where("channel_account = ?", q.ChannelAccount)
+	}
+	if len(q.ChannelAccountList) > 0 {
+		db = db.Where("channel_account IN ?", q.ChannelAccountList)
+	}
+	if q.ChannelGroup != "" {
+		db = db.Where("channel_group = ?", q.ChannelGroup)
+	}
+	if len(q.ChannelGroupList) > 0 {
+		db = db.Where("channel_group IN ?", q.ChannelGroupList)
+	}
+	if q.ChannelTrxID != "" {
+		db = db.Where("channel_trx_id = ?", q.ChannelTrxID)
+	}
+	if len(q.ChannelTrxIDList) > 0 {
+		db = db.Where("channel_trx_id IN ?", q.ChannelTrxIDList)
+	}
 	return db
 }
 
@@ -510,6 +567,14 @@ func (tv *TransactionValues) GetProofID() string {
 	return *tv.ProofID
 }
 
+// GetReceivedAmount returns the ReceivedAmount value
+func (tv *TransactionValues) GetReceivedAmount() decimal.Decimal {
+	if tv.ReceivedAmount == nil {
+		return decimal.Zero
+	}
+	return *tv.ReceivedAmount
+}
+
 // GetVersion returns the Version value
 func (tv *TransactionValues) GetVersion() int64 {
 	if tv.Version == nil {
@@ -734,6 +799,12 @@ func (tv *TransactionValues) SetProofID(value string) *TransactionValues {
 	return tv
 }
 
+// SetReceivedAmount sets the ReceivedAmount value
+func (tv *TransactionValues) SetReceivedAmount(value decimal.Decimal) *TransactionValues {
+	tv.ReceivedAmount = &value
+	return tv
+}
+
 // SetVersion sets the Version value
 func (tv *TransactionValues) SetVersion(value int64) *TransactionValues {
 	tv.Version = &value
@@ -868,6 +939,9 @@ func (t *Transaction) SetValues(values *TransactionValues) *Transaction {
 	if values.ProofID != nil {
 		t.TransactionValues.SetProofID(*values.ProofID)
 	}
+	if values.ReceivedAmount != nil {
+		t.TransactionValues.SetReceivedAmount(*values.ReceivedAmount)
+	}
 	if values.Version != nil {
 		t.TransactionValues.SetVersion(*values.Version)
 	}
@@ -926,6 +1000,44 @@ func GetTransactionByMidAndTrxID(mid, trxID, trxType string) *Transaction {
 	return &transaction
 }
 
+// GetAmount 获取交易金额
+func (t *Transaction) GetAmount() decimal.Decimal {
+	if t.Amount == nil {
+		return decimal.Zero
+	}
+	return *t.Amount
+}
+
+// GetSettleAmount 获取结算金额，记录了实收金额时按实收金额结算
+func (t *Transaction) GetSettleAmount() decimal.Decimal {
+	if t.TransactionValues != nil && t.TransactionValues.ReceivedAmount != nil {
+		return *t.TransactionValues.ReceivedAmount
+	}
+	return t.GetAmount()
+}
+
+// GetSettleUsdAmount 获取结算美元金额，实收金额按创建时的美元汇率折算
+func (t *Transaction) GetSettleUsdAmount() decimal.Decimal {
+	if t.TransactionValues != nil && t.TransactionValues.ReceivedAmount != nil && t.UsdRate != nil {
+		return t.TransactionValues.ReceivedAmount.Mul(*t.UsdRate).Round(protocol.FxUsdPrecision)
+	}
+	if t.UsdAmount == nil {
+		return decimal.Zero
+	}
+	return *t.UsdAmount
+}
+
+// GetTransactionByTrxID 按交易ID获取交易，不限制商户或团队
+func GetTransactionByTrxID(trxID, trxType string) *Transaction {
+	var transaction Transaction
+	err := GetTransactionQueryByType(trxType).Where("trx_id = ?", trxID).First(&transaction).Error
+	if err != nil {
+		log.Get().Errorf("GetTransactionByTrxID: %v", err)
+		return nil
+	}
+	return &transaction
+}
+
 // CountTransactionByQuery 根据查询条件统计交易数量
 func CountTransactionByQuery(query *TrxQuery) int64 {
 	var count int64
@@ -957,11 +1069,41 @@ func SaveTransactionValues(db *gorm.DB, trx *Transaction, values *TransactionVal
 			trx.SetValues(values)
 		}
 	}()
-	// 执行更新
-	err = GetTransactionQueryByType(trx.TrxType).Where("trx_id=?", trx.TrxID).UpdateColumns(values).Error
+	// 执行更新，使用调用方传入的db以便参与事务
+	if table, ok := TrxTypeTableMap[trx.TrxType]; ok {
+		db = db.Table(table)
+	}
+	err = db.Where("trx_id=?", trx.TrxID).UpdateColumns(values).Error
 	return
 }
 
+// UpdateTransactionValuesWithStatus 以当前状态为条件更新交易，避免与渠道回调或其他审核并发重复处理
+func UpdateTransactionValuesWithStatus(db *gorm.DB, trx *Transaction, fromStatus []string, values *TransactionValues) (bool, error) {
+	if table, ok := TrxTypeTableMap[trx.TrxType]; ok {
+		db = db.Table(table)
+	}
+	result := db.Where("trx_id = ? AND status IN ?", trx.TrxID, fromStatus).UpdateColumns(values)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	trx.SetValues(values)
+	return true, nil
+}
+
+// CountTransactionByProofID 统计其他交易已使用该支付凭证ID/UTR的数量
+func CountTransactionByProofID(trxType, proofID, excludeTrxID string) int64 {
+	table, ok := TrxTypeTableMap[trxType]
+	if !ok {
+		return 0
+	}
+	var count int64
+	ReadDB.Table(table).Where("proof_id = ? AND trx_id <> ?", proofID, excludeTrxID).Count(&count)
+	return count
+}
+
 func (t *Transaction) Protocol() *protocol.Transaction {
 	if t == nil {
 		return nil
@@ -1007,6 +1149,10 @@ func (t *Transaction) Protocol() *protocol.Transaction {
 
 		// URL信息
 		ReturnURL: t.ReturnURL,
+		LinkID:    t.LinkID,
+
+		// 收款人信息
+		BeneficiaryID: t.BeneficiaryID,
 
 		// 时间戳
 		CreatedAt: t.CreatedAt,
@@ -1028,6 +1174,9 @@ func (t *Transaction) Protocol() *protocol.Transaction {
 	if t.UsdAmount != nil {
 		info.UsdAmount = t.UsdAmount.String()
 	}
+	if t.UsdRate != nil {
+		info.UsdRate = t.UsdRate.String()
+	}
 
 	// 安全处理 TransactionValues 字段
 	if t.TransactionValues != nil {
@@ -1044,6 +1193,10 @@ func (t *Transaction) Protocol() *protocol.Transaction {
 		// 流程信息
 		info.FlowNo = t.TransactionValues.GetFlowNo()
 		info.Link = t.TransactionValues.GetLink()
+		info.ProofID = t.TransactionValues.GetProofID()
+		if t.TransactionValues.ReceivedAmount != nil {
+			info.ReceivedAmount = t.TransactionValues.ReceivedAmount.String()
+		}
 
 		// 费用信息
 		info.FeeCcy = t.TransactionValues.GetFeeCcy()
@@ -1140,12 +1293,14 @@ func (t *Transaction) ToMerchantPayin() *MerchantPayin {
 		Ccy:         t.Ccy,
 		Amount:      t.Amount,
 		UsdAmount:   t.UsdAmount,
+		UsdRate:     t.UsdRate,
 		AccountNo:   t.AccountNo,
 		AccountName: t.AccountName,
 		AccountType: t.AccountType,
 		BankCode:    t.BankCode,
 		BankName:    t.BankName,
 		ReturnURL:   t.ReturnURL,
+		LinkID:      t.LinkID,
 		CreatedAt:   t.CreatedAt,
 		UpdatedAt:   t.UpdatedAt,
 	}
@@ -1167,6 +1322,8 @@ func (t *Transaction) ToMerchantPayin() *MerchantPayin {
 			Status:              t.TransactionValues.Status,
 			Reason:              t.TransactionValues.Reason,
 			Link:                t.TransactionValues.Link,
+			ProofID:             t.TransactionValues.ProofID,
+			ReceivedAmount:      t.TransactionValues.ReceivedAmount,
 			Detail:              t.TransactionValues.Detail,
 			NotifyURL:           t.TransactionValues.NotifyURL,
 			FeeCcy:              t.TransactionValues.FeeCcy,
@@ -1225,6 +1382,7 @@ func (t *Transaction) ToMerchantPayout() *MerchantPayout {
 		Ccy:         t.Ccy,
 		Amount:      t.Amount,
 		UsdAmount:   t.UsdAmount,
+		UsdRate:     t.UsdRate,
 		AccountNo:   t.AccountNo,
 		AccountName: t.AccountName,
 		AccountType: t.AccountType,
@@ -1279,6 +1437,7 @@ func (t *Transaction) ToMerchantPayout() *MerchantPayout {
 		}
 	}
 
+	payout.BeneficiaryID = t.BeneficiaryID
 	return payout
 }
 
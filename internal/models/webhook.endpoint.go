@@ -0,0 +1,187 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEndpoint 通知地址表，一个通知接收方可配置多个地址，按订阅的事件分别推送，每个地址使用独立的签名密钥
+type WebhookEndpoint struct {
+	ID         uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EndpointID string `json:"endpoint_id" gorm:"column:endpoint_id;type:varchar(64);uniqueIndex"`
+	UserID     string `json:"user_id" gorm:"column:user_id;type:varchar(32);index:idx_webhook_endpoint_user"`
+	UserType   string `json:"user_type" gorm:"column:user_type;type:varchar(16);index:idx_webhook_endpoint_user"`
+	*WebhookEndpointValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+}
+
+type WebhookEndpointValues struct {
+	URL         *string   `json:"url" gorm:"column:url;type:varchar(512)"`
+	Events      *[]string `json:"events" gorm:"column:events;type:json;serializer:json"` // 订阅的事件，为空表示全部
+	APIVersion  *string   `json:"api_version" gorm:"column:api_version;type:varchar(8);default:'v1'"`
+	Status      *string   `json:"status" gorm:"column:status;type:varchar(16);default:'active'"` // active, inactive
+	Description *string   `json:"description" gorm:"column:description;type:varchar(128)"`
+	UpdatedBy   *string   `json:"updated_by" gorm:"column:updated_by;type:varchar(64)"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "t_webhook_endpoints"
+}
+
+func (v *WebhookEndpointValues) SetURL(url string) *WebhookEndpointValues {
+	v.URL = &url
+	return v
+}
+
+func (v *WebhookEndpointValues) SetEvents(events []string) *WebhookEndpointValues {
+	v.Events = &events
+	return v
+}
+
+func (v *WebhookEndpointValues) SetAPIVersion(version string) *WebhookEndpointValues {
+	v.APIVersion = &version
+	return v
+}
+
+func (v *WebhookEndpointValues) SetStatus(status string) *WebhookEndpointValues {
+	v.Status = &status
+	return v
+}
+
+func (v *WebhookEndpointValues) SetDescription(description string) *WebhookEndpointValues {
+	v.Description = &description
+	return v
+}
+
+func (v *WebhookEndpointValues) SetUpdatedBy(operator string) *WebhookEndpointValues {
+	v.UpdatedBy = &operator
+	return v
+}
+
+func (v *WebhookEndpointValues) GetURL() string {
+	if v.URL == nil {
+		return ""
+	}
+	return *v.URL
+}
+
+func (v *WebhookEndpointValues) GetEvents() []string {
+	if v.Events == nil {
+		return nil
+	}
+	return *v.Events
+}
+
+func (v *WebhookEndpointValues) GetAPIVersion() string {
+	if v.APIVersion == nil || *v.APIVersion == "" {
+		return protocol.WebhookAPIVersionV1
+	}
+	return *v.APIVersion
+}
+
+func (v *WebhookEndpointValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *WebhookEndpointValues) GetDescription() string {
+	if v.Description == nil {
+		return ""
+	}
+	return *v.Description
+}
+
+// Subscribes 是否订阅了该事件，未配置事件时订阅全部
+func (v *WebhookEndpointValues) Subscribes(event string) bool {
+	events := v.GetEvents()
+	return len(events) == 0 || slices.Contains(events, event)
+}
+
+// SetValues 合并非空字段
+func (e *WebhookEndpoint) SetValues(values *WebhookEndpointValues) *WebhookEndpoint {
+	if values == nil {
+		return e
+	}
+	if e.WebhookEndpointValues == nil {
+		e.WebhookEndpointValues = &WebhookEndpointValues{}
+	}
+	if values.URL != nil {
+		e.SetURL(*values.URL)
+	}
+	if values.Events != nil {
+		e.SetEvents(*values.Events)
+	}
+	if values.APIVersion != nil {
+		e.SetAPIVersion(*values.APIVersion)
+	}
+	if values.Status != nil {
+		e.SetStatus(*values.Status)
+	}
+	if values.Description != nil {
+		e.SetDescription(*values.Description)
+	}
+	if values.UpdatedBy != nil {
+		e.SetUpdatedBy(*values.UpdatedBy)
+	}
+	return e
+}
+
+func (e *WebhookEndpoint) Protocol() *protocol.WebhookEndpoint {
+	events := e.GetEvents()
+	if events == nil {
+		events = []string{}
+	}
+	return &protocol.WebhookEndpoint{
+		EndpointID:  e.EndpointID,
+		URL:         e.GetURL(),
+		Events:      events,
+		APIVersion:  e.GetAPIVersion(),
+		Status:      e.GetStatus(),
+		Description: e.GetDescription(),
+		CreatedAt:   e.CreatedAt,
+		UpdatedAt:   e.UpdatedAt,
+	}
+}
+
+// GetUserWebhookEndpoint 获取通知接收方的通知地址
+func GetUserWebhookEndpoint(db *gorm.DB, userType, userID, endpointID string) *WebhookEndpoint {
+	var endpoint WebhookEndpoint
+	err := db.Where("user_type = ? AND user_id = ? AND endpoint_id = ?", userType, userID, endpointID).
+		First(&endpoint).Error
+	if err != nil {
+		return nil
+	}
+	return &endpoint
+}
+
+// ListUserWebhookEndpoints 获取通知接收方的全部通知地址，status为空时不限状态
+func ListUserWebhookEndpoints(db *gorm.DB, userType, userID, status string) ([]*WebhookEndpoint, error) {
+	var list []*WebhookEndpoint
+	db = db.Where("user_type = ? AND user_id = ?", userType, userID)
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	err := db.Order("id asc").Find(&list).Error
+	return list, err
+}
+
+// CountUserWebhookEndpoints 统计通知接收方的通知地址数
+func CountUserWebhookEndpoints(db *gorm.DB, userType, userID string) (int64, error) {
+	var count int64
+	err := db.Model(&WebhookEndpoint{}).Where("user_type = ? AND user_id = ?", userType, userID).Count(&count).Error
+	return count, err
+}
+
+// UpdateWebhookEndpointValues 更新通知地址配置
+func UpdateWebhookEndpointValues(db *gorm.DB, endpoint *WebhookEndpoint, values *WebhookEndpointValues) error {
+	if err := db.Model(&WebhookEndpoint{}).Where("id = ?", endpoint.ID).Updates(values).Error; err != nil {
+		return err
+	}
+	endpoint.SetValues(values)
+	return nil
+}
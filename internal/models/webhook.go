@@ -1,7 +1,10 @@
 package models
 
 import (
+	"inpayos/internal/protocol"
+
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 // Webhook Webhook通知记录表
@@ -19,7 +22,7 @@ type WebhookValues struct {
 	TransactionID *string          `json:"trx_id" gorm:"column:trx_id;type:varchar(64);index"`
 	BillID        *string          `json:"bill_id" gorm:"column:bill_id;type:varchar(64);index"`
 	Type          *string          `json:"type" gorm:"column:type;type:varchar(16);index"`
-	Status        *string          `json:"status" gorm:"column:status;type:varchar(16);index;default:'pending'"`
+	Status        *string          `json:"status" gorm:"column:status;type:varchar(32);index;default:'pending'"`
 	Amount        *decimal.Decimal `json:"amount" gorm:"column:amount;type:decimal(20,8)"`
 	Fee           *decimal.Decimal `json:"fee" gorm:"column:fee;type:decimal(20,8)"`
 	Ccy           *string          `json:"ccy" gorm:"column:ccy;type:varchar(8);index"`
@@ -33,6 +36,12 @@ type WebhookValues struct {
 	ResponseBody  *string          `json:"response_body" gorm:"column:response_body;type:text"`
 	RequestBody   *string          `json:"request_body" gorm:"column:request_body;type:text"`
 	Remark        *string          `json:"remark" gorm:"column:remark;type:varchar(512)"`
+
+	ReceivedAmount *decimal.Decimal `json:"received_amount" gorm:"column:received_amount;type:decimal(20,8)"` // 实收金额，Amount为订单金额
+	LinkID         *string          `json:"link_id" gorm:"column:link_id;type:varchar(64)"`                   // 来源支付链接ID
+
+	EndpointID *string `json:"endpoint_id" gorm:"column:endpoint_id;type:varchar(64);index;default:''"` // 通知地址ID，为空表示默认通知地址
+	APIVersion *string `json:"api_version" gorm:"column:api_version;type:varchar(8);default:'v1'"`      // 推送内容版本
 }
 
 // 表名
@@ -81,6 +90,26 @@ func (v *WebhookValues) SetFee(fee decimal.Decimal) *WebhookValues {
 	return v
 }
 
+func (v *WebhookValues) SetReceivedAmount(amount decimal.Decimal) *WebhookValues {
+	v.ReceivedAmount = &amount
+	return v
+}
+
+func (v *WebhookValues) SetLinkID(linkID string) *WebhookValues {
+	v.LinkID = &linkID
+	return v
+}
+
+func (v *WebhookValues) SetEndpointID(endpointID string) *WebhookValues {
+	v.EndpointID = &endpointID
+	return v
+}
+
+func (v *WebhookValues) SetAPIVersion(version string) *WebhookValues {
+	v.APIVersion = &version
+	return v
+}
+
 func (v *WebhookValues) SetCcy(currency string) *WebhookValues {
 	v.Ccy = &currency
 	return v
@@ -193,6 +222,36 @@ func (v *WebhookValues) GetFee() decimal.Decimal {
 	return *v.Fee
 }
 
+// GetReceivedAmount 实收金额，未记录时与订单金额一致
+func (v *WebhookValues) GetReceivedAmount() decimal.Decimal {
+	if v.ReceivedAmount == nil {
+		return v.GetAmount()
+	}
+	return *v.ReceivedAmount
+}
+
+func (v *WebhookValues) GetLinkID() string {
+	if v.LinkID == nil {
+		return ""
+	}
+	return *v.LinkID
+}
+
+func (v *WebhookValues) GetEndpointID() string {
+	if v.EndpointID == nil {
+		return ""
+	}
+	return *v.EndpointID
+}
+
+// GetAPIVersion 推送内容版本，历史记录未记录时为v1
+func (v *WebhookValues) GetAPIVersion() string {
+	if v.APIVersion == nil || *v.APIVersion == "" {
+		return protocol.WebhookAPIVersionV1
+	}
+	return *v.APIVersion
+}
+
 func (v *WebhookValues) GetCcy() string {
 	if v.Ccy == nil {
 		return ""
@@ -269,3 +328,221 @@ func (v *WebhookValues) GetRemark() string {
 	}
 	return *v.Remark
 }
+
+// CreateWebhook 创建待推送的Webhook通知记录
+func CreateWebhook(db *gorm.DB, webhook *Webhook) error {
+	return db.Create(webhook).Error
+}
+
+// ListDueWebhooks 获取已到推送时间的待推送通知
+func ListDueWebhooks(now int64, limit int) ([]*Webhook, error) {
+	var list []*Webhook
+	err := ReadDB.Where("notify_status = ? AND next_notify_at <= ?", protocol.StatusPending, now).
+		Order("next_notify_at asc, id asc").
+		Limit(limit).
+		Find(&list).Error
+	return list, err
+}
+
+// ClaimWebhook 推送前将下次推送时间顺延至leaseUntil，避免并发重复推送，返回是否抢占成功
+func ClaimWebhook(db *gorm.DB, webhook *Webhook, leaseUntil int64) (bool, error) {
+	result := db.Model(&Webhook{}).
+		Where("id = ? AND notify_status = ? AND next_notify_at = ?", webhook.ID, protocol.StatusPending, webhook.GetNextNotifyAt()).
+		UpdateColumn("next_notify_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	webhook.SetNextNotifyAt(leaseUntil)
+	return true, nil
+}
+
+// UpdateWebhookValues 更新通知推送结果
+func UpdateWebhookValues(db *gorm.DB, webhook *Webhook, values *WebhookValues) error {
+	if err := db.Model(&Webhook{}).Where("id = ?", webhook.ID).UpdateColumns(values).Error; err != nil {
+		return err
+	}
+	webhook.SetValues(values)
+	return nil
+}
+
+// SetValues 合并非空字段
+func (w *Webhook) SetValues(values *WebhookValues) *Webhook {
+	if values == nil {
+		return w
+	}
+	if w.WebhookValues == nil {
+		w.WebhookValues = &WebhookValues{}
+	}
+	if values.NotifyStatus != nil {
+		w.SetNotifyStatus(*values.NotifyStatus)
+	}
+	if values.NotifyTimes != nil {
+		w.SetNotifyTimes(*values.NotifyTimes)
+	}
+	if values.MaxRetryTimes != nil {
+		w.SetMaxRetryTimes(*values.MaxRetryTimes)
+	}
+	if values.NextNotifyAt != nil {
+		w.SetNextNotifyAt(*values.NextNotifyAt)
+	}
+	if values.LastNotifyAt != nil {
+		w.SetLastNotifyAt(*values.LastNotifyAt)
+	}
+	if values.ResponseCode != nil {
+		w.SetResponseCode(*values.ResponseCode)
+	}
+	if values.ResponseBody != nil {
+		w.SetResponseBody(*values.ResponseBody)
+	}
+	if values.Remark != nil {
+		w.SetRemark(*values.Remark)
+	}
+	return w
+}
+
+// Protocol 转换为商户可见的通知推送记录
+func (w *Webhook) Protocol() *protocol.WebhookDelivery {
+	return &protocol.WebhookDelivery{
+		WebhookID:     w.WebhookID,
+		Event:         w.GetType(),
+		TrxID:         w.GetTransactionID(),
+		BillID:        w.GetBillID(),
+		Status:        w.GetStatus(),
+		Amount:        w.GetAmount().String(),
+		Ccy:           w.GetCcy(),
+		NotifyURL:     w.GetNotifyURL(),
+		NotifyStatus:  w.GetNotifyStatus(),
+		NotifyTimes:   w.GetNotifyTimes(),
+		MaxRetryTimes: w.GetMaxRetryTimes(),
+		NextNotifyAt:  w.GetNextNotifyAt(),
+		LastNotifyAt:  w.GetLastNotifyAt(),
+		ResponseCode:  w.GetResponseCode(),
+		ResponseBody:  w.GetResponseBody(),
+		RequestBody:   w.GetRequestBody(),
+		Remark:        w.GetRemark(),
+		CreatedAt:     w.CreatedAt,
+		EndpointID:    w.GetEndpointID(),
+		APIVersion:    w.GetAPIVersion(),
+	}
+}
+
+// WebhookQuery 通知记录查询参数
+type WebhookQuery struct {
+	UserType       string
+	UserID         string
+	WebhookID      string
+	TrxID          string
+	Type           string
+	NotifyStatus   string
+	CreatedAtStart int64
+	CreatedAtEnd   int64
+	Page           int
+	Size           int
+
+	EndpointID string // protocol.WebhookEndpointDefault表示默认通知地址
+}
+
+// ListWebhookByQuery 分页查询通知记录
+func ListWebhookByQuery(q *WebhookQuery) ([]*Webhook, int64, error) {
+	db := ReadDB.Model(&Webhook{}).Where("user_type = ? AND user_id = ?", q.UserType, q.UserID)
+	if q.WebhookID != "" {
+		db = db.Where("webhook_id = ?", q.WebhookID)
+	}
+	if q.TrxID != "" {
+		db = db.Where("trx_id = ?", q.TrxID)
+	}
+	if q.Type != "" {
+		db = db.Where("type = ?", q.Type)
+	}
+	if q.NotifyStatus != "" {
+		db = db.Where("notify_status = ?", q.NotifyStatus)
+	}
+	switch q.EndpointID {
+	case "":
+	case protocol.WebhookEndpointDefault:
+		db = db.Where("(endpoint_id = '' OR endpoint_id IS NULL)")
+	default:
+		db = db.Where("endpoint_id = ?", q.EndpointID)
+	}
+	if q.CreatedAtStart > 0 {
+		db = db.Where("created_at >= ?", q.CreatedAtStart)
+	}
+	if q.CreatedAtEnd > 0 {
+		db = db.Where("created_at <= ?", q.CreatedAtEnd)
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var list []*Webhook
+	err := db.Order("created_at desc").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&list).Error
+	return list, total, err
+}
+
+// GetUserWebhook 获取通知接收方的通知记录
+func GetUserWebhook(userType, userID, webhookID string) *Webhook {
+	var webhook Webhook
+	err := ReadDB.Where("user_type = ? AND user_id = ? AND webhook_id = ?", userType, userID, webhookID).
+		First(&webhook).Error
+	if err != nil {
+		return nil
+	}
+	return &webhook
+}
+
+// ListUserWebhooksByIDs 批量获取通知接收方的通知记录
+func ListUserWebhooksByIDs(db *gorm.DB, userType, userID string, webhookIDs []string) ([]*Webhook, error) {
+	var list []*Webhook
+	err := db.Where("user_type = ? AND user_id = ? AND webhook_id IN ?", userType, userID, webhookIDs).
+		Find(&list).Error
+	return list, err
+}
+
+// WebhookAttempt 通知推送记录表，每次推送请求一条
+type WebhookAttempt struct {
+	ID           uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	WebhookID    string `json:"webhook_id" gorm:"column:webhook_id;type:varchar(64);index"`
+	Attempt      int32  `json:"attempt" gorm:"column:attempt"` // 第几次推送
+	NotifyURL    string `json:"notify_url" gorm:"column:notify_url;type:varchar(512)"`
+	Status       string `json:"status" gorm:"column:status;type:varchar(16)"` // success, failed
+	ResponseCode string `json:"response_code" gorm:"column:response_code;type:varchar(16)"`
+	ResponseBody string `json:"response_body" gorm:"column:response_body;type:text"`
+	Error        string `json:"error" gorm:"column:error;type:varchar(512)"` // 网络错误或超时
+	Duration     int64  `json:"duration" gorm:"column:duration"`             // 请求耗时，毫秒
+	CreatedAt    int64  `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+
+	RequestHeaders protocol.MapData `json:"request_headers" gorm:"column:request_headers;type:json;serializer:json"` // 含签名的请求头，请求体见通知记录
+}
+
+func (WebhookAttempt) TableName() string {
+	return "t_webhook_attempts"
+}
+
+// CreateWebhookAttempt 记录一次推送请求
+func CreateWebhookAttempt(db *gorm.DB, attempt *WebhookAttempt) error {
+	return db.Create(attempt).Error
+}
+
+// ListWebhookAttempts 获取通知的全部推送记录，按推送顺序
+func ListWebhookAttempts(webhookID string) ([]*WebhookAttempt, error) {
+	var list []*WebhookAttempt
+	err := ReadDB.Where("webhook_id = ?", webhookID).Order("id asc").Find(&list).Error
+	return list, err
+}
+
+func (a *WebhookAttempt) Protocol() *protocol.WebhookAttemptLog {
+	return &protocol.WebhookAttemptLog{
+		Attempt:        a.Attempt,
+		NotifyURL:      a.NotifyURL,
+		Status:         a.Status,
+		RequestHeaders: a.RequestHeaders,
+		ResponseCode:   a.ResponseCode,
+		ResponseBody:   a.ResponseBody,
+		Error:          a.Error,
+		Duration:       a.Duration,
+		CreatedAt:      a.CreatedAt,
+	}
+}
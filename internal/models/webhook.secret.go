@@ -0,0 +1,95 @@
+package models
+
+import (
+	"inpayos/internal/protocol"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSecret 通知签名密钥表，与API密钥分离，轮换时旧密钥在过渡期内继续参与签名
+type WebhookSecret struct {
+	ID       uint64 `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	SecretID string `json:"secret_id" gorm:"column:secret_id;type:varchar(64);uniqueIndex"`
+	UserID   string `json:"user_id" gorm:"column:user_id;type:varchar(32);index:idx_webhook_secret_user"`
+	UserType string `json:"user_type" gorm:"column:user_type;type:varchar(16);index:idx_webhook_secret_user"`
+	Secret   string `json:"secret" gorm:"column:secret;type:varchar(128)"`
+	*WebhookSecretValues
+	CreatedAt int64 `json:"created_at" gorm:"column:created_at;autoCreateTime:milli"`
+	UpdatedAt int64 `json:"updated_at" gorm:"column:updated_at;autoUpdateTime:milli"`
+
+	EndpointID string `json:"endpoint_id" gorm:"column:endpoint_id;type:varchar(64);not null;default:'';index"` // 通知地址ID，为空表示默认通知地址的密钥
+}
+
+type WebhookSecretValues struct {
+	Status    *string `json:"status" gorm:"column:status;type:varchar(16);default:'active'"` // active, inactive
+	ExpiresAt *int64  `json:"expires_at" gorm:"column:expires_at;default:0"`                 // 轮换过渡期结束时间，0表示长期有效
+	RotatedBy *string `json:"rotated_by" gorm:"column:rotated_by;type:varchar(64)"`          // 生成该密钥的操作人
+}
+
+func (WebhookSecret) TableName() string {
+	return "t_webhook_secrets"
+}
+
+func (v *WebhookSecretValues) SetStatus(status string) *WebhookSecretValues {
+	v.Status = &status
+	return v
+}
+
+func (v *WebhookSecretValues) SetExpiresAt(expiresAt int64) *WebhookSecretValues {
+	v.ExpiresAt = &expiresAt
+	return v
+}
+
+func (v *WebhookSecretValues) SetRotatedBy(operator string) *WebhookSecretValues {
+	v.RotatedBy = &operator
+	return v
+}
+
+func (v *WebhookSecretValues) GetStatus() string {
+	if v.Status == nil {
+		return ""
+	}
+	return *v.Status
+}
+
+func (v *WebhookSecretValues) GetExpiresAt() int64 {
+	if v.ExpiresAt == nil {
+		return 0
+	}
+	return *v.ExpiresAt
+}
+
+func (v *WebhookSecretValues) GetRotatedBy() string {
+	if v.RotatedBy == nil {
+		return ""
+	}
+	return *v.RotatedBy
+}
+
+func (s *WebhookSecret) Protocol() *protocol.WebhookSecret {
+	return &protocol.WebhookSecret{
+		SecretID:  s.SecretID,
+		Secret:    s.Secret,
+		Status:    s.GetStatus(),
+		ExpiresAt: s.GetExpiresAt(),
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// ListActiveWebhookSecrets 获取通知地址当前参与签名的密钥，最新的在前
+func ListActiveWebhookSecrets(db *gorm.DB, userType, userID, endpointID string, now int64) ([]*WebhookSecret, error) {
+	var list []*WebhookSecret
+	err := db.Where("user_type = ? AND user_id = ? AND endpoint_id = ? AND status = ? AND (expires_at = 0 OR expires_at > ?)",
+		userType, userID, endpointID, protocol.StatusActive, now).
+		Order("id desc").
+		Find(&list).Error
+	return list, err
+}
+
+// ExpireWebhookSecrets 将仍长期有效或过期时间晚于expiresAt的密钥截止到expiresAt，用于轮换
+func ExpireWebhookSecrets(db *gorm.DB, userType, userID, endpointID string, expiresAt int64) error {
+	return db.Model(&WebhookSecret{}).
+		Where("user_type = ? AND user_id = ? AND endpoint_id = ? AND status = ? AND (expires_at = 0 OR expires_at > ?)",
+			userType, userID, endpointID, protocol.StatusActive, expiresAt).
+		UpdateColumn("expires_at", expiresAt).Error
+}
@@ -16,6 +16,11 @@ const (
 	DirectionOut = "out" // 出账
 )
 
+// 资金流水类型
+const (
+	FlowTypeAdjust = "adjust" // 调账
+)
+
 var (
 	AccountDirectionMap = map[string]string{
 		TrxTypePayin:         DirectionIn,
@@ -35,13 +40,17 @@ var (
 		TrxTypeDividend:      DirectionIn,
 		TrxTypeRfRecover:     DirectionOut,
 		TrxTypeWdRecover:     DirectionIn,
+		TrxTypeConvertOut:    DirectionOut,
+		TrxTypeConvertIn:     DirectionIn,
+		TrxTypeRsvHold:       DirectionOut,
+		TrxTypeRsvRelease:    DirectionIn,
 	}
 )
 
 // 账户相关请求/响应
 type CreateAccountRequest struct {
 	UserID   string `json:"user_id" binding:"required"`
-	UserType string `json:"user_type" binding:"required,oneof=merchant cashier bank"`
+	UserType string `json:"user_type" binding:"required,oneof=merchant cashier cashier_team bank"`
 	Ccy      string `json:"ccy" binding:"required"`
 }
 
@@ -54,6 +63,15 @@ type UpdateBalanceRequest struct {
 	TrxType     string          `json:"trx_type"`
 	ReqID       string          `json:"req_id"`
 	Description string          `json:"description"`
+	Direction   string          `json:"direction"`   // 调账方向: in-加款, out-扣款，仅调账使用
+	FlowType    string          `json:"flow_type"`   // 资金流水类型
+	OperatorID  string          `json:"operator_id"` // 操作人ID
+	OriFlowNo   string          `json:"ori_flow_no"` // 关联的原始流水号，同一业务的多笔流水互相关联时使用
+
+	ToUserID   string `json:"to_user_id"`   // 转入方用户ID，仅内部转账使用
+	ToUserType string `json:"to_user_type"` // 转入方用户类型，仅内部转账使用
+
+	Fee decimal.Decimal `json:"fee"` // 手续费，仅代收/充值入账使用，由渠道清算账户计入手续费收入
 }
 
 type Assert struct {
@@ -63,6 +81,7 @@ type Assert struct {
 	MarginBalance          string `json:"margin_balance"`
 	AvailableMarginBalance string `json:"available_margin_balance"`
 	FrozenMarginBalance    string `json:"frozen_margin_balance"`
+	ReserveBalance         string `json:"reserve_balance"`
 	Ccy                    string `json:"ccy"`
 	UpdatedAt              int64  `json:"updated_at"`
 }
@@ -114,4 +133,6 @@ type FundFlow struct {
 	OperatorId     string          `json:"operator_id"`     // 操作人ID
 	CreatedAt      int64           `json:"created_at"`
 	UpdatedAt      int64           `json:"updated_at"`
+
+	EntryID string `json:"entry_id"` // 记账凭证ID
 }
@@ -0,0 +1,38 @@
+package protocol
+
+// 人工调整审批参数字段
+const (
+	AdjustPayloadTrxType    = "trx_type"
+	AdjustPayloadFromStatus = "from_status"
+	AdjustPayloadStatus     = "status"
+	AdjustPayloadUserID     = "user_id"
+	AdjustPayloadUserType   = "user_type"
+	AdjustPayloadDirection  = "direction"
+)
+
+// TrxStatusOverrideRequest 交易状态人工修改申请，代收由结算入账，代付按终态解冻或扣款，余额修正使用调账申请
+type TrxStatusOverrideRequest struct {
+	TrxID   string `json:"trx_id" binding:"required"`
+	TrxType string `json:"trx_type" binding:"required,oneof=payin payout cashier_payin cashier_payout"`
+	Status  string `json:"status" binding:"required,oneof=success failed"` // 目标状态
+	Reason  string `json:"reason" binding:"required"`                      // 修改原因
+	Code    string `json:"code" binding:"required"`                        // 发起人G2FA验证码
+}
+
+// BalanceAdjustRequest 账户调账申请
+type BalanceAdjustRequest struct {
+	UserID    string `json:"user_id" binding:"required"`
+	UserType  string `json:"user_type" binding:"required,oneof=merchant cashier cashier_team"`
+	Ccy       string `json:"ccy" binding:"required"`
+	Direction string `json:"direction" binding:"required,oneof=in out"` // in-加款, out-扣款
+	Amount    string `json:"amount" binding:"required"`
+	Reason    string `json:"reason" binding:"required"` // 调账原因
+	Code      string `json:"code" binding:"required"`   // 发起人G2FA验证码
+}
+
+// AdjustmentDecisionRequest 人工调整复核请求
+type AdjustmentDecisionRequest struct {
+	ApprovalID string `json:"approval_id" binding:"required"`
+	Code       string `json:"code" binding:"required"`   // 复核人G2FA验证码
+	Reason     string `json:"reason" binding:"required"` // 审批意见
+}
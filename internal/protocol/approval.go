@@ -0,0 +1,53 @@
+package protocol
+
+// 审批业务类型
+const (
+	ApprovalBizTypePayout            = "payout"              // 大额代付复核
+	ApprovalBizTypeTrxStatusOverride = "trx_status_override" // 交易状态人工修改
+	ApprovalBizTypeBalanceAdjust     = "balance_adjust"      // 账户人工调账
+	ApprovalBizTypeTransfer          = "internal_transfer"   // 内部转账
+)
+
+// 代付复核风险标记
+const (
+	ApprovalFlagOverThreshold  = "over_threshold"   // 超过复核阈值
+	ApprovalFlagNewAccount     = "new_account"      // 首次向该收款账户代付
+	ApprovalFlagOverDailyLimit = "over_daily_limit" // 超过当日限额
+)
+
+// Approval 审批记录
+type Approval struct {
+	ApprovalID     string   `json:"approval_id"`
+	Mid            string   `json:"mid"`
+	BizType        string   `json:"biz_type"`
+	BizID          string   `json:"biz_id"`
+	Status         string   `json:"status"`
+	Ccy            string   `json:"ccy"`
+	Amount         string   `json:"amount"`
+	RiskFlags      []string `json:"risk_flags"`
+	RequestedBy    string   `json:"requested_by"`
+	RequestReason  string   `json:"request_reason,omitempty"`
+	ApprovedBy     string   `json:"approved_by,omitempty"`
+	DecisionReason string   `json:"decision_reason,omitempty"`
+	DecidedAt      int64    `json:"decided_at,omitempty"`
+	CreatedAt      int64    `json:"created_at"`
+	UpdatedAt      int64    `json:"updated_at"`
+}
+
+// ApprovalListRequest 审批列表请求
+type ApprovalListRequest struct {
+	BizType        string `json:"biz_type"`                     // 业务类型
+	BizID          string `json:"biz_id"`                       // 业务ID
+	Status         string `json:"status"`                       // 审批状态
+	CreatedAtStart int64  `json:"created_at_start"`             // 开始时间
+	CreatedAtEnd   int64  `json:"created_at_end"`               // 结束时间
+	Page           int    `json:"page" binding:"min=1"`         // 页码
+	Size           int    `json:"size" binding:"min=1,max=100"` // 每页记录数
+}
+
+// ApprovalDecisionRequest 审批通过/驳回请求，复核员为当前登录的子账号
+type ApprovalDecisionRequest struct {
+	ApprovalID string `json:"approval_id" binding:"required"`
+	Code       string `json:"code" binding:"required"`   // 复核员G2FA验证码
+	Reason     string `json:"reason" binding:"required"` // 审批意见
+}
@@ -0,0 +1,48 @@
+package protocol
+
+// 银行目录导入类型与编码类型
+const (
+	BankDirectoryBanks = "banks" // 各国银行编码，列顺序：country,bank_code,bank_name,short_name
+	BankDirectoryIFSC  = "ifsc"  // 印度IFSC分行，列顺序：ifsc,bank_name,branch,address,city,state
+	BankDirectorySwift = "swift" // SWIFT/BIC，列顺序：swift_code,bank_name,country,city,branch
+)
+
+// BankInfo 银行目录条目，code_type为banks时code即银行编码
+type BankInfo struct {
+	CodeType  string `json:"code_type"` // banks, ifsc, swift
+	Code      string `json:"code"`      // 规范化后的银行编码、IFSC或SWIFT/BIC
+	Country   string `json:"country"`
+	BankCode  string `json:"bank_code"`
+	BankName  string `json:"bank_name"`
+	ShortName string `json:"short_name,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Address   string `json:"address,omitempty"`
+	City      string `json:"city,omitempty"`
+	State     string `json:"state,omitempty"`
+}
+
+// BankLookupRequest 按编码查询银行请求，国家为空时按币种推断
+type BankLookupRequest struct {
+	Code    string `json:"code" binding:"required,max=34"`
+	Country string `json:"country"` // ISO 3166 alpha-3
+	Ccy     string `json:"ccy"`
+}
+
+// BankSearchRequest 银行目录搜索请求
+type BankSearchRequest struct {
+	CodeType string `json:"code_type"` // banks, ifsc, swift，默认banks
+	Country  string `json:"country"`
+	BankCode string `json:"bank_code"` // 查询分行时按银行编码筛选
+	City     string `json:"city"`
+	Keyword  string `json:"keyword"` // 按名称、分行或编码模糊查询
+	Page     int    `json:"page" binding:"min=1"`
+	Size     int    `json:"size" binding:"min=1,max=100"`
+}
+
+// BankImportResult 银行目录导入结果
+type BankImportResult struct {
+	Type            string `json:"type"`
+	Total           int    `json:"total"`            // 有效行数
+	Imported        int64  `json:"imported"`         // 新增或更新的条目数
+	CashiersUpdated int    `json:"cashiers_updated"` // 按目录规范化银行编码与名称的出纳员卡数
+}
@@ -0,0 +1,119 @@
+package protocol
+
+// 收款人账户类型
+const (
+	BeneficiaryAccountBank = "bank_account" // 本地银行账户，按国家校验账号与银行编码
+	BeneficiaryAccountIBAN = "iban"         // 国际银行账号，银行编码为SWIFT/BIC
+	BeneficiaryAccountUPI  = "upi"          // UPI VPA，仅限印度
+)
+
+// 收款人账户验证状态
+const (
+	VerifyStatusUnverified = "unverified" // 未验证
+	VerifyStatusPending    = "pending"    // 验证中
+	VerifyStatusVerified   = "verified"   // 验证通过
+	VerifyStatusFailed     = "failed"     // 验证失败
+)
+
+// 收款人变更审计动作
+const (
+	BeneficiaryActionCreate     = "create"
+	BeneficiaryActionUpdate     = "update"
+	BeneficiaryActionVerify     = "verify"
+	BeneficiaryActionDeactivate = "deactivate"
+)
+
+// 收款人变更来源
+const (
+	BeneficiarySourcePortal  = "portal"  // 商户后台
+	BeneficiarySourceOpenAPI = "openapi" // 商户API
+)
+
+// Beneficiary 收款人信息，账号仅展示掩码
+type Beneficiary struct {
+	BeneficiaryID string `json:"beneficiary_id"`
+	Mid           string `json:"mid,omitempty"`
+	Alias         string `json:"alias,omitempty"` // 商户自定义备注名
+	Country       string `json:"country"`
+	Ccy           string `json:"ccy"`
+	AccountType   string `json:"account_type"`
+	AccountNo     string `json:"account_no"` // 掩码后的账号
+	AccountName   string `json:"account_name"`
+	BankCode      string `json:"bank_code,omitempty"` // IFSC、SWIFT/BIC、ABA、Sort Code等
+	BankName      string `json:"bank_name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	Status        string `json:"status"`        // active, inactive
+	VerifyStatus  string `json:"verify_status"` // unverified, pending, verified, failed
+	VerifiedName  string `json:"verified_name,omitempty"`
+	VerifyMsg     string `json:"verify_msg,omitempty"`
+	VerifiedAt    int64  `json:"verified_at,omitempty"`
+	LastUsedAt    int64  `json:"last_used_at,omitempty"`
+	DeactivatedAt int64  `json:"deactivated_at,omitempty"`
+	CreatedAt     int64  `json:"created_at"`
+	UpdatedAt     int64  `json:"updated_at,omitempty"`
+}
+
+// CreateBeneficiaryRequest 创建收款人请求
+type CreateBeneficiaryRequest struct {
+	Alias       string `json:"alias" binding:"max=64"`
+	Country     string `json:"country" binding:"required,len=3"` // ISO 3166 alpha-3
+	Ccy         string `json:"ccy" binding:"required"`
+	AccountType string `json:"account_type" binding:"required"`
+	AccountNo   string `json:"account_no" binding:"required,max=64"`
+	AccountName string `json:"account_name" binding:"required,max=128"`
+	BankCode    string `json:"bank_code" binding:"max=32"`
+	BankName    string `json:"bank_name" binding:"max=128"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Phone       string `json:"phone" binding:"max=32"`
+	Verify      bool   `json:"verify"` // 创建后立即通过渠道进行小额验证
+}
+
+// UpdateBeneficiaryRequest 修改收款人请求，账户信息不可修改，需停用后重新创建
+type UpdateBeneficiaryRequest struct {
+	BeneficiaryID string  `json:"beneficiary_id" binding:"required"`
+	Alias         *string `json:"alias" binding:"omitempty,max=64"`
+	Email         *string `json:"email" binding:"omitempty,max=128"`
+	Phone         *string `json:"phone" binding:"omitempty,max=32"`
+}
+
+// BeneficiaryListRequest 收款人列表请求
+type BeneficiaryListRequest struct {
+	Status       string `json:"status"`
+	VerifyStatus string `json:"verify_status"`
+	Ccy          string `json:"ccy"`
+	Keyword      string `json:"keyword"` // 按备注名或收款人姓名模糊查询
+	Page         int    `json:"page" binding:"min=1"`
+	Size         int    `json:"size" binding:"min=1,max=100"`
+}
+
+// BeneficiaryRequest 按收款人ID操作的请求
+type BeneficiaryRequest struct {
+	BeneficiaryID string `json:"beneficiary_id" binding:"required"`
+}
+
+// BeneficiaryAuditListRequest 收款人变更记录查询请求
+type BeneficiaryAuditListRequest struct {
+	BeneficiaryID string `json:"beneficiary_id" binding:"required"`
+	Page          int    `json:"page" binding:"min=1"`
+	Size          int    `json:"size" binding:"min=1,max=100"`
+}
+
+// BeneficiaryAudit 收款人变更记录
+type BeneficiaryAudit struct {
+	BeneficiaryID string  `json:"beneficiary_id"`
+	Action        string  `json:"action"`   // create, update, verify, deactivate
+	Operator      string  `json:"operator"` // 操作商户ID
+	Source        string  `json:"source"`   // portal, openapi
+	Changes       MapData `json:"changes,omitempty"`
+	Remark        string  `json:"remark,omitempty"`
+	CreatedAt     int64   `json:"created_at"`
+}
+
+// AccountVerifyResult 渠道账户验证结果
+type AccountVerifyResult struct {
+	Status       string `json:"status"`        // verified, failed, pending
+	VerifiedName string `json:"verified_name"` // 银行返回的户名
+	ChannelTrxID string `json:"channel_trx_id"`
+	ResMsg       string `json:"res_msg"`
+}
@@ -0,0 +1,93 @@
+package protocol
+
+// 车队状态
+const (
+	CashierTeamStatusOffboarded = "offboarded" // 已下线，不再受理代收，保证金待释放
+)
+
+// 车队保证金任务处理器
+const (
+	CashierMarginRelease = "cashier.margin.release" // 已下线车队的保证金释放
+)
+
+// CashierMarginDeposit 车队保证金充值记录
+type CashierMarginDeposit struct {
+	TrxID       string `json:"trx_id"`
+	Tid         string `json:"tid"`
+	AccountID   string `json:"account_id"`
+	Status      string `json:"status"` // pending, success, rejected, cancelled
+	Ccy         string `json:"ccy"`
+	Amount      string `json:"amount"`
+	TransferRef string `json:"transfer_ref"`
+	HasImage    bool   `json:"has_image"`
+	Remark      string `json:"remark,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	ReviewedBy  string `json:"reviewed_by,omitempty"`
+	ConfirmedAt int64  `json:"confirmed_at,omitempty"`
+	CanceledAt  int64  `json:"canceled_at,omitempty"`
+	CompletedAt int64  `json:"completed_at,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+}
+
+// SubmitMarginDepositRequest 车队提交保证金充值，multipart表单，转账凭证图片字段为proof
+type SubmitMarginDepositRequest struct {
+	Ccy         string `json:"ccy" form:"ccy" binding:"required"`
+	Amount      string `json:"amount" form:"amount" binding:"required"`
+	TransferRef string `json:"transfer_ref" form:"transfer_ref" binding:"required,max=128"` // 银行转账流水号
+	Remark      string `json:"remark" form:"remark" binding:"max=512"`
+}
+
+// MarginDepositListRequest 保证金充值列表请求
+type MarginDepositListRequest struct {
+	Tid            string `json:"tid"` // 车队ID，仅管理后台可用
+	TrxID          string `json:"trx_id"`
+	Status         string `json:"status"`
+	Ccy            string `json:"ccy"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// MarginDepositRequest 保证金充值详情、撤销请求
+type MarginDepositRequest struct {
+	TrxID string `json:"trx_id" binding:"required"`
+}
+
+// ConfirmMarginDepositRequest 确认保证金到账（管理后台）
+type ConfirmMarginDepositRequest struct {
+	TrxID  string `json:"trx_id" binding:"required"`
+	Remark string `json:"remark" binding:"max=512"`
+	Code   string `json:"code" binding:"required"` // 管理员G2FA验证码
+}
+
+// RejectMarginDepositRequest 驳回保证金充值（管理后台）
+type RejectMarginDepositRequest struct {
+	TrxID  string `json:"trx_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=512"`
+}
+
+// MarginCapacityRequest 代收额度查询请求
+type MarginCapacityRequest struct {
+	Tid string `json:"tid"` // 车队ID，仅管理后台可用
+	Ccy string `json:"ccy" binding:"required"`
+}
+
+// CashierMarginCapacity 车队代收额度，可用额度 = 保证金余额 × 倍数 - 未结算代收金额
+type CashierMarginCapacity struct {
+	Tid           string `json:"tid"`
+	Ccy           string `json:"ccy"`
+	MarginBalance string `json:"margin_balance"`
+	Multiplier    string `json:"multiplier"`
+	Capacity      string `json:"capacity"`
+	Outstanding   string `json:"outstanding"` // 处理中及成功未结算的代收金额
+	Available     string `json:"available"`
+}
+
+// OffboardCashierTeamRequest 车队下线（管理后台），下线后未结算代收清零时自动释放保证金
+type OffboardCashierTeamRequest struct {
+	Tid    string `json:"tid" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=512"`
+	Code   string `json:"code" binding:"required"` // 管理员G2FA验证码
+}
@@ -13,6 +13,7 @@ type MerchantCheckoutConfig struct {
 	Mid       string                            `json:"mid"`
 	Countries []string                          `json:"countries"`         // 支持的国家列表
 	Configs   map[string]*CountryCheckoutConfig `json:"configs,omitempty"` // 按国家分组的配置
+	QRCode    *QRCode                           `json:"qr_code,omitempty"` // 收银台地址二维码，未配置收银台地址时为空
 }
 
 // CountryCheckoutConfig 国家级收银台配置信息
@@ -28,6 +29,8 @@ type TrxMethodConfig struct {
 	TrxMethod string   `json:"trx_method"`         // 交易方式
 	Ccy       []string `json:"ccy"`                // 支持的币种列表
 	LogoURL   string   `json:"logo_url,omitempty"` // Logo地址
+
+	UpiIntent *UpiIntent `json:"upi_intent,omitempty"` // UPI意图及二维码，仅UPI方式且商户配置了VPA时返回
 }
 
 // CheckoutServiceListRequest 获取收银台服务列表请求
@@ -50,6 +53,9 @@ type CreateCheckoutRequest struct {
 	TrxMethod string `json:"trx_method" `
 	NotifyURL string `json:"notify_url"`
 	ReturnURL string `json:"return_url"`
+
+	LinkID    string `json:"-"` // 来源支付链接ID，由支付链接访问时内部填充
+	ExpiredAt int64  `json:"-"` // 会话过期时间上限（毫秒），为空时按默认配置
 }
 type ConfirmCheckoutRequest struct {
 	Mid        string `json:"mid"`
@@ -75,6 +81,7 @@ type Checkout struct {
 	CheckoutID  string       `json:"checkout_id"`
 	Mid         string       `json:"mid"`
 	ReqID       string       `json:"req_id"`
+	LinkID      string       `json:"link_id,omitempty"`
 	TrxID       string       `json:"trx_id"`
 	Amount      string       `json:"amount"`
 	Ccy         string       `json:"ccy"`
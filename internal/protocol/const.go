@@ -16,6 +16,7 @@ const (
 	UserTypeMerchant    = "merchant"
 	UserTypeCashier     = "cashier"
 	UserTypeCashierTeam = "cashier_team"
+	UserTypeAdmin       = "admin"
 )
 
 // 交易类型常量
@@ -53,6 +54,8 @@ const (
 	StatusOffline    = "offline"
 	StatusBusy       = "busy"
 	StatusLocked     = "locked"
+
+	StatusPendingApproval = "pending_approval" // 待复核
 )
 
 // 流水类型常量
@@ -91,6 +94,11 @@ const (
 	TrxTypeUnfreeze      = "unfreeze"       // 解冻订单
 	TrxTypeRfRecover     = "rf_recover"     // 退款回撤订单
 	TrxTypeWdRecover     = "wd_recover"     // 提现回撤订单
+	TrxTypeConvertOut    = "convert_out"    // 换汇转出
+	TrxTypeConvertIn     = "convert_in"     // 换汇转入
+
+	TrxTypeRsvHold    = "reserve_hold"    // 结算准备金扣留
+	TrxTypeRsvRelease = "reserve_release" // 结算准备金释放
 )
 
 const (
@@ -104,4 +112,11 @@ var (
 		StatusPending,
 		StatusCancelled,
 	}
+	// TrxFinalStatusList 交易终态，进入终态后通知商户
+	TrxFinalStatusList = []string{
+		StatusSuccess,
+		StatusFailed,
+		StatusCancelled,
+		StatusExpired,
+	}
 )
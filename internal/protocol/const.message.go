@@ -16,7 +16,13 @@ const (
 	MsgTypePasswordReset       = "password_reset"
 	MsgTypeAccountVerification = "account_verification"
 	MsgTypePasswordUpdate      = "password_update"
-	MsgTypeNewPassword         = "new_password" // 新密码邮件
+	MsgTypeNewPassword         = "new_password"     // 新密码邮件
+	MsgTypeExportReady         = "export_ready"     // 导出文件就绪
+	MsgTypeDisputeOpened       = "dispute_opened"   // 新争议通知
+	MsgTypeDisputeResolved     = "dispute_resolved" // 争议裁决通知
+
+	MsgTypeLedgerDiscrepancy = "ledger_discrepancy" // 账务核对差异告警
+	MsgTypeStatementReady    = "statement_ready"    // 账户对账单就绪
 )
 
 // 语言常量
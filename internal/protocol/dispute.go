@@ -0,0 +1,119 @@
+package protocol
+
+// 争议类型
+const (
+	DisputeTypeChargeback = "chargeback" // 渠道退单
+	DisputeTypeComplaint  = "complaint"  // 付款人投诉
+)
+
+// 争议状态
+const (
+	DisputeStatusOpen              = "open"               // 待商户响应
+	DisputeStatusEvidenceSubmitted = "evidence_submitted" // 商户已提交证据
+	DisputeStatusWon               = "won"                // 商户胜诉，解冻资金
+	DisputeStatusLost              = "lost"               // 商户败诉，扣除资金
+)
+
+// 争议原因码
+const (
+	DisputeReasonFraud          = "fraud"           // 欺诈交易
+	DisputeReasonNotReceived    = "not_received"    // 未收到商品或服务
+	DisputeReasonDuplicate      = "duplicate"       // 重复扣款
+	DisputeReasonAmountMismatch = "amount_mismatch" // 金额不符
+	DisputeReasonUnrecognized   = "unrecognized"    // 付款人不认可该交易
+	DisputeReasonOther          = "other"           // 其他
+)
+
+// 商户响应方式
+const (
+	DisputeActionEvidence = "evidence" // 提交证据抗辩
+	DisputeActionAccept   = "accept"   // 接受退单
+)
+
+const (
+	DefaultDisputeResponseDays = 7                // 默认商户响应期限，单位：天
+	DisputeExpire              = "dispute.expire" // 争议超期处理任务
+	WebhookTypeDispute         = "dispute"        // 争议通知类型
+)
+
+// DisputeEvidence 争议证据附件
+type DisputeEvidence struct {
+	Name        string `json:"name" binding:"required"` // 文件名
+	URL         string `json:"url" binding:"required"`  // 附件地址
+	Description string `json:"description"`             // 说明
+	UploadedBy  string `json:"uploaded_by"`
+	UploadedAt  int64  `json:"uploaded_at"`
+}
+
+// Dispute 争议信息
+type Dispute struct {
+	DisputeID      string             `json:"dispute_id"`
+	Mid            string             `json:"mid"`
+	TrxID          string             `json:"trx_id"`
+	TrxType        string             `json:"trx_type"`
+	Type           string             `json:"type"`
+	Status         string             `json:"status"`
+	ReasonCode     string             `json:"reason_code"`
+	ReasonMsg      string             `json:"reason_msg,omitempty"`
+	ChannelCaseID  string             `json:"channel_case_id,omitempty"`
+	Ccy            string             `json:"ccy"`
+	Amount         string             `json:"amount"`
+	FrozenAmount   string             `json:"frozen_amount"` // 实际冻结金额
+	Shortfall      string             `json:"shortfall"`     // 未冻结或败诉后未能扣回的金额
+	Deadline       int64              `json:"deadline"`
+	Evidence       []*DisputeEvidence `json:"evidence"`
+	MerchantRemark string             `json:"merchant_remark,omitempty"`
+	ResolveRemark  string             `json:"resolve_remark,omitempty"`
+	CreatedBy      string             `json:"created_by"`
+	ResolvedBy     string             `json:"resolved_by,omitempty"`
+	RespondedAt    int64              `json:"responded_at,omitempty"`
+	ResolvedAt     int64              `json:"resolved_at,omitempty"`
+	CreatedAt      int64              `json:"created_at"`
+	UpdatedAt      int64              `json:"updated_at"`
+}
+
+// CreateDisputeRequest 登记争议请求（管理后台）
+type CreateDisputeRequest struct {
+	TrxID         string `json:"trx_id" binding:"required"`
+	Type          string `json:"type" binding:"required,oneof=chargeback complaint"`
+	ReasonCode    string `json:"reason_code" binding:"required,oneof=fraud not_received duplicate amount_mismatch unrecognized other"`
+	ReasonMsg     string `json:"reason_msg"`
+	ChannelCaseID string `json:"channel_case_id"` // 渠道争议单号
+	Amount        string `json:"amount"`          // 争议金额，为空时取交易金额
+	Deadline      int64  `json:"deadline"`        // 商户响应截止时间（毫秒），为空时按默认期限
+}
+
+// DisputeListRequest 争议列表请求
+type DisputeListRequest struct {
+	Mid            string `json:"mid"` // 商户ID，仅管理后台可用
+	TrxID          string `json:"trx_id"`
+	DisputeID      string `json:"dispute_id"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	ReasonCode     string `json:"reason_code"`
+	DeadlineBefore int64  `json:"deadline_before"` // 截止时间早于该时间
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// DisputeRequest 争议详情请求
+type DisputeRequest struct {
+	DisputeID string `json:"dispute_id" binding:"required"`
+}
+
+// DisputeRespondRequest 商户响应争议请求
+type DisputeRespondRequest struct {
+	DisputeID string             `json:"dispute_id" binding:"required"`
+	Action    string             `json:"action" binding:"required,oneof=evidence accept"`
+	Evidence  []*DisputeEvidence `json:"evidence" binding:"omitempty,max=20,dive"`
+	Remark    string             `json:"remark"`
+}
+
+// ResolveDisputeRequest 裁决争议请求（管理后台）
+type ResolveDisputeRequest struct {
+	DisputeID string `json:"dispute_id" binding:"required"`
+	Result    string `json:"result" binding:"required,oneof=won lost"`
+	Remark    string `json:"remark" binding:"required"`
+}
@@ -67,6 +67,11 @@ const (
 	InvalidAppID          ErrorCode = "4008" // 应用ID无效
 	AppIDNotFound         ErrorCode = "4009" // 应用ID不存在
 
+	MerchantUserNotFound    ErrorCode = "4010" // 商户子账号不存在
+	MerchantUserExists      ErrorCode = "4011" // 商户子账号已存在
+	MerchantUserDisabled    ErrorCode = "4012" // 商户子账号被禁用
+	MerchantUserNotApprover ErrorCode = "4013" // 商户子账号无复核权限
+
 	// 出纳员相关错误码 (4100-4199)
 	CashierNotFound      ErrorCode = "4100" // 出纳员不存在
 	CashierAlreadyExists ErrorCode = "4101" // 出纳员已存在
@@ -74,6 +79,12 @@ const (
 	CashierSuspended     ErrorCode = "4103" // 出纳员被暂停
 	InvalidCashierID     ErrorCode = "4104" // 出纳员ID无效
 	CashierNotActive     ErrorCode = "4105" // 出纳员未激活
+
+	// 车队保证金相关错误码 (4200-4299)
+	MarginDepositNotFound      ErrorCode = "4200" // 保证金充值记录不存在
+	MarginDepositStatusInvalid ErrorCode = "4201" // 保证金充值当前状态不允许该操作
+	MarginCapacityExceeded     ErrorCode = "4202" // 未结算代收金额超出保证金额度
+	CashierTeamOffboarded      ErrorCode = "4203" // 车队已下线
 )
 
 // 交易相关错误码 (5000-5999)
@@ -146,6 +157,41 @@ const (
 	AccountErrorInsufficientMarginBalance ErrorCode = "5504" // 保证金余额不足
 	AccountErrorUnsupportedTrxType        ErrorCode = "5505" // 不支持的交易类型
 	AccountErrorUpdateFailed              ErrorCode = "5506" // 账户更新失败
+	AccountErrorLedgerUnbalanced          ErrorCode = "5507" // 记账凭证借贷不平
+	AccountErrorInsufficientReserve       ErrorCode = "5508" // 结算准备金余额不足
+)
+
+// 审批相关错误码 (5600-5699)
+const (
+	ApprovalNotFound       ErrorCode = "5600" // 审批记录不存在
+	ApprovalAlreadyDecided ErrorCode = "5601" // 审批已处理
+	ApprovalSelfReview     ErrorCode = "5602" // 发起人不能复核自己的申请
+)
+
+// 导出相关错误码 (5700-5799)
+const (
+	ExportJobNotFound ErrorCode = "5700" // 导出任务不存在
+	ExportJobNotReady ErrorCode = "5701" // 导出文件尚未生成
+	ExportLinkInvalid ErrorCode = "5702" // 下载链接无效
+	ExportLinkExpired ErrorCode = "5703" // 下载链接或文件已过期
+)
+
+// 人工调整相关错误码 (5800-5899)
+const (
+	AdminG2FANotBound          ErrorCode = "5800" // 管理员未绑定G2FA
+	TrxStatusUnchanged         ErrorCode = "5801" // 交易已是目标状态
+	TrxStatusOverrideForbidden ErrorCode = "5802" // 交易当前状态不允许人工改状态
+)
+
+// 争议相关错误码 (5900-5999)
+const (
+	DisputeNotFound        ErrorCode = "5900" // 争议不存在
+	DisputeAlreadyResolved ErrorCode = "5901" // 争议已裁决
+	DisputeDeadlinePassed  ErrorCode = "5902" // 已超过响应期限
+	DisputeAmountExceeded  ErrorCode = "5903" // 争议金额超过交易金额
+	DisputeAlreadyOpen     ErrorCode = "5904" // 交易已有未裁决的争议
+	DisputeTrxNotEligible  ErrorCode = "5905" // 交易状态不支持发起争议
+	DisputeTrxNotSettled   ErrorCode = "5906" // 代收尚未结算入账，无可冻结余额
 )
 
 // 验证相关错误码 (9000-9999)
@@ -185,6 +231,92 @@ const (
 	ChannelNotSupported ErrorCode = "6006" // 渠道不支持
 )
 
+// 汇率相关错误码 (6100-6199)
+const (
+	FxRateNotFound      ErrorCode = "6100" // 汇率不存在
+	FxRateInvalid       ErrorCode = "6101" // 汇率数据无效
+	FxRateFileInvalid   ErrorCode = "6102" // 汇率文件格式错误
+	FxFeedNotConfigured ErrorCode = "6103" // 未配置汇率行情源
+
+	FxQuoteNotFound           ErrorCode = "6110" // 换汇报价不存在
+	FxQuoteExpired            ErrorCode = "6111" // 换汇报价已过期
+	FxQuoteStatusInvalid      ErrorCode = "6112" // 换汇报价已执行或已失效
+	FxMarkupNotConfigured     ErrorCode = "6113" // 该币种对未配置换汇加点，暂不支持换汇
+	FxMarkupInvalid           ErrorCode = "6114" // 换汇加点无效
+	FxConversionLimitExceeded ErrorCode = "6115" // 换汇金额超出商户限额
+)
+
+// 支付凭证相关错误码 (6200-6299)
+const (
+	ProofNotFound         ErrorCode = "6200" // 支付凭证不存在
+	ProofDuplicateUtr     ErrorCode = "6201" // UTR已被其他交易使用
+	ProofAlreadySubmitted ErrorCode = "6202" // 交易已有待审核凭证
+	ProofTrxNotPayable    ErrorCode = "6203" // 交易当前状态不可提交凭证
+	ProofImageInvalid     ErrorCode = "6204" // 凭证图片无效
+	ProofAlreadyReviewed  ErrorCode = "6205" // 凭证已审核
+	StatementFileInvalid  ErrorCode = "6206" // 银行流水文件格式错误
+)
+
+// 支付链接相关错误码 (6300-6399)
+const (
+	PaymentLinkNotFound      ErrorCode = "6300" // 支付链接不存在
+	PaymentLinkInactive      ErrorCode = "6301" // 支付链接已停用或已过期
+	PaymentLinkExhausted     ErrorCode = "6302" // 支付链接使用次数已达上限
+	PaymentLinkAmountInvalid ErrorCode = "6303" // 支付金额不符合链接要求
+)
+
+// 二维码与UPI意图相关错误码 (6400-6499)
+const (
+	QRCodeContentUnavailable ErrorCode = "6400" // 交易无可渲染的支付链接
+	UpiVpaNotConfigured      ErrorCode = "6401" // 未配置UPI收款VPA
+	UpiVpaInvalid            ErrorCode = "6402" // UPI收款VPA格式错误
+)
+
+// 收款人相关错误码 (6500-6599)
+const (
+	BeneficiaryNotFound          ErrorCode = "6500" // 收款人不存在
+	BeneficiaryInactive          ErrorCode = "6501" // 收款人已停用
+	BeneficiaryInvalidAccount    ErrorCode = "6502" // 收款账号格式错误
+	BeneficiaryInvalidBankCode   ErrorCode = "6503" // 银行编码格式错误
+	BeneficiaryDuplicate         ErrorCode = "6504" // 收款账户已存在
+	BeneficiaryVerifyUnsupported ErrorCode = "6505" // 无支持账户验证的渠道
+	BeneficiaryVerifyFailed      ErrorCode = "6506" // 收款账户验证未通过
+	BeneficiaryCcyMismatch       ErrorCode = "6507" // 收款人币种与代付币种不一致
+)
+
+// 银行目录相关错误码 (6600-6699)
+const (
+	BankDirectoryFileInvalid ErrorCode = "6600" // 银行目录文件格式错误
+	BankCodeNotFound         ErrorCode = "6601" // 银行编码不在银行目录中
+)
+
+// 账务核对相关错误码 (6700-6799)
+const (
+	LedgerCheckNotFound ErrorCode = "6700" // 账务核对记录不存在
+	LedgerCheckRunning  ErrorCode = "6701" // 已有账务核对正在进行
+)
+
+// 对账单相关错误码 (6800-6899)
+const (
+	StatementPeriodInvalid ErrorCode = "6800" // 对账单周期无效
+	StatementTooLarge      ErrorCode = "6801" // 对账单流水条数超出上限
+	StatementLinkInvalid   ErrorCode = "6802" // 对账单下载链接无效或已过期
+)
+
+// 提现及内部转账相关错误码 (6900-6999)
+const (
+	WithdrawNotFound              ErrorCode = "6900" // 提现记录不存在
+	WithdrawStatusInvalid         ErrorCode = "6901" // 提现当前状态不允许该操作
+	WithdrawBeneficiaryUnverified ErrorCode = "6902" // 提现收款账户未通过验证
+	WithdrawAmountInvalid         ErrorCode = "6903" // 提现金额超出允许范围
+
+	TransferNotFound           ErrorCode = "6950" // 内部转账记录不存在
+	TransferSameAccount        ErrorCode = "6951" // 转出账户与转入账户相同
+	TransferTargetNotAllowed   ErrorCode = "6952" // 转入商户不属于同一商户集团
+	TransferStatusInvalid      ErrorCode = "6953" // 内部转账当前状态不允许该操作
+	MerchantGroupNotConfigured ErrorCode = "6954" // 商户未加入商户集团
+)
+
 // Webhook相关错误码 (7000-7999)
 const (
 	WebhookNotFound       ErrorCode = "7000" // Webhook不存在
@@ -193,6 +325,15 @@ const (
 	InvalidWebhookURL     ErrorCode = "7003" // Webhook URL无效
 	WebhookConfigError    ErrorCode = "7004" // Webhook配置错误
 	WebhookSignatureError ErrorCode = "7005" // Webhook签名错误
+
+	WebhookEndpointNotFound      ErrorCode = "7006" // 通知地址不存在
+	WebhookEndpointLimitExceeded ErrorCode = "7007" // 通知地址数量超过上限
+)
+
+// 结算准备金相关错误码 (7100-7199)
+const (
+	ReserveRuleInvalid ErrorCode = "7100" // 准备金规则无效
+	ReserveNotFound    ErrorCode = "7101" // 准备金记录不存在
 )
 
 // 配置相关错误码 (8000-8999)
@@ -263,6 +404,11 @@ func (code ErrorCode) GetMessage() string {
 		InvalidAppID:          "Invalid app ID",
 		AppIDNotFound:         "App ID not found",
 
+		MerchantUserNotFound:    "Merchant user not found",
+		MerchantUserExists:      "Merchant user already exists",
+		MerchantUserDisabled:    "Merchant user disabled",
+		MerchantUserNotApprover: "Merchant user is not an approver",
+
 		// 出纳员相关错误码
 		CashierNotFound:      "Cashier not found",
 		CashierAlreadyExists: "Cashier already exists",
@@ -271,6 +417,12 @@ func (code ErrorCode) GetMessage() string {
 		InvalidCashierID:     "Invalid cashier ID",
 		CashierNotActive:     "Cashier not active",
 
+		// 车队保证金相关错误码
+		MarginDepositNotFound:      "Margin deposit not found",
+		MarginDepositStatusInvalid: "Margin deposit status does not allow this operation",
+		MarginCapacityExceeded:     "Outstanding payin amount exceeds margin capacity",
+		CashierTeamOffboarded:      "Cashier team has been offboarded",
+
 		// 交易相关错误码
 		TransactionNotFound:      "Transaction not found",
 		TransactionExpired:       "Transaction expired",
@@ -330,6 +482,33 @@ func (code ErrorCode) GetMessage() string {
 		AccountErrorInsufficientMarginBalance: "Insufficient margin balance",
 		AccountErrorUnsupportedTrxType:        "Unsupported transaction type",
 		AccountErrorUpdateFailed:              "Account update failed",
+		AccountErrorLedgerUnbalanced:          "Ledger entry is unbalanced",
+		AccountErrorInsufficientReserve:       "Insufficient reserve balance",
+
+		// 审批相关错误码
+		ApprovalNotFound:       "Approval not found",
+		ApprovalAlreadyDecided: "Approval already decided",
+		ApprovalSelfReview:     "Requester cannot approve own request",
+
+		// 导出相关错误码
+		ExportJobNotFound: "Export job not found",
+		ExportJobNotReady: "Export file is not ready",
+		ExportLinkInvalid: "Invalid download link",
+		ExportLinkExpired: "Download link or file expired",
+
+		// 人工调整相关错误码
+		AdminG2FANotBound:          "Admin G2FA is not bound",
+		TrxStatusUnchanged:         "Transaction is already in the target status",
+		TrxStatusOverrideForbidden: "Transaction status cannot be overridden in its current state",
+
+		// 争议相关错误码
+		DisputeNotFound:        "Dispute not found",
+		DisputeAlreadyResolved: "Dispute already resolved",
+		DisputeDeadlinePassed:  "Dispute response deadline has passed",
+		DisputeAmountExceeded:  "Dispute amount exceeds transaction amount",
+		DisputeAlreadyOpen:     "Transaction already has an open dispute",
+		DisputeTrxNotEligible:  "Only successful payins can be disputed",
+		DisputeTrxNotSettled:   "Payin has not been settled yet; register the dispute after it is settled",
 
 		// 渠道相关错误码
 		ChannelNotFound:     "Channel not found",
@@ -340,6 +519,74 @@ func (code ErrorCode) GetMessage() string {
 		InvalidChannelID:    "Invalid channel ID",
 		ChannelNotSupported: "Channel not supported",
 
+		// 汇率相关错误码
+		FxRateNotFound:      "FX rate not found",
+		FxRateInvalid:       "Invalid FX rate",
+		FxRateFileInvalid:   "Invalid FX rate file",
+		FxFeedNotConfigured: "FX rate feed is not configured",
+
+		FxQuoteNotFound:           "FX conversion quote not found",
+		FxQuoteExpired:            "FX conversion quote has expired",
+		FxQuoteStatusInvalid:      "FX conversion quote is no longer executable",
+		FxMarkupNotConfigured:     "FX conversion is not available for this currency pair",
+		FxMarkupInvalid:           "Invalid FX markup",
+		FxConversionLimitExceeded: "FX conversion amount exceeds the merchant limit",
+
+		// 支付凭证相关错误码
+		ProofNotFound:         "Payment proof not found",
+		ProofDuplicateUtr:     "UTR has already been used by another transaction",
+		ProofAlreadySubmitted: "A payment proof is already under review for this transaction",
+		ProofTrxNotPayable:    "Transaction is not awaiting payment",
+		ProofImageInvalid:     "Invalid payment proof image",
+		ProofAlreadyReviewed:  "Payment proof has already been reviewed",
+		StatementFileInvalid:  "Invalid bank statement file",
+
+		// 支付链接相关错误码
+		PaymentLinkNotFound:      "Payment link not found",
+		PaymentLinkInactive:      "Payment link is disabled or expired",
+		PaymentLinkExhausted:     "Payment link has reached its usage limit",
+		PaymentLinkAmountInvalid: "Amount does not meet the payment link requirements",
+
+		// 二维码与UPI意图相关错误码
+		QRCodeContentUnavailable: "No payment link is available for this transaction",
+		UpiVpaNotConfigured:      "UPI VPA is not configured",
+		UpiVpaInvalid:            "Invalid UPI VPA",
+
+		// 收款人相关错误码
+		BeneficiaryNotFound:          "Beneficiary not found",
+		BeneficiaryInactive:          "Beneficiary is inactive",
+		BeneficiaryInvalidAccount:    "Invalid beneficiary account number",
+		BeneficiaryInvalidBankCode:   "Invalid beneficiary bank code",
+		BeneficiaryDuplicate:         "Beneficiary account already exists",
+		BeneficiaryVerifyUnsupported: "No channel supports account verification",
+		BeneficiaryVerifyFailed:      "Beneficiary account verification failed",
+		BeneficiaryCcyMismatch:       "Beneficiary currency does not match the payout currency",
+
+		// 银行目录相关错误码
+		BankDirectoryFileInvalid: "Invalid bank directory file",
+		BankCodeNotFound:         "Bank code not found in the bank directory",
+
+		// 账务核对相关错误码
+		LedgerCheckNotFound: "Ledger check not found",
+		LedgerCheckRunning:  "A ledger check is already running",
+
+		// 对账单相关错误码
+		StatementPeriodInvalid: "Invalid statement period",
+		StatementTooLarge:      "Statement has too many movements, please choose a shorter period",
+		StatementLinkInvalid:   "Statement download link is invalid or expired",
+
+		// 提现相关错误码
+		WithdrawNotFound:              "Withdrawal not found",
+		WithdrawStatusInvalid:         "Withdrawal status does not allow this operation",
+		WithdrawBeneficiaryUnverified: "Withdrawal requires a verified beneficiary account",
+		WithdrawAmountInvalid:         "Withdrawal amount is out of the allowed range",
+
+		TransferNotFound:           "Internal transfer not found",
+		TransferSameAccount:        "Source and destination accounts must be different",
+		TransferTargetNotAllowed:   "Destination merchant is not in the same merchant group",
+		TransferStatusInvalid:      "Internal transfer status does not allow this operation",
+		MerchantGroupNotConfigured: "Merchant does not belong to a merchant group",
+
 		// Webhook相关错误码
 		WebhookNotFound:       "Webhook not found",
 		WebhookFailed:         "Webhook failed",
@@ -348,6 +595,13 @@ func (code ErrorCode) GetMessage() string {
 		WebhookConfigError:    "Webhook configuration error",
 		WebhookSignatureError: "Webhook signature error",
 
+		WebhookEndpointNotFound:      "Webhook endpoint not found",
+		WebhookEndpointLimitExceeded: "Webhook endpoint limit exceeded",
+
+		// 结算准备金相关错误码
+		ReserveRuleInvalid: "Reserve rule is invalid",
+		ReserveNotFound:    "Reserve record not found",
+
 		// 配置相关错误码
 		ConfigNotFound:     "Configuration not found",
 		ConfigInvalid:      "Invalid configuration",
@@ -0,0 +1,88 @@
+package protocol
+
+// 导出文件格式
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// 导出任务处理器
+const (
+	TransactionExportProcess = "transaction.export.process"
+	TransactionExportCleanup = "transaction.export.cleanup"
+)
+
+// ExportJob 导出任务
+type ExportJob struct {
+	JobID       string   `json:"job_id"`
+	UserID      string   `json:"user_id"`
+	UserType    string   `json:"user_type"`
+	TrxType     string   `json:"trx_type"`
+	Format      string   `json:"format"`
+	Columns     []string `json:"columns"`
+	Timezone    string   `json:"timezone"`
+	Status      string   `json:"status"` // pending, processing, success, failed, expired
+	FileName    string   `json:"file_name,omitempty"`
+	FileSize    int64    `json:"file_size,omitempty"`
+	RowCount    int64    `json:"row_count"`
+	Error       string   `json:"error,omitempty"`
+	CompletedAt int64    `json:"completed_at,omitempty"`
+	ExpiredAt   int64    `json:"expired_at,omitempty"` // 文件过期时间
+	CreatedAt   int64    `json:"created_at"`
+	UpdatedAt   int64    `json:"updated_at"`
+}
+
+// TransactionExportRequest 交易导出请求，筛选条件与交易列表一致
+type TransactionExportRequest struct {
+	Mid              string   `json:"mid"`                                      // 商户ID，仅管理后台可用
+	Tid              string   `json:"tid"`                                      // 出纳团队ID，仅管理后台可用
+	TrxType          string   `json:"trx_type" binding:"required"`              // 交易类型：payin, payout
+	TrxID            string   `json:"trx_id"`                                   // 交易ID
+	ReqID            string   `json:"req_id"`                                   // 商户订单号
+	TrxMethod        string   `json:"trx_method"`                               // 交易方式
+	TrxMode          string   `json:"trx_mode"`                                 // 交易模式
+	Status           string   `json:"status"`                                   // 交易状态
+	StatusList       []string `json:"status_list"`                              // 交易状态列表
+	FlowNo           string   `json:"flow_no"`                                  // 流水号
+	ChannelCode      string   `json:"channel_code"`                             // 渠道代码
+	ChannelAccount   string   `json:"channel_account"`                          // 渠道账号
+	ChannelGroup     string   `json:"channel_group"`                            // 渠道组
+	ChannelTrxID     string   `json:"channel_trx_id"`                           // 渠道交易ID
+	SettleStatus     string   `json:"settle_status"`                            // 结算状态
+	CreatedAtStart   int64    `json:"created_at_start"`                         // 开始时间
+	CreatedAtEnd     int64    `json:"created_at_end"`                           // 结束时间
+	CompletedAtStart int64    `json:"completed_at_start"`                       // 交易完成开始时间
+	CompletedAtEnd   int64    `json:"completed_at_end"`                         // 交易完成结束时间
+	SettledAtStart   int64    `json:"settled_at_start"`                         // 结算开始时间
+	SettledAtEnd     int64    `json:"settled_at_end"`                           // 结算结束时间
+	Format           string   `json:"format" binding:"required,oneof=csv xlsx"` // 文件格式
+	Columns          []string `json:"columns"`                                  // 导出列，为空时使用默认列
+	Timezone         string   `json:"timezone"`                                 // 时间列时区，如 Asia/Kolkata，默认UTC
+}
+
+// ExportJobListRequest 导出任务列表请求
+type ExportJobListRequest struct {
+	Status string `json:"status"`                       // 任务状态
+	Page   int    `json:"page" binding:"min=1"`         // 页码
+	Size   int    `json:"size" binding:"min=1,max=100"` // 每页记录数
+}
+
+// ExportJobRequest 导出任务操作请求
+type ExportJobRequest struct {
+	JobID string `json:"job_id" binding:"required"`
+}
+
+// ExportDownloadLink 导出文件签名下载链接
+type ExportDownloadLink struct {
+	JobID     string `json:"job_id"`
+	FileName  string `json:"file_name"`
+	URL       string `json:"url"`
+	ExpiredAt int64  `json:"expired_at"` // 链接过期时间
+}
+
+// ExportDownloadRequest 签名下载参数
+type ExportDownloadRequest struct {
+	JobID   string `form:"job_id" binding:"required"`
+	Expires int64  `form:"expires" binding:"required"`
+	Sign    string `form:"sign" binding:"required"`
+}
@@ -0,0 +1,83 @@
+package protocol
+
+// 换汇状态
+const (
+	FxConversionStatusQuoted = "quoted" // 已报价，待执行
+)
+
+// FxMarkup 换汇加点配置，商户按 中间价 × (1 - Markup) 兑换
+type FxMarkup struct {
+	ID        int64  `json:"id"`
+	Mid       string `json:"mid"` // 商户ID，*表示全部商户的默认加点
+	FromCcy   string `json:"from_ccy"`
+	ToCcy     string `json:"to_ccy"`
+	Markup    string `json:"markup"` // 加点比例，如0.005表示0.5%
+	Status    string `json:"status"` // active, inactive
+	UpdatedBy string `json:"updated_by,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// SaveFxMarkupRequest 保存换汇加点（管理后台），同一商户同一币种对重复保存时覆盖
+type SaveFxMarkupRequest struct {
+	Mid     string `json:"mid"` // 为空时保存全部商户的默认加点
+	FromCcy string `json:"from_ccy" binding:"required"`
+	ToCcy   string `json:"to_ccy" binding:"required"`
+	Markup  string `json:"markup" binding:"required"`
+	Status  string `json:"status" binding:"required,oneof=active inactive"` // 停用后该币种对不可换汇
+}
+
+// FxMarkupListRequest 换汇加点列表请求
+type FxMarkupListRequest struct {
+	Mid     string `json:"mid"`
+	FromCcy string `json:"from_ccy"`
+	ToCcy   string `json:"to_ccy"`
+	Page    int    `json:"page" binding:"min=1"`
+	Size    int    `json:"size" binding:"min=1,max=100"`
+}
+
+// FxConversion 商户换汇报价及执行结果
+type FxConversion struct {
+	ConversionID string `json:"conversion_id"`
+	Mid          string `json:"mid"`
+	FromCcy      string `json:"from_ccy"`
+	ToCcy        string `json:"to_ccy"`
+	FromAmount   string `json:"from_amount"`
+	ToAmount     string `json:"to_amount"`     // 商户实际入账金额
+	MidRate      string `json:"mid_rate"`      // 报价时的中间价，1 FromCcy = MidRate ToCcy
+	Markup       string `json:"markup"`        // 加点比例
+	Rate         string `json:"rate"`          // 加点后的成交汇率
+	MarkupAmount string `json:"markup_amount"` // 加点收取的金额，以ToCcy计
+	Status       string `json:"status"`        // quoted, success, expired
+	ExpiredAt    int64  `json:"expired_at"`
+	OutFlowNo    string `json:"out_flow_no,omitempty"` // 转出账户流水号
+	InFlowNo     string `json:"in_flow_no,omitempty"`  // 转入账户流水号，关联转出流水
+	ExecutedAt   int64  `json:"executed_at,omitempty"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+// FxConversionQuoteRequest 换汇报价请求
+type FxConversionQuoteRequest struct {
+	FromCcy    string `json:"from_ccy" binding:"required"`
+	ToCcy      string `json:"to_ccy" binding:"required"`
+	FromAmount string `json:"from_amount" binding:"required"`
+}
+
+// FxConversionRequest 换汇执行、详情请求
+type FxConversionRequest struct {
+	ConversionID string `json:"conversion_id" binding:"required"`
+}
+
+// FxConversionListRequest 换汇记录列表请求
+type FxConversionListRequest struct {
+	Mid            string `json:"mid"` // 商户ID，仅管理后台可用
+	ConversionID   string `json:"conversion_id"`
+	Status         string `json:"status"`
+	FromCcy        string `json:"from_ccy"`
+	ToCcy          string `json:"to_ccy"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
@@ -0,0 +1,75 @@
+package protocol
+
+// 汇率来源
+const (
+	FxSourceManual = "manual" // 管理后台录入
+	FxSourceUpload = "upload" // 管理后台文件上传
+)
+
+// 汇率任务处理器
+const (
+	FxRateSync    = "fx.rate.sync"    // 行情源汇率同步
+	FxUsdBackfill = "fx.usd.backfill" // 历史交易美元金额回填
+)
+
+// 汇率精度
+const (
+	FxUsdPrecision  = 4  // 美元金额保留小数位
+	FxRatePrecision = 12 // 汇率保留小数位
+)
+
+// FxRate 汇率信息，1 BaseCcy = Rate QuoteCcy
+type FxRate struct {
+	BaseCcy     string `json:"base_ccy"`
+	QuoteCcy    string `json:"quote_ccy"`
+	Rate        string `json:"rate"`
+	Source      string `json:"source"`
+	EffectiveAt int64  `json:"effective_at"`
+	CreatedBy   string `json:"created_by,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// FxRateItem 汇率录入项
+type FxRateItem struct {
+	BaseCcy     string `json:"base_ccy" binding:"required"`
+	QuoteCcy    string `json:"quote_ccy" binding:"required"`
+	Rate        string `json:"rate" binding:"required"`
+	EffectiveAt int64  `json:"effective_at"` // 生效时间（毫秒），为空时立即生效
+}
+
+// CreateFxRatesRequest 批量录入汇率请求
+type CreateFxRatesRequest struct {
+	Rates []*FxRateItem `json:"rates" binding:"required,min=1,max=500,dive"`
+}
+
+// FxRateListRequest 汇率列表请求
+type FxRateListRequest struct {
+	BaseCcy          string `json:"base_ccy"`
+	QuoteCcy         string `json:"quote_ccy"`
+	Source           string `json:"source"`
+	EffectiveAtStart int64  `json:"effective_at_start"`
+	EffectiveAtEnd   int64  `json:"effective_at_end"`
+	Page             int    `json:"page" binding:"min=1"`
+	Size             int    `json:"size" binding:"min=1,max=100"`
+}
+
+// FxRateQuoteRequest 汇率查询请求，按指定时间回溯
+type FxRateQuoteRequest struct {
+	BaseCcy  string `json:"base_ccy" binding:"required"`
+	QuoteCcy string `json:"quote_ccy" binding:"required"`
+	At       int64  `json:"at"` // 查询时间（毫秒），为空时取当前时间
+}
+
+// FxUsdBackfillRequest 历史交易美元金额回填请求
+type FxUsdBackfillRequest struct {
+	TrxType        string `json:"trx_type" binding:"omitempty,oneof=payin payout"` // 为空时回填代收和代付
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+}
+
+// FxUsdBackfillResult 回填结果
+type FxUsdBackfillResult struct {
+	Scanned int64 `json:"scanned"`
+	Updated int64 `json:"updated"`
+	Missed  int64 `json:"missed"` // 无可用汇率而跳过的记录数
+}
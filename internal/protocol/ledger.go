@@ -0,0 +1,119 @@
+package protocol
+
+// 系统账户，用户类型为system，按币种在首次过账时自动开户
+// 所有账户均以贷方为增加、借方为减少，清算账户为负数表示应从渠道收回的款项
+const (
+	LedgerAccountChannelClearing = "channel_clearing" // 渠道清算
+	LedgerAccountFeeIncome       = "fee_income"       // 手续费收入
+	LedgerAccountSuspense        = "suspense"         // 挂账，人工调账的对手方
+	LedgerAccountFxClearing      = "fx_clearing"      // 换汇清算，商户换汇时各币种的对手方
+)
+
+// 分录借贷方向
+const (
+	LedgerSideDebit  = "debit"  // 借方，账户减少
+	LedgerSideCredit = "credit" // 贷方，账户增加
+)
+
+// 分录对应的账户资金属性
+const (
+	LedgerBucketBalance = "balance" // 余额
+	LedgerBucketFrozen  = "frozen"  // 冻结余额
+	LedgerBucketMargin  = "margin"  // 保证金
+	LedgerBucketReserve = "reserve" // 结算准备金
+)
+
+const (
+	LedgerReconcile = "ledger.reconcile" // 账务核对任务
+)
+
+// 账务核对差异类型
+const (
+	LedgerDiscrepancyVersionGap      = "version_gap"      // 账户流水版本号不连续或重复
+	LedgerDiscrepancyChainBroken     = "chain_broken"     // 流水变动前资产与上一条流水变动后资产不一致
+	LedgerDiscrepancyFlowAmount      = "flow_amount"      // 流水金额与变动前后资产差额不一致
+	LedgerDiscrepancyAssetMismatch   = "asset_mismatch"   // 重放后的资产与账户当前资产不一致
+	LedgerDiscrepancyVersionMismatch = "version_mismatch" // 账户版本号与最后一条流水不衔接
+	LedgerDiscrepancyFlowMissing     = "flow_missing"     // 业务单据缺少应有的流水
+	LedgerDiscrepancyFlowMismatch    = "flow_mismatch"    // 业务单据流水金额不一致
+	LedgerDiscrepancyFlowDuplicate   = "flow_duplicate"   // 业务单据同类流水重复
+	LedgerDiscrepancyUnbalanced      = "unbalanced_entry" // 记账凭证借贷不平
+	LedgerDiscrepancySettleMissing   = "settle_missing"   // 已结算的交易缺少对应的结算记录
+)
+
+// LedgerCheckRunRequest 发起账务核对，时间范围用于业务与流水交叉核对，为空时按配置回溯
+type LedgerCheckRunRequest struct {
+	CreatedAtStart int64 `json:"created_at_start"`
+	CreatedAtEnd   int64 `json:"created_at_end"`
+}
+
+// LedgerCheckListRequest 账务核对记录列表请求
+type LedgerCheckListRequest struct {
+	Status         string `json:"status"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// LedgerCheckRequest 账务核对详情请求
+type LedgerCheckRequest struct {
+	CheckID string `json:"check_id" binding:"required"`
+}
+
+// LedgerDiscrepancyListRequest 账务核对差异列表请求
+type LedgerDiscrepancyListRequest struct {
+	CheckID   string `json:"check_id" binding:"required"`
+	Type      string `json:"type"`
+	AccountID string `json:"account_id"`
+	Page      int    `json:"page" binding:"min=1"`
+	Size      int    `json:"size" binding:"min=1,max=100"`
+}
+
+// LedgerReplayRequest 重放单个账户流水
+type LedgerReplayRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+}
+
+// LedgerCheck 账务核对记录
+type LedgerCheck struct {
+	CheckID          string `json:"check_id"`
+	Status           string `json:"status"` // processing, completed, failed
+	TriggeredBy      string `json:"triggered_by"`
+	CreatedAtStart   int64  `json:"created_at_start"`
+	CreatedAtEnd     int64  `json:"created_at_end"`
+	AccountCount     int64  `json:"account_count"`
+	FlowCount        int64  `json:"flow_count"`
+	TrxCount         int64  `json:"trx_count"`
+	DiscrepancyCount int64  `json:"discrepancy_count"`
+	Error            string `json:"error,omitempty"`
+	StartedAt        int64  `json:"started_at"`
+	CompletedAt      int64  `json:"completed_at"`
+	CreatedAt        int64  `json:"created_at"`
+}
+
+// LedgerDiscrepancy 账务核对差异
+type LedgerDiscrepancy struct {
+	CheckID   string `json:"check_id,omitempty"`
+	Type      string `json:"type"`
+	AccountID string `json:"account_id,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	UserType  string `json:"user_type,omitempty"`
+	Ccy       string `json:"ccy,omitempty"`
+	FlowNo    string `json:"flow_no,omitempty"`
+	TrxID     string `json:"trx_id,omitempty"`
+	TrxType   string `json:"trx_type,omitempty"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+	CreatedAt int64  `json:"created_at,omitempty"`
+}
+
+// LedgerReplayResult 单个账户流水重放结果
+type LedgerReplayResult struct {
+	AccountID     string               `json:"account_id"`
+	FlowCount     int64                `json:"flow_count"`
+	Version       int64                `json:"version"` // 账户当前版本号
+	ReplayedAsset *Assert              `json:"replayed_asset"`
+	StoredAsset   *Assert              `json:"stored_asset"`
+	Discrepancies []*LedgerDiscrepancy `json:"discrepancies"`
+}
@@ -24,4 +24,48 @@ type Merchant struct {
 	Region  string `json:"region,omitempty"` // 商户区域
 	Avatar  string `json:"avatar,omitempty"` // 商户头像
 	HasG2FA bool   `json:"has_g2fa"`         // 是否启用二次验证
+	GroupID string `json:"group_id"`         // 商户集团ID
+}
+
+// 商户子账号角色
+const (
+	MerchantUserRoleOwner    = "owner"    // 商户主账号
+	MerchantUserRoleApprover = "approver" // 复核员
+	MerchantUserRoleOperator = "operator" // 操作员
+)
+
+// MerchantUser 商户子账号信息
+type MerchantUser struct {
+	UserID    string `json:"user_id"`
+	Mid       string `json:"mid"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Status    string `json:"status"`
+	HasG2FA   bool   `json:"has_g2fa"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+}
+
+// CreateMerchantUserRequest 创建商户子账号请求
+type CreateMerchantUserRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8,max=64"` // 子账号登录密码
+	Role     string `json:"role" binding:"required,oneof=approver operator"`
+	Code     string `json:"code" binding:"required"` // 商户主账号G2FA验证码
+}
+
+// CreateMerchantUserResponse 创建商户子账号响应，G2FA密钥仅返回一次
+type CreateMerchantUserResponse struct {
+	*MerchantUser
+	G2FAKey string `json:"g2fa_key"`
+	QRCode  string `json:"qr_code"`
+}
+
+// UpdateMerchantUserStatusRequest 启用/停用商户子账号请求
+type UpdateMerchantUserStatusRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	Status string `json:"status" binding:"required,oneof=active inactive"`
+	Code   string `json:"code" binding:"required"` // 商户主账号G2FA验证码
 }
@@ -55,6 +55,14 @@ type MerchantPayoutRequest struct {
 	ReturnURL    string `json:"return_url"`
 	ChannelCode  string `json:"channel_code"`
 	ChannelGroup string `json:"channel_group"`
+	AccountNo    string `json:"account_no"`   // 收款账号
+	AccountName  string `json:"account_name"` // 收款人姓名
+	AccountType  string `json:"account_type"` // 收款账户类型
+	BankCode     string `json:"bank_code"`    // 银行编码
+	BankName     string `json:"bank_name"`    // 银行名称
+
+	// 引用已保存的收款人，指定后收款账户信息取自收款人
+	BeneficiaryID string `json:"beneficiary_id"`
 }
 
 type MerchantCancelRequest struct {
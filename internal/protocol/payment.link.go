@@ -0,0 +1,72 @@
+package protocol
+
+// PaymentLink 支付链接信息
+type PaymentLink struct {
+	LinkID      string `json:"link_id"`
+	Mid         string `json:"mid,omitempty"`
+	URL         string `json:"url,omitempty"` // 支付链接地址
+	Ccy         string `json:"ccy"`
+	Amount      string `json:"amount,omitempty"`     // 固定金额，为空时由付款人输入
+	MinAmount   string `json:"min_amount,omitempty"` // 开放金额下限
+	MaxAmount   string `json:"max_amount,omitempty"` // 开放金额上限
+	Description string `json:"description,omitempty"`
+	ReqIDPrefix string `json:"req_id_prefix,omitempty"`
+	MaxUses     int    `json:"max_uses"` // 最大支付次数（含支付中），0为不限
+	Status      string `json:"status"`   // active, disabled, expired
+	ExpiredAt   int64  `json:"expired_at,omitempty"`
+	NotifyURL   string `json:"notify_url,omitempty"`
+	ReturnURL   string `json:"return_url,omitempty"`
+	DisabledAt  int64  `json:"disabled_at,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at,omitempty"`
+}
+
+// CreatePaymentLinkRequest 创建支付链接请求
+type CreatePaymentLinkRequest struct {
+	Mid         string `json:"-"`
+	Ccy         string `json:"ccy" binding:"required"`
+	Amount      string `json:"amount"`     // 固定金额，为空时为开放金额
+	MinAmount   string `json:"min_amount"` // 开放金额下限（可选）
+	MaxAmount   string `json:"max_amount"` // 开放金额上限（可选）
+	Description string `json:"description" binding:"max=256"`
+	ReqIDPrefix string `json:"req_id_prefix" binding:"max=24"` // 会话请求ID前缀，为空时使用链接ID
+	MaxUses     int    `json:"max_uses" binding:"min=0"`       // 最大支付次数（含支付中），0为不限
+	ExpiredAt   int64  `json:"expired_at"`                     // 过期时间（毫秒），为空时长期有效
+	NotifyURL   string `json:"notify_url" binding:"max=1024"`
+	ReturnURL   string `json:"return_url" binding:"max=1024"`
+}
+
+// PaymentLinkListRequest 支付链接列表请求
+type PaymentLinkListRequest struct {
+	Status string `json:"status"`
+	Ccy    string `json:"ccy"`
+	Page   int    `json:"page" binding:"min=1"`
+	Size   int    `json:"size" binding:"min=1,max=100"`
+}
+
+// PaymentLinkRequest 按链接ID操作的请求
+type PaymentLinkRequest struct {
+	LinkID string `json:"link_id" binding:"required"`
+}
+
+// PaymentLinkVisitRequest 付款人访问支付链接请求，每次访问创建一个收银台会话
+type PaymentLinkVisitRequest struct {
+	LinkID    string `json:"link_id" binding:"required"`
+	Amount    string `json:"amount"` // 开放金额链接必填
+	Country   string `json:"country"`
+	TrxMethod string `json:"trx_method"`
+}
+
+// PaymentLinkStats 支付链接统计
+type PaymentLinkStats struct {
+	LinkID        string `json:"link_id"`
+	Ccy           string `json:"ccy"`
+	VisitCount    int64  `json:"visit_count"`    // 创建的收银台会话数
+	ConfirmCount  int64  `json:"confirm_count"`  // 已确认支付方式的会话数
+	PaidCount     int64  `json:"paid_count"`     // 成功支付笔数
+	PaidAmount    string `json:"paid_amount"`    // 成功支付实收总额
+	PendingCount  int64  `json:"pending_count"`  // 支付中笔数
+	FailedCount   int64  `json:"failed_count"`   // 失败、取消或过期笔数
+	RemainingUses int    `json:"remaining_uses"` // 剩余可支付次数，-1为不限
+	LastPaidAt    int64  `json:"last_paid_at,omitempty"`
+}
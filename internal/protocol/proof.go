@@ -0,0 +1,120 @@
+package protocol
+
+// 支付凭证状态
+const (
+	ProofStatusPending  = "pending"  // 待审核
+	ProofStatusMatched  = "matched"  // 已与银行流水自动匹配
+	ProofStatusApproved = "approved" // 人工审核通过
+	ProofStatusRejected = "rejected" // 人工审核驳回
+)
+
+// 银行流水匹配状态
+const (
+	StatementStatusUnmatched = "unmatched" // 未匹配
+	StatementStatusMatched   = "matched"   // 已匹配交易
+)
+
+// 实收金额与订单金额不一致时的处理方式
+const (
+	AmountActionExact  = "exact"  // 金额一致
+	AmountActionAccept = "accept" // 按实收金额确认成功
+	AmountActionReject = "reject" // 拒绝，交易置为失败
+	AmountActionReview = "review" // 挂起转人工审核
+)
+
+// 支付凭证任务处理器
+const (
+	ProofStatementMatch = "proof.statement.match" // 待审核凭证与银行流水定时匹配
+)
+
+// PaymentProof 付款人提交的支付凭证
+type PaymentProof struct {
+	ProofID        string `json:"proof_id"`
+	TrxID          string `json:"trx_id"`
+	TrxType        string `json:"trx_type"`
+	Mid            string `json:"mid"`
+	CheckoutID     string `json:"checkout_id,omitempty"`
+	Tid            string `json:"tid,omitempty"`
+	Utr            string `json:"utr"`
+	Ccy            string `json:"ccy"`
+	Amount         string `json:"amount"`
+	HasImage       bool   `json:"has_image"`
+	ReceivedAmount string `json:"received_amount,omitempty"` // 银行流水实收金额，与订单金额不一致时返回
+	Status         string `json:"status"`
+	StatementID    string `json:"statement_id,omitempty"`
+	ReviewedBy     string `json:"reviewed_by,omitempty"`
+	ReviewRemark   string `json:"review_remark,omitempty"`
+	ReviewedAt     int64  `json:"reviewed_at,omitempty"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
+
+// SubmitProofRequest 提交支付凭证请求，multipart表单，图片字段为image（可选）
+type SubmitProofRequest struct {
+	Mid        string `json:"-" form:"-"`
+	CheckoutID string `json:"checkout_id" form:"checkout_id" binding:"required"`
+	TrxID      string `json:"trx_id" form:"trx_id" binding:"required"`
+	Utr        string `json:"utr" form:"utr" binding:"required,min=6,max=32"`
+}
+
+// ProofListRequest 支付凭证列表请求
+type ProofListRequest struct {
+	Tid            string `json:"-"` // 收银团队ID，由登录态注入，为空时不限制
+	Mid            string `json:"mid"`
+	TrxID          string `json:"trx_id"`
+	Utr            string `json:"utr"`
+	Status         string `json:"status"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// ProofDetailRequest 支付凭证详情请求
+type ProofDetailRequest struct {
+	ProofID string `json:"proof_id" binding:"required"`
+}
+
+// ProofReviewRequest 支付凭证审核请求
+type ProofReviewRequest struct {
+	ProofID        string `json:"proof_id" binding:"required"`
+	Remark         string `json:"remark" binding:"max=512"`
+	ReceivedAmount string `json:"received_amount"` // 审核通过时确认的实收金额，为空时取关联流水金额或订单金额
+}
+
+// BankStatement 银行流水
+type BankStatement struct {
+	StatementID  string `json:"statement_id"`
+	BatchID      string `json:"batch_id"`
+	AccountNo    string `json:"account_no"`
+	Utr          string `json:"utr"`
+	Ccy          string `json:"ccy"`
+	Amount       string `json:"amount"`
+	TxnAt        int64  `json:"txn_at"`
+	Narration    string `json:"narration,omitempty"`
+	Status       string `json:"status"`
+	MatchedTrxID string `json:"matched_trx_id,omitempty"`
+	MatchedAt    int64  `json:"matched_at,omitempty"`
+	ImportedBy   string `json:"imported_by"`
+	CreatedAt    int64  `json:"created_at"`
+}
+
+// BankStatementImportResult 银行流水导入结果
+type BankStatementImportResult struct {
+	BatchID  string `json:"batch_id"`
+	Imported int    `json:"imported"`
+	Skipped  int    `json:"skipped"` // 重复导入而跳过的行数
+	Matched  int    `json:"matched"` // 自动匹配成功的凭证数
+}
+
+// BankStatementListRequest 银行流水列表请求
+type BankStatementListRequest struct {
+	BatchID    string `json:"batch_id"`
+	AccountNo  string `json:"account_no"`
+	Utr        string `json:"utr"`
+	Status     string `json:"status"`
+	TxnAtStart int64  `json:"txn_at_start"`
+	TxnAtEnd   int64  `json:"txn_at_end"`
+	Page       int    `json:"page" binding:"min=1"`
+	Size       int    `json:"size" binding:"min=1,max=100"`
+}
@@ -0,0 +1,50 @@
+package protocol
+
+// 二维码输出格式
+const (
+	QRCodeFormatPNG = "png"
+	QRCodeFormatSVG = "svg"
+)
+
+// QRCode 二维码渲染结果，DataURI可直接用于img标签
+type QRCode struct {
+	Content string `json:"content"` // 二维码内容
+	Format  string `json:"format"`
+	Size    int    `json:"size"` // 边长，单位：像素
+	DataURI string `json:"data_uri"`
+}
+
+// QRCodeRequest 交易或收银台二维码请求，trx_id与checkout_id二选一
+type QRCodeRequest struct {
+	Mid        string `json:"-"`
+	TrxID      string `json:"trx_id"`      // 代收交易ID，渲染渠道返回的支付链接
+	CheckoutID string `json:"checkout_id"` // 收银台会话ID，渲染收银台地址
+	Format     string `json:"format" binding:"omitempty,oneof=png svg"`
+	Size       int    `json:"size" binding:"min=0"` // 为空时使用默认尺寸
+}
+
+// UpiIntentRequest UPI意图生成请求，指定trx_id时金额与参考号取自交易，VPA依次取请求、受理出纳员、商户配置
+type UpiIntentRequest struct {
+	Mid       string `json:"-"`
+	TrxID     string `json:"trx_id"`
+	Vpa       string `json:"vpa"`
+	PayeeName string `json:"payee_name" binding:"max=99"`
+	Amount    string `json:"amount"`
+	Ref       string `json:"ref" binding:"max=35"`
+	Note      string `json:"note" binding:"max=50"`
+	QRCode    bool   `json:"qr_code"` // 是否同时返回二维码
+	Format    string `json:"format" binding:"omitempty,oneof=png svg"`
+	Size      int    `json:"size" binding:"min=0"`
+}
+
+// UpiIntent UPI收款意图
+type UpiIntent struct {
+	Intent    string  `json:"intent"` // upi://pay 意图串
+	Vpa       string  `json:"vpa"`
+	PayeeName string  `json:"payee_name,omitempty"`
+	Amount    string  `json:"amount,omitempty"`
+	Ccy       string  `json:"ccy"`
+	Ref       string  `json:"ref,omitempty"`
+	Note      string  `json:"note,omitempty"`
+	QRCode    *QRCode `json:"qr_code,omitempty"`
+}
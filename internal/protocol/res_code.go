@@ -5,4 +5,6 @@ const (
 	ResCodeChannelError  = "channel_error"
 	ResCodeRequestError  = "request_error"
 	ResCodeResponseError = "response_error"
+
+	ResCodeApprovalRejected = "approval_rejected"
 )
@@ -0,0 +1,91 @@
+package protocol
+
+// 结算准备金状态
+const (
+	ReserveStatusHeld     = "held"     // 扣留中，到期后释放
+	ReserveStatusReleased = "released" // 已释放到余额
+)
+
+// 结算准备金任务处理器
+const (
+	MerchantReserveRelease = "merchant.reserve.release" // 到期准备金释放
+)
+
+// MerchantReserveRule 商户结算准备金规则
+type MerchantReserveRule struct {
+	ID         int64  `json:"id"`
+	Mid        string `json:"mid"`
+	Ccy        string `json:"ccy"`
+	Percentage string `json:"percentage"`  // 每笔结算扣留比例，如0.1表示10%
+	FixedFloor string `json:"fixed_floor"` // 每笔结算最低扣留金额，不超过结算金额
+	HoldDays   int    `json:"hold_days"`   // 扣留天数
+	Status     string `json:"status"`      // active, inactive
+	UpdatedBy  string `json:"updated_by,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// SaveReserveRuleRequest 保存商户准备金规则（管理后台），同一商户同一币种重复保存时覆盖
+type SaveReserveRuleRequest struct {
+	Mid        string `json:"mid" binding:"required"`
+	Ccy        string `json:"ccy" binding:"required"`
+	Percentage string `json:"percentage" binding:"required"`
+	FixedFloor string `json:"fixed_floor"`
+	HoldDays   int    `json:"hold_days" binding:"required,min=1,max=365"`
+	Status     string `json:"status" binding:"required,oneof=active inactive"` // 停用后新结算不再扣留，已扣留的按期释放
+}
+
+// ReserveRuleListRequest 准备金规则列表请求
+type ReserveRuleListRequest struct {
+	Mid    string `json:"mid"`
+	Ccy    string `json:"ccy"`
+	Status string `json:"status"`
+	Page   int    `json:"page" binding:"min=1"`
+	Size   int    `json:"size" binding:"min=1,max=100"`
+}
+
+// MerchantReserve 结算准备金分笔记录
+type MerchantReserve struct {
+	ReserveID  string `json:"reserve_id"`
+	Mid        string `json:"mid"`
+	SettleID   string `json:"settle_id"` // 来源结算记录
+	Ccy        string `json:"ccy"`
+	Amount     string `json:"amount"`
+	HoldDays   int    `json:"hold_days"`
+	ReleaseAt  int64  `json:"release_at"` // 到期释放时间
+	Status     string `json:"status"`     // held, released
+	ReleasedAt int64  `json:"released_at,omitempty"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// ReserveListRequest 准备金记录列表请求
+type ReserveListRequest struct {
+	Mid            string `json:"mid"` // 商户ID，仅管理后台可用
+	SettleID       string `json:"settle_id"`
+	Status         string `json:"status"`
+	Ccy            string `json:"ccy"`
+	ReleaseAtStart int64  `json:"release_at_start"`
+	ReleaseAtEnd   int64  `json:"release_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// ReserveRequest 准备金记录详情请求
+type ReserveRequest struct {
+	ReserveID string `json:"reserve_id" binding:"required"`
+}
+
+// ReserveSchedule 商户某币种的准备金释放计划
+type ReserveSchedule struct {
+	Ccy        string                 `json:"ccy"`
+	HeldAmount string                 `json:"held_amount"` // 扣留中的准备金合计
+	Releases   []*ReserveScheduleItem `json:"releases"`    // 按日汇总的待释放金额
+}
+
+// ReserveScheduleItem 某日到期的准备金
+type ReserveScheduleItem struct {
+	Date   string `json:"date"` // 到期日期，格式：2006-01-02
+	Amount string `json:"amount"`
+	Count  int    `json:"count"` // 到期笔数
+}
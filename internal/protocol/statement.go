@@ -0,0 +1,85 @@
+package protocol
+
+import "github.com/shopspring/decimal"
+
+// 对账单文件格式
+const (
+	StatementFormatCSV = "csv"
+	StatementFormatPDF = "pdf"
+)
+
+// 对账单任务处理器
+const (
+	StatementDelivery = "statement.delivery"
+)
+
+// StatementRequest 对账单请求，按自然月或日期区间指定周期
+type StatementRequest struct {
+	Ccy       string `json:"ccy" binding:"required"`                   // 账户币种
+	Month     string `json:"month"`                                    // 自然月，如 2026-09，与日期区间二选一
+	StartDate string `json:"start_date"`                               // 开始日期（含），如 2026-09-01
+	EndDate   string `json:"end_date"`                                 // 结束日期（含），如 2026-09-30
+	Timezone  string `json:"timezone"`                                 // 周期及时间列时区，如 Asia/Kolkata，默认UTC
+	Format    string `json:"format" binding:"omitempty,oneof=csv pdf"` // 文件格式，获取下载链接时使用，默认csv
+}
+
+// StatementSubtotal 按业务类型汇总
+type StatementSubtotal struct {
+	TrxType string          `json:"trx_type"`
+	Count   int64           `json:"count"`
+	Credit  decimal.Decimal `json:"credit"` // 入账合计
+	Debit   decimal.Decimal `json:"debit"`  // 出账合计
+}
+
+// Statement 账户对账单摘要
+type Statement struct {
+	AccountID      string               `json:"account_id"`
+	UserID         string               `json:"user_id"`
+	UserType       string               `json:"user_type"`
+	Ccy            string               `json:"ccy"`
+	PeriodStart    int64                `json:"period_start"` // 周期开始时间（含）
+	PeriodEnd      int64                `json:"period_end"`   // 周期结束时间（不含）
+	Timezone       string               `json:"timezone"`
+	OpeningBalance decimal.Decimal      `json:"opening_balance"` // 期初余额
+	ClosingBalance decimal.Decimal      `json:"closing_balance"` // 期末余额
+	TotalCredit    decimal.Decimal      `json:"total_credit"`
+	TotalDebit     decimal.Decimal      `json:"total_debit"`
+	Count          int64                `json:"count"` // 流水条数
+	Subtotals      []*StatementSubtotal `json:"subtotals"`
+}
+
+// StatementLink 对账单签名下载链接
+type StatementLink struct {
+	FileName  string `json:"file_name"`
+	URL       string `json:"url"`
+	ExpiredAt int64  `json:"expired_at"` // 链接过期时间
+}
+
+// StatementDownloadRequest 签名下载参数
+type StatementDownloadRequest struct {
+	AccountID string `form:"account_id" binding:"required"`
+	Start     int64  `form:"start" binding:"required"`
+	End       int64  `form:"end" binding:"required"`
+	Timezone  string `form:"timezone" binding:"required"`
+	Format    string `form:"format" binding:"required,oneof=csv pdf"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Sign      string `form:"sign" binding:"required"`
+}
+
+// StatementSubscription 对账单邮件订阅，每月初发送上月对账单
+type StatementSubscription struct {
+	Status     string   `json:"status"` // active, inactive
+	Emails     []string `json:"emails"`
+	Formats    []string `json:"formats"`
+	Timezone   string   `json:"timezone"`
+	LastPeriod string   `json:"last_period,omitempty"` // 最近一次发送的月份
+	UpdatedAt  int64    `json:"updated_at,omitempty"`
+}
+
+// StatementSubscriptionRequest 修改对账单邮件订阅
+type StatementSubscriptionRequest struct {
+	Status   string   `json:"status" binding:"required,oneof=active inactive"`
+	Emails   []string `json:"emails" binding:"max=5,dive,email"`
+	Formats  []string `json:"formats" binding:"max=2,dive,oneof=csv pdf"`
+	Timezone string   `json:"timezone"`
+}
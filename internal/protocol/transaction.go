@@ -31,10 +31,12 @@ type Transaction struct {
 	Country string `json:"country,omitempty"`
 
 	// 金额信息
-	Ccy          string `json:"ccy,omitempty"`
-	Amount       string `json:"amount,omitempty"`
-	ActualAmount string `json:"actual_amount,omitempty"`
-	UsdAmount    string `json:"usd_amount,omitempty"`
+	Ccy            string `json:"ccy,omitempty"`
+	Amount         string `json:"amount,omitempty"`
+	ActualAmount   string `json:"actual_amount,omitempty"`
+	UsdAmount      string `json:"usd_amount,omitempty"`
+	UsdRate        string `json:"usd_rate,omitempty"`
+	ReceivedAmount string `json:"received_amount,omitempty"` // 实收金额，与订单金额不一致时手续费和结算按实收金额计算
 
 	// 费用信息
 	FeeCcy       string `json:"fee_ccy,omitempty"`
@@ -70,10 +72,15 @@ type Transaction struct {
 	// 流程信息
 	FlowNo    string `json:"flow_no,omitempty"`
 	Link      string `json:"link,omitempty"`
+	ProofID   string `json:"proof_id,omitempty"` // 支付凭证ID/UTR
 	NotifyURL string `json:"notify_url,omitempty"`
 	ReturnURL string `json:"return_url,omitempty"`
+	LinkID    string `json:"link_id,omitempty"` // 来源支付链接ID
 	Remark    string `json:"remark,omitempty"`
 
+	// 收款人信息
+	BeneficiaryID string `json:"beneficiary_id,omitempty"` // 引用的收款人ID
+
 	// 退款信息
 	RefundedCount     int    `json:"refunded_count,omitempty"`
 	RefundedAmount    string `json:"refunded_amount,omitempty"`
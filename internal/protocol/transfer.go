@@ -0,0 +1,74 @@
+package protocol
+
+// 内部转账发起方
+const (
+	TransferSourceAdmin    = "admin"    // 运营发起，经另一名管理员复核后执行
+	TransferSourceMerchant = "merchant" // 商户在同一集团的商户间发起，立即执行
+)
+
+// InternalTransfer 平台内部转账，两条资金流水以转账单号关联
+type InternalTransfer struct {
+	TransferID   string `json:"transfer_id"`
+	Source       string `json:"source"`
+	FromUserID   string `json:"from_user_id"`
+	FromUserType string `json:"from_user_type"`
+	ToUserID     string `json:"to_user_id"`
+	ToUserType   string `json:"to_user_type"`
+	Ccy          string `json:"ccy"`
+	Amount       string `json:"amount"`
+	Status       string `json:"status"` // pending, success, rejected
+	ApprovalID   string `json:"approval_id,omitempty"`
+	RequestedBy  string `json:"requested_by"`
+	ApprovedBy   string `json:"approved_by,omitempty"`
+	Remark       string `json:"remark,omitempty"`
+	EntryID      string `json:"entry_id,omitempty"` // 记账凭证号
+	ExecutedAt   int64  `json:"executed_at,omitempty"`
+	CreatedAt    int64  `json:"created_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
+
+// CreateInternalTransferRequest 运营发起内部转账申请
+type CreateInternalTransferRequest struct {
+	FromUserID   string `json:"from_user_id" binding:"required"`
+	FromUserType string `json:"from_user_type" binding:"required,oneof=merchant cashier cashier_team"`
+	ToUserID     string `json:"to_user_id" binding:"required"`
+	ToUserType   string `json:"to_user_type" binding:"required,oneof=merchant cashier cashier_team"`
+	Ccy          string `json:"ccy" binding:"required"`
+	Amount       string `json:"amount" binding:"required"`
+	Reason       string `json:"reason" binding:"required"` // 转账原因
+	Code         string `json:"code" binding:"required"`   // 发起人G2FA验证码
+}
+
+// MerchantTransferRequest 商户向同一集团的商户转账
+type MerchantTransferRequest struct {
+	ToMid  string `json:"to_mid" binding:"required"`
+	Ccy    string `json:"ccy" binding:"required"`
+	Amount string `json:"amount" binding:"required"`
+	Remark string `json:"remark"`
+	Code   string `json:"code" binding:"required"` // 商户G2FA验证码
+}
+
+// InternalTransferRequest 内部转账详情请求
+type InternalTransferRequest struct {
+	TransferID string `json:"transfer_id" binding:"required"`
+}
+
+// InternalTransferListRequest 内部转账列表请求，按用户查询时转出、转入均包含
+type InternalTransferListRequest struct {
+	UserID         string `json:"user_id"` // 仅管理后台可用
+	UserType       string `json:"user_type"`
+	TransferID     string `json:"transfer_id"`
+	Source         string `json:"source"`
+	Status         string `json:"status"`
+	Ccy            string `json:"ccy"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// SetMerchantGroupRequest 设置商户所属集团
+type SetMerchantGroupRequest struct {
+	Mid     string `json:"mid" binding:"required"`
+	GroupID string `json:"group_id"` // 为空时移出集团
+}
@@ -35,3 +35,222 @@ type WebhookResponse struct {
 	CreatedAt     int64           `json:"created_at"`
 	UpdatedAt     int64           `json:"updated_at"`
 }
+
+// 商户异步通知类型，交易通知的类型即交易类型（payin、payout、refund等）
+const (
+	WebhookTypeCheckout = "checkout" // 收银台会话通知类型
+	WebhookTypeSettle   = "settle"   // 交易结算通知类型
+)
+
+// 商户异步通知推送
+const (
+	WebhookDispatch        = "webhook.dispatch" // 待推送通知定时投递
+	WebhookSuccessResponse = "success"          // 商户返回HTTP 200且响应体为success（不区分大小写）视为送达，其余均按失败重试
+)
+
+// WebhookNotify 推送给商户的通知内容，生成通知时快照事件当时的状态
+type WebhookNotify struct {
+	WebhookID      string `json:"webhook_id"`
+	Event          string `json:"event"` // 交易类型、checkout、settle或dispute
+	Mid            string `json:"mid"`
+	TrxID          string `json:"trx_id,omitempty"`
+	ReqID          string `json:"req_id,omitempty"`
+	BillID         string `json:"bill_id,omitempty"` // 收银台、结算或争议单号
+	Status         string `json:"status"`
+	Amount         string `json:"amount"`
+	ReceivedAmount string `json:"received_amount,omitempty"` // 实收金额，与订单金额不一致时返回
+	Fee            string `json:"fee,omitempty"`
+	Ccy            string `json:"ccy"`
+	LinkID         string `json:"link_id,omitempty"`
+	BeneficiaryID  string `json:"beneficiary_id,omitempty"`
+	ResCode        string `json:"res_code,omitempty"`
+	ResMsg         string `json:"res_msg,omitempty"`
+	CreatedAt      int64  `json:"created_at"` // 事件发生时间，毫秒
+}
+
+// WebhookSecret 通知签名密钥，轮换过渡期内新旧密钥同时签名
+type WebhookSecret struct {
+	SecretID  string `json:"secret_id"`
+	Secret    string `json:"secret"`
+	Status    string `json:"status"`
+	ExpiresAt int64  `json:"expires_at,omitempty"` // 过渡期结束时间，为空表示长期有效
+	CreatedAt int64  `json:"created_at"`
+}
+
+// WebhookSecretInfo 当前参与签名的密钥，最新的在前
+type WebhookSecretInfo struct {
+	Header  string           `json:"header"` // 签名请求头名称
+	Secrets []*WebhookSecret `json:"secrets"`
+}
+
+// RotateWebhookSecretRequest 轮换通知签名密钥请求
+type RotateWebhookSecretRequest struct {
+	WindowHours *int   `json:"window_hours" binding:"omitempty,min=0,max=168"` // 旧密钥继续签名的小时数，默认按系统配置，0表示立即失效
+	Code        string `json:"code"`                                           // 已绑定G2FA时必填
+
+	EndpointID string `json:"endpoint_id"` // 为空时轮换默认通知地址的密钥
+}
+
+// WebhookTypePing 测试通知类型，仅用于商户验证通知地址与验签，不落库
+const WebhookTypePing = "ping"
+
+// WebhookListRequest 通知记录查询请求
+type WebhookListRequest struct {
+	WebhookID      string `json:"webhook_id"`
+	TrxID          string `json:"trx_id"`
+	Event          string `json:"event"`         // 交易类型、checkout、settle或dispute
+	NotifyStatus   string `json:"notify_status"` // pending, success, failed
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+
+	EndpointID string `json:"endpoint_id"` // 按通知地址筛选，default表示默认通知地址
+}
+
+// WebhookDelivery 通知推送记录，详情包含每次推送明细
+type WebhookDelivery struct {
+	WebhookID     string               `json:"webhook_id"`
+	Event         string               `json:"event"`
+	TrxID         string               `json:"trx_id,omitempty"`
+	BillID        string               `json:"bill_id,omitempty"`
+	Status        string               `json:"status"` // 事件状态
+	Amount        string               `json:"amount"`
+	Ccy           string               `json:"ccy"`
+	NotifyURL     string               `json:"notify_url"`
+	NotifyStatus  string               `json:"notify_status"` // pending, success, failed
+	NotifyTimes   int32                `json:"notify_times"`
+	MaxRetryTimes int32                `json:"max_retry_times"`
+	NextNotifyAt  int64                `json:"next_notify_at,omitempty"`
+	LastNotifyAt  int64                `json:"last_notify_at,omitempty"`
+	ResponseCode  string               `json:"response_code,omitempty"`
+	ResponseBody  string               `json:"response_body,omitempty"`
+	RequestBody   string               `json:"request_body"`
+	Remark        string               `json:"remark,omitempty"`
+	Attempts      []*WebhookAttemptLog `json:"attempts,omitempty"`
+	CreatedAt     int64                `json:"created_at"`
+
+	EndpointID string `json:"endpoint_id,omitempty"` // 为空表示默认通知地址
+	APIVersion string `json:"api_version"`
+}
+
+// WebhookAttemptLog 单次推送明细
+type WebhookAttemptLog struct {
+	Attempt        int32   `json:"attempt"`
+	NotifyURL      string  `json:"notify_url"`
+	Status         string  `json:"status"` // success, failed
+	RequestHeaders MapData `json:"request_headers,omitempty"`
+	ResponseCode   string  `json:"response_code,omitempty"`
+	ResponseBody   string  `json:"response_body,omitempty"`
+	Error          string  `json:"error,omitempty"`
+	Duration       int64   `json:"duration"` // 毫秒
+	CreatedAt      int64   `json:"created_at"`
+}
+
+// WebhookRequest 按通知ID查询的请求
+type WebhookRequest struct {
+	WebhookID string `json:"webhook_id" binding:"required"`
+}
+
+// WebhookResendRequest 手动重推请求，单次最多100条
+type WebhookResendRequest struct {
+	WebhookIDs []string `json:"webhook_ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// WebhookResendResult 手动重推结果，已结束的通知由投递任务再推送一次，仍在重试中的通知立即推送
+type WebhookResendResult struct {
+	Queued   int      `json:"queued"`
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// WebhookPingResult 测试通知结果
+type WebhookPingResult struct {
+	WebhookID   string             `json:"webhook_id"`
+	RequestBody string             `json:"request_body"`
+	Result      *WebhookAttemptLog `json:"result"`
+}
+
+// WebhookEndpointDefault 通知记录筛选时表示商户默认通知地址（商户配置或订单指定的notify_url）
+const WebhookEndpointDefault = "default"
+
+// 通知地址推送内容版本
+const (
+	WebhookAPIVersionV1 = "v1" // 平铺的WebhookNotify，默认通知地址固定使用该版本
+	WebhookAPIVersionV2 = "v2" // WebhookEnvelope，事件内容放在data中
+)
+
+// WebhookEventList 通知地址可订阅的事件
+var WebhookEventList = []string{TrxTypePayin, TrxTypePayout, TrxTypeRefund, TrxTypeWithdraw, WebhookTypeCheckout, WebhookTypeSettle, WebhookTypeDispute}
+
+// WebhookEnvelope v2版本的推送内容
+type WebhookEnvelope struct {
+	ID         string         `json:"id"` // 通知ID，同一事件推送到不同通知地址时各不相同
+	Event      string         `json:"event"`
+	APIVersion string         `json:"api_version"`
+	CreatedAt  int64          `json:"created_at"`
+	Data       *WebhookNotify `json:"data"`
+}
+
+// WebhookEndpoint 商户通知地址
+type WebhookEndpoint struct {
+	EndpointID  string             `json:"endpoint_id"`
+	URL         string             `json:"url"`
+	Events      []string           `json:"events"` // 订阅的事件，为空表示全部
+	APIVersion  string             `json:"api_version"`
+	Status      string             `json:"status"` // active, inactive
+	Description string             `json:"description,omitempty"`
+	Secrets     *WebhookSecretInfo `json:"secrets,omitempty"` // 仅创建和详情返回
+	CreatedAt   int64              `json:"created_at"`
+	UpdatedAt   int64              `json:"updated_at"`
+}
+
+// CreateWebhookEndpointRequest 创建通知地址请求
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,url,startswith=https://,max=512"` // 仅支持公网https地址
+	Events      []string `json:"events" binding:"omitempty,dive,oneof=payin payout refund withdraw checkout settle dispute"`
+	APIVersion  string   `json:"api_version" binding:"omitempty,oneof=v1 v2"` // 默认v1
+	Description string   `json:"description" binding:"max=128"`
+}
+
+// UpdateWebhookEndpointRequest 修改通知地址请求，未传的字段不修改
+type UpdateWebhookEndpointRequest struct {
+	EndpointID  string    `json:"endpoint_id" binding:"required"`
+	URL         *string   `json:"url" binding:"omitempty,url,startswith=https://,max=512"` // 仅支持公网https地址
+	Events      *[]string `json:"events" binding:"omitempty,dive,oneof=payin payout refund withdraw checkout settle dispute"`
+	APIVersion  *string   `json:"api_version" binding:"omitempty,oneof=v1 v2"`
+	Status      *string   `json:"status" binding:"omitempty,oneof=active inactive"` // 停用后不再接收新事件，未完成的推送不再重试
+	Description *string   `json:"description" binding:"omitempty,max=128"`
+}
+
+// WebhookEndpointRequest 按通知地址ID操作的请求
+type WebhookEndpointRequest struct {
+	EndpointID string `json:"endpoint_id" binding:"required"`
+}
+
+// 收银团队通知事件，推送到团队配置的通知地址，签名与重试规则与商户通知一致
+const (
+	CashierWebhookPayinAssigned  = "payin_assigned"  // 代收订单分配到团队
+	CashierWebhookPayoutAssigned = "payout_assigned" // 代付订单分配到团队
+	CashierWebhookTrxCompleted   = "trx_completed"   // 团队处理的订单进入终态
+	CashierWebhookSettlePosted   = "settle_posted"   // 团队处理的订单完成结算
+)
+
+// CashierTeamNotify 推送给收银团队的通知内容
+type CashierTeamNotify struct {
+	WebhookID string `json:"webhook_id"`
+	Event     string `json:"event"`
+	Tid       string `json:"tid"`
+	CashierID string `json:"cashier_id,omitempty"`
+	TrxID     string `json:"trx_id,omitempty"`
+	TrxType   string `json:"trx_type,omitempty"`
+	BillID    string `json:"bill_id,omitempty"` // 结算单号
+	Status    string `json:"status,omitempty"`
+	Amount    string `json:"amount,omitempty"`
+	Ccy       string `json:"ccy,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// UpdateWebhookNotifyURLRequest 修改收银团队通知地址请求
+type UpdateWebhookNotifyURLRequest struct {
+	NotifyURL string `json:"notify_url" binding:"omitempty,url,startswith=https://,max=1024"` // 仅支持公网https地址，为空表示不再接收通知
+}
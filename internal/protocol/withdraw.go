@@ -0,0 +1,112 @@
+package protocol
+
+// 提现执行方式
+const (
+	WithdrawMethodChannel = "channel" // 通过代付渠道出款
+	WithdrawMethodManual  = "manual"  // 线下人工转账后登记
+)
+
+// 提现任务处理器
+const (
+	WithdrawSync = "withdraw.sync" // 渠道出款中的提现状态同步
+)
+
+// MerchantWithdraw 商户提现信息
+type MerchantWithdraw struct {
+	TrxID         string `json:"trx_id"`
+	Mid           string `json:"mid"`
+	AccountID     string `json:"account_id"`
+	BeneficiaryID string `json:"beneficiary_id"`
+	AccountType   string `json:"account_type"`
+	AccountNo     string `json:"account_no"`
+	AccountName   string `json:"account_name"`
+	BankCode      string `json:"bank_code"`
+	BankName      string `json:"bank_name"`
+	Country       string `json:"country"`
+	Status        string `json:"status"` // pending, processing, success, failed, rejected, cancelled
+	Ccy           string `json:"ccy"`
+	Amount        string `json:"amount"`
+	Fee           string `json:"fee"`
+	ExecMethod    string `json:"exec_method,omitempty"`
+	ChannelCode   string `json:"channel_code,omitempty"`
+	ChannelTrxID  string `json:"channel_trx_id,omitempty"`
+	TransferRef   string `json:"transfer_ref,omitempty"`
+	ResCode       string `json:"res_code,omitempty"`
+	ResMsg        string `json:"res_msg,omitempty"`
+	Remark        string `json:"remark,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	RequestedBy   string `json:"requested_by"`
+	ReviewedBy    string `json:"reviewed_by,omitempty"`
+	ConfirmedAt   int64  `json:"confirmed_at,omitempty"`
+	CanceledAt    int64  `json:"canceled_at,omitempty"`
+	CompletedAt   int64  `json:"completed_at,omitempty"`
+	CreatedAt     int64  `json:"created_at"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+// WithdrawFeeRequest 提现手续费试算请求
+type WithdrawFeeRequest struct {
+	Ccy    string `json:"ccy" binding:"required"`
+	Amount string `json:"amount" binding:"required"`
+}
+
+// WithdrawFee 提现手续费试算结果，冻结金额为提现金额与手续费之和
+type WithdrawFee struct {
+	Ccy          string `json:"ccy"`
+	Amount       string `json:"amount"`
+	Fee          string `json:"fee"`
+	FrozenAmount string `json:"frozen_amount"`
+}
+
+// CreateWithdrawRequest 商户申请提现
+type CreateWithdrawRequest struct {
+	BeneficiaryID string `json:"beneficiary_id" binding:"required"` // 已验证的收款人ID
+	Ccy           string `json:"ccy" binding:"required"`
+	Amount        string `json:"amount" binding:"required"`
+	Remark        string `json:"remark" binding:"max=512"`
+	Code          string `json:"code" binding:"required"` // 商户G2FA验证码
+}
+
+// WithdrawListRequest 提现列表请求
+type WithdrawListRequest struct {
+	Mid            string `json:"mid"` // 商户ID，仅管理后台可用
+	TrxID          string `json:"trx_id"`
+	Status         string `json:"status"`
+	Ccy            string `json:"ccy"`
+	ExecMethod     string `json:"exec_method"`
+	CreatedAtStart int64  `json:"created_at_start"`
+	CreatedAtEnd   int64  `json:"created_at_end"`
+	Page           int    `json:"page" binding:"min=1"`
+	Size           int    `json:"size" binding:"min=1,max=100"`
+}
+
+// WithdrawRequest 提现详情、撤销请求
+type WithdrawRequest struct {
+	TrxID string `json:"trx_id" binding:"required"`
+}
+
+// ApproveWithdrawRequest 审核通过提现（管理后台）
+type ApproveWithdrawRequest struct {
+	TrxID  string `json:"trx_id" binding:"required"`
+	Method string `json:"method" binding:"required,oneof=channel manual"` // 执行方式
+	Code   string `json:"code" binding:"required"`                        // 管理员G2FA验证码
+}
+
+// RejectWithdrawRequest 驳回提现（管理后台）
+type RejectWithdrawRequest struct {
+	TrxID  string `json:"trx_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=512"`
+}
+
+// CompleteWithdrawRequest 登记人工转账完成（管理后台）
+type CompleteWithdrawRequest struct {
+	TrxID     string `json:"trx_id" binding:"required"`
+	Reference string `json:"reference" binding:"required,max=128"` // 银行转账流水号
+	Code      string `json:"code" binding:"required"`              // 管理员G2FA验证码
+}
+
+// FailWithdrawRequest 将处理中的提现置为失败并解冻资金（管理后台）
+type FailWithdrawRequest struct {
+	TrxID  string `json:"trx_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=512"`
+}
@@ -60,6 +60,7 @@ func (s *AccountService) CreateAccount(req *protocol.CreateAccountRequest) (*mod
 		MarginBalance:          decimal.Zero,
 		AvailableMarginBalance: decimal.Zero,
 		FrozenMarginBalance:    decimal.Zero,
+		ReserveBalance:         decimal.Zero,
 		Ccy:                    req.Ccy,
 		UpdatedAt:              time.Now().UnixMilli(),
 	}
@@ -74,6 +75,25 @@ func (s *AccountService) CreateAccount(req *protocol.CreateAccountRequest) (*mod
 	return account, nil
 }
 
+// GetOrCreateAccount 获取用户该币种账户，不存在时开户
+func (s *AccountService) GetOrCreateAccount(userID, userType, ccy string) (*models.Account, protocol.ErrorCode) {
+	if account, err := models.GetAccountByUserIDAndCurrency(userID, userType, ccy); err == nil && account != nil {
+		return account, protocol.Success
+	}
+	if _, err := s.CreateAccount(&protocol.CreateAccountRequest{
+		UserID:   userID,
+		UserType: userType,
+		Ccy:      ccy,
+	}); err != nil {
+		log.Get().Errorf("Create account: user=%s, type=%s, ccy=%s, err=%v", userID, userType, ccy, err)
+	}
+	account, err := models.GetAccountByUserIDAndCurrency(userID, userType, ccy)
+	if err != nil || account == nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+	return account, protocol.Success
+}
+
 func (s AccountService) GetMerchantAccountBalance(merchantID string) (balance []*protocol.Account) {
 	acct := models.GetAccountsByUserID(merchantID, protocol.UserTypeMerchant)
 	if acct == nil {
@@ -84,117 +104,129 @@ func (s AccountService) GetMerchantAccountBalance(merchantID string) (balance []
 
 // UpdateBalance 更新账户余额
 func (s *AccountService) UpdateBalance(req *protocol.UpdateBalanceRequest) (err_code protocol.ErrorCode) {
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		err_code = s.UpdateBalanceWithTx(tx, req)
+		if err_code != protocol.Success {
+			return protocol.NewServiceError(err_code, "update balance failed")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Error("UpdateBalance error:", err)
+		if err_code == protocol.Success {
+			err_code = protocol.AccountErrorUpdateFailed
+		}
+	}
+	return
+}
 
-	direction, ok := protocol.AccountDirectionMap[req.TrxType]
-	if !ok {
+// UpdateBalanceWithTx 在调用方事务中更新账户余额，便于和业务数据一起原子提交
+// 余额变动以复式凭证过账，账户余额与资金流水由凭证投影生成
+func (s *AccountService) UpdateBalanceWithTx(tx *gorm.DB, req *protocol.UpdateBalanceRequest) (err_code protocol.ErrorCode) {
+	if _, ok := protocol.AccountDirectionMap[req.TrxType]; !ok {
 		return protocol.AccountErrorInvalidTrxType
 	}
-	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
-		// 锁定账户
-		account, err := models.GetAccountForUpdate(tx, req.UserID, req.UserType, req.Ccy)
-		if err != nil {
-			err_code = protocol.AccountErrorAccountNotFound
-			return err
+	posting, code := s.newLedgerPosting(req)
+	if code != protocol.Success {
+		return code
+	}
+	_, code = GetLedgerService().Post(tx, posting)
+	return code
+}
+
+// newLedgerPosting 按业务类型生成借贷平衡的凭证
+// 用户账户为一方，另一方为渠道清算、手续费收入、挂账等系统账户，或用户自身的冻结、保证金资金属性
+func (s *AccountService) newLedgerPosting(req *protocol.UpdateBalanceRequest) (*LedgerPosting, protocol.ErrorCode) {
+	posting := &LedgerPosting{
+		TrxID:      req.TrxID,
+		TrxType:    req.TrxType,
+		Ccy:        req.Ccy,
+		FlowType:   req.FlowType,
+		Remark:     req.Description,
+		OperatorID: req.OperatorID,
+		OriFlowNo:  req.OriFlowNo,
+	}
+	switch req.TrxType {
+	case protocol.TrxTypePayin, protocol.TrxTypeDeposit:
+		// 渠道清算借记含手续费的金额，用户入账净额，手续费计入手续费收入
+		fee := decimal.Zero
+		if req.Fee.IsPositive() {
+			fee = req.Fee
 		}
-		// 初始化资产如果为空
-		if account.Asset == nil {
-			account.Asset = &models.Asset{
-				Balance:             decimal.Zero,
-				FrozenBalance:       decimal.Zero,
-				MarginBalance:       decimal.Zero,
-				FrozenMarginBalance: decimal.Zero,
-				Ccy:                 req.Ccy,
-				UpdatedAt:           time.Now().UnixMilli(),
-			}
+		posting.Debit(protocol.LedgerAccountChannelClearing, protocol.System, protocol.LedgerBucketBalance, req.Amount.Add(fee)).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+		if fee.IsPositive() {
+			posting.Credit(protocol.LedgerAccountFeeIncome, protocol.System, protocol.LedgerBucketBalance, fee)
 		}
-		// 记录操作前余额
-		beforeAssert := *account.Asset
-		afterAssert := account.Asset
-		afterAssert.UpdatedAt = utils.TimeNowMilli()
-
-		values := &models.AccountValues{
-			Asset: afterAssert,
+	case protocol.TrxTypePayout, protocol.TrxTypeChargeback:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(protocol.LedgerAccountChannelClearing, protocol.System, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeWithdraw:
+		// 提现金额及手续费在申请时已冻结，出款完成后从冻结资金中扣除，手续费计入手续费收入
+		fee := decimal.Zero
+		if req.Fee.IsPositive() {
+			fee = req.Fee
 		}
-		// 执行余额操作
-		switch req.TrxType {
-		case protocol.TrxTypePayin:
-			afterAssert.Balance = afterAssert.Balance.Add(req.Amount)
-		case protocol.TrxTypePayout:
-			if afterAssert.Balance.LessThan(req.Amount) {
-				err_code = protocol.AccountErrorInsufficientBalance
-				return fmt.Errorf("insufficient available balance")
-			}
-			direction = protocol.DirectionOut
-			afterAssert.Balance = afterAssert.Balance.Sub(req.Amount)
-			afterAssert.Balance = afterAssert.Balance.Sub(req.Amount)
-		case protocol.TrxTypeFreeze:
-			if afterAssert.Balance.LessThan(req.Amount) {
-				err_code = protocol.AccountErrorInsufficientBalance
-				return fmt.Errorf("insufficient available balance to freeze")
-			}
-			direction = protocol.DirectionOut
-			afterAssert.Balance = afterAssert.Balance.Sub(req.Amount)
-			afterAssert.FrozenBalance = afterAssert.FrozenBalance.Add(req.Amount)
-		case protocol.TrxTypeUnfreeze:
-			if afterAssert.FrozenBalance.LessThan(req.Amount) {
-				err_code = protocol.AccountErrorInsufficientFrozenBalance
-				return fmt.Errorf("insufficient frozen balance to unfreeze")
-			}
-			afterAssert.FrozenBalance = afterAssert.FrozenBalance.Sub(req.Amount)
-			afterAssert.Balance = afterAssert.Balance.Add(req.Amount)
-		case protocol.TrxTypeMarginDeposit:
-			if afterAssert.Balance.LessThan(req.Amount) {
-				err_code = protocol.AccountErrorInsufficientBalance
-				return fmt.Errorf("insufficient available balance for margin")
-			}
-			afterAssert.MarginBalance = afterAssert.MarginBalance.Add(req.Amount)
-		case protocol.TrxTypeMarginRelease:
-			if afterAssert.MarginBalance.LessThan(req.Amount) {
-				err_code = protocol.AccountErrorInsufficientMarginBalance
-				return fmt.Errorf("insufficient margin balance to release")
-			}
-			direction = protocol.DirectionOut
-			afterAssert.MarginBalance = afterAssert.MarginBalance.Sub(req.Amount)
-		default:
-			err_code = protocol.AccountErrorUnsupportedTrxType
-			return fmt.Errorf("unsupported trx_type: %s", req.TrxType)
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketFrozen, req.Amount.Add(fee)).
+			Credit(protocol.LedgerAccountChannelClearing, protocol.System, protocol.LedgerBucketBalance, req.Amount)
+		if fee.IsPositive() {
+			posting.Credit(protocol.LedgerAccountFeeIncome, protocol.System, protocol.LedgerBucketBalance, fee)
 		}
-
-		// 更新账户信息
-		afterAssert.AvailableBalance = afterAssert.Balance.Sub(afterAssert.FrozenBalance)
-		values.SetVersion(account.GetVersion() + 1)
-
-		// 创建资金流水记录
-		fundFlow := &models.FundFlow{
-			FlowNo:         utils.GenerateFlowNo(),
-			Direction:      direction,
-			UserID:         req.UserID,
-			UserType:       req.UserType,
-			AccountID:      account.AccountID,
-			AccountVersion: account.GetVersion(),
-			TrxID:          req.TrxID,
-			TrxType:        req.TrxType,
-			Ccy:            req.Ccy,
-			Amount:         &req.Amount,
-			BeforeAsset:    &beforeAssert,
-			AfterAsset:     afterAssert,
-			CreatedAt:      utils.TimeNowMilli(),
+	case protocol.TrxTypeFreeze:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketFrozen, req.Amount)
+	case protocol.TrxTypeUnfreeze:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketFrozen, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeMarginDeposit:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketMargin, req.Amount)
+	case protocol.TrxTypeMarginRelease:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketMargin, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeRsvHold:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketReserve, req.Amount)
+	case protocol.TrxTypeRsvRelease:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketReserve, req.Amount).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeConvertOut:
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(protocol.LedgerAccountFxClearing, protocol.System, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeConvertIn:
+		// 按中间价折算的金额从换汇清算转出，商户按加点后的汇率入账，差额计入手续费收入
+		fee := decimal.Zero
+		if req.Fee.IsPositive() {
+			fee = req.Fee
 		}
-		if err := tx.Create(fundFlow).Error; err != nil {
-			return err
+		posting.Debit(protocol.LedgerAccountFxClearing, protocol.System, protocol.LedgerBucketBalance, req.Amount.Add(fee)).
+			Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+		if fee.IsPositive() {
+			posting.Credit(protocol.LedgerAccountFeeIncome, protocol.System, protocol.LedgerBucketBalance, fee)
 		}
-		// 保存账户
-		if err := tx.Model(account).UpdateColumns(values).Error; err != nil {
-			err_code = protocol.AccountErrorUpdateFailed
-			return err
+	case protocol.TrxTypeTransfer:
+		// 内部转账在同一凭证内借记转出方、贷记转入方，两条资金流水共用业务单号和凭证号
+		if req.ToUserID == "" || req.ToUserType == "" {
+			return nil, protocol.AccountErrorInvalidTrxType
 		}
-
-		return nil
-	})
-	if err != nil {
-		log.Get().Error("UpdateBalance error:", err)
+		posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+			Credit(req.ToUserID, req.ToUserType, protocol.LedgerBucketBalance, req.Amount)
+	case protocol.TrxTypeAdjustment:
+		// 人工调账以挂账账户为对手方，待核实后从挂账转出
+		switch req.Direction {
+		case protocol.DirectionIn:
+			posting.Debit(protocol.LedgerAccountSuspense, protocol.System, protocol.LedgerBucketBalance, req.Amount).
+				Credit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount)
+		case protocol.DirectionOut:
+			posting.Debit(req.UserID, req.UserType, protocol.LedgerBucketBalance, req.Amount).
+				Credit(protocol.LedgerAccountSuspense, protocol.System, protocol.LedgerBucketBalance, req.Amount)
+		default:
+			return nil, protocol.AccountErrorInvalidTrxType
+		}
+	default:
+		return nil, protocol.AccountErrorUnsupportedTrxType
 	}
-	return protocol.Success
+	return posting, protocol.Success
 }
 
 // GetAccountList 获取账户列表
@@ -0,0 +1,372 @@
+package services
+
+import (
+	"fmt"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// AdminAdjustmentService 运营人工调整服务：交易改状态、账户调账、内部转账，均需另一名管理员复核后执行
+type AdminAdjustmentService struct{}
+
+var (
+	adminAdjustmentService     *AdminAdjustmentService
+	adminAdjustmentServiceOnce sync.Once
+)
+
+func SetupAdminAdjustmentService() {
+	adminAdjustmentServiceOnce.Do(func() {
+		adminAdjustmentService = &AdminAdjustmentService{}
+	})
+}
+
+// GetAdminAdjustmentService 获取人工调整服务单例
+func GetAdminAdjustmentService() *AdminAdjustmentService {
+	if adminAdjustmentService == nil {
+		SetupAdminAdjustmentService()
+	}
+	return adminAdjustmentService
+}
+
+// RequestStatusOverride 发起交易状态人工修改申请
+func (s *AdminAdjustmentService) RequestStatusOverride(admin *models.Admin, req *protocol.TrxStatusOverrideRequest) (*protocol.Approval, protocol.ErrorCode) {
+	if code := s.verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	trx := models.GetTransactionByTrxID(req.TrxID, req.TrxType)
+	if trx == nil {
+		return nil, protocol.TransactionNotFound
+	}
+	if code := s.checkStatusOverride(trx, req.Status); code != protocol.Success {
+		return nil, code
+	}
+
+	approval := &models.Approval{
+		ApprovalID:     utils.GenerateApprovalID(),
+		Mid:            trx.Mid,
+		BizType:        protocol.ApprovalBizTypeTrxStatusOverride,
+		BizID:          trx.TrxID,
+		ApprovalValues: &models.ApprovalValues{},
+	}
+	approval.SetStatus(protocol.StatusPending).
+		SetCcy(trx.Ccy).
+		SetAmount(trx.GetAmount()).
+		SetPayload(protocol.MapData{
+			protocol.AdjustPayloadTrxType:    trx.TrxType,
+			protocol.AdjustPayloadFromStatus: trx.GetStatus(),
+			protocol.AdjustPayloadStatus:     req.Status,
+		}).
+		SetRequestedBy(admin.UserID).
+		SetRequestReason(req.Reason)
+	if err := models.WriteDB.Create(approval).Error; err != nil {
+		log.Get().Errorf("RequestStatusOverride: trx_id=%s, err=%v", trx.TrxID, err)
+		return nil, protocol.DatabaseError
+	}
+	return approval.Protocol(), protocol.Success
+}
+
+// RequestBalanceAdjust 发起账户调账申请
+func (s *AdminAdjustmentService) RequestBalanceAdjust(admin *models.Admin, req *protocol.BalanceAdjustRequest) (*protocol.Approval, protocol.ErrorCode) {
+	if code := s.verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, protocol.InvalidParams
+	}
+	if _, err := models.GetAccountByUserIDAndCurrency(req.UserID, req.UserType, req.Ccy); err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+
+	approval := &models.Approval{
+		ApprovalID:     utils.GenerateApprovalID(),
+		BizType:        protocol.ApprovalBizTypeBalanceAdjust,
+		BizID:          utils.GenerateAdjustmentID(),
+		ApprovalValues: &models.ApprovalValues{},
+	}
+	if req.UserType == protocol.UserTypeMerchant {
+		approval.Mid = req.UserID
+	}
+	approval.SetStatus(protocol.StatusPending).
+		SetCcy(req.Ccy).
+		SetAmount(amount).
+		SetPayload(protocol.MapData{
+			protocol.AdjustPayloadUserID:    req.UserID,
+			protocol.AdjustPayloadUserType:  req.UserType,
+			protocol.AdjustPayloadDirection: req.Direction,
+		}).
+		SetRequestedBy(admin.UserID).
+		SetRequestReason(req.Reason)
+	if err := models.WriteDB.Create(approval).Error; err != nil {
+		log.Get().Errorf("RequestBalanceAdjust: user=%s, err=%v", req.UserID, err)
+		return nil, protocol.DatabaseError
+	}
+	return approval.Protocol(), protocol.Success
+}
+
+// List 人工调整申请列表
+func (s *AdminAdjustmentService) List(req *protocol.ApprovalListRequest) ([]*protocol.Approval, int64, protocol.ErrorCode) {
+	if !isAdjustmentBizType(req.BizType) {
+		return nil, 0, protocol.InvalidParams
+	}
+	approvals, total, err := models.ListApprovalByQuery(&models.ApprovalQuery{
+		BizType:        req.BizType,
+		BizID:          req.BizID,
+		Status:         req.Status,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.Approval, 0, len(approvals))
+	for _, approval := range approvals {
+		list = append(list, approval.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Approve 复核通过并执行调整
+func (s *AdminAdjustmentService) Approve(admin *models.Admin, req *protocol.AdjustmentDecisionRequest) (*protocol.Approval, protocol.ErrorCode) {
+	approval, code := s.checkDecision(admin, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	decision := &models.ApprovalValues{}
+	decision.SetStatus(protocol.StatusApproved).
+		SetApprovedBy(admin.UserID).
+		SetDecisionReason(req.Reason).
+		SetDecidedAt(utils.TimeNowMilli())
+
+	switch approval.BizType {
+	case protocol.ApprovalBizTypeTrxStatusOverride:
+		code = s.executeStatusOverride(approval, decision)
+	case protocol.ApprovalBizTypeBalanceAdjust:
+		code = s.executeBalanceAdjust(approval, decision)
+	case protocol.ApprovalBizTypeTransfer:
+		code = GetInternalTransferService().executeApproval(approval, decision)
+	}
+	if code != protocol.Success {
+		return nil, code
+	}
+	return approval.Protocol(), protocol.Success
+}
+
+// Reject 复核驳回，不做任何调整
+func (s *AdminAdjustmentService) Reject(admin *models.Admin, req *protocol.AdjustmentDecisionRequest) (*protocol.Approval, protocol.ErrorCode) {
+	approval, code := s.checkDecision(admin, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	decision := &models.ApprovalValues{}
+	decision.SetStatus(protocol.StatusRejected).
+		SetApprovedBy(admin.UserID).
+		SetDecisionReason(req.Reason).
+		SetDecidedAt(utils.TimeNowMilli())
+	code = protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		if approval.BizType == protocol.ApprovalBizTypeTransfer {
+			if err := GetInternalTransferService().rejectApproval(tx, approval, decision); err != nil {
+				code = protocol.DatabaseError
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Reject adjustment: approval_id=%s, err=%v", approval.ApprovalID, err)
+		return nil, code
+	}
+	return approval.Protocol(), protocol.Success
+}
+
+// executeStatusOverride 修改交易状态并生成商户通知：代收改为成功后进入待结算，由结算任务生成入账流水；
+// 代付与渠道返回结果走同一资金处理，改为失败时解冻，改为成功时解冻并扣款；已结算的交易及成功的代付不允许改状态，
+// 如需修正余额，另行发起调账申请
+func (s *AdminAdjustmentService) executeStatusOverride(approval *models.Approval, decision *models.ApprovalValues) protocol.ErrorCode {
+	payload := approval.GetPayload()
+	trx := models.GetTransactionByTrxID(approval.BizID, payload.Get(protocol.AdjustPayloadTrxType))
+	if trx == nil {
+		return protocol.TransactionNotFound
+	}
+	// 申请后交易状态已发生变化（如渠道回调），需重新发起申请
+	if trx.GetStatus() != payload.Get(protocol.AdjustPayloadFromStatus) {
+		return protocol.TrxStatusOverrideForbidden
+	}
+	status := payload.Get(protocol.AdjustPayloadStatus)
+	if code := s.checkStatusOverride(trx, status); code != protocol.Success {
+		return code
+	}
+
+	history := models.NewTrxHistoryByTransaction(trx)
+	values := models.NewTrxValues()
+	values.SetStatus(status).
+		SetReason(approval.GetRequestReason()).
+		SetCompletedAt(utils.TimeNowMilli())
+	if status == protocol.StatusSuccess && trx.TrxType == protocol.TrxTypePayin {
+		values.SetSettleStatus(protocol.StatusPending)
+	}
+	GetFxRateService().SnapshotCompletion(trx, values)
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		// 代付与渠道返回结果走同一资金处理：解冻冻结资金，成功时从商户余额扣款
+		if code = applyPayoutResult(tx, trx, trx.GetStatus(), status, approval.GetApprovedBy()); code != protocol.Success {
+			return protocol.NewServiceError(code, "apply payout result failed")
+		}
+		if err := models.SaveTransactionValues(tx, trx, values); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if err := CreateWebhooks(tx, NewTransactionWebhook(trx)); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if err := CreateCashierCompletedWebhook(tx, trx); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Override transaction status: approval_id=%s, err=%v", approval.ApprovalID, err)
+		return code
+	}
+	if status == protocol.StatusSuccess && trx.TrxType == protocol.TrxTypePayin {
+		GetMerchantTransactionService().AfterPayinSuccess(trx)
+	}
+
+	history.TrxType = trx.TrxType
+	history.FillValues(trx.TransactionValues)
+	history.ChangedBy = approval.GetApprovedBy()
+	history.Remark = fmt.Sprintf("manual override by %s: %s", approval.GetRequestedBy(), approval.GetRequestReason())
+	if err := models.CreateHistory(history); err != nil {
+		log.Get().Errorf("Save override history error: trx_id=%s, err=%v", trx.TrxID, err)
+	}
+	return protocol.Success
+}
+
+// executeBalanceAdjust 执行调账，生成调账类型的资金流水
+func (s *AdminAdjustmentService) executeBalanceAdjust(approval *models.Approval, decision *models.ApprovalValues) protocol.ErrorCode {
+	payload := approval.GetPayload()
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      payload.Get(protocol.AdjustPayloadUserID),
+			UserType:    payload.Get(protocol.AdjustPayloadUserType),
+			Ccy:         approval.GetCcy(),
+			Amount:      approval.GetAmount(),
+			TrxID:       approval.BizID,
+			TrxType:     protocol.TrxTypeAdjustment,
+			Direction:   payload.Get(protocol.AdjustPayloadDirection),
+			FlowType:    protocol.FlowTypeAdjust,
+			OperatorID:  approval.GetApprovedBy(),
+			Description: approval.GetRequestReason(),
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "adjust balance failed")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Adjust balance: approval_id=%s, err=%v", approval.ApprovalID, err)
+		return code
+	}
+	return protocol.Success
+}
+
+// checkStatusOverride 校验交易是否允许改为目标状态
+func (s *AdminAdjustmentService) checkStatusOverride(trx *models.Transaction, status string) protocol.ErrorCode {
+	if trx.GetStatus() == status {
+		return protocol.TrxStatusUnchanged
+	}
+	// 待复核代付走代付复核流程；已结算的交易及成功的代付不能再改状态
+	if trx.GetStatus() == protocol.StatusPendingApproval {
+		return protocol.TrxStatusOverrideForbidden
+	}
+	if trx.GetSettleStatus() == protocol.StatusSuccess {
+		return protocol.TrxStatusOverrideForbidden
+	}
+	// 成功的代付已从商户余额扣款出款，撤回需走调账申请
+	if trx.TrxType == protocol.TrxTypePayout && trx.GetStatus() == protocol.StatusSuccess {
+		return protocol.TrxStatusOverrideForbidden
+	}
+	return protocol.Success
+}
+
+// checkDecision 校验审批记录、复核人身份及G2FA，发起人不能复核自己的申请
+func (s *AdminAdjustmentService) checkDecision(admin *models.Admin, req *protocol.AdjustmentDecisionRequest) (*models.Approval, protocol.ErrorCode) {
+	approval := models.GetApprovalByID("", req.ApprovalID)
+	if approval == nil {
+		return nil, protocol.ApprovalNotFound
+	}
+	if !isAdjustmentBizType(approval.BizType) {
+		return nil, protocol.ApprovalNotFound
+	}
+	if approval.GetStatus() != protocol.StatusPending {
+		return nil, protocol.ApprovalAlreadyDecided
+	}
+	if !admin.IsSuperAdmin() && !admin.IsAdmin() {
+		return nil, protocol.PermissionDenied
+	}
+	if admin.UserID == approval.GetRequestedBy() {
+		return nil, protocol.ApprovalSelfReview
+	}
+	if code := s.verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	return approval, protocol.Success
+}
+
+// isAdjustmentBizType 是否为需管理员复核的人工调整类审批
+func isAdjustmentBizType(bizType string) bool {
+	switch bizType {
+	case protocol.ApprovalBizTypeTrxStatusOverride, protocol.ApprovalBizTypeBalanceAdjust, protocol.ApprovalBizTypeTransfer:
+		return true
+	}
+	return false
+}
+
+func (s *AdminAdjustmentService) verifyAdminG2FA(admin *models.Admin, code string) protocol.ErrorCode {
+	if admin.GetG2FA() == "" {
+		return protocol.AdminG2FANotBound
+	}
+	if !VerifyG2FACode(admin.GetG2FA(), code) {
+		return protocol.InvalidTwoFactorCode
+	}
+	return protocol.Success
+}
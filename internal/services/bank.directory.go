@@ -0,0 +1,331 @@
+package services
+
+import (
+	"encoding/csv"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// BankDirectoryService 银行目录服务，维护各国银行编码、印度IFSC与SWIFT/BIC，用于校验与规范化银行编码
+type BankDirectoryService struct{}
+
+var (
+	bankDirectoryService     *BankDirectoryService
+	bankDirectoryServiceOnce sync.Once
+
+	countryCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// cashierBankBatchSize 规范化出纳员银行编码的单批条数
+const cashierBankBatchSize = 500
+
+func SetupBankDirectoryService() {
+	bankDirectoryServiceOnce.Do(func() {
+		bankDirectoryService = &BankDirectoryService{}
+	})
+}
+
+// GetBankDirectoryService 获取银行目录服务单例
+func GetBankDirectoryService() *BankDirectoryService {
+	if bankDirectoryService == nil {
+		SetupBankDirectoryService()
+	}
+	return bankDirectoryService
+}
+
+// Import 导入银行目录CSV，首行可为表头，已存在的编码覆盖更新，导入后按目录规范化出纳员卡的银行编码
+func (s *BankDirectoryService) Import(kind string, reader io.Reader) (*protocol.BankImportResult, protocol.ErrorCode) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, protocol.BankDirectoryFileInvalid
+	}
+
+	result := &protocol.BankImportResult{Type: kind}
+	switch kind {
+	case protocol.BankDirectoryBanks:
+		banks, ok := s.parseBanks(records)
+		if !ok {
+			return nil, protocol.BankDirectoryFileInvalid
+		}
+		result.Total = len(banks)
+		result.Imported, err = models.SaveBanks(models.WriteDB, banks)
+	case protocol.BankDirectoryIFSC, protocol.BankDirectorySwift:
+		branches, ok := s.parseBranches(kind, records)
+		if !ok {
+			return nil, protocol.BankDirectoryFileInvalid
+		}
+		result.Total = len(branches)
+		result.Imported, err = models.SaveBankBranches(models.WriteDB, branches)
+	default:
+		return nil, protocol.InvalidParams
+	}
+	if err != nil {
+		log.Get().Errorf("Import bank directory %s failed: %v", kind, err)
+		return nil, protocol.DatabaseError
+	}
+	result.CashiersUpdated = s.NormalizeCashierBanks()
+	return result, protocol.Success
+}
+
+// parseBanks 解析银行编码文件，列顺序：country,bank_code,bank_name,short_name
+func (s *BankDirectoryService) parseBanks(records [][]string) ([]*models.Bank, bool) {
+	banks := make([]*models.Bank, 0, len(records))
+	index := make(map[string]int, len(records))
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "country") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, false
+		}
+		bank := &models.Bank{
+			Country:  strings.ToUpper(strings.TrimSpace(record[0])),
+			BankCode: utils.NormalizeBankField(record[1]),
+			BankName: strings.TrimSpace(record[2]),
+		}
+		if len(record) > 3 {
+			bank.ShortName = strings.TrimSpace(record[3])
+		}
+		if !countryCodePattern.MatchString(bank.Country) || bank.BankCode == "" || len(bank.BankCode) > 32 || bank.BankName == "" {
+			return nil, false
+		}
+		// 同一文件内重复的编码以最后一行为准，避免同批次冲突更新
+		key := bank.Country + "|" + bank.BankCode
+		if pos, ok := index[key]; ok {
+			banks[pos] = bank
+			continue
+		}
+		index[key] = len(banks)
+		banks = append(banks, bank)
+	}
+	return banks, len(banks) > 0
+}
+
+// parseBranches 解析分行编码文件
+// ifsc: ifsc,bank_name,branch,address,city,state
+// swift: swift_code,bank_name,country,city,branch，8位BIC补齐为XXX结尾的11位
+func (s *BankDirectoryService) parseBranches(kind string, records [][]string) ([]*models.BankBranch, bool) {
+	branches := make([]*models.BankBranch, 0, len(records))
+	index := make(map[string]int, len(records))
+	column := func(record []string, i int) string {
+		if i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	for i, record := range records {
+		if i == 0 && (strings.EqualFold(column(record, 0), "ifsc") || strings.EqualFold(column(record, 0), "swift_code")) {
+			continue
+		}
+		if len(record) < 2 {
+			return nil, false
+		}
+		branch := &models.BankBranch{
+			CodeType: kind,
+			Code:     utils.NormalizeBankField(record[0]),
+			BankName: column(record, 1),
+		}
+		if kind == protocol.BankDirectoryIFSC {
+			if !utils.ValidateIFSC(branch.Code) {
+				return nil, false
+			}
+			branch.Country = "IND"
+			branch.Branch = column(record, 2)
+			branch.Address = column(record, 3)
+			branch.City = column(record, 4)
+			branch.State = column(record, 5)
+		} else {
+			if !utils.ValidateSwiftBIC(branch.Code) {
+				return nil, false
+			}
+			branch.Code = canonicalSwiftCode(branch.Code)
+			branch.Country = strings.ToUpper(column(record, 2))
+			branch.City = column(record, 3)
+			branch.Branch = column(record, 4)
+			if !countryCodePattern.MatchString(branch.Country) {
+				return nil, false
+			}
+		}
+		if branch.BankName == "" {
+			return nil, false
+		}
+		branch.BankCode = branch.Code[:4]
+		if pos, ok := index[branch.Code]; ok {
+			branches[pos] = branch
+			continue
+		}
+		index[branch.Code] = len(branches)
+		branches = append(branches, branch)
+	}
+	return branches, len(branches) > 0
+}
+
+// Lookup 按编码查询银行目录，自动识别IFSC与SWIFT/BIC，其余按国家查询本地银行编码
+func (s *BankDirectoryService) Lookup(req *protocol.BankLookupRequest) (*protocol.BankInfo, protocol.ErrorCode) {
+	info, _ := s.find(s.countries(req.Country, req.Ccy), req.Code)
+	if info == nil {
+		return nil, protocol.BankCodeNotFound
+	}
+	return info, protocol.Success
+}
+
+// Search 搜索银行目录，code_type为空时搜索本地银行编码
+func (s *BankDirectoryService) Search(req *protocol.BankSearchRequest) ([]*protocol.BankInfo, int64, protocol.ErrorCode) {
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+	keyword := strings.TrimSpace(req.Keyword)
+	list := []*protocol.BankInfo{}
+	switch req.CodeType {
+	case "", protocol.BankDirectoryBanks:
+		banks, total, err := models.ListBankByQuery(&models.BankQuery{
+			Country: country,
+			Keyword: keyword,
+			Page:    req.Page,
+			Size:    req.Size,
+		})
+		if err != nil {
+			log.Get().Errorf("Search banks failed: %v", err)
+			return nil, 0, protocol.DatabaseError
+		}
+		for _, bank := range banks {
+			list = append(list, bank.Protocol())
+		}
+		return list, total, protocol.Success
+	case protocol.BankDirectoryIFSC, protocol.BankDirectorySwift:
+		branches, total, err := models.ListBankBranchByQuery(&models.BankBranchQuery{
+			CodeType: req.CodeType,
+			Country:  country,
+			BankCode: utils.NormalizeBankField(req.BankCode),
+			City:     strings.TrimSpace(req.City),
+			Keyword:  keyword,
+			Page:     req.Page,
+			Size:     req.Size,
+		})
+		if err != nil {
+			log.Get().Errorf("Search bank branches failed: %v", err)
+			return nil, 0, protocol.DatabaseError
+		}
+		for _, branch := range branches {
+			list = append(list, branch.Protocol())
+		}
+		return list, total, protocol.Success
+	default:
+		return nil, 0, protocol.InvalidParams
+	}
+}
+
+// Resolve 校验并规范化银行编码，目录已收录该类编码但查不到时返回错误，未收录时仅做格式规范化
+func (s *BankDirectoryService) Resolve(countries []string, code string) (*protocol.BankInfo, protocol.ErrorCode) {
+	info, covered := s.find(countries, code)
+	if info != nil {
+		return info, protocol.Success
+	}
+	if covered {
+		return nil, protocol.BankCodeNotFound
+	}
+	return &protocol.BankInfo{Code: utils.NormalizeBankField(code)}, protocol.Success
+}
+
+// ResolveByCcy 按币种的使用国家校验银行编码，用于不含国家信息的代付与出纳员卡
+func (s *BankDirectoryService) ResolveByCcy(ccy, code string) (*protocol.BankInfo, protocol.ErrorCode) {
+	return s.Resolve(s.countries("", ccy), code)
+}
+
+// NormalizeCashierBanks 按银行目录规范化出纳员卡的银行编码，并补全缺失的银行名称
+func (s *BankDirectoryService) NormalizeCashierBanks() int {
+	updated := 0
+	var afterID uint64
+	for {
+		cashiers, err := models.ListCashiersWithBankCode(afterID, cashierBankBatchSize)
+		if err != nil {
+			log.Get().Errorf("List cashiers for bank normalization failed: %v", err)
+			return updated
+		}
+		for _, cashier := range cashiers {
+			afterID = cashier.ID
+			info, covered := s.find(s.countries(cashier.GetCountry(), cashier.GetCurrency()), cashier.GetBankCode())
+			if info == nil {
+				if covered {
+					log.Get().Warnf("Cashier %s bank code %s not found in bank directory", cashier.CashierID, cashier.GetBankCode())
+				}
+				continue
+			}
+			values := &models.CashierValues{}
+			if info.Code != cashier.GetBankCode() {
+				values.SetBankCode(info.Code)
+			}
+			if cashier.GetBankName() == "" && info.BankName != "" {
+				values.SetBankName(info.BankName)
+			}
+			if values.BankCode == nil && values.BankName == nil {
+				continue
+			}
+			if err := models.UpdateCashierValues(models.WriteDB, cashier, values); err != nil {
+				log.Get().Errorf("Normalize cashier %s bank failed: %v", cashier.CashierID, err)
+				continue
+			}
+			updated++
+		}
+		if len(cashiers) < cashierBankBatchSize {
+			return updated
+		}
+	}
+}
+
+// find 查询银行目录，covered表示目录已收录该类编码（IFSC、SWIFT或对应国家的本地编码）
+func (s *BankDirectoryService) find(countries []string, code string) (info *protocol.BankInfo, covered bool) {
+	code = utils.NormalizeBankField(code)
+	if code == "" {
+		return nil, false
+	}
+	switch {
+	case utils.ValidateIFSC(code):
+		if branch := models.GetBankBranchByCode(protocol.BankDirectoryIFSC, code); branch != nil {
+			return branch.Protocol(), true
+		}
+		return nil, models.HasBankBranches(protocol.BankDirectoryIFSC, "")
+	case utils.ValidateSwiftBIC(code):
+		// 11位BIC查不到分行时回退到总行（XXX）
+		canonical := canonicalSwiftCode(code)
+		if branch := models.GetBankBranchByCode(protocol.BankDirectorySwift, canonical, canonical[:8]+"XXX"); branch != nil {
+			return branch.Protocol(), true
+		}
+		covered = models.HasBankBranches(protocol.BankDirectorySwift, "")
+	}
+	// 部分国家的本地银行编码与BIC格式相同，继续按本地编码查询
+	if len(countries) == 0 {
+		return nil, covered
+	}
+	if bank := models.GetBankByCode(countries, code); bank != nil {
+		return bank.Protocol(), true
+	}
+	return nil, covered || models.HasBanks(countries)
+}
+
+// countries 银行编码适用的国家，未指定国家时取币种的主要使用国家
+func (s *BankDirectoryService) countries(country, ccy string) []string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if countryCodePattern.MatchString(country) {
+		return []string{country}
+	}
+	if info, ok := protocol.GetCurrencyInfo(strings.ToUpper(ccy)); ok {
+		return info.CountryCodes
+	}
+	return nil
+}
+
+// canonicalSwiftCode 8位BIC表示总行，统一补齐为XXX结尾的11位
+func canonicalSwiftCode(code string) string {
+	if len(code) == 8 {
+		return code + "XXX"
+	}
+	return code
+}
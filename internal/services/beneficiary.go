@@ -0,0 +1,443 @@
+package services
+
+import (
+	"errors"
+	"inpayos/internal/channels"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// BeneficiaryService 商户收款人服务，保存校验后的代付收款账户，所有变更写入审计记录
+type BeneficiaryService struct{}
+
+var (
+	beneficiaryService     *BeneficiaryService
+	beneficiaryServiceOnce sync.Once
+
+	// beneficiaryVerifyAmount 小额打款验证时用于路由匹配的金额
+	beneficiaryVerifyAmount = decimal.NewFromInt(1)
+)
+
+func SetupBeneficiaryService() {
+	beneficiaryServiceOnce.Do(func() {
+		beneficiaryService = &BeneficiaryService{}
+	})
+}
+
+// GetBeneficiaryService 获取收款人服务单例
+func GetBeneficiaryService() *BeneficiaryService {
+	if beneficiaryService == nil {
+		SetupBeneficiaryService()
+	}
+	return beneficiaryService
+}
+
+// Create 创建收款人，校验账户格式，同一商户下相同账户仅保留一个可用收款人
+func (s *BeneficiaryService) Create(mid, source string, req *protocol.CreateBeneficiaryRequest) (*protocol.Beneficiary, protocol.ErrorCode) {
+	if !protocol.IsValidCurrency(req.Ccy) {
+		return nil, protocol.InvalidCurrency
+	}
+	country := strings.ToUpper(strings.TrimSpace(req.Country))
+	accountNo, bankCode := normalizeBeneficiaryAccount(req.AccountType, req.AccountNo, req.BankCode)
+	if err := utils.ValidateBankAccount(country, req.AccountType, accountNo, bankCode); err != nil {
+		return nil, beneficiaryValidateCode(err)
+	}
+	// 按银行目录校验银行编码，未填写银行名称时自动补全
+	bankName := strings.TrimSpace(req.BankName)
+	if bankCode != "" {
+		bank, code := GetBankDirectoryService().Resolve([]string{country}, bankCode)
+		if code != protocol.Success {
+			return nil, code
+		}
+		bankCode = bank.Code
+		if bankName == "" {
+			bankName = bank.BankName
+		}
+	}
+	fingerprint := utils.BankAccountFingerprint(mid, req.Ccy, req.AccountType, accountNo, bankCode)
+	if models.GetActiveBeneficiaryByFingerprint(mid, fingerprint) != nil {
+		return nil, protocol.BeneficiaryDuplicate
+	}
+
+	beneficiary := &models.MerchantBeneficiary{
+		BeneficiaryID:             utils.GenerateBeneficiaryID(),
+		Mid:                       mid,
+		Country:                   country,
+		Ccy:                       req.Ccy,
+		AccountType:               req.AccountType,
+		AccountNo:                 accountNo,
+		AccountName:               strings.TrimSpace(req.AccountName),
+		BankCode:                  bankCode,
+		BankName:                  bankName,
+		Fingerprint:               fingerprint,
+		MerchantBeneficiaryValues: &models.MerchantBeneficiaryValues{},
+	}
+	beneficiary.SetAlias(req.Alias).
+		SetEmail(req.Email).
+		SetPhone(req.Phone).
+		SetStatus(protocol.StatusActive).
+		SetVerifyStatus(protocol.VerifyStatusUnverified)
+
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(beneficiary).Error; err != nil {
+			return err
+		}
+		return s.audit(tx, beneficiary, protocol.BeneficiaryActionCreate, mid, source, protocol.MapData{
+			"country":      beneficiary.Country,
+			"ccy":          beneficiary.Ccy,
+			"account_type": beneficiary.AccountType,
+			"account_no":   utils.MaskAccountNo(beneficiary.AccountNo),
+			"account_name": beneficiary.AccountName,
+			"bank_code":    beneficiary.BankCode,
+		}, "")
+	})
+	if err != nil {
+		log.Get().Errorf("Create beneficiary failed: %v", err)
+		return nil, protocol.SystemError
+	}
+
+	if req.Verify {
+		// 验证失败不影响收款人创建，商户可稍后重新发起验证
+		info, code := s.verify(beneficiary, mid, source)
+		if code == protocol.Success {
+			return info, protocol.Success
+		}
+		log.Get().Warnf("Verify beneficiary %s after create failed: %v", beneficiary.BeneficiaryID, code)
+	}
+	return beneficiary.Protocol(), protocol.Success
+}
+
+// List 商户收款人列表
+func (s *BeneficiaryService) List(mid string, req *protocol.BeneficiaryListRequest) ([]*protocol.Beneficiary, int64, protocol.ErrorCode) {
+	beneficiaries, total, err := models.ListBeneficiaryByQuery(&models.BeneficiaryQuery{
+		Mid:          mid,
+		Status:       req.Status,
+		VerifyStatus: req.VerifyStatus,
+		Ccy:          req.Ccy,
+		Keyword:      strings.TrimSpace(req.Keyword),
+		Page:         req.Page,
+		Size:         req.Size,
+	})
+	if err != nil {
+		log.Get().Errorf("List beneficiaries failed: %v", err)
+		return nil, 0, protocol.SystemError
+	}
+	list := make([]*protocol.Beneficiary, 0, len(beneficiaries))
+	for _, beneficiary := range beneficiaries {
+		list = append(list, beneficiary.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Detail 收款人详情
+func (s *BeneficiaryService) Detail(mid, beneficiaryID string) (*protocol.Beneficiary, protocol.ErrorCode) {
+	beneficiary := models.GetMerchantBeneficiaryByID(mid, beneficiaryID)
+	if beneficiary == nil {
+		return nil, protocol.BeneficiaryNotFound
+	}
+	return beneficiary.Protocol(), protocol.Success
+}
+
+// Update 修改收款人备注名与联系方式，仅记录实际发生变化的字段
+func (s *BeneficiaryService) Update(mid, source string, req *protocol.UpdateBeneficiaryRequest) (*protocol.Beneficiary, protocol.ErrorCode) {
+	beneficiary := models.GetMerchantBeneficiaryByID(mid, req.BeneficiaryID)
+	if beneficiary == nil {
+		return nil, protocol.BeneficiaryNotFound
+	}
+	if beneficiary.GetStatus() != protocol.StatusActive {
+		return nil, protocol.BeneficiaryInactive
+	}
+
+	values := &models.MerchantBeneficiaryValues{}
+	changes := protocol.MapData{}
+	if req.Alias != nil && *req.Alias != beneficiary.GetAlias() {
+		values.SetAlias(*req.Alias)
+		changes["alias"] = beneficiaryChange(beneficiary.GetAlias(), *req.Alias)
+	}
+	if req.Email != nil && *req.Email != beneficiary.GetEmail() {
+		values.SetEmail(*req.Email)
+		changes["email"] = beneficiaryChange(beneficiary.GetEmail(), *req.Email)
+	}
+	if req.Phone != nil && *req.Phone != beneficiary.GetPhone() {
+		values.SetPhone(*req.Phone)
+		changes["phone"] = beneficiaryChange(beneficiary.GetPhone(), *req.Phone)
+	}
+	if len(changes) == 0 {
+		return beneficiary.Protocol(), protocol.Success
+	}
+
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateBeneficiaryValues(tx, beneficiary, []string{protocol.StatusActive}, values)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			code = protocol.BeneficiaryInactive
+			return errBeneficiaryStatusChanged
+		}
+		return s.audit(tx, beneficiary, protocol.BeneficiaryActionUpdate, mid, source, changes, "")
+	})
+	if code != protocol.Success {
+		return nil, code
+	}
+	if err != nil {
+		log.Get().Errorf("Update beneficiary %s failed: %v", req.BeneficiaryID, err)
+		return nil, protocol.SystemError
+	}
+	return beneficiary.Protocol(), protocol.Success
+}
+
+// Deactivate 停用收款人，停用后不能再用于代付，相同账户可重新创建
+func (s *BeneficiaryService) Deactivate(mid, source, beneficiaryID string) (*protocol.Beneficiary, protocol.ErrorCode) {
+	beneficiary := models.GetMerchantBeneficiaryByID(mid, beneficiaryID)
+	if beneficiary == nil {
+		return nil, protocol.BeneficiaryNotFound
+	}
+	if beneficiary.GetStatus() == protocol.StatusInactive {
+		return beneficiary.Protocol(), protocol.Success
+	}
+
+	values := &models.MerchantBeneficiaryValues{}
+	values.SetStatus(protocol.StatusInactive).SetDeactivatedAt(utils.TimeNowMilli())
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateBeneficiaryValues(tx, beneficiary, []string{protocol.StatusActive}, values)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			code = protocol.BeneficiaryInactive
+			return errBeneficiaryStatusChanged
+		}
+		return s.audit(tx, beneficiary, protocol.BeneficiaryActionDeactivate, mid, source, protocol.MapData{
+			"status": beneficiaryChange(protocol.StatusActive, protocol.StatusInactive),
+		}, "")
+	})
+	if code != protocol.Success {
+		return nil, code
+	}
+	if err != nil {
+		log.Get().Errorf("Deactivate beneficiary %s failed: %v", beneficiaryID, err)
+		return nil, protocol.SystemError
+	}
+	return beneficiary.Protocol(), protocol.Success
+}
+
+// Verify 通过代付渠道对收款账户进行小额打款验证，已验证通过的收款人直接返回
+func (s *BeneficiaryService) Verify(mid, source, beneficiaryID string) (*protocol.Beneficiary, protocol.ErrorCode) {
+	beneficiary := models.GetMerchantBeneficiaryByID(mid, beneficiaryID)
+	if beneficiary == nil {
+		return nil, protocol.BeneficiaryNotFound
+	}
+	if beneficiary.GetStatus() != protocol.StatusActive {
+		return nil, protocol.BeneficiaryInactive
+	}
+	if beneficiary.GetVerifyStatus() == protocol.VerifyStatusVerified {
+		return beneficiary.Protocol(), protocol.Success
+	}
+	return s.verify(beneficiary, mid, source)
+}
+
+// ListAudits 收款人变更记录
+func (s *BeneficiaryService) ListAudits(mid string, req *protocol.BeneficiaryAuditListRequest) ([]*protocol.BeneficiaryAudit, int64, protocol.ErrorCode) {
+	if models.GetMerchantBeneficiaryByID(mid, req.BeneficiaryID) == nil {
+		return nil, 0, protocol.BeneficiaryNotFound
+	}
+	audits, total, err := models.ListBeneficiaryAudits(mid, req.BeneficiaryID, req.Page, req.Size)
+	if err != nil {
+		log.Get().Errorf("List beneficiary %s audits failed: %v", req.BeneficiaryID, err)
+		return nil, 0, protocol.SystemError
+	}
+	list := make([]*protocol.BeneficiaryAudit, 0, len(audits))
+	for _, audit := range audits {
+		list = append(list, audit.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// ResolveForPayout 代付引用收款人时校验收款人可用、币种一致且验证未失败
+func (s *BeneficiaryService) ResolveForPayout(mid, beneficiaryID, ccy string) (*models.MerchantBeneficiary, protocol.ErrorCode) {
+	beneficiary := models.GetMerchantBeneficiaryByID(mid, beneficiaryID)
+	if beneficiary == nil {
+		return nil, protocol.BeneficiaryNotFound
+	}
+	if beneficiary.GetStatus() != protocol.StatusActive {
+		return nil, protocol.BeneficiaryInactive
+	}
+	if beneficiary.Ccy != ccy {
+		return nil, protocol.BeneficiaryCcyMismatch
+	}
+	if beneficiary.GetVerifyStatus() == protocol.VerifyStatusFailed {
+		return nil, protocol.BeneficiaryVerifyFailed
+	}
+	return beneficiary, protocol.Success
+}
+
+// MatchForPayout 按账户指纹匹配未指定收款人ID的代付，复用已保存的收款人
+func (s *BeneficiaryService) MatchForPayout(mid, ccy, accountType, accountNo, bankCode string) *models.MerchantBeneficiary {
+	if accountNo == "" {
+		return nil
+	}
+	accountNo, bankCode = normalizeBeneficiaryAccount(accountType, accountNo, bankCode)
+	fingerprint := utils.BankAccountFingerprint(mid, ccy, accountType, accountNo, bankCode)
+	return models.GetActiveBeneficiaryByFingerprint(mid, fingerprint)
+}
+
+// MarkUsed 记录收款人最近一次用于代付的时间
+func (s *BeneficiaryService) MarkUsed(beneficiary *models.MerchantBeneficiary) {
+	values := &models.MerchantBeneficiaryValues{}
+	values.SetLastUsedAt(utils.TimeNowMilli())
+	if _, err := models.UpdateBeneficiaryValues(models.WriteDB, beneficiary, []string{protocol.StatusActive}, values); err != nil {
+		log.Get().Warnf("Mark beneficiary %s used failed: %v", beneficiary.BeneficiaryID, err)
+	}
+}
+
+var errBeneficiaryStatusChanged = errors.New("beneficiary status changed")
+
+// verify 选取商户代付路由中支持账户验证的渠道发起验证，渠道返回户名需与收款人姓名一致
+func (s *BeneficiaryService) verify(beneficiary *models.MerchantBeneficiary, operator, source string) (*protocol.Beneficiary, protocol.ErrorCode) {
+	trxMethod := protocol.TrxMethodBankTransfer
+	if beneficiary.AccountType == protocol.BeneficiaryAccountUPI {
+		trxMethod = protocol.TrxMethodUPI
+	}
+	amount := beneficiaryVerifyAmount
+	routerInfo := GetChannelRouterByMerchant(&models.Transaction{
+		Mid:       beneficiary.Mid,
+		TrxType:   protocol.TrxTypePayout,
+		Ccy:       beneficiary.Ccy,
+		Amount:    &amount,
+		TrxMethod: trxMethod,
+	})
+	if routerInfo == nil {
+		return nil, protocol.BeneficiaryVerifyUnsupported
+	}
+
+	var (
+		result  *protocol.AccountVerifyResult
+		account string
+	)
+	for _, accountID := range routerInfo.ChannelAccounts {
+		svc, ok := channels.GetOpenApiChannelService(accountID)
+		if !ok {
+			continue
+		}
+		verifier, ok := svc.(channels.AccountVerifier)
+		if !ok {
+			continue
+		}
+		account = accountID
+		result = verifier.VerifyAccount(&channels.AccountVerifyRequest{
+			Mid:         beneficiary.Mid,
+			RefID:       beneficiary.BeneficiaryID,
+			Country:     beneficiary.Country,
+			Ccy:         beneficiary.Ccy,
+			AccountType: beneficiary.AccountType,
+			AccountNo:   beneficiary.AccountNo,
+			AccountName: beneficiary.AccountName,
+			BankCode:    beneficiary.BankCode,
+		})
+		if result != nil {
+			break
+		}
+	}
+	if result == nil {
+		return nil, protocol.BeneficiaryVerifyUnsupported
+	}
+
+	status, msg := result.Status, result.ResMsg
+	if status == protocol.VerifyStatusVerified && !beneficiaryNameMatches(beneficiary.AccountName, result.VerifiedName) {
+		status, msg = protocol.VerifyStatusFailed, "account name mismatch"
+	}
+	values := &models.MerchantBeneficiaryValues{}
+	values.SetVerifyStatus(status).
+		SetVerifiedName(result.VerifiedName).
+		SetVerifyChannel(account).
+		SetVerifyTrxID(result.ChannelTrxID).
+		SetVerifyMsg(msg)
+	if status == protocol.VerifyStatusVerified {
+		values.SetVerifiedAt(utils.TimeNowMilli())
+	}
+
+	fromStatus := beneficiary.GetVerifyStatus()
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateBeneficiaryValues(tx, beneficiary, []string{protocol.StatusActive}, values)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			code = protocol.BeneficiaryInactive
+			return errBeneficiaryStatusChanged
+		}
+		return s.audit(tx, beneficiary, protocol.BeneficiaryActionVerify, operator, source, protocol.MapData{
+			"verify_status": beneficiaryChange(fromStatus, status),
+			"verified_name": result.VerifiedName,
+			"channel":       account,
+		}, msg)
+	})
+	if code != protocol.Success {
+		return nil, code
+	}
+	if err != nil {
+		log.Get().Errorf("Save beneficiary %s verification failed: %v", beneficiary.BeneficiaryID, err)
+		return nil, protocol.SystemError
+	}
+	return beneficiary.Protocol(), protocol.Success
+}
+
+// audit 写入收款人变更记录，与变更在同一事务内
+func (s *BeneficiaryService) audit(tx *gorm.DB, beneficiary *models.MerchantBeneficiary, action, operator, source string, changes protocol.MapData, remark string) error {
+	return tx.Create(&models.BeneficiaryAudit{
+		BeneficiaryID: beneficiary.BeneficiaryID,
+		Mid:           beneficiary.Mid,
+		Action:        action,
+		Operator:      operator,
+		Source:        source,
+		Changes:       changes,
+		Remark:        remark,
+	}).Error
+}
+
+// normalizeBeneficiaryAccount 规范化账号与银行编码，UPI VPA统一小写
+func normalizeBeneficiaryAccount(accountType, accountNo, bankCode string) (string, string) {
+	if accountType == protocol.BeneficiaryAccountUPI {
+		return strings.ToLower(strings.TrimSpace(accountNo)), ""
+	}
+	return utils.NormalizeBankField(accountNo), utils.NormalizeBankField(bankCode)
+}
+
+// beneficiaryValidateCode 账户校验错误转换为错误码
+func beneficiaryValidateCode(err error) protocol.ErrorCode {
+	switch {
+	case errors.Is(err, utils.ErrInvalidBankCode):
+		return protocol.BeneficiaryInvalidBankCode
+	case errors.Is(err, utils.ErrUnsupportedAccount):
+		return protocol.InvalidParams
+	default:
+		return protocol.BeneficiaryInvalidAccount
+	}
+}
+
+// beneficiaryNameMatches 忽略大小写、标点与多余空格比较户名
+func beneficiaryNameMatches(name, verifiedName string) bool {
+	normalize := func(value string) string {
+		fields := strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		return strings.Join(fields, " ")
+	}
+	return normalize(name) != "" && normalize(name) == normalize(verifiedName)
+}
+
+func beneficiaryChange(from, to any) protocol.MapData {
+	return protocol.MapData{"from": from, "to": to}
+}
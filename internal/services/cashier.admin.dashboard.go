@@ -79,8 +79,8 @@ func GetCashierTeamTodayStats(tid string, currency string) (*DashboardTodayStats
 	var recentPayins []models.CashierPayin
 	db.Where("tid = ?", team.Tid).Order("created_at DESC").Limit(3).Find(&recentPayins)
 	for i, payin := range recentPayins {
-		fmt.Printf("Recent payin %d: ID=%d, Amount=%.2f, Status=%s, CreatedAt=%d\n",
-			i+1, payin.ID, payin.Amount, payin.Status, payin.CreatedAt)
+		fmt.Printf("Recent payin %d: ID=%d, Amount=%s, Status=%s, CreatedAt=%d\n",
+			i+1, payin.ID, payin.Amount, payin.GetStatus(), payin.CreatedAt)
 	}
 
 	// 昨日代收 - 临时设为0，专注今日数据
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"mime/multipart"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// CashierMarginService 车队保证金服务：车队线下转账后提交保证金充值及转账凭证，管理员确认到账后计入保证金；
+// 车队承接的未结算代收金额不得超过保证金余额乘以配置倍数，车队下线且代收全部结算后自动释放保证金
+type CashierMarginService struct{}
+
+var (
+	cashierMarginService     *CashierMarginService
+	cashierMarginServiceOnce sync.Once
+)
+
+// marginOutstandingStatuses 已分配给车队、尚未进入失败类终态的代收状态
+var marginOutstandingStatuses = []string{
+	protocol.StatusPending,
+	protocol.StatusProcessing,
+	protocol.StatusSubmitted,
+	protocol.StatusConfirming,
+}
+
+const marginReleaseBatchSize = 100
+
+func init() {
+	task.RegisterHandler(protocol.CashierMarginRelease, HandleCashierMarginRelease)
+}
+
+func SetupCashierMarginService() {
+	cashierMarginServiceOnce.Do(func() {
+		cashierMarginService = &CashierMarginService{}
+	})
+}
+
+// GetCashierMarginService 获取车队保证金服务单例
+func GetCashierMarginService() *CashierMarginService {
+	if cashierMarginService == nil {
+		SetupCashierMarginService()
+	}
+	return cashierMarginService
+}
+
+// Submit 车队提交保证金充值，转账凭证图片必传，待管理员确认到账
+func (s *CashierMarginService) Submit(team *models.CashierTeam, req *protocol.SubmitMarginDepositRequest, image *multipart.FileHeader) (*protocol.CashierMarginDeposit, protocol.ErrorCode) {
+	if team.GetStatus() == protocol.CashierTeamStatusOffboarded {
+		return nil, protocol.CashierTeamOffboarded
+	}
+	if !protocol.IsValidCurrency(req.Ccy) {
+		return nil, protocol.InvalidCurrency
+	}
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, protocol.InvalidAmount
+	}
+	if image == nil {
+		return nil, protocol.ProofImageInvalid
+	}
+	if models.CountActiveCashierDepositByTransferRef(req.TransferRef) > 0 {
+		return nil, protocol.DuplicateTransaction
+	}
+	account, code := s.ensureAccount(team.Tid, req.Ccy)
+	if code != protocol.Success {
+		return nil, code
+	}
+
+	deposit := &models.CashierDeposit{
+		TrxID:                utils.GenerateDepositID(),
+		Tid:                  team.Tid,
+		AccountID:            account.AccountID,
+		CashierDepositValues: &models.CashierDepositValues{},
+	}
+	path, code := GetPaymentProofService().saveImage(deposit.TrxID, image)
+	if code != protocol.Success {
+		return nil, code
+	}
+	deposit.ImagePath = path
+	deposit.SetStatus(protocol.StatusPending).
+		SetCcy(req.Ccy).
+		SetAmount(amount).
+		SetFee(decimal.Zero).
+		SetTransferRef(req.TransferRef).
+		SetRemark(req.Remark).
+		SetNotifyURL(team.GetNotifyURL())
+	if err := models.WriteDB.Create(deposit).Error; err != nil {
+		log.Get().Errorf("Create margin deposit failed: tid=%s, err=%v", team.Tid, err)
+		return nil, protocol.DatabaseError
+	}
+	return deposit.Protocol(), protocol.Success
+}
+
+// ensureAccount 获取车队该币种账户，首次充值时开户
+func (s *CashierMarginService) ensureAccount(tid, ccy string) (*models.Account, protocol.ErrorCode) {
+	return GetAccountService().GetOrCreateAccount(tid, protocol.UserTypeCashierTeam, ccy)
+}
+
+// List 保证金充值列表，tid为空时查询全部车队
+func (s *CashierMarginService) List(tid string, req *protocol.MarginDepositListRequest) ([]*protocol.CashierMarginDeposit, int64, protocol.ErrorCode) {
+	if tid == "" {
+		tid = req.Tid
+	}
+	deposits, total, err := models.ListCashierDepositByQuery(&models.CashierDepositQuery{
+		Tid:            tid,
+		TrxID:          req.TrxID,
+		Status:         req.Status,
+		Ccy:            req.Ccy,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.CashierMarginDeposit, 0, len(deposits))
+	for _, deposit := range deposits {
+		list = append(list, deposit.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 保证金充值详情，tid为空时不限制车队
+func (s *CashierMarginService) Get(tid, trxID string) (*protocol.CashierMarginDeposit, protocol.ErrorCode) {
+	deposit := models.GetCashierDeposit(tid, trxID)
+	if deposit == nil {
+		return nil, protocol.MarginDepositNotFound
+	}
+	return deposit.Protocol(), protocol.Success
+}
+
+// ImagePath 获取转账凭证图片路径，供审核人查看
+func (s *CashierMarginService) ImagePath(tid, trxID string) (string, protocol.ErrorCode) {
+	deposit := models.GetCashierDeposit(tid, trxID)
+	if deposit == nil || deposit.ImagePath == "" {
+		return "", protocol.MarginDepositNotFound
+	}
+	return deposit.ImagePath, protocol.Success
+}
+
+// Cancel 车队撤销待确认的保证金充值
+func (s *CashierMarginService) Cancel(tid, trxID string) (*protocol.CashierMarginDeposit, protocol.ErrorCode) {
+	deposit := models.GetCashierDeposit(tid, trxID)
+	if deposit == nil {
+		return nil, protocol.MarginDepositNotFound
+	}
+	values := &models.CashierDepositValues{}
+	values.SetStatus(protocol.StatusCancelled).
+		SetCanceledAt(utils.TimeNowMilli())
+	ok, err := models.UpdateCashierDepositValues(models.WriteDB, deposit, []string{protocol.StatusPending}, values)
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.MarginDepositStatusInvalid
+	}
+	return deposit.Protocol(), protocol.Success
+}
+
+// Confirm 管理员确认保证金到账：同一事务内入账车队余额并转入保证金
+func (s *CashierMarginService) Confirm(admin *models.Admin, req *protocol.ConfirmMarginDepositRequest) (*protocol.CashierMarginDeposit, protocol.ErrorCode) {
+	if code := GetAdminAdjustmentService().verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	deposit := models.GetCashierDeposit("", req.TrxID)
+	if deposit == nil {
+		return nil, protocol.MarginDepositNotFound
+	}
+	team := models.GetCashierTeamByTid(deposit.Tid)
+	if team == nil {
+		return nil, protocol.CashierNotFound
+	}
+	if team.GetStatus() == protocol.CashierTeamStatusOffboarded {
+		return nil, protocol.CashierTeamOffboarded
+	}
+
+	now := utils.TimeNowMilli()
+	values := &models.CashierDepositValues{}
+	values.SetStatus(protocol.StatusSuccess).
+		SetReviewedBy(admin.UserID).
+		SetConfirmedAt(now).
+		SetCompletedAt(now)
+	if req.Remark != "" {
+		values.SetRemark(req.Remark)
+	}
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateCashierDepositValues(tx, deposit, []string{protocol.StatusPending}, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.MarginDepositStatusInvalid
+			return protocol.NewServiceError(code, "margin deposit status changed")
+		}
+		for _, trxType := range []string{protocol.TrxTypeDeposit, protocol.TrxTypeMarginDeposit} {
+			code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+				UserID:      deposit.Tid,
+				UserType:    protocol.UserTypeCashierTeam,
+				Ccy:         deposit.GetCcy(),
+				Amount:      deposit.GetAmount(),
+				TrxID:       deposit.TrxID,
+				TrxType:     trxType,
+				OperatorID:  admin.UserID,
+				Description: "margin deposit confirmed",
+			})
+			if code != protocol.Success {
+				return protocol.NewServiceError(code, "post margin deposit failed")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Confirm margin deposit: trx_id=%s, err=%v", deposit.TrxID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	return deposit.Protocol(), protocol.Success
+}
+
+// Reject 管理员驳回未到账的保证金充值
+func (s *CashierMarginService) Reject(operatorID string, req *protocol.RejectMarginDepositRequest) (*protocol.CashierMarginDeposit, protocol.ErrorCode) {
+	deposit := models.GetCashierDeposit("", req.TrxID)
+	if deposit == nil {
+		return nil, protocol.MarginDepositNotFound
+	}
+	now := utils.TimeNowMilli()
+	values := &models.CashierDepositValues{}
+	values.SetStatus(protocol.StatusRejected).
+		SetReason(req.Reason).
+		SetReviewedBy(operatorID).
+		SetConfirmedAt(now).
+		SetCompletedAt(now)
+	ok, err := models.UpdateCashierDepositValues(models.WriteDB, deposit, []string{protocol.StatusPending}, values)
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.MarginDepositStatusInvalid
+	}
+	return deposit.Protocol(), protocol.Success
+}
+
+// Capacity 车队代收额度：保证金余额乘以配置倍数，扣除处理中及成功未结算的代收金额
+func (s *CashierMarginService) Capacity(tid, ccy string) (*protocol.CashierMarginCapacity, protocol.ErrorCode) {
+	if models.GetCashierTeamByTid(tid) == nil {
+		return nil, protocol.CashierNotFound
+	}
+	margin := decimal.Zero
+	if account, err := models.GetAccountByUserIDAndCurrency(tid, protocol.UserTypeCashierTeam, ccy); err == nil && account != nil && account.Asset != nil {
+		margin = account.Asset.MarginBalance
+	}
+	outstanding, err := s.outstanding(tid, ccy)
+	if err != nil {
+		log.Get().Errorf("Sum cashier outstanding payin: tid=%s, ccy=%s, err=%v", tid, ccy, err)
+		return nil, protocol.DatabaseError
+	}
+	multiplier := config.Get().CashierMargin.GetCapacityMultiplier()
+	capacity := margin.Mul(multiplier)
+	return &protocol.CashierMarginCapacity{
+		Tid:           tid,
+		Ccy:           ccy,
+		MarginBalance: margin.String(),
+		Multiplier:    multiplier.String(),
+		Capacity:      capacity.String(),
+		Outstanding:   outstanding.String(),
+		Available:     decimal.Max(capacity.Sub(outstanding), decimal.Zero).String(),
+	}, protocol.Success
+}
+
+// CheckPayinCapacity 校验车队承接该笔代收后未结算金额是否仍在保证金额度内，已下线的车队不再承接代收
+func (s *CashierMarginService) CheckPayinCapacity(tid, ccy string, amount decimal.Decimal) protocol.ErrorCode {
+	team := models.GetCashierTeamByTid(tid)
+	if team == nil {
+		return protocol.CashierNotFound
+	}
+	if team.GetStatus() == protocol.CashierTeamStatusOffboarded {
+		return protocol.CashierTeamOffboarded
+	}
+	capacity, code := s.Capacity(tid, ccy)
+	if code != protocol.Success {
+		return code
+	}
+	available, _ := decimal.NewFromString(capacity.Available)
+	if amount.GreaterThan(available) {
+		return protocol.MarginCapacityExceeded
+	}
+	return protocol.Success
+}
+
+// outstanding 车队承接的处理中及成功未结算的代收金额
+func (s *CashierMarginService) outstanding(tid, ccy string) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := models.GetTransactionQueryByType(protocol.TrxTypePayin).
+		Where("tid = ? AND ccy = ?", tid, ccy).
+		Where("status IN ? OR (status = ? AND (settle_status IS NULL OR settle_status <> ?))",
+			marginOutstandingStatuses, protocol.StatusSuccess, protocol.StatusSuccess).
+		Select("SUM(amount)").
+		Scan(&total).Error
+	return total.Decimal, err
+}
+
+// Offboard 管理员下线车队，下线后不再承接代收，未结算代收全部结算后释放保证金
+func (s *CashierMarginService) Offboard(admin *models.Admin, req *protocol.OffboardCashierTeamRequest) (*protocol.CashierTeam, protocol.ErrorCode) {
+	if code := GetAdminAdjustmentService().verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	team := models.GetCashierTeamByTid(req.Tid)
+	if team == nil {
+		return nil, protocol.CashierNotFound
+	}
+	ok, err := models.UpdateCashierTeamStatus(models.WriteDB, team, protocol.CashierTeamStatusOffboarded)
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.CashierTeamOffboarded
+	}
+	log.Get().Infof("Cashier team offboarded: tid=%s, operator=%s, reason=%s", team.Tid, admin.UserID, req.Reason)
+	s.releaseMargin(team.Tid, admin.UserID)
+	return team.Protocol(), protocol.Success
+}
+
+// releaseMargin 释放已下线车队各币种的保证金，仍有未结算代收的币种等待定时任务重试
+func (s *CashierMarginService) releaseMargin(tid, operatorID string) {
+	for _, account := range models.GetAccountsByUserID(tid, protocol.UserTypeCashierTeam) {
+		if account.Asset == nil || !account.Asset.MarginBalance.IsPositive() {
+			continue
+		}
+		outstanding, err := s.outstanding(tid, account.Ccy)
+		if err != nil {
+			log.Get().Errorf("Sum cashier outstanding payin: tid=%s, ccy=%s, err=%v", tid, account.Ccy, err)
+			continue
+		}
+		if outstanding.IsPositive() {
+			continue
+		}
+		code := GetAccountService().UpdateBalance(&protocol.UpdateBalanceRequest{
+			UserID:      tid,
+			UserType:    protocol.UserTypeCashierTeam,
+			Ccy:         account.Ccy,
+			Amount:      account.Asset.MarginBalance,
+			TrxID:       utils.GenerateMarginReleaseID(),
+			TrxType:     protocol.TrxTypeMarginRelease,
+			OperatorID:  operatorID,
+			Description: "cashier team offboarded",
+		})
+		if code != protocol.Success {
+			log.Get().Errorf("Release cashier margin: tid=%s, ccy=%s, code=%s", tid, account.Ccy, code)
+		}
+	}
+}
+
+// RegisterCashierMarginTasks 注册车队保证金释放任务
+func RegisterCashierMarginTasks() {
+	log.Get().Info("注册车队保证金任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "cashier_margin_release",
+			Type:       protocol.CashierMarginRelease,
+			HandlerKey: protocol.CashierMarginRelease,
+			Name:       "已下线车队保证金释放",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 10m"}[0], // 每10分钟执行一次
+				Timeout: &[]int{600}[0],            // 10分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("车队保证金任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleCashierMarginRelease 已下线车队的未结算代收全部结算后释放保证金
+func HandleCashierMarginRelease(ctx context.Context, params protocol.MapData) error {
+	service := GetCashierMarginService()
+	var lastID int64
+	for {
+		teams, err := models.ListCashierTeamsByStatus(protocol.CashierTeamStatusOffboarded, lastID, marginReleaseBatchSize)
+		if err != nil {
+			return fmt.Errorf("查询已下线车队失败: %v", err)
+		}
+		for _, team := range teams {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			service.releaseMargin(team.Tid, protocol.System)
+		}
+		if len(teams) < marginReleaseBatchSize {
+			return nil
+		}
+		lastID = teams[len(teams)-1].ID
+	}
+}
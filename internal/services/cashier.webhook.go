@@ -0,0 +1,94 @@
+package services
+
+import (
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// newCashierTeamWebhook 生成交易相关的收银团队通知，交易未分配到团队或团队未配置通知地址时返回nil
+func newCashierTeamWebhook(trx *models.Transaction, event string) *models.Webhook {
+	if trx.Tid == "" {
+		return nil
+	}
+	team := models.GetCashierTeamByTid(trx.Tid)
+	if team == nil || team.GetNotifyURL() == "" {
+		return nil
+	}
+	webhook := newWebhook(protocol.UserTypeCashierTeam, trx.Tid, team.GetNotifyURL())
+	webhook.SetTransactionID(trx.TrxID).
+		SetType(event).
+		SetStatus(trx.GetStatus()).
+		SetAmount(trx.GetAmount()).
+		SetCcy(trx.Ccy).
+		SetAPIVersion(protocol.WebhookAPIVersionV1)
+	return webhook
+}
+
+// createCashierTeamWebhook 生成收银团队通知，推送内容不含商户信息
+func createCashierTeamWebhook(db *gorm.DB, trx *models.Transaction, event, billID string) error {
+	webhook := newCashierTeamWebhook(trx, event)
+	if webhook == nil {
+		return nil
+	}
+	if billID != "" {
+		webhook.SetBillID(billID)
+	}
+	webhook.SetRequestBody(utils.ToJsonString(&protocol.CashierTeamNotify{
+		WebhookID: webhook.WebhookID,
+		Event:     event,
+		Tid:       trx.Tid,
+		CashierID: trx.CashierID,
+		TrxID:     trx.TrxID,
+		TrxType:   trx.TrxType,
+		BillID:    billID,
+		Status:    webhook.GetStatus(),
+		Amount:    webhook.GetAmount().String(),
+		Ccy:       webhook.GetCcy(),
+		CreatedAt: utils.TimeNowMilli(),
+	}))
+	return models.CreateWebhook(db, webhook)
+}
+
+// CreateCashierAssignedWebhook 交易完成渠道分配后由收银团队承接时通知团队
+func CreateCashierAssignedWebhook(db *gorm.DB, trx *models.Transaction) error {
+	switch trx.TrxType {
+	case protocol.TrxTypePayin:
+		return createCashierTeamWebhook(db, trx, protocol.CashierWebhookPayinAssigned, "")
+	case protocol.TrxTypePayout:
+		return createCashierTeamWebhook(db, trx, protocol.CashierWebhookPayoutAssigned, "")
+	}
+	return nil
+}
+
+// CreateCashierCompletedWebhook 团队处理的交易进入终态时通知团队
+func CreateCashierCompletedWebhook(db *gorm.DB, trx *models.Transaction) error {
+	if !slices.Contains(protocol.TrxFinalStatusList, trx.GetStatus()) {
+		return nil
+	}
+	return createCashierTeamWebhook(db, trx, protocol.CashierWebhookTrxCompleted, "")
+}
+
+// CreateCashierSettledWebhook 团队处理的交易完成结算时通知团队
+func CreateCashierSettledWebhook(db *gorm.DB, trx *models.Transaction, settleLogID string) error {
+	return createCashierTeamWebhook(db, trx, protocol.CashierWebhookSettlePosted, settleLogID)
+}
+
+// UpdateNotifyURL 修改收银团队通知地址，为空表示不再接收通知
+func (s *CashierAdminService) UpdateNotifyURL(team *models.CashierTeam, notifyURL string) protocol.ErrorCode {
+	if notifyURL != "" && utils.ValidatePublicURL(notifyURL) != nil {
+		return protocol.InvalidWebhookURL
+	}
+	values := &models.CashierTeamValues{}
+	values.SetNotifyURL(notifyURL)
+	if err := models.WriteDB.Model(team).Updates(values).Error; err != nil {
+		log.Get().Errorf("Update notify url of cashier team %s failed: %v", team.Tid, err)
+		return protocol.DatabaseError
+	}
+	team.SetValues(values)
+	return protocol.Success
+}
@@ -50,7 +50,7 @@ func (s *ConfigService) GetTrxConfigByMerchantID(merchantID, trxType string) *mo
 func (s *ConfigService) SaveMerchantConfig(merchantID, trxType string, config *models.TrxConfig) error {
 	return s.db.Transaction(func(tx *gorm.DB) error {
 		var record models.MerchantConfig
-		err := tx.Where("merchant_id = ? AND type = ?", merchantID, trxType).First(&record).Error
+		err := tx.Where("mid = ? AND type = ?", merchantID, trxType).First(&record).Error
 
 		if err == gorm.ErrRecordNotFound {
 			// 新建记录
@@ -78,7 +78,7 @@ func (s *ConfigService) SaveMerchantConfig(merchantID, trxType string, config *m
 // GetMerchantConfig 获取商户配置记录
 func (s *ConfigService) GetMerchantConfig(merchantID, trxType string) (*models.MerchantConfig, error) {
 	var record models.MerchantConfig
-	err := s.db.Where("merchant_id = ? AND type = ?", merchantID, trxType).First(&record).Error
+	err := s.db.Where("mid = ? AND type = ?", merchantID, trxType).First(&record).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -90,7 +90,7 @@ func (s *ConfigService) GetMerchantConfig(merchantID, trxType string) (*models.M
 
 // DeleteMerchantConfig 删除商户配置
 func (s *ConfigService) DeleteMerchantConfig(merchantID, trxType string) error {
-	result := s.db.Where("merchant_id = ? AND type = ?", merchantID, trxType).Delete(&models.MerchantConfig{})
+	result := s.db.Where("mid = ? AND type = ?", merchantID, trxType).Delete(&models.MerchantConfig{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete config: %w", result.Error)
 	}
@@ -103,7 +103,7 @@ func (s *ConfigService) ListMerchantConfigs(merchantID string) ([]*models.Mercha
 	query := s.db.Model(&models.MerchantConfig{})
 
 	if merchantID != "" {
-		query = query.Where("merchant_id = ?", merchantID)
+		query = query.Where("mid = ?", merchantID)
 	}
 
 	err := query.Order("created_at DESC").Find(&configs).Error
@@ -151,6 +151,7 @@ func (s *ConfigService) InitDefaultConfigs() error {
 		models.TrxTypeDeposit,
 		models.TrxTypeWithdraw,
 		models.TrxTypeRefund,
+		models.TrxTypeConvert,
 	}
 
 	for _, trxType := range trxTypes {
@@ -229,7 +230,7 @@ func (s *ConfigService) ValidateAmount(merchantID, trxType, currency string, amo
 // getMerchantConfig 获取商户配置记录（内部方法）
 func (s *ConfigService) getMerchantConfig(merchantID, trxType string) *models.MerchantConfig {
 	var record models.MerchantConfig
-	err := s.db.Where("merchant_id = ? AND type = ?", merchantID, trxType).First(&record).Error
+	err := s.db.Where("mid = ? AND type = ?", merchantID, trxType).First(&record).Error
 	if err != nil {
 		return nil
 	}
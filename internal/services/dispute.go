@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// DisputeService 争议/退单服务：登记时按商户余额冻结争议金额，裁决后解冻，败诉则扣款
+type DisputeService struct{}
+
+var (
+	disputeService     *DisputeService
+	disputeServiceOnce sync.Once
+)
+
+func init() {
+	task.RegisterHandler(protocol.DisputeExpire, HandleDisputeExpire)
+}
+
+func SetupDisputeService() {
+	disputeServiceOnce.Do(func() {
+		disputeService = &DisputeService{}
+	})
+}
+
+// GetDisputeService 获取争议服务单例
+func GetDisputeService() *DisputeService {
+	if disputeService == nil {
+		SetupDisputeService()
+	}
+	return disputeService
+}
+
+// Create 登记渠道退单或付款人投诉，冻结商户账户中的争议金额并通知商户；
+// 商户余额不足时争议照常登记，冻结可用部分并记录差额，败诉时再扣回；
+// 代收资金在结算时才入账商户余额，未结算的代收没有可冻结的余额，需结算后再登记
+func (s *DisputeService) Create(operatorID string, req *protocol.CreateDisputeRequest) (*protocol.Dispute, protocol.ErrorCode) {
+	trx := models.GetTransactionByTrxID(req.TrxID, protocol.TrxTypePayin)
+	if trx == nil {
+		return nil, protocol.TransactionNotFound
+	}
+	if trx.GetStatus() != protocol.StatusSuccess {
+		return nil, protocol.DisputeTrxNotEligible
+	}
+	if trx.GetSettleStatus() != protocol.StatusSuccess {
+		return nil, protocol.DisputeTrxNotSettled
+	}
+	if models.CountActiveDisputeByTrxID(trx.TrxID) > 0 {
+		return nil, protocol.DisputeAlreadyOpen
+	}
+	amount := trx.GetAmount()
+	if req.Amount != "" {
+		var err error
+		if amount, err = decimal.NewFromString(req.Amount); err != nil || !amount.IsPositive() {
+			return nil, protocol.InvalidParams
+		}
+		if amount.GreaterThan(trx.GetAmount()) {
+			return nil, protocol.DisputeAmountExceeded
+		}
+	}
+	now := utils.TimeNowMilli()
+	deadline := req.Deadline
+	if deadline == 0 {
+		deadline = time.Now().AddDate(0, 0, protocol.DefaultDisputeResponseDays).UnixMilli()
+	}
+	if deadline <= now {
+		return nil, protocol.InvalidParams
+	}
+
+	dispute := &models.Dispute{
+		DisputeID:     utils.GenerateDisputeID(),
+		Mid:           trx.Mid,
+		TrxID:         trx.TrxID,
+		TrxType:       trx.TrxType,
+		Type:          req.Type,
+		ReasonCode:    req.ReasonCode,
+		ReasonMsg:     req.ReasonMsg,
+		ChannelCaseID: req.ChannelCaseID,
+		Ccy:           trx.Ccy,
+		CreatedBy:     operatorID,
+		DisputeValues: &models.DisputeValues{},
+	}
+	dispute.SetStatus(protocol.DisputeStatusOpen).
+		SetAmount(amount).
+		SetDeadline(deadline)
+
+	merchant := models.GetMerchantByMID(trx.Mid)
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		frozen := decimal.Min(amount, merchantBalanceForUpdate(tx, dispute.Mid, dispute.Ccy))
+		dispute.SetFrozenAmount(frozen).
+			SetShortfall(amount.Sub(frozen))
+		if err := tx.Create(dispute).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if frozen.IsPositive() {
+			code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+				UserID:      dispute.Mid,
+				UserType:    protocol.UserTypeMerchant,
+				Ccy:         dispute.Ccy,
+				Amount:      frozen,
+				TrxID:       dispute.DisputeID,
+				TrxType:     protocol.TrxTypeFreeze,
+				OperatorID:  operatorID,
+				Description: fmt.Sprintf("dispute opened on %s", dispute.TrxID),
+			})
+			if code != protocol.Success {
+				return protocol.NewServiceError(code, "freeze dispute amount failed")
+			}
+		}
+		if dispute.GetShortfall().IsPositive() {
+			log.Get().Warnf("Create dispute: dispute_id=%s, merchant %s balance short of dispute amount, frozen %s, shortfall %s %s",
+				dispute.DisputeID, dispute.Mid, frozen, dispute.GetShortfall(), dispute.Ccy)
+		}
+		return s.createWebhook(tx, dispute, merchant, trx)
+	})
+	if err != nil {
+		log.Get().Errorf("Create dispute: trx_id=%s, err=%v", trx.TrxID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	s.notify(dispute, merchant, protocol.MsgTypeDisputeOpened)
+	return dispute.Protocol(), protocol.Success
+}
+
+// List 争议列表，mid为空时查询全部商户（管理后台争议队列）
+func (s *DisputeService) List(mid string, req *protocol.DisputeListRequest) ([]*protocol.Dispute, int64, protocol.ErrorCode) {
+	query := &models.DisputeQuery{
+		Mid:            req.Mid,
+		TrxID:          req.TrxID,
+		DisputeID:      req.DisputeID,
+		Type:           req.Type,
+		Status:         req.Status,
+		ReasonCode:     req.ReasonCode,
+		DeadlineBefore: req.DeadlineBefore,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	}
+	if mid != "" {
+		query.Mid = mid
+	}
+	disputes, total, err := models.ListDisputeByQuery(query)
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.Dispute, 0, len(disputes))
+	for _, dispute := range disputes {
+		list = append(list, dispute.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 争议详情
+func (s *DisputeService) Get(mid, disputeID string) (*protocol.Dispute, protocol.ErrorCode) {
+	dispute := models.GetDisputeByID(mid, disputeID)
+	if dispute == nil {
+		return nil, protocol.DisputeNotFound
+	}
+	return dispute.Protocol(), protocol.Success
+}
+
+// Respond 商户在截止时间前提交证据或接受退单
+func (s *DisputeService) Respond(mid string, req *protocol.DisputeRespondRequest) (*protocol.Dispute, protocol.ErrorCode) {
+	dispute := models.GetDisputeByID(mid, req.DisputeID)
+	if dispute == nil {
+		return nil, protocol.DisputeNotFound
+	}
+	if dispute.IsResolved() {
+		return nil, protocol.DisputeAlreadyResolved
+	}
+	now := utils.TimeNowMilli()
+	if dispute.GetDeadline() > 0 && now > dispute.GetDeadline() {
+		return nil, protocol.DisputeDeadlinePassed
+	}
+	if req.Action == protocol.DisputeActionAccept {
+		return s.resolve(dispute, protocol.DisputeStatusLost, mid, req.Remark)
+	}
+	if len(req.Evidence) == 0 {
+		return nil, protocol.InvalidParams
+	}
+
+	evidence := append([]*protocol.DisputeEvidence{}, dispute.Evidence...)
+	for _, item := range req.Evidence {
+		item.UploadedBy = mid
+		item.UploadedAt = now
+		evidence = append(evidence, item)
+	}
+	values := &models.DisputeValues{}
+	values.SetStatus(protocol.DisputeStatusEvidenceSubmitted).
+		SetEvidence(evidence).
+		SetMerchantRemark(req.Remark).
+		SetRespondedAt(now)
+	ok, err := models.UpdateDisputeValues(models.WriteDB, dispute,
+		[]string{protocol.DisputeStatusOpen, protocol.DisputeStatusEvidenceSubmitted}, values)
+	if err != nil {
+		log.Get().Errorf("Respond dispute: dispute_id=%s, err=%v", dispute.DisputeID, err)
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.DisputeAlreadyResolved
+	}
+	return dispute.Protocol(), protocol.Success
+}
+
+// Resolve 管理后台裁决争议
+func (s *DisputeService) Resolve(operatorID string, req *protocol.ResolveDisputeRequest) (*protocol.Dispute, protocol.ErrorCode) {
+	dispute := models.GetDisputeByID("", req.DisputeID)
+	if dispute == nil {
+		return nil, protocol.DisputeNotFound
+	}
+	if dispute.IsResolved() {
+		return nil, protocol.DisputeAlreadyResolved
+	}
+	return s.resolve(dispute, req.Result, operatorID, req.Remark)
+}
+
+// merchantBalanceForUpdate 锁定商户账户并返回可冻结、扣款的余额，账户不存在时为零
+func merchantBalanceForUpdate(tx *gorm.DB, mid, ccy string) decimal.Decimal {
+	account, err := models.GetAccountForUpdate(tx, mid, protocol.UserTypeMerchant, ccy)
+	if err != nil || account.Asset == nil || !account.Asset.Balance.IsPositive() {
+		return decimal.Zero
+	}
+	return account.Asset.Balance
+}
+
+// resolve 裁决争议：解冻已冻结金额，败诉时以退单类型扣款，登记时未冻结的差额按当前余额扣回，
+// 仍不足的部分记为未扣回金额，并通知商户
+func (s *DisputeService) resolve(dispute *models.Dispute, result, operatorID, remark string) (*protocol.Dispute, protocol.ErrorCode) {
+	values := &models.DisputeValues{}
+	values.SetStatus(result).
+		SetResolveRemark(remark).
+		SetResolvedBy(operatorID).
+		SetResolvedAt(utils.TimeNowMilli())
+
+	trx := models.GetTransactionByTrxID(dispute.TrxID, dispute.TrxType)
+	merchant := models.GetMerchantByMID(dispute.Mid)
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateDisputeValues(tx, dispute,
+			[]string{protocol.DisputeStatusOpen, protocol.DisputeStatusEvidenceSubmitted}, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.DisputeAlreadyResolved
+			return protocol.NewServiceError(code, "dispute already resolved")
+		}
+		frozen := dispute.GetFrozenAmount()
+		balanceReq := &protocol.UpdateBalanceRequest{
+			UserID:      dispute.Mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         dispute.Ccy,
+			Amount:      frozen,
+			TrxID:       dispute.DisputeID,
+			TrxType:     protocol.TrxTypeUnfreeze,
+			OperatorID:  operatorID,
+			Description: fmt.Sprintf("dispute %s", result),
+		}
+		if frozen.IsPositive() {
+			if code = GetAccountService().UpdateBalanceWithTx(tx, balanceReq); code != protocol.Success {
+				return protocol.NewServiceError(code, "unfreeze dispute amount failed")
+			}
+		}
+		if result != protocol.DisputeStatusLost {
+			return s.createWebhook(tx, dispute, merchant, trx)
+		}
+		// 已冻结部分解冻后余额足以扣回，差额部分按当前余额扣回
+		shortfall := dispute.GetShortfall()
+		recovered := decimal.Min(shortfall, merchantBalanceForUpdate(tx, dispute.Mid, dispute.Ccy).Sub(frozen))
+		if recovered.IsNegative() {
+			recovered = decimal.Zero
+		}
+		balanceReq.TrxType = protocol.TrxTypeChargeback
+		balanceReq.Amount = frozen.Add(recovered)
+		balanceReq.Description = fmt.Sprintf("chargeback for %s", dispute.TrxID)
+		if balanceReq.Amount.IsPositive() {
+			if code = GetAccountService().UpdateBalanceWithTx(tx, balanceReq); code != protocol.Success {
+				return protocol.NewServiceError(code, "debit dispute amount failed")
+			}
+		}
+		if !shortfall.IsZero() {
+			unrecovered := &models.DisputeValues{}
+			unrecovered.SetShortfall(shortfall.Sub(recovered))
+			if _, err := models.UpdateDisputeValues(tx, dispute, []string{result}, unrecovered); err != nil {
+				code = protocol.DatabaseError
+				return err
+			}
+			if dispute.GetShortfall().IsPositive() {
+				log.Get().Warnf("Resolve dispute: dispute_id=%s, merchant %s chargeback unrecovered %s %s",
+					dispute.DisputeID, dispute.Mid, dispute.GetShortfall(), dispute.Ccy)
+			}
+		}
+		return s.createWebhook(tx, dispute, merchant, trx)
+	})
+	if err != nil {
+		log.Get().Errorf("Resolve dispute: dispute_id=%s, err=%v", dispute.DisputeID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	s.notify(dispute, merchant, protocol.MsgTypeDisputeResolved)
+	return dispute.Protocol(), protocol.Success
+}
+
+// createWebhook 生成争议通知，优先使用商户配置的通知地址
+func (s *DisputeService) createWebhook(tx *gorm.DB, dispute *models.Dispute, merchant *models.Merchant, trx *models.Transaction) error {
+	notifyURL := ""
+	if merchant != nil {
+		notifyURL = merchant.GetNotifyURL()
+	}
+	if notifyURL == "" && trx != nil {
+		notifyURL = trx.GetNotifyURL()
+	}
+	return CreateWebhooks(tx, NewDisputeWebhook(dispute, notifyURL))
+}
+
+// notify 邮件通知商户
+func (s *DisputeService) notify(dispute *models.Dispute, merchant *models.Merchant, msgType string) {
+	if merchant == nil || merchant.GetEmail() == "" {
+		return
+	}
+	msg := &Message{
+		Type:     msgType,
+		To:       merchant.GetEmail(),
+		Language: protocol.LangEnglish,
+		Params: map[string]any{
+			"to":          merchant.GetEmail(),
+			"dispute_id":  dispute.DisputeID,
+			"trx_id":      dispute.TrxID,
+			"type":        dispute.Type,
+			"reason_code": dispute.ReasonCode,
+			"status":      dispute.GetStatus(),
+			"amount":      dispute.GetAmount().String(),
+			"ccy":         dispute.Ccy,
+			"deadline":    time.UnixMilli(dispute.GetDeadline()).UTC().Format(time.RFC3339),
+			"remark":      dispute.GetResolveRemark(),
+		},
+	}
+	if err := GetMessageService().SendEmailMessage(msg); err != nil {
+		log.Get().Errorf("Send dispute email for %s failed: %v", dispute.DisputeID, err)
+	}
+}
+
+func RegisterDisputeTasks() {
+	log.Get().Info("注册争议任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "dispute_expire",
+			Type:       protocol.DisputeExpire,
+			HandlerKey: protocol.DisputeExpire,
+			Name:       "争议超期处理",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 10m"}[0], // 每10分钟执行一次
+				Timeout: &[]int{600}[0],            // 10分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("争议任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleDisputeExpire 商户超过截止时间未响应的争议按败诉处理
+func HandleDisputeExpire(ctx context.Context, params protocol.MapData) error {
+	disputes, err := models.ListExpiredDisputes(utils.TimeNowMilli(), 100)
+	if err != nil {
+		return fmt.Errorf("查询超期争议失败: %v", err)
+	}
+	service := GetDisputeService()
+	for _, dispute := range disputes {
+		if _, code := service.resolve(dispute, protocol.DisputeStatusLost, protocol.System, "no response before deadline"); code != protocol.Success {
+			log.Get().Errorf("Expire dispute %s failed: %s", dispute.DisputeID, code)
+		}
+	}
+	return nil
+}
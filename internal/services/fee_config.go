@@ -1,6 +1,12 @@
 package services
 
-import "sync"
+import (
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
 
 type MerchantFeeConfigService struct {
 }
@@ -23,3 +29,63 @@ func GetFeeConfigService() *MerchantFeeConfigService {
 	}
 	return merchantfeeConfigService
 }
+
+// ResolveFeeConfig 按国家、支付方式、币种匹配最精确的商户费率配置，同等精确度取最近更新的一条
+func (s *MerchantFeeConfigService) ResolveFeeConfig(trx *models.Transaction) *models.MerchantFeeConfig {
+	var matched *models.MerchantFeeConfig
+	best := -1
+	for _, cfg := range models.ListActiveMerchantFeeConfigs(trx.Mid, trx.TrxType) {
+		if score := cfg.MatchScore(trx.GetCountry(), trx.TrxMethod, trx.Ccy); score > best {
+			matched, best = cfg, score
+		}
+	}
+	return matched
+}
+
+// CalculateFee 计算交易的商户手续费：金额*百分比费率/100+固定费用，再按最小、最大费用限制
+// 未匹配到费率配置时返回false，交易不记录手续费
+func (s *MerchantFeeConfigService) CalculateFee(trx *models.Transaction) (fee decimal.Decimal, ccy string, ok bool) {
+	if trx == nil || trx.Amount == nil {
+		return
+	}
+	return s.CalculateFeeForAmount(trx, *trx.Amount)
+}
+
+// CalculateFeeForAmount 按指定金额计算手续费，用于实收金额与订单金额不一致时重算
+func (s *MerchantFeeConfigService) CalculateFeeForAmount(trx *models.Transaction, amount decimal.Decimal) (fee decimal.Decimal, ccy string, ok bool) {
+	if trx == nil {
+		return
+	}
+	cfg := s.ResolveFeeConfig(trx)
+	if cfg == nil {
+		return
+	}
+	percent := parseFeeDecimal(cfg.GetPercent())
+	fixed := parseFeeDecimal(cfg.GetFixed())
+	minFee := parseFeeDecimal(cfg.GetMinFee())
+	maxFee := parseFeeDecimal(cfg.GetMaxFee())
+
+	fee = amount.Mul(percent).Div(decimal.NewFromInt(100)).Add(fixed)
+	if minFee.IsPositive() && fee.LessThan(minFee) {
+		fee = minFee
+	}
+	if maxFee.IsPositive() && fee.GreaterThan(maxFee) {
+		fee = maxFee
+	}
+	places := int32(2)
+	if info, exists := protocol.GetCurrencyInfo(trx.Ccy); exists {
+		places = int32(info.Decimals)
+	}
+	return fee.Round(places), trx.Ccy, true
+}
+
+func parseFeeDecimal(value string) decimal.Decimal {
+	if value == "" {
+		return decimal.Zero
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
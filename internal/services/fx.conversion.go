@@ -0,0 +1,279 @@
+package services
+
+import (
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// FxConversionService 商户换汇服务：按中间价及加点报价并锁定有效期，执行时在同一事务内扣减转出币种账户、
+// 入账转入币种账户，两条资金流水以换汇单号及原流水号关联，加点部分计入手续费收入
+type FxConversionService struct{}
+
+var (
+	fxConversionService     *FxConversionService
+	fxConversionServiceOnce sync.Once
+)
+
+func SetupFxConversionService() {
+	fxConversionServiceOnce.Do(func() {
+		fxConversionService = &FxConversionService{}
+	})
+}
+
+// GetFxConversionService 获取换汇服务单例
+func GetFxConversionService() *FxConversionService {
+	if fxConversionService == nil {
+		SetupFxConversionService()
+	}
+	return fxConversionService
+}
+
+// Quote 换汇报价：成交汇率 = 中间价 × (1 - 加点)，报价在有效期内可执行
+func (s *FxConversionService) Quote(mid string, req *protocol.FxConversionQuoteRequest) (*protocol.FxConversion, protocol.ErrorCode) {
+	fromCcy, toCcy := strings.ToUpper(req.FromCcy), strings.ToUpper(req.ToCcy)
+	if !protocol.IsValidCurrency(fromCcy) || !protocol.IsValidCurrency(toCcy) || fromCcy == toCcy {
+		return nil, protocol.InvalidCurrency
+	}
+	fromAmount, err := decimal.NewFromString(req.FromAmount)
+	if err != nil || !fromAmount.IsPositive() {
+		return nil, protocol.InvalidAmount
+	}
+	if !GetConfigService().GetTrxConfigByMerchantID(mid, models.TrxTypeConvert).IsEnabled() {
+		return nil, protocol.FxConversionLimitExceeded
+	}
+	if err := GetConfigService().ValidateAmount(mid, models.TrxTypeConvert, fromCcy, fromAmount); err != nil {
+		return nil, protocol.FxConversionLimitExceeded
+	}
+	markup := models.GetFxMarkup(mid, fromCcy, toCcy)
+	if markup == nil || markup.Status != protocol.StatusActive {
+		return nil, protocol.FxMarkupNotConfigured
+	}
+	now := time.Now().UnixMilli()
+	midRate, ok := GetFxRateService().GetRate(fromCcy, toCcy, now)
+	if !ok {
+		return nil, protocol.FxRateNotFound
+	}
+
+	rate := midRate.Mul(decimal.NewFromInt(1).Sub(markup.Markup)).Round(protocol.FxRatePrecision)
+	decimals := int32(2)
+	if info, ok := protocol.GetCurrencyInfo(toCcy); ok {
+		decimals = int32(info.Decimals)
+	}
+	toAmount := fromAmount.Mul(rate).Truncate(decimals)
+	if !toAmount.IsPositive() {
+		return nil, protocol.InvalidAmount
+	}
+	grossAmount := fromAmount.Mul(midRate).Truncate(decimals)
+
+	conversion := &models.FxConversion{
+		ConversionID:       utils.GenerateConversionID(),
+		Mid:                mid,
+		FromCcy:            fromCcy,
+		ToCcy:              toCcy,
+		FromAmount:         fromAmount,
+		ToAmount:           toAmount,
+		MidRate:            midRate,
+		Markup:             markup.Markup,
+		Rate:               rate,
+		MarkupAmount:       grossAmount.Sub(toAmount),
+		ExpiredAt:          now + config.Get().Fx.GetQuoteExpiry(),
+		FxConversionValues: &models.FxConversionValues{},
+	}
+	conversion.SetStatus(protocol.FxConversionStatusQuoted)
+	if err := models.WriteDB.Create(conversion).Error; err != nil {
+		log.Get().Errorf("Create fx conversion quote failed: mid=%s, err=%v", mid, err)
+		return nil, protocol.DatabaseError
+	}
+	return conversion.Protocol(), protocol.Success
+}
+
+// Execute 执行有效期内的报价，转出、转入两笔记账与状态变更在同一事务内完成
+func (s *FxConversionService) Execute(mid string, req *protocol.FxConversionRequest) (*protocol.FxConversion, protocol.ErrorCode) {
+	conversion := models.GetFxConversion(mid, req.ConversionID)
+	if conversion == nil {
+		return nil, protocol.FxQuoteNotFound
+	}
+	if conversion.GetStatus() != protocol.FxConversionStatusQuoted {
+		return nil, protocol.FxQuoteStatusInvalid
+	}
+	now := time.Now().UnixMilli()
+	if now > conversion.ExpiredAt {
+		values := (&models.FxConversionValues{}).SetStatus(protocol.StatusExpired)
+		if _, err := models.UpdateFxConversionValues(models.WriteDB, conversion, []string{protocol.FxConversionStatusQuoted}, values); err != nil {
+			log.Get().Errorf("Expire fx conversion %s failed: %v", conversion.ConversionID, err)
+		}
+		return nil, protocol.FxQuoteExpired
+	}
+	cfg := GetConfigService().GetTrxConfigByMerchantID(mid, models.TrxTypeConvert)
+	if limit := cfg.GetDailyLimit(conversion.FromCcy); limit.IsPositive() {
+		used := models.SumExecutedFxConversions(mid, conversion.FromCcy, utils.TodayZeroTimeMilli())
+		if used.Add(conversion.FromAmount).GreaterThan(limit) {
+			return nil, protocol.FxConversionLimitExceeded
+		}
+	}
+	if _, code := GetAccountService().GetOrCreateAccount(mid, protocol.UserTypeMerchant, conversion.ToCcy); code != protocol.Success {
+		return nil, code
+	}
+
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		values := (&models.FxConversionValues{}).
+			SetStatus(protocol.StatusSuccess).
+			SetExecutedAt(now)
+		ok, err := models.UpdateFxConversionValues(tx, conversion, []string{protocol.FxConversionStatusQuoted}, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.FxQuoteStatusInvalid
+			return protocol.NewServiceError(code, "fx conversion status changed")
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         conversion.FromCcy,
+			Amount:      conversion.FromAmount,
+			TrxID:       conversion.ConversionID,
+			TrxType:     protocol.TrxTypeConvertOut,
+			OperatorID:  mid,
+			Description: "fx conversion to " + conversion.ToCcy,
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "debit conversion amount failed")
+		}
+		outFlow := models.GetUserFundFlowByTrx(tx, conversion.ConversionID, protocol.TrxTypeConvertOut, mid, protocol.UserTypeMerchant)
+		if outFlow == nil {
+			code = protocol.DatabaseError
+			return protocol.NewServiceError(code, "conversion out flow not found")
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         conversion.ToCcy,
+			Amount:      conversion.ToAmount,
+			Fee:         conversion.MarkupAmount,
+			TrxID:       conversion.ConversionID,
+			TrxType:     protocol.TrxTypeConvertIn,
+			OperatorID:  mid,
+			Description: "fx conversion from " + conversion.FromCcy,
+			OriFlowNo:   outFlow.FlowNo,
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "credit conversion amount failed")
+		}
+		inFlow := models.GetUserFundFlowByTrx(tx, conversion.ConversionID, protocol.TrxTypeConvertIn, mid, protocol.UserTypeMerchant)
+		if inFlow == nil {
+			code = protocol.DatabaseError
+			return protocol.NewServiceError(code, "conversion in flow not found")
+		}
+		flows := (&models.FxConversionValues{}).
+			SetOutFlowNo(outFlow.FlowNo).
+			SetInFlowNo(inFlow.FlowNo)
+		if _, err := models.UpdateFxConversionValues(tx, conversion, []string{protocol.StatusSuccess}, flows); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Execute fx conversion %s failed: %v", conversion.ConversionID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	return conversion.Protocol(), protocol.Success
+}
+
+// List 换汇记录列表，mid为空时查询全部商户
+func (s *FxConversionService) List(mid string, req *protocol.FxConversionListRequest) ([]*protocol.FxConversion, int64, protocol.ErrorCode) {
+	if mid == "" {
+		mid = req.Mid
+	}
+	conversions, total, err := models.ListFxConversionByQuery(&models.FxConversionQuery{
+		Mid:            mid,
+		ConversionID:   req.ConversionID,
+		Status:         req.Status,
+		FromCcy:        strings.ToUpper(req.FromCcy),
+		ToCcy:          strings.ToUpper(req.ToCcy),
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.FxConversion, 0, len(conversions))
+	for _, conversion := range conversions {
+		list = append(list, conversion.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 换汇详情，mid为空时不限制商户
+func (s *FxConversionService) Get(mid, conversionID string) (*protocol.FxConversion, protocol.ErrorCode) {
+	conversion := models.GetFxConversion(mid, conversionID)
+	if conversion == nil {
+		return nil, protocol.FxQuoteNotFound
+	}
+	return conversion.Protocol(), protocol.Success
+}
+
+// SaveMarkup 保存换汇加点，加点比例须在[0, 1)之间
+func (s *FxConversionService) SaveMarkup(operatorID string, req *protocol.SaveFxMarkupRequest) (*protocol.FxMarkup, protocol.ErrorCode) {
+	fromCcy, toCcy := strings.ToUpper(req.FromCcy), strings.ToUpper(req.ToCcy)
+	if !protocol.IsValidCurrency(fromCcy) || !protocol.IsValidCurrency(toCcy) || fromCcy == toCcy {
+		return nil, protocol.InvalidCurrency
+	}
+	value, err := decimal.NewFromString(req.Markup)
+	if err != nil || value.IsNegative() || value.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return nil, protocol.FxMarkupInvalid
+	}
+	mid := req.Mid
+	if mid == "" {
+		mid = models.GlobalMerchantID
+	}
+	markup := &models.FxMarkup{
+		Mid:       mid,
+		FromCcy:   fromCcy,
+		ToCcy:     toCcy,
+		Markup:    value,
+		Status:    req.Status,
+		UpdatedBy: operatorID,
+	}
+	if err := models.SaveFxMarkup(models.WriteDB, markup); err != nil {
+		log.Get().Errorf("Save fx markup failed: mid=%s, pair=%s/%s, err=%v", mid, fromCcy, toCcy, err)
+		return nil, protocol.DatabaseError
+	}
+	return markup.Protocol(), protocol.Success
+}
+
+// ListMarkups 换汇加点列表
+func (s *FxConversionService) ListMarkups(req *protocol.FxMarkupListRequest) ([]*protocol.FxMarkup, int64, protocol.ErrorCode) {
+	markups, total, err := models.ListFxMarkupByQuery(&models.FxMarkupQuery{
+		Mid:     req.Mid,
+		FromCcy: strings.ToUpper(req.FromCcy),
+		ToCcy:   strings.ToUpper(req.ToCcy),
+		Page:    req.Page,
+		Size:    req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.FxMarkup, 0, len(markups))
+	for _, markup := range markups {
+		list = append(list, markup.Protocol())
+	}
+	return list, total, protocol.Success
+}
@@ -0,0 +1,589 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+// FxRateFeed 汇率行情源，按配置中的feed名称选用
+type FxRateFeed interface {
+	Name() string
+	Fetch(ctx context.Context, cfg *config.FxConfig) ([]*models.FxRate, error)
+}
+
+var (
+	fxRateFeeds   = map[string]FxRateFeed{}
+	fxRateFeedsMu sync.RWMutex
+)
+
+// RegisterFxRateFeed 注册汇率行情源
+func RegisterFxRateFeed(feed FxRateFeed) {
+	fxRateFeedsMu.Lock()
+	defer fxRateFeedsMu.Unlock()
+	fxRateFeeds[feed.Name()] = feed
+}
+
+// GetFxRateFeed 获取已注册的汇率行情源
+func GetFxRateFeed(name string) FxRateFeed {
+	fxRateFeedsMu.RLock()
+	defer fxRateFeedsMu.RUnlock()
+	return fxRateFeeds[name]
+}
+
+// httpFxRateFeed 通用HTTP JSON行情源
+// 响应格式：{"base":"USD","timestamp":1700000000,"rates":{"INR":83.12,"EUR":"0.92"}}
+type httpFxRateFeed struct{}
+
+type httpFxRateResponse struct {
+	Base      string                     `json:"base"`
+	Timestamp int64                      `json:"timestamp"` // 秒或毫秒
+	Rates     map[string]decimal.Decimal `json:"rates"`
+}
+
+func (f *httpFxRateFeed) Name() string {
+	return "http"
+}
+
+func (f *httpFxRateFeed) Fetch(ctx context.Context, cfg *config.FxConfig) ([]*models.FxRate, error) {
+	if cfg.FeedURL == "" {
+		return nil, fmt.Errorf("fx feed_url is empty")
+	}
+	headers := map[string]string{}
+	if cfg.FeedAPIKey != "" {
+		headers["Authorization"] = "Bearer " + cfg.FeedAPIKey
+	}
+	cli := utils.NewHttpClient()
+	cli.Timeout = cfg.GetFeedTimeout()
+	body, err, resp := utils.GetClientWithHeader(cli, cfg.FeedURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fx feed status %d", resp.StatusCode)
+	}
+	var data httpFxRateResponse
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return nil, fmt.Errorf("parse fx feed response: %v", err)
+	}
+	base := strings.ToUpper(data.Base)
+	if base == "" {
+		base = protocol.CcyUSD
+	}
+	effectiveAt := data.Timestamp
+	if effectiveAt <= 0 {
+		effectiveAt = utils.TimeNowMilli()
+	} else if effectiveAt < 1e12 {
+		effectiveAt *= 1000
+	}
+	rates := make([]*models.FxRate, 0, len(data.Rates))
+	for quote, rate := range data.Rates {
+		quote = strings.ToUpper(quote)
+		if quote == base || !rate.IsPositive() {
+			continue
+		}
+		rates = append(rates, &models.FxRate{
+			BaseCcy:     base,
+			QuoteCcy:    quote,
+			Rate:        rate,
+			Source:      f.Name(),
+			EffectiveAt: effectiveAt,
+		})
+	}
+	return rates, nil
+}
+
+// fxUsdBackfillDays 定时回填的回溯天数
+const fxUsdBackfillDays = 7
+
+// fxRateLookup 查找指定时间点生效的汇率
+type fxRateLookup func(base, quote string, at int64) *models.FxRate
+
+// FxRateService 汇率服务
+type FxRateService struct{}
+
+var (
+	fxRateService     *FxRateService
+	fxRateServiceOnce sync.Once
+)
+
+func init() {
+	RegisterFxRateFeed(&httpFxRateFeed{})
+	task.RegisterHandler(protocol.FxRateSync, HandleFxRateSync)
+	task.RegisterHandler(protocol.FxUsdBackfill, HandleFxUsdBackfill)
+}
+
+func SetupFxRateService() {
+	fxRateServiceOnce.Do(func() {
+		fxRateService = &FxRateService{}
+	})
+}
+
+// GetFxRateService 获取汇率服务单例
+func GetFxRateService() *FxRateService {
+	if fxRateService == nil {
+		SetupFxRateService()
+	}
+	return fxRateService
+}
+
+// GetRate 获取at时刻 1 base = ? quote 的汇率，依次尝试直接汇率、反向汇率和经美元交叉汇率
+func (s *FxRateService) GetRate(base, quote string, at int64) (decimal.Decimal, bool) {
+	return s.resolve(models.GetFxRateAt, base, quote, at)
+}
+
+func (s *FxRateService) resolve(lookup fxRateLookup, base, quote string, at int64) (decimal.Decimal, bool) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == "" || quote == "" {
+		return decimal.Zero, false
+	}
+	if base == quote {
+		return decimal.NewFromInt(1), true
+	}
+	if rate, ok := s.resolvePair(lookup, base, quote, at); ok {
+		return rate, true
+	}
+	if base == protocol.CcyUSD || quote == protocol.CcyUSD {
+		return decimal.Zero, false
+	}
+	// 经美元交叉
+	baseUsd, ok := s.resolvePair(lookup, base, protocol.CcyUSD, at)
+	if !ok {
+		return decimal.Zero, false
+	}
+	usdQuote, ok := s.resolvePair(lookup, protocol.CcyUSD, quote, at)
+	if !ok {
+		return decimal.Zero, false
+	}
+	return baseUsd.Mul(usdQuote).Round(protocol.FxRatePrecision), true
+}
+
+func (s *FxRateService) resolvePair(lookup fxRateLookup, base, quote string, at int64) (decimal.Decimal, bool) {
+	maxAge := config.Get().Fx.GetMaxRateAge()
+	if r := lookup(base, quote, at); r != nil && at-r.EffectiveAt <= maxAge && r.Rate.IsPositive() {
+		return r.Rate, true
+	}
+	if r := lookup(quote, base, at); r != nil && at-r.EffectiveAt <= maxAge && r.Rate.IsPositive() {
+		return decimal.NewFromInt(1).DivRound(r.Rate, protocol.FxRatePrecision), true
+	}
+	return decimal.Zero, false
+}
+
+// ToUsd 按at时刻汇率将金额换算为美元，返回币种兑美元汇率和美元金额
+func (s *FxRateService) ToUsd(ccy string, amount decimal.Decimal, at int64) (rate, usd decimal.Decimal, ok bool) {
+	return s.toUsd(models.GetFxRateAt, ccy, amount, at)
+}
+
+func (s *FxRateService) toUsd(lookup fxRateLookup, ccy string, amount decimal.Decimal, at int64) (rate, usd decimal.Decimal, ok bool) {
+	rate, ok = s.resolve(lookup, ccy, protocol.CcyUSD, at)
+	if !ok {
+		return
+	}
+	usd = amount.Mul(rate).Round(protocol.FxUsdPrecision)
+	return
+}
+
+// UsdSnapshot 创建交易时快照美元汇率和金额，无可用汇率时返回nil，由回填任务补齐
+func (s *FxRateService) UsdSnapshot(ccy string, amount *decimal.Decimal, at int64) (usdRate, usdAmount *decimal.Decimal) {
+	if amount == nil {
+		return nil, nil
+	}
+	rate, usd, ok := s.ToUsd(ccy, *amount, at)
+	if !ok {
+		log.Get().Warnf("UsdSnapshot: no usd rate for %s at %d", ccy, at)
+		return nil, nil
+	}
+	return &rate, &usd
+}
+
+// SnapshotCompletion 交易完成时快照手续费与渠道手续费的美元汇率和金额
+func (s *FxRateService) SnapshotCompletion(trx *models.Transaction, values *models.TransactionValues) {
+	if trx == nil || values == nil || values.CompletedAt == nil {
+		return
+	}
+	at := *values.CompletedAt
+	merged := &models.Transaction{TransactionValues: &models.TransactionValues{}}
+	if trx.TransactionValues != nil {
+		*merged.TransactionValues = *trx.TransactionValues
+	}
+	merged.SetValues(values)
+
+	if merged.FeeAmount != nil {
+		ccy := merged.GetFeeCcy()
+		if ccy == "" {
+			ccy = trx.Ccy
+		}
+		if rate, usd, ok := s.ToUsd(ccy, merged.GetFeeAmount(), at); ok {
+			values.SetFeeUsdRate(rate).SetFeeUsdAmount(usd)
+		}
+	}
+	if merged.ChannelFeeAmount != nil {
+		ccy := merged.GetChannelFeeCcy()
+		if ccy == "" {
+			ccy = trx.Ccy
+		}
+		if rate, usd, ok := s.ToUsd(ccy, merged.GetChannelFeeAmount(), at); ok {
+			values.SetChannelFeeUsdRate(rate).SetChannelFeeUsdAmount(usd)
+		}
+	}
+}
+
+// Create 批量录入汇率
+func (s *FxRateService) Create(adminID string, req *protocol.CreateFxRatesRequest) ([]*protocol.FxRate, protocol.ErrorCode) {
+	now := utils.TimeNowMilli()
+	rates := make([]*models.FxRate, 0, len(req.Rates))
+	for _, item := range req.Rates {
+		rate, code := s.buildRate(item.BaseCcy, item.QuoteCcy, item.Rate, item.EffectiveAt, now)
+		if code != protocol.Success {
+			return nil, code
+		}
+		rate.Source = protocol.FxSourceManual
+		rate.CreatedBy = adminID
+		rates = append(rates, rate)
+	}
+	return s.save(rates)
+}
+
+// Upload 上传CSV汇率文件，列顺序：base_ccy,quote_ccy,rate,effective_at，首行可为表头
+// effective_at 支持毫秒时间戳、RFC3339或"2006-01-02 15:04:05"(UTC)，为空时立即生效
+func (s *FxRateService) Upload(adminID string, reader io.Reader) ([]*protocol.FxRate, protocol.ErrorCode) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, protocol.FxRateFileInvalid
+	}
+	now := utils.TimeNowMilli()
+	rates := make([]*models.FxRate, 0, len(records))
+	for i, record := range records {
+		if len(record) < 3 {
+			return nil, protocol.FxRateFileInvalid
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "base_ccy") {
+			continue
+		}
+		var effectiveAt int64
+		if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+			if effectiveAt = parseFxEffectiveAt(strings.TrimSpace(record[3])); effectiveAt <= 0 {
+				return nil, protocol.FxRateFileInvalid
+			}
+		}
+		rate, code := s.buildRate(record[0], record[1], record[2], effectiveAt, now)
+		if code != protocol.Success {
+			return nil, protocol.FxRateFileInvalid
+		}
+		rate.Source = protocol.FxSourceUpload
+		rate.CreatedBy = adminID
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return nil, protocol.FxRateFileInvalid
+	}
+	return s.save(rates)
+}
+
+func parseFxEffectiveAt(value string) int64 {
+	if ms := cast.ToInt64(value); ms > 0 {
+		return ms
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UnixMilli()
+	}
+	if t, err := time.Parse(time.DateTime, value); err == nil {
+		return t.UnixMilli()
+	}
+	return 0
+}
+
+func (s *FxRateService) buildRate(base, quote, rateStr string, effectiveAt, now int64) (*models.FxRate, protocol.ErrorCode) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if base == "" || quote == "" || base == quote {
+		return nil, protocol.FxRateInvalid
+	}
+	if !protocol.IsValidCurrency(base) || !protocol.IsValidCurrency(quote) {
+		return nil, protocol.InvalidCurrency
+	}
+	rate, err := decimal.NewFromString(strings.TrimSpace(rateStr))
+	if err != nil || !rate.IsPositive() {
+		return nil, protocol.FxRateInvalid
+	}
+	if effectiveAt <= 0 {
+		effectiveAt = now
+	}
+	return &models.FxRate{
+		BaseCcy:     base,
+		QuoteCcy:    quote,
+		Rate:        rate.Round(protocol.FxRatePrecision),
+		EffectiveAt: effectiveAt,
+	}, protocol.Success
+}
+
+func (s *FxRateService) save(rates []*models.FxRate) ([]*protocol.FxRate, protocol.ErrorCode) {
+	if err := models.SaveFxRates(models.WriteDB, rates); err != nil {
+		log.Get().Errorf("SaveFxRates error: %v", err)
+		return nil, protocol.DatabaseError
+	}
+	list := make([]*protocol.FxRate, 0, len(rates))
+	for _, rate := range rates {
+		list = append(list, rate.Protocol())
+	}
+	return list, protocol.Success
+}
+
+// List 汇率历史列表
+func (s *FxRateService) List(req *protocol.FxRateListRequest) ([]*protocol.FxRate, int64, protocol.ErrorCode) {
+	list, total, err := models.ListFxRateByQuery(&models.FxRateQuery{
+		BaseCcy:          strings.ToUpper(req.BaseCcy),
+		QuoteCcy:         strings.ToUpper(req.QuoteCcy),
+		Source:           req.Source,
+		EffectiveAtStart: req.EffectiveAtStart,
+		EffectiveAtEnd:   req.EffectiveAtEnd,
+		Page:             req.Page,
+		Size:             req.Size,
+	})
+	if err != nil {
+		log.Get().Errorf("ListFxRateByQuery error: %v", err)
+		return nil, 0, protocol.DatabaseError
+	}
+	records := make([]*protocol.FxRate, 0, len(list))
+	for _, rate := range list {
+		records = append(records, rate.Protocol())
+	}
+	return records, total, protocol.Success
+}
+
+// Quote 查询指定时间点的汇率
+func (s *FxRateService) Quote(req *protocol.FxRateQuoteRequest) (*protocol.FxRate, protocol.ErrorCode) {
+	at := req.At
+	if at <= 0 {
+		at = utils.TimeNowMilli()
+	}
+	rate, ok := s.GetRate(req.BaseCcy, req.QuoteCcy, at)
+	if !ok {
+		return nil, protocol.FxRateNotFound
+	}
+	return &protocol.FxRate{
+		BaseCcy:     strings.ToUpper(req.BaseCcy),
+		QuoteCcy:    strings.ToUpper(req.QuoteCcy),
+		Rate:        rate.String(),
+		EffectiveAt: at,
+	}, protocol.Success
+}
+
+// Sync 从配置的行情源拉取最新汇率
+func (s *FxRateService) Sync(ctx context.Context) (int, protocol.ErrorCode) {
+	cfg := config.Get().Fx
+	if cfg.Feed == "" {
+		return 0, protocol.FxFeedNotConfigured
+	}
+	feed := GetFxRateFeed(cfg.Feed)
+	if feed == nil {
+		log.Get().Errorf("FxRate Sync: unknown feed %s", cfg.Feed)
+		return 0, protocol.FxFeedNotConfigured
+	}
+	rates, err := feed.Fetch(ctx, cfg)
+	if err != nil {
+		log.Get().Errorf("FxRate Sync: fetch from %s failed: %v", cfg.Feed, err)
+		return 0, protocol.ThirdPartyError
+	}
+	valid := make([]*models.FxRate, 0, len(rates))
+	for _, rate := range rates {
+		if !protocol.IsValidCurrency(rate.BaseCcy) || !protocol.IsValidCurrency(rate.QuoteCcy) {
+			continue
+		}
+		rate.Rate = rate.Rate.Round(protocol.FxRatePrecision)
+		valid = append(valid, rate)
+	}
+	if _, code := s.save(valid); code != protocol.Success {
+		return 0, code
+	}
+	return len(valid), protocol.Success
+}
+
+// fxRateHistory 回填时按币种对缓存全部历史汇率，避免逐行查询
+type fxRateHistory struct {
+	pairs map[string][]*models.FxRate
+}
+
+func newFxRateHistory() *fxRateHistory {
+	return &fxRateHistory{pairs: map[string][]*models.FxRate{}}
+}
+
+func (h *fxRateHistory) lookup(base, quote string, at int64) *models.FxRate {
+	key := base + "/" + quote
+	list, ok := h.pairs[key]
+	if !ok {
+		var err error
+		if list, err = models.ListFxRatesByPair(base, quote); err != nil {
+			log.Get().Errorf("ListFxRatesByPair %s error: %v", key, err)
+			return nil
+		}
+		h.pairs[key] = list
+	}
+	// 第一条生效时间晚于at的位置，其前一条即为at时刻生效的汇率
+	idx := sort.Search(len(list), func(i int) bool { return list[i].EffectiveAt > at })
+	if idx == 0 {
+		return nil
+	}
+	return list[idx-1]
+}
+
+// Backfill 按交易创建时间和完成时间回溯历史汇率，补齐美元金额和手续费美元汇率
+func (s *FxRateService) Backfill(req *protocol.FxUsdBackfillRequest) (*protocol.FxUsdBackfillResult, protocol.ErrorCode) {
+	cfg := config.Get().Fx
+	trxTypes := []string{protocol.TrxTypePayin, protocol.TrxTypePayout}
+	if req.TrxType != "" {
+		trxTypes = []string{req.TrxType}
+	}
+	history := newFxRateHistory()
+	result := &protocol.FxUsdBackfillResult{}
+	for _, trxType := range trxTypes {
+		var afterID int64
+		for result.Scanned < int64(cfg.BackfillMaxRows) {
+			rows, err := models.ListUsdBackfillRows(&models.UsdBackfillQuery{
+				TrxType:        trxType,
+				AfterID:        afterID,
+				CreatedAtStart: req.CreatedAtStart,
+				CreatedAtEnd:   req.CreatedAtEnd,
+				Limit:          cfg.BackfillBatchSize,
+			})
+			if err != nil {
+				log.Get().Errorf("ListUsdBackfillRows %s error: %v", trxType, err)
+				return result, protocol.DatabaseError
+			}
+			if len(rows) == 0 {
+				break
+			}
+			for _, row := range rows {
+				afterID = row.ID
+				result.Scanned++
+				updates := s.backfillUpdates(history.lookup, row)
+				if len(updates) == 0 {
+					result.Missed++
+					continue
+				}
+				if err := models.UpdateUsdBackfillRow(trxType, row.ID, updates); err != nil {
+					log.Get().Errorf("UpdateUsdBackfillRow %s error: %v", row.TrxID, err)
+					return result, protocol.DatabaseError
+				}
+				result.Updated++
+			}
+		}
+	}
+	return result, protocol.Success
+}
+
+func (s *FxRateService) backfillUpdates(lookup fxRateLookup, row *models.UsdBackfillRow) map[string]any {
+	updates := map[string]any{}
+	if row.Amount != nil && (row.UsdAmount == nil || row.UsdRate == nil) {
+		if rate, usd, ok := s.toUsd(lookup, row.Ccy, *row.Amount, row.CreatedAt); ok {
+			updates["usd_rate"] = rate
+			if row.UsdAmount == nil {
+				updates["usd_amount"] = usd
+			}
+		}
+	}
+	if row.CompletedAt == nil || *row.CompletedAt <= 0 {
+		return updates
+	}
+	if row.FeeAmount != nil && row.FeeUsdRate == nil {
+		ccy := row.Ccy
+		if row.FeeCcy != nil && *row.FeeCcy != "" {
+			ccy = *row.FeeCcy
+		}
+		if rate, usd, ok := s.toUsd(lookup, ccy, *row.FeeAmount, *row.CompletedAt); ok {
+			updates["fee_usd_rate"] = rate
+			updates["fee_usd_amount"] = usd
+		}
+	}
+	if row.ChannelFeeAmount != nil && row.ChannelFeeUsdRate == nil {
+		ccy := row.Ccy
+		if row.ChannelFeeCcy != nil && *row.ChannelFeeCcy != "" {
+			ccy = *row.ChannelFeeCcy
+		}
+		if rate, usd, ok := s.toUsd(lookup, ccy, *row.ChannelFeeAmount, *row.CompletedAt); ok {
+			updates["channel_fee_usd_rate"] = rate
+			updates["channel_fee_usd_amount"] = usd
+		}
+	}
+	return updates
+}
+
+// RegisterFxTasks 注册汇率相关定时任务
+func RegisterFxTasks() {
+	log.Get().Info("注册汇率任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "fx_rate_sync",
+			Type:       protocol.FxRateSync,
+			HandlerKey: protocol.FxRateSync,
+			Name:       "行情源汇率同步",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 10m"}[0], // 每10分钟执行一次
+				Timeout: &[]int{120}[0],            // 2分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+		{
+			TaskID:     "fx_usd_backfill",
+			Type:       protocol.FxUsdBackfill,
+			HandlerKey: protocol.FxUsdBackfill,
+			Name:       "交易美元金额回填",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 30m"}[0], // 每30分钟执行一次
+				Timeout: &[]int{1800}[0],           // 30分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("汇率任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleFxRateSync 定时从行情源同步汇率，未配置行情源时跳过
+func HandleFxRateSync(ctx context.Context, params protocol.MapData) error {
+	count, code := GetFxRateService().Sync(ctx)
+	switch code {
+	case protocol.Success:
+		log.Get().Infof("FxRate Sync: saved %d rates", count)
+		return nil
+	case protocol.FxFeedNotConfigured:
+		return nil
+	default:
+		return fmt.Errorf("汇率同步失败: %s", code)
+	}
+}
+
+// HandleFxUsdBackfill 定时回填近期缺少美元金额的交易，如汇率晚于交易录入的情况；更早的历史数据由管理后台按时间范围触发回填
+func HandleFxUsdBackfill(ctx context.Context, params protocol.MapData) error {
+	result, code := GetFxRateService().Backfill(&protocol.FxUsdBackfillRequest{
+		CreatedAtStart: time.Now().AddDate(0, 0, -fxUsdBackfillDays).UnixMilli(),
+	})
+	if code != protocol.Success {
+		return fmt.Errorf("美元金额回填失败: %s", code)
+	}
+	if result.Scanned > 0 {
+		log.Get().Infof("FxUsdBackfill: scanned=%d, updated=%d, missed=%d", result.Scanned, result.Updated, result.Missed)
+	}
+	return nil
+}
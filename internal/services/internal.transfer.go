@@ -0,0 +1,328 @@
+package services
+
+import (
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// InternalTransferService 平台内部转账服务：同币种账户间划转，转出、转入以同一凭证过账。
+// 运营发起的转账经另一名管理员复核后执行，商户可在同一商户集团的商户间直接转账
+type InternalTransferService struct{}
+
+var (
+	internalTransferService     *InternalTransferService
+	internalTransferServiceOnce sync.Once
+)
+
+func SetupInternalTransferService() {
+	internalTransferServiceOnce.Do(func() {
+		internalTransferService = &InternalTransferService{}
+	})
+}
+
+// GetInternalTransferService 获取内部转账服务单例
+func GetInternalTransferService() *InternalTransferService {
+	if internalTransferService == nil {
+		SetupInternalTransferService()
+	}
+	return internalTransferService
+}
+
+// Request 运营发起内部转账，生成待复核的审批记录，复核通过后执行
+func (s *InternalTransferService) Request(admin *models.Admin, req *protocol.CreateInternalTransferRequest) (*protocol.InternalTransfer, protocol.ErrorCode) {
+	if code := GetAdminAdjustmentService().verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	if req.FromUserID == req.ToUserID && req.FromUserType == req.ToUserType {
+		return nil, protocol.TransferSameAccount
+	}
+	amount, code := s.parseAmount(req.Amount)
+	if code != protocol.Success {
+		return nil, code
+	}
+	if _, err := models.GetAccountByUserIDAndCurrency(req.FromUserID, req.FromUserType, req.Ccy); err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+	if _, err := models.GetAccountByUserIDAndCurrency(req.ToUserID, req.ToUserType, req.Ccy); err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+
+	transfer := &models.InternalTransfer{
+		TransferID:             utils.GenerateTransferID(),
+		Source:                 protocol.TransferSourceAdmin,
+		FromUserID:             req.FromUserID,
+		FromUserType:           req.FromUserType,
+		ToUserID:               req.ToUserID,
+		ToUserType:             req.ToUserType,
+		Ccy:                    req.Ccy,
+		Amount:                 amount,
+		InternalTransferValues: &models.InternalTransferValues{},
+	}
+	approval := &models.Approval{
+		ApprovalID:     utils.GenerateApprovalID(),
+		BizType:        protocol.ApprovalBizTypeTransfer,
+		BizID:          transfer.TransferID,
+		ApprovalValues: &models.ApprovalValues{},
+	}
+	if req.FromUserType == protocol.UserTypeMerchant {
+		approval.Mid = req.FromUserID
+	}
+	approval.SetStatus(protocol.StatusPending).
+		SetCcy(req.Ccy).
+		SetAmount(amount).
+		SetRequestedBy(admin.UserID).
+		SetRequestReason(req.Reason)
+	transfer.SetStatus(protocol.StatusPending).
+		SetApprovalID(approval.ApprovalID).
+		SetRequestedBy(admin.UserID).
+		SetRemark(req.Reason)
+
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transfer).Error; err != nil {
+			return err
+		}
+		return tx.Create(approval).Error
+	})
+	if err != nil {
+		log.Get().Errorf("Request internal transfer: from=%s, to=%s, err=%v", req.FromUserID, req.ToUserID, err)
+		return nil, protocol.DatabaseError
+	}
+	return transfer.Protocol(), protocol.Success
+}
+
+// MerchantTransfer 商户向同一集团的商户转账，G2FA确认后立即执行
+func (s *InternalTransferService) MerchantTransfer(merchant *models.Merchant, req *protocol.MerchantTransferRequest) (*protocol.InternalTransfer, protocol.ErrorCode) {
+	if code := verifyMerchantG2FA(merchant, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	if merchant.GetGroupID() == "" {
+		return nil, protocol.MerchantGroupNotConfigured
+	}
+	if req.ToMid == merchant.Mid {
+		return nil, protocol.TransferSameAccount
+	}
+	target := models.GetMerchantByMID(req.ToMid)
+	if target == nil || target.GetGroupID() != merchant.GetGroupID() {
+		return nil, protocol.TransferTargetNotAllowed
+	}
+	amount, code := s.parseAmount(req.Amount)
+	if code != protocol.Success {
+		return nil, code
+	}
+	if _, err := models.GetAccountByUserIDAndCurrency(merchant.Mid, protocol.UserTypeMerchant, req.Ccy); err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+	if _, code := GetAccountService().GetOrCreateAccount(target.Mid, protocol.UserTypeMerchant, req.Ccy); code != protocol.Success {
+		return nil, code
+	}
+
+	transfer := &models.InternalTransfer{
+		TransferID:             utils.GenerateTransferID(),
+		Source:                 protocol.TransferSourceMerchant,
+		FromUserID:             merchant.Mid,
+		FromUserType:           protocol.UserTypeMerchant,
+		ToUserID:               target.Mid,
+		ToUserType:             protocol.UserTypeMerchant,
+		Ccy:                    req.Ccy,
+		Amount:                 amount,
+		InternalTransferValues: &models.InternalTransferValues{},
+	}
+	transfer.SetStatus(protocol.StatusPending).
+		SetRequestedBy(merchant.Mid).
+		SetRemark(req.Remark)
+
+	code = protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transfer).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		code = s.execute(tx, transfer, merchant.Mid)
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "execute internal transfer failed")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Merchant internal transfer: from=%s, to=%s, err=%v", merchant.Mid, target.Mid, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	return transfer.Protocol(), protocol.Success
+}
+
+// executeApproval 复核通过后执行运营发起的转账，审批状态与记账在同一事务内提交
+func (s *InternalTransferService) executeApproval(approval *models.Approval, decision *models.ApprovalValues) protocol.ErrorCode {
+	transfer := models.GetInternalTransfer("", "", approval.BizID)
+	if transfer == nil {
+		return protocol.TransferNotFound
+	}
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		transfer.SetApprovedBy(decision.GetApprovedBy())
+		code = s.execute(tx, transfer, decision.GetApprovedBy())
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "execute internal transfer failed")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Execute internal transfer: approval_id=%s, err=%v", approval.ApprovalID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return code
+	}
+	return protocol.Success
+}
+
+// rejectApproval 复核驳回时在同一事务内关闭转账
+func (s *InternalTransferService) rejectApproval(tx *gorm.DB, approval *models.Approval, decision *models.ApprovalValues) error {
+	transfer := models.GetInternalTransfer("", "", approval.BizID)
+	if transfer == nil {
+		return protocol.NewServiceError(protocol.TransferNotFound, "internal transfer not found")
+	}
+	values := (&models.InternalTransferValues{}).
+		SetStatus(protocol.StatusRejected).
+		SetApprovedBy(decision.GetApprovedBy())
+	_, err := models.UpdateInternalTransferValues(tx, transfer, []string{protocol.StatusPending}, values)
+	return err
+}
+
+// execute 在调用方事务中执行转账：以待执行状态为条件置为成功，同一凭证借记转出方、贷记转入方
+func (s *InternalTransferService) execute(tx *gorm.DB, transfer *models.InternalTransfer, operatorID string) protocol.ErrorCode {
+	values := (&models.InternalTransferValues{}).
+		SetStatus(protocol.StatusSuccess).
+		SetExecutedAt(utils.TimeNowMilli())
+	if transfer.GetApprovedBy() != "" {
+		values.SetApprovedBy(transfer.GetApprovedBy())
+	}
+	ok, err := models.UpdateInternalTransferValues(tx, transfer, []string{protocol.StatusPending}, values)
+	if err != nil {
+		return protocol.DatabaseError
+	}
+	if !ok {
+		return protocol.TransferStatusInvalid
+	}
+	code := GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+		UserID:      transfer.FromUserID,
+		UserType:    transfer.FromUserType,
+		ToUserID:    transfer.ToUserID,
+		ToUserType:  transfer.ToUserType,
+		Ccy:         transfer.Ccy,
+		Amount:      transfer.Amount,
+		TrxID:       transfer.TransferID,
+		TrxType:     protocol.TrxTypeTransfer,
+		OperatorID:  operatorID,
+		Description: transfer.GetRemark(),
+	})
+	if code != protocol.Success {
+		return code
+	}
+	flow := models.GetUserFundFlowByTrx(tx, transfer.TransferID, protocol.TrxTypeTransfer, transfer.FromUserID, transfer.FromUserType)
+	if flow == nil {
+		return protocol.DatabaseError
+	}
+	entry := (&models.InternalTransferValues{}).SetEntryID(flow.EntryID)
+	if _, err := models.UpdateInternalTransferValues(tx, transfer, []string{protocol.StatusSuccess}, entry); err != nil {
+		return protocol.DatabaseError
+	}
+	return protocol.Success
+}
+
+// List 内部转账列表，userID为空时按请求条件查询全部
+func (s *InternalTransferService) List(userID, userType string, req *protocol.InternalTransferListRequest) ([]*protocol.InternalTransfer, int64, protocol.ErrorCode) {
+	if userID == "" {
+		userID, userType = req.UserID, req.UserType
+	}
+	transfers, total, err := models.ListInternalTransferByQuery(&models.InternalTransferQuery{
+		UserID:         userID,
+		UserType:       userType,
+		TransferID:     req.TransferID,
+		Source:         req.Source,
+		Status:         req.Status,
+		Ccy:            req.Ccy,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.InternalTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		list = append(list, transfer.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 内部转账详情，userID为空时不限制用户
+func (s *InternalTransferService) Get(userID, userType, transferID string) (*protocol.InternalTransfer, protocol.ErrorCode) {
+	transfer := models.GetInternalTransfer(userID, userType, transferID)
+	if transfer == nil {
+		return nil, protocol.TransferNotFound
+	}
+	return transfer.Protocol(), protocol.Success
+}
+
+// GroupMerchants 商户所在集团的其他商户，即商户可转入的对象
+func (s *InternalTransferService) GroupMerchants(merchant *models.Merchant) ([]*protocol.Merchant, protocol.ErrorCode) {
+	if merchant.GetGroupID() == "" {
+		return []*protocol.Merchant{}, protocol.Success
+	}
+	merchants, err := models.ListMerchantsByGroupID(merchant.GetGroupID())
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	list := make([]*protocol.Merchant, 0, len(merchants))
+	for _, item := range merchants {
+		if item.Mid == merchant.Mid {
+			continue
+		}
+		list = append(list, item.Protocol())
+	}
+	return list, protocol.Success
+}
+
+// SetMerchantGroup 设置商户所属集团
+func (s *InternalTransferService) SetMerchantGroup(req *protocol.SetMerchantGroupRequest) (*protocol.Merchant, protocol.ErrorCode) {
+	ok, err := models.UpdateMerchantGroupID(req.Mid, req.GroupID)
+	if err != nil {
+		log.Get().Errorf("Set merchant group: mid=%s, err=%v", req.Mid, err)
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.MerchantNotFound
+	}
+	merchant := models.GetMerchantByMID(req.Mid)
+	if merchant == nil {
+		return nil, protocol.MerchantNotFound
+	}
+	return merchant.Protocol(), protocol.Success
+}
+
+func (s *InternalTransferService) parseAmount(value string) (decimal.Decimal, protocol.ErrorCode) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Zero, protocol.InvalidAmount
+	}
+	return amount, protocol.Success
+}
@@ -0,0 +1,886 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func init() {
+	task.RegisterHandler(protocol.LedgerReconcile, HandleLedgerReconcile)
+}
+
+// ledgerCheckRun 单次账务核对的统计与差异记录，差异超过上限后只计数不保存
+type ledgerCheckRun struct {
+	check         *models.LedgerCheck
+	accounts      int64
+	flows         int64
+	trxs          int64
+	discrepancies int64
+}
+
+func (r *ledgerCheckRun) report(items ...*protocol.LedgerDiscrepancy) error {
+	for _, item := range items {
+		r.discrepancies++
+		if r.discrepancies > int64(config.Get().Ledger.MaxDiscrepancies) {
+			continue
+		}
+		discrepancy := &models.LedgerDiscrepancy{
+			CheckID:   r.check.CheckID,
+			Type:      item.Type,
+			AccountID: item.AccountID,
+			UserID:    item.UserID,
+			UserType:  item.UserType,
+			Ccy:       item.Ccy,
+			FlowNo:    item.FlowNo,
+			TrxID:     item.TrxID,
+			TrxType:   item.TrxType,
+			Expected:  item.Expected,
+			Actual:    item.Actual,
+		}
+		if err := models.WriteDB.Create(discrepancy).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ledgerExpectedFlow 业务单据应有的用户流水
+type ledgerExpectedFlow struct {
+	TrxID    string
+	TrxType  string
+	UserID   string
+	UserType string
+	Ccy      string
+	Amount   decimal.Decimal
+}
+
+func (f *ledgerExpectedFlow) key() string {
+	return f.TrxID + "|" + f.TrxType + "|" + f.UserType + "|" + f.UserID
+}
+
+// RunCheck 后台发起账务核对，异步执行并限制最长执行时间，返回核对记录
+func (s *LedgerService) RunCheck(operator string, req *protocol.LedgerCheckRunRequest) (*protocol.LedgerCheck, protocol.ErrorCode) {
+	start, end := req.CreatedAtStart, req.CreatedAtEnd
+	if end <= 0 {
+		end = utils.TimeNowMilli()
+	}
+	if start <= 0 {
+		start = end - config.Get().Ledger.GetCheckLookback()
+	}
+	if start > end {
+		return nil, protocol.InvalidParams
+	}
+	if !s.checking.CompareAndSwap(false, true) {
+		return nil, protocol.LedgerCheckRunning
+	}
+	check, err := s.newCheck(operator, start, end)
+	if err != nil {
+		s.checking.Store(false)
+		log.Get().Errorf("Create ledger check failed: %v", err)
+		return nil, protocol.DatabaseError
+	}
+	go func() {
+		defer s.checking.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), config.Get().Ledger.GetCheckTimeout())
+		defer cancel()
+		s.runCheck(ctx, check)
+	}()
+	return check.Protocol(), protocol.Success
+}
+
+// Reconcile 定时账务核对，交叉核对最近一个回溯周期内的业务单据
+func (s *LedgerService) Reconcile(ctx context.Context) error {
+	if !s.checking.CompareAndSwap(false, true) {
+		log.Get().Warn("Ledger reconcile skipped: another check is running")
+		return nil
+	}
+	defer s.checking.Store(false)
+	end := utils.TimeNowMilli()
+	check, err := s.newCheck(protocol.System, end-config.Get().Ledger.GetCheckLookback(), end)
+	if err != nil {
+		return fmt.Errorf("创建账务核对记录失败: %v", err)
+	}
+	s.runCheck(ctx, check)
+	if check.GetStatus() == protocol.StatusFailed {
+		return fmt.Errorf("账务核对失败: %s", check.GetError())
+	}
+	return nil
+}
+
+func (s *LedgerService) newCheck(operator string, start, end int64) (*models.LedgerCheck, error) {
+	check := &models.LedgerCheck{
+		CheckID:           utils.GenerateLedgerCheckID(),
+		TriggeredBy:       operator,
+		CreatedAtStart:    start,
+		CreatedAtEnd:      end,
+		LedgerCheckValues: &models.LedgerCheckValues{},
+	}
+	check.SetStatus(protocol.StatusProcessing).
+		SetStartedAt(utils.TimeNowMilli())
+	return check, models.WriteDB.Create(check).Error
+}
+
+// runCheck 执行核对：重放全部账户流水，交叉核对业务单据与流水，检查凭证借贷平衡，完成后有差异或失败时告警
+func (s *LedgerService) runCheck(ctx context.Context, check *models.LedgerCheck) {
+	run := &ledgerCheckRun{check: check}
+	err := s.checkAll(ctx, run)
+	values := &models.LedgerCheckValues{}
+	values.SetAccountCount(run.accounts).
+		SetFlowCount(run.flows).
+		SetTrxCount(run.trxs).
+		SetDiscrepancyCount(run.discrepancies).
+		SetCompletedAt(utils.TimeNowMilli())
+	if err != nil {
+		log.Get().Errorf("Ledger check %s failed: %v", check.CheckID, err)
+		values.SetStatus(protocol.StatusFailed).SetError(err.Error())
+	} else {
+		values.SetStatus(protocol.StatusCompleted)
+	}
+	if err := models.UpdateLedgerCheckValues(models.WriteDB, check, values); err != nil {
+		log.Get().Errorf("Update ledger check %s failed: %v", check.CheckID, err)
+	}
+	if check.GetStatus() == protocol.StatusFailed || run.discrepancies > 0 {
+		s.alert(check)
+	}
+}
+
+// checkAll 依次执行各项核对，超时返回错误，panic转为错误，保证核对记录置为失败而不是一直处理中
+func (s *LedgerService) checkAll(ctx context.Context, run *ledgerCheckRun) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Get().Errorf("Ledger check %s panic: %v\n%s", run.check.CheckID, r, debug.Stack())
+			err = fmt.Errorf("核对异常: %v", r)
+		}
+	}()
+	if err = s.checkAccounts(ctx, run); err == nil {
+		err = s.checkBusinessFlows(ctx, run)
+	}
+	if err == nil {
+		err = s.checkEntries(run)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("核对超时: %v", err)
+	}
+	return err
+}
+
+// checkAccounts 分批重放全部账户的流水
+func (s *LedgerService) checkAccounts(ctx context.Context, run *ledgerCheckRun) error {
+	batch := config.Get().Ledger.CheckBatchSize
+	var lastID uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		accounts, err := models.ListAccountsAfterID(models.ReadDB, lastID, batch)
+		if err != nil {
+			return fmt.Errorf("查询账户失败: %v", err)
+		}
+		for _, account := range accounts {
+			result, err := s.replay(account)
+			if err != nil {
+				return fmt.Errorf("重放账户%s流水失败: %v", account.AccountID, err)
+			}
+			run.accounts++
+			run.flows += result.FlowCount
+			if err := run.report(result.Discrepancies...); err != nil {
+				return fmt.Errorf("保存核对差异失败: %v", err)
+			}
+		}
+		if len(accounts) < batch {
+			return nil
+		}
+		lastID = accounts[len(accounts)-1].ID
+	}
+}
+
+// ReplayAccount 重放单个账户的流水并返回核对结果，不保存差异
+func (s *LedgerService) ReplayAccount(req *protocol.LedgerReplayRequest) (*protocol.LedgerReplayResult, protocol.ErrorCode) {
+	account, err := models.GetAccountByAccountID(req.AccountID)
+	if err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+	result, err := s.replay(account)
+	if err != nil {
+		log.Get().Errorf("Replay account %s failed: %v", account.AccountID, err)
+		return nil, protocol.DatabaseError
+	}
+	return result, protocol.Success
+}
+
+// replay 按账户版本号顺序重放流水：每条流水的版本号连续、变动前资产等于上一条的变动后资产、金额与资产差额一致，
+// 重放结果与账户当前资产和版本号一致。只重放账户当前版本之前的流水，避免与核对期间的新过账冲突
+func (s *LedgerService) replay(account *models.Account) (*protocol.LedgerReplayResult, error) {
+	result := &protocol.LedgerReplayResult{
+		AccountID:     account.AccountID,
+		Version:       account.GetVersion(),
+		Discrepancies: make([]*protocol.LedgerDiscrepancy, 0),
+	}
+	newDiscrepancy := func(typ string, flow *models.FundFlow, expected, actual string) *protocol.LedgerDiscrepancy {
+		item := &protocol.LedgerDiscrepancy{
+			Type:      typ,
+			AccountID: account.AccountID,
+			UserID:    account.UserID,
+			UserType:  account.UserType,
+			Ccy:       account.Ccy,
+			Expected:  expected,
+			Actual:    actual,
+		}
+		if flow != nil {
+			item.FlowNo = flow.FlowNo
+			item.TrxID = flow.TrxID
+			item.TrxType = flow.TrxType
+		}
+		return item
+	}
+
+	batch := config.Get().Ledger.CheckBatchSize
+	var prev *models.FundFlow
+	var lastVersion int64
+	var lastID uint64
+	for {
+		flows, err := models.ListAccountFundFlows(models.ReadDB, account.AccountID, account.GetVersion(), lastVersion, lastID, batch)
+		if err != nil {
+			return nil, err
+		}
+		for _, flow := range flows {
+			result.FlowCount++
+			expectedVersion := int64(1)
+			var expectedBefore *models.Asset
+			if prev != nil {
+				expectedVersion = prev.AccountVersion + 1
+				expectedBefore = prev.AfterAsset
+			}
+			if flow.AccountVersion != expectedVersion {
+				result.Discrepancies = append(result.Discrepancies, newDiscrepancy(protocol.LedgerDiscrepancyVersionGap, flow,
+					fmt.Sprintf("%d", expectedVersion), fmt.Sprintf("%d", flow.AccountVersion)))
+			}
+			if !sameLedgerAsset(expectedBefore, flow.BeforeAsset) {
+				result.Discrepancies = append(result.Discrepancies, newDiscrepancy(protocol.LedgerDiscrepancyChainBroken, flow,
+					formatLedgerAsset(expectedBefore), formatLedgerAsset(flow.BeforeAsset)))
+			}
+			if change, ok := fundFlowChange(flow); !ok {
+				result.Discrepancies = append(result.Discrepancies, newDiscrepancy(protocol.LedgerDiscrepancyFlowAmount, flow,
+					fmt.Sprintf("%s %s", flow.Direction, flowAmount(flow)), change.String()))
+			}
+			prev = flow
+		}
+		if len(flows) < batch {
+			break
+		}
+		lastVersion, lastID = prev.AccountVersion, prev.ID
+	}
+
+	var replayed *models.Asset
+	expectedVersion := int64(1)
+	if prev != nil {
+		replayed = prev.AfterAsset
+		expectedVersion = prev.AccountVersion + 1
+	}
+	if !sameLedgerAsset(replayed, account.Asset) {
+		result.Discrepancies = append(result.Discrepancies, newDiscrepancy(protocol.LedgerDiscrepancyAssetMismatch, prev,
+			formatLedgerAsset(replayed), formatLedgerAsset(account.Asset)))
+	}
+	if account.GetVersion() != expectedVersion {
+		result.Discrepancies = append(result.Discrepancies, newDiscrepancy(protocol.LedgerDiscrepancyVersionMismatch, prev,
+			fmt.Sprintf("%d", expectedVersion), fmt.Sprintf("%d", account.GetVersion())))
+	}
+	if replayed == nil {
+		replayed = &models.Asset{Ccy: account.Ccy}
+	}
+	result.ReplayedAsset = replayed.Protocol()
+	result.StoredAsset = account.Asset.Protocol()
+	return result, nil
+}
+
+// fundFlowChange 计算流水的资产变动并校验与流水方向和金额一致，余额不变时取冻结、保证金和准备金的合计变动
+func fundFlowChange(flow *models.FundFlow) (decimal.Decimal, bool) {
+	before, after := flow.BeforeAsset, flow.AfterAsset
+	if before == nil {
+		before = &models.Asset{}
+	}
+	if after == nil {
+		after = &models.Asset{}
+	}
+	change := after.Balance.Sub(before.Balance)
+	if change.IsZero() {
+		change = after.FrozenBalance.Add(after.MarginBalance).Add(after.ReserveBalance).
+			Sub(before.FrozenBalance).Sub(before.MarginBalance).Sub(before.ReserveBalance)
+	}
+	if change.IsNegative() != (flow.Direction == protocol.DirectionOut) && !change.IsZero() {
+		return change, false
+	}
+	return change, change.Abs().Equal(flowAmount(flow))
+}
+
+func flowAmount(flow *models.FundFlow) decimal.Decimal {
+	if flow.Amount == nil {
+		return decimal.Zero
+	}
+	return *flow.Amount
+}
+
+// sameLedgerAsset 比较两个资产的各资金属性，nil视为零
+func sameLedgerAsset(a, b *models.Asset) bool {
+	if a == nil {
+		a = &models.Asset{}
+	}
+	if b == nil {
+		b = &models.Asset{}
+	}
+	return a.Balance.Equal(b.Balance) &&
+		a.FrozenBalance.Equal(b.FrozenBalance) &&
+		a.MarginBalance.Equal(b.MarginBalance) &&
+		a.FrozenMarginBalance.Equal(b.FrozenMarginBalance) &&
+		a.ReserveBalance.Equal(b.ReserveBalance)
+}
+
+func formatLedgerAsset(asset *models.Asset) string {
+	if asset == nil {
+		asset = &models.Asset{}
+	}
+	return fmt.Sprintf("balance=%s frozen=%s margin=%s frozen_margin=%s reserve=%s",
+		asset.Balance, asset.FrozenBalance, asset.MarginBalance, asset.FrozenMarginBalance, asset.ReserveBalance)
+}
+
+// ledgerFlowSource 一类业务单据：按主键顺序分批查询，并构建每批单据应有的用户流水
+type ledgerFlowSource struct {
+	name  string
+	build func(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error)
+}
+
+// ledgerFlowBatch 一批业务单据的核对内容
+type ledgerFlowBatch struct {
+	count         int    // 本批单据数，少于批量大小时表示已查询完
+	lastID        uint64 // 本批最后一条单据的主键，作为下一批的游标
+	expected      []*ledgerExpectedFlow
+	discrepancies []*protocol.LedgerDiscrepancy // 无需比对流水即可确定的差异
+}
+
+// checkBusinessFlows 交叉核对时间范围内的业务单据与应有的用户流水，按单据来源依次分批核对
+func (s *LedgerService) checkBusinessFlows(ctx context.Context, run *ledgerCheckRun) error {
+	start, end := run.check.CreatedAtStart, run.check.CreatedAtEnd
+	batch := config.Get().Ledger.CheckBatchSize
+	for _, source := range newLedgerFlowSources() {
+		var lastID uint64
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			result, err := source.build(start, end, lastID, batch)
+			if err != nil {
+				return fmt.Errorf("查询%s失败: %v", source.name, err)
+			}
+			if err := run.report(result.discrepancies...); err != nil {
+				return fmt.Errorf("保存核对差异失败: %v", err)
+			}
+			if err := s.matchExpectedFlows(run, int64(result.count), result.expected); err != nil {
+				return err
+			}
+			if result.count < batch {
+				break
+			}
+			lastID = result.lastID
+		}
+	}
+	return nil
+}
+
+// newLedgerFlowSources 单次核对的业务单据来源：已记账的结算入账、已结算的成功代收/代付交易、争议冻结/解冻/扣款、
+// 大额代付复核冻结/解冻与已通过的人工调账、提现冻结/解冻/出款、车队保证金充值入账、换汇转出/转入、内部转账、结算准备金扣留/释放。
+// 结算记录可能同时经由结算和交易两个来源核对，按结算ID只核对一次
+func newLedgerFlowSources() []*ledgerFlowSource {
+	settled := make(map[string]bool)
+	return []*ledgerFlowSource{
+		{name: "结算记录", build: ledgerSettleFlows(settled)},
+		{name: "代收交易", build: ledgerTrxFlows(protocol.TrxTypePayin, settled)},
+		{name: "代付交易", build: ledgerTrxFlows(protocol.TrxTypePayout, settled)},
+		{name: "争议", build: ledgerDisputeFlows},
+		{name: "审批记录", build: ledgerApprovalFlows},
+		{name: "提现记录", build: ledgerWithdrawFlows},
+		{name: "保证金充值", build: ledgerMarginDepositFlows},
+		{name: "换汇记录", build: ledgerConversionFlows},
+		{name: "内部转账", build: ledgerTransferFlows},
+		{name: "结算准备金", build: ledgerReserveFlows},
+	}
+}
+
+// ledgerSettleFlow 结算记录记账时的结算流水，结算金额为负时为代付扣款，为零时不入账
+func ledgerSettleFlow(settle *models.MerchantSettle) *ledgerExpectedFlow {
+	if settle.GetSettleAmount().IsZero() {
+		return nil
+	}
+	req := newSettleBalanceRequest(settle)
+	return &ledgerExpectedFlow{
+		TrxID: req.TrxID, TrxType: req.TrxType,
+		UserID: req.UserID, UserType: req.UserType, Ccy: req.Ccy,
+		Amount: req.Amount,
+	}
+}
+
+func ledgerSettleFlows(settled map[string]bool) func(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	return func(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+		settles, err := models.ListAccountedMerchantSettles(models.ReadDB, start, end, int64(afterID), limit)
+		if err != nil {
+			return nil, err
+		}
+		result := &ledgerFlowBatch{count: len(settles), expected: make([]*ledgerExpectedFlow, 0, len(settles))}
+		for _, settle := range settles {
+			settled[settle.SettleID] = true
+			if flow := ledgerSettleFlow(settle); flow != nil {
+				result.expected = append(result.expected, flow)
+			}
+		}
+		if len(settles) > 0 {
+			result.lastID = uint64(settles[len(settles)-1].ID)
+		}
+		return result, nil
+	}
+}
+
+// ledgerTrxFlows 时间范围内完成且已结算的成功交易：余额按结算记录汇总入账，交易关联的结算记录必须存在，
+// 结算记录已记账时应有结算入账流水，覆盖记账完成时间不在核对范围内的结算记录
+func ledgerTrxFlows(trxType string, settled map[string]bool) func(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	return func(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+		trxs, err := models.ListTransactionBatchByQuery(&models.TrxQuery{
+			TrxType:          trxType,
+			Status:           protocol.StatusSuccess,
+			SettleStatus:     protocol.StatusSuccess,
+			CompletedAtStart: start,
+			CompletedAtEnd:   end,
+		}, int64(afterID), limit)
+		if err != nil {
+			return nil, err
+		}
+		result := &ledgerFlowBatch{count: len(trxs)}
+		if len(trxs) == 0 {
+			return result, nil
+		}
+		result.lastID = uint64(trxs[len(trxs)-1].ID)
+
+		settleIDs := make([]string, 0, len(trxs))
+		for _, trx := range trxs {
+			if settleID := trx.GetSettleID(); settleID != "" && !settled[settleID] && !slices.Contains(settleIDs, settleID) {
+				settleIDs = append(settleIDs, settleID)
+			}
+		}
+		settles, err := models.ListMerchantSettlesBySettleIDs(models.ReadDB, settleIDs)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[string]*models.MerchantSettle, len(settles))
+		for _, settle := range settles {
+			found[settle.SettleID] = settle
+		}
+		for _, trx := range trxs {
+			settleID := trx.GetSettleID()
+			if settled[settleID] {
+				continue
+			}
+			settle := found[settleID]
+			if settle == nil {
+				result.discrepancies = append(result.discrepancies, &protocol.LedgerDiscrepancy{
+					Type:     protocol.LedgerDiscrepancySettleMissing,
+					UserID:   trx.Mid,
+					UserType: protocol.UserTypeMerchant,
+					Ccy:      trx.Ccy,
+					TrxID:    trx.TrxID,
+					TrxType:  trx.TrxType,
+					Expected: "settle " + settleID,
+					Actual:   "none",
+				})
+				continue
+			}
+			settled[settleID] = true
+			if settle.CompletedAt == nil || *settle.CompletedAt == 0 {
+				continue // 结算记录尚未到记账时间
+			}
+			if flow := ledgerSettleFlow(settle); flow != nil {
+				result.expected = append(result.expected, flow)
+			}
+		}
+		return result, nil
+	}
+}
+
+func ledgerDisputeFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	disputes, err := models.ListDisputesCreatedBetween(models.ReadDB, start, end, int64(afterID), limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(disputes), expected: make([]*ledgerExpectedFlow, 0, len(disputes)*3)}
+	for _, dispute := range disputes {
+		// 余额不足时只冻结部分金额，未冻结时没有冻结及解冻流水；败诉扣款为争议金额减去未扣回金额
+		flow := &ledgerExpectedFlow{
+			TrxID: dispute.DisputeID, TrxType: protocol.TrxTypeFreeze,
+			UserID: dispute.Mid, UserType: protocol.UserTypeMerchant, Ccy: dispute.Ccy,
+			Amount: dispute.GetFrozenAmount(),
+		}
+		if flow.Amount.IsPositive() {
+			result.expected = append(result.expected, flow)
+			if dispute.IsResolved() {
+				result.expected = append(result.expected, flow.with(protocol.TrxTypeUnfreeze))
+			}
+		}
+		if dispute.GetStatus() == protocol.DisputeStatusLost {
+			chargeback := flow.with(protocol.TrxTypeChargeback)
+			chargeback.Amount = dispute.GetAmount().Sub(dispute.GetShortfall())
+			if chargeback.Amount.IsPositive() {
+				result.expected = append(result.expected, chargeback)
+			}
+		}
+	}
+	if len(disputes) > 0 {
+		result.lastID = uint64(disputes[len(disputes)-1].ID)
+	}
+	return result, nil
+}
+
+func ledgerApprovalFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	bizTypes := []string{protocol.ApprovalBizTypePayout, protocol.ApprovalBizTypeBalanceAdjust}
+	approvals, err := models.ListApprovalsCreatedBetween(models.ReadDB, bizTypes, start, end, int64(afterID), limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(approvals), expected: make([]*ledgerExpectedFlow, 0, len(approvals)*2)}
+	for _, approval := range approvals {
+		status := approval.GetStatus()
+		switch approval.BizType {
+		case protocol.ApprovalBizTypePayout:
+			flow := &ledgerExpectedFlow{
+				TrxID: approval.BizID, TrxType: protocol.TrxTypeFreeze,
+				UserID: approval.Mid, UserType: protocol.UserTypeMerchant, Ccy: approval.GetCcy(),
+				Amount: approval.GetAmount(),
+			}
+			result.expected = append(result.expected, flow)
+			// 复核通过后资金继续冻结，解冻随代付进入终态发生
+			if status == protocol.StatusRejected {
+				result.expected = append(result.expected, flow.with(protocol.TrxTypeUnfreeze))
+			}
+		case protocol.ApprovalBizTypeBalanceAdjust:
+			if status != protocol.StatusApproved {
+				continue
+			}
+			payload := approval.GetPayload()
+			result.expected = append(result.expected, &ledgerExpectedFlow{
+				TrxID: approval.BizID, TrxType: protocol.TrxTypeAdjustment,
+				UserID:   payload.Get(protocol.AdjustPayloadUserID),
+				UserType: payload.Get(protocol.AdjustPayloadUserType),
+				Ccy:      approval.GetCcy(), Amount: approval.GetAmount(),
+			})
+		}
+	}
+	if len(approvals) > 0 {
+		result.lastID = uint64(approvals[len(approvals)-1].ID)
+	}
+	return result, nil
+}
+
+func ledgerWithdrawFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	withdraws, err := models.ListMerchantWithdrawsCreatedBetween(models.ReadDB, start, end, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(withdraws), expected: make([]*ledgerExpectedFlow, 0, len(withdraws)*2)}
+	for _, withdraw := range withdraws {
+		flow := &ledgerExpectedFlow{
+			TrxID: withdraw.TrxID, TrxType: protocol.TrxTypeFreeze,
+			UserID: withdraw.Mid, UserType: protocol.UserTypeMerchant, Ccy: withdraw.GetCcy(),
+			Amount: withdraw.GetFrozenAmount(),
+		}
+		result.expected = append(result.expected, flow)
+		switch withdraw.GetStatus() {
+		case protocol.StatusSuccess:
+			result.expected = append(result.expected, flow.with(protocol.TrxTypeWithdraw))
+		case protocol.StatusFailed, protocol.StatusRejected, protocol.StatusCancelled:
+			result.expected = append(result.expected, flow.with(protocol.TrxTypeUnfreeze))
+		}
+	}
+	if len(withdraws) > 0 {
+		result.lastID = withdraws[len(withdraws)-1].ID
+	}
+	return result, nil
+}
+
+func ledgerMarginDepositFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	deposits, err := models.ListCashierDepositsConfirmedBetween(models.ReadDB, start, end, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(deposits), expected: make([]*ledgerExpectedFlow, 0, len(deposits)*2)}
+	for _, deposit := range deposits {
+		flow := &ledgerExpectedFlow{
+			TrxID: deposit.TrxID, TrxType: protocol.TrxTypeDeposit,
+			UserID: deposit.Tid, UserType: protocol.UserTypeCashierTeam, Ccy: deposit.GetCcy(),
+			Amount: deposit.GetAmount(),
+		}
+		result.expected = append(result.expected, flow, flow.with(protocol.TrxTypeMarginDeposit))
+	}
+	if len(deposits) > 0 {
+		result.lastID = deposits[len(deposits)-1].ID
+	}
+	return result, nil
+}
+
+func ledgerConversionFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	conversions, err := models.ListFxConversionsExecutedBetween(models.ReadDB, start, end, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(conversions), expected: make([]*ledgerExpectedFlow, 0, len(conversions)*2)}
+	for _, conversion := range conversions {
+		result.expected = append(result.expected, &ledgerExpectedFlow{
+			TrxID: conversion.ConversionID, TrxType: protocol.TrxTypeConvertOut,
+			UserID: conversion.Mid, UserType: protocol.UserTypeMerchant, Ccy: conversion.FromCcy,
+			Amount: conversion.FromAmount,
+		}, &ledgerExpectedFlow{
+			TrxID: conversion.ConversionID, TrxType: protocol.TrxTypeConvertIn,
+			UserID: conversion.Mid, UserType: protocol.UserTypeMerchant, Ccy: conversion.ToCcy,
+			Amount: conversion.ToAmount,
+		})
+	}
+	if len(conversions) > 0 {
+		result.lastID = conversions[len(conversions)-1].ID
+	}
+	return result, nil
+}
+
+func ledgerTransferFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	transfers, err := models.ListInternalTransfersExecutedBetween(models.ReadDB, start, end, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(transfers), expected: make([]*ledgerExpectedFlow, 0, len(transfers)*2)}
+	for _, transfer := range transfers {
+		result.expected = append(result.expected, &ledgerExpectedFlow{
+			TrxID: transfer.TransferID, TrxType: protocol.TrxTypeTransfer,
+			UserID: transfer.FromUserID, UserType: transfer.FromUserType, Ccy: transfer.Ccy,
+			Amount: transfer.Amount,
+		}, &ledgerExpectedFlow{
+			TrxID: transfer.TransferID, TrxType: protocol.TrxTypeTransfer,
+			UserID: transfer.ToUserID, UserType: transfer.ToUserType, Ccy: transfer.Ccy,
+			Amount: transfer.Amount,
+		})
+	}
+	if len(transfers) > 0 {
+		result.lastID = transfers[len(transfers)-1].ID
+	}
+	return result, nil
+}
+
+func ledgerReserveFlows(start, end int64, afterID uint64, limit int) (*ledgerFlowBatch, error) {
+	reserves, err := models.ListMerchantReservesCreatedBetween(models.ReadDB, start, end, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &ledgerFlowBatch{count: len(reserves), expected: make([]*ledgerExpectedFlow, 0, len(reserves)*2)}
+	for _, reserve := range reserves {
+		flow := &ledgerExpectedFlow{
+			TrxID: reserve.ReserveID, TrxType: protocol.TrxTypeRsvHold,
+			UserID: reserve.Mid, UserType: protocol.UserTypeMerchant, Ccy: reserve.Ccy,
+			Amount: reserve.Amount,
+		}
+		result.expected = append(result.expected, flow)
+		if reserve.GetStatus() == protocol.ReserveStatusReleased {
+			result.expected = append(result.expected, flow.with(protocol.TrxTypeRsvRelease))
+		}
+	}
+	if len(reserves) > 0 {
+		result.lastID = reserves[len(reserves)-1].ID
+	}
+	return result, nil
+}
+
+// with 复制应有流水并替换业务类型
+func (f *ledgerExpectedFlow) with(trxType string) *ledgerExpectedFlow {
+	flow := *f
+	flow.TrxType = trxType
+	return &flow
+}
+
+// matchExpectedFlows 将应有流水与实际用户流水逐一比对，缺失、重复和金额不一致均记为差异
+func (s *LedgerService) matchExpectedFlows(run *ledgerCheckRun, trxCount int64, expected []*ledgerExpectedFlow) error {
+	run.trxs += trxCount
+	if len(expected) == 0 {
+		return nil
+	}
+	trxIDs := make([]string, 0, len(expected))
+	for _, item := range expected {
+		trxIDs = append(trxIDs, item.TrxID)
+	}
+	flows, err := models.ListFundFlowsByTrxIDs(models.ReadDB, trxIDs)
+	if err != nil {
+		return fmt.Errorf("查询业务流水失败: %v", err)
+	}
+	actual := make(map[string][]*models.FundFlow, len(flows))
+	for _, flow := range flows {
+		key := (&ledgerExpectedFlow{TrxID: flow.TrxID, TrxType: flow.TrxType, UserID: flow.UserID, UserType: flow.UserType}).key()
+		actual[key] = append(actual[key], flow)
+	}
+	for _, item := range expected {
+		discrepancy := &protocol.LedgerDiscrepancy{
+			UserID:   item.UserID,
+			UserType: item.UserType,
+			Ccy:      item.Ccy,
+			TrxID:    item.TrxID,
+			TrxType:  item.TrxType,
+			Expected: item.Amount.String(),
+		}
+		matched := actual[item.key()]
+		switch {
+		case len(matched) == 0:
+			discrepancy.Type = protocol.LedgerDiscrepancyFlowMissing
+			discrepancy.Actual = "none"
+		case len(matched) > 1:
+			discrepancy.Type = protocol.LedgerDiscrepancyFlowDuplicate
+			discrepancy.Actual = fmt.Sprintf("%d flows", len(matched))
+		case !flowAmount(matched[0]).Equal(item.Amount) || matched[0].Ccy != item.Ccy:
+			discrepancy.Type = protocol.LedgerDiscrepancyFlowMismatch
+			discrepancy.Actual = fmt.Sprintf("%s %s", flowAmount(matched[0]), matched[0].Ccy)
+			discrepancy.Expected = fmt.Sprintf("%s %s", item.Amount, item.Ccy)
+		default:
+			continue
+		}
+		if len(matched) > 0 {
+			discrepancy.AccountID = matched[0].AccountID
+			discrepancy.FlowNo = matched[0].FlowNo
+		}
+		if err := run.report(discrepancy); err != nil {
+			return fmt.Errorf("保存核对差异失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkEntries 检查时间范围内的记账凭证借贷平衡
+func (s *LedgerService) checkEntries(run *ledgerCheckRun) error {
+	entryIDs, err := models.ListUnbalancedLedgerEntryIDs(models.ReadDB, run.check.CreatedAtStart, run.check.CreatedAtEnd)
+	if err != nil {
+		return fmt.Errorf("查询记账凭证失败: %v", err)
+	}
+	for _, entryID := range entryIDs {
+		if err := run.report(&protocol.LedgerDiscrepancy{
+			Type:     protocol.LedgerDiscrepancyUnbalanced,
+			Expected: "debit = credit",
+			Actual:   entryID,
+		}); err != nil {
+			return fmt.Errorf("保存核对差异失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// alert 核对发现差异或执行失败时记录错误日志并邮件通知配置的告警邮箱
+func (s *LedgerService) alert(check *models.LedgerCheck) {
+	log.Get().Errorf("Ledger check %s: status=%s, discrepancies=%d, error=%s",
+		check.CheckID, check.GetStatus(), check.GetDiscrepancyCount(), check.GetError())
+	for _, email := range config.Get().Ledger.AlertEmails {
+		msg := &Message{
+			Type:     protocol.MsgTypeLedgerDiscrepancy,
+			To:       email,
+			Language: protocol.LangEnglish,
+			Params: map[string]any{
+				"to":                email,
+				"check_id":          check.CheckID,
+				"status":            check.GetStatus(),
+				"account_count":     check.GetAccountCount(),
+				"flow_count":        check.GetFlowCount(),
+				"trx_count":         check.GetTrxCount(),
+				"discrepancy_count": check.GetDiscrepancyCount(),
+				"error":             check.GetError(),
+				"completed_at":      time.UnixMilli(check.GetCompletedAt()).UTC().Format(time.RFC3339),
+			},
+		}
+		if err := GetMessageService().SendEmailMessage(msg); err != nil {
+			log.Get().Errorf("Send ledger check alert for %s to %s failed: %v", check.CheckID, email, err)
+		}
+	}
+}
+
+// GetCheck 获取账务核对记录
+func (s *LedgerService) GetCheck(checkID string) (*protocol.LedgerCheck, protocol.ErrorCode) {
+	check := models.GetLedgerCheck(checkID)
+	if check == nil {
+		return nil, protocol.LedgerCheckNotFound
+	}
+	return check.Protocol(), protocol.Success
+}
+
+// ListChecks 分页查询账务核对记录
+func (s *LedgerService) ListChecks(req *protocol.LedgerCheckListRequest) ([]*protocol.LedgerCheck, int64, protocol.ErrorCode) {
+	checks, total, err := models.ListLedgerCheckByQuery(&models.LedgerCheckQuery{
+		Status:         req.Status,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.LedgerCheck, 0, len(checks))
+	for _, check := range checks {
+		list = append(list, check.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// ListDiscrepancies 分页查询账务核对差异
+func (s *LedgerService) ListDiscrepancies(req *protocol.LedgerDiscrepancyListRequest) ([]*protocol.LedgerDiscrepancy, int64, protocol.ErrorCode) {
+	if models.GetLedgerCheck(req.CheckID) == nil {
+		return nil, 0, protocol.LedgerCheckNotFound
+	}
+	items, total, err := models.ListLedgerDiscrepancyByQuery(&models.LedgerDiscrepancyQuery{
+		CheckID:   req.CheckID,
+		Type:      req.Type,
+		AccountID: req.AccountID,
+		Page:      req.Page,
+		Size:      req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.LedgerDiscrepancy, 0, len(items))
+	for _, item := range items {
+		list = append(list, item.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// RegisterLedgerTasks 注册账务核对任务
+func RegisterLedgerTasks() {
+	log.Get().Info("注册账务核对任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "ledger_reconcile",
+			Type:       protocol.LedgerReconcile,
+			HandlerKey: protocol.LedgerReconcile,
+			Name:       "账务核对",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"30 3 * * *"}[0], // 每天03:30执行
+				Timeout: &[]int{3600}[0],            // 1小时超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("账务核对任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleLedgerReconcile 定时重放账户流水并交叉核对业务单据
+func HandleLedgerReconcile(ctx context.Context, params protocol.MapData) error {
+	return GetLedgerService().Reconcile(ctx)
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// LedgerService 复式记账服务，资金变动先以借贷平衡的凭证过账，再投影到账户余额和资金流水
+type LedgerService struct {
+	checking atomic.Bool // 是否有账务核对正在进行
+}
+
+var (
+	ledgerService     *LedgerService
+	ledgerServiceOnce sync.Once
+)
+
+func SetupLedgerService() {
+	ledgerServiceOnce.Do(func() {
+		ledgerService = &LedgerService{}
+	})
+}
+
+// GetLedgerService 获取复式记账服务单例
+func GetLedgerService() *LedgerService {
+	if ledgerService == nil {
+		SetupLedgerService()
+	}
+	return ledgerService
+}
+
+// LedgerLeg 凭证分录，账户由用户和凭证币种定位
+type LedgerLeg struct {
+	UserID   string
+	UserType string
+	Bucket   string
+	Side     string
+	Amount   decimal.Decimal
+}
+
+// LedgerPosting 待过账凭证
+type LedgerPosting struct {
+	TrxID      string
+	TrxType    string
+	Ccy        string
+	FlowType   string
+	Remark     string
+	OperatorID string
+	OriFlowNo  string // 用户流水关联的原始流水号
+	Legs       []*LedgerLeg
+}
+
+// Debit 添加借方分录
+func (p *LedgerPosting) Debit(userID, userType, bucket string, amount decimal.Decimal) *LedgerPosting {
+	p.Legs = append(p.Legs, &LedgerLeg{UserID: userID, UserType: userType, Bucket: bucket, Side: protocol.LedgerSideDebit, Amount: amount})
+	return p
+}
+
+// Credit 添加贷方分录
+func (p *LedgerPosting) Credit(userID, userType, bucket string, amount decimal.Decimal) *LedgerPosting {
+	p.Legs = append(p.Legs, &LedgerLeg{UserID: userID, UserType: userType, Bucket: bucket, Side: protocol.LedgerSideCredit, Amount: amount})
+	return p
+}
+
+// ledgerAccount 过账中的账户及其分录
+type ledgerAccount struct {
+	account *models.Account
+	legs    []*LedgerLeg
+}
+
+func ledgerAccountKey(userType, userID string) string {
+	return userType + ":" + userID
+}
+
+// Post 在调用方事务中过账：校验借贷平衡，锁定涉及的账户，写入凭证和分录，再为每个账户更新余额并生成一条资金流水
+func (s *LedgerService) Post(tx *gorm.DB, posting *LedgerPosting) (*models.LedgerEntry, protocol.ErrorCode) {
+	debit, credit := decimal.Zero, decimal.Zero
+	for _, leg := range posting.Legs {
+		if !leg.Amount.IsPositive() {
+			return nil, protocol.InvalidAmount
+		}
+		switch leg.Side {
+		case protocol.LedgerSideDebit:
+			debit = debit.Add(leg.Amount)
+		case protocol.LedgerSideCredit:
+			credit = credit.Add(leg.Amount)
+		default:
+			return nil, protocol.AccountErrorLedgerUnbalanced
+		}
+	}
+	if len(posting.Legs) < 2 || !debit.Equal(credit) {
+		log.Get().Errorf("Ledger posting unbalanced: trx_id=%s, debit=%s, credit=%s", posting.TrxID, debit, credit)
+		return nil, protocol.AccountErrorLedgerUnbalanced
+	}
+
+	accounts, keys, code := s.lockAccounts(tx, posting)
+	if code != protocol.Success {
+		return nil, code
+	}
+
+	entry := &models.LedgerEntry{
+		EntryID:    utils.GenerateLedgerEntryID(),
+		TrxID:      posting.TrxID,
+		TrxType:    posting.TrxType,
+		Ccy:        posting.Ccy,
+		Amount:     &debit,
+		Remark:     posting.Remark,
+		OperatorID: posting.OperatorID,
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return nil, protocol.DatabaseError
+	}
+	lines := make([]*models.LedgerLine, 0, len(posting.Legs))
+	for i, leg := range posting.Legs {
+		amount := leg.Amount
+		lines = append(lines, &models.LedgerLine{
+			EntryID:   entry.EntryID,
+			Seq:       i + 1,
+			AccountID: accounts[ledgerAccountKey(leg.UserType, leg.UserID)].account.AccountID,
+			UserID:    leg.UserID,
+			UserType:  leg.UserType,
+			Ccy:       posting.Ccy,
+			Bucket:    leg.Bucket,
+			Side:      leg.Side,
+			Amount:    &amount,
+		})
+	}
+	if err := tx.Create(&lines).Error; err != nil {
+		return nil, protocol.DatabaseError
+	}
+
+	for _, key := range keys {
+		if code := s.project(tx, entry, posting, accounts[key]); code != protocol.Success {
+			return nil, code
+		}
+	}
+	return entry, protocol.Success
+}
+
+// lockAccounts 按固定顺序锁定凭证涉及的账户，避免并发过账死锁，系统账户不存在时自动开户
+// 返回的keys为账户在凭证中首次出现的顺序，用于按序生成资金流水
+func (s *LedgerService) lockAccounts(tx *gorm.DB, posting *LedgerPosting) (map[string]*ledgerAccount, []string, protocol.ErrorCode) {
+	accounts := make(map[string]*ledgerAccount)
+	keys := make([]string, 0, len(posting.Legs))
+	for _, leg := range posting.Legs {
+		key := ledgerAccountKey(leg.UserType, leg.UserID)
+		if _, ok := accounts[key]; !ok {
+			accounts[key] = &ledgerAccount{}
+			keys = append(keys, key)
+		}
+		accounts[key].legs = append(accounts[key].legs, leg)
+	}
+
+	lockOrder := append([]string(nil), keys...)
+	sort.Strings(lockOrder)
+	for _, key := range lockOrder {
+		item := accounts[key]
+		userID, userType := item.legs[0].UserID, item.legs[0].UserType
+		var account *models.Account
+		var err error
+		if userType == protocol.System {
+			account, err = s.lockSystemAccount(tx, userID, posting.Ccy)
+		} else {
+			account, err = models.GetAccountForUpdate(tx, userID, userType, posting.Ccy)
+		}
+		if err != nil {
+			log.Get().Errorf("Ledger posting: account not found, user=%s type=%s ccy=%s: %v", userID, userType, posting.Ccy, err)
+			return nil, nil, protocol.AccountErrorAccountNotFound
+		}
+		if account.Asset == nil {
+			account.Asset = &models.Asset{Ccy: posting.Ccy}
+		}
+		item.account = account
+	}
+	return accounts, keys, protocol.Success
+}
+
+// lockSystemAccount 锁定系统账户，首次使用该币种时开户
+func (s *LedgerService) lockSystemAccount(tx *gorm.DB, userID, ccy string) (*models.Account, error) {
+	account, err := models.GetAccountForUpdate(tx, userID, protocol.System, ccy)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return account, err
+	}
+	account = models.NewAccount()
+	account.AccountID = utils.GenerateAccountID()
+	account.UserID = userID
+	account.UserType = protocol.System
+	account.Ccy = ccy
+	account.SetAsset(&models.Asset{Ccy: ccy, UpdatedAt: utils.TimeNowMilli()}).
+		SetStatus(protocol.StatusActive).
+		SetVersion(1).
+		SetLastActiveAt(utils.TimeNowMilli())
+	if err := models.CreateAccountIfNotExists(tx, account); err != nil {
+		return nil, err
+	}
+	return models.GetAccountForUpdate(tx, userID, protocol.System, ccy)
+}
+
+// project 将凭证中属于该账户的分录投影到账户余额，并生成一条资金流水
+// 用户账户被借记的资金属性不能为负，系统账户允许为负
+func (s *LedgerService) project(tx *gorm.DB, entry *models.LedgerEntry, posting *LedgerPosting, item *ledgerAccount) protocol.ErrorCode {
+	account := item.account
+	beforeAsset := *account.Asset
+	afterAsset := account.Asset
+	for _, leg := range item.legs {
+		amount := leg.Amount
+		if leg.Side == protocol.LedgerSideDebit {
+			amount = amount.Neg()
+		}
+		switch leg.Bucket {
+		case protocol.LedgerBucketBalance:
+			afterAsset.Balance = afterAsset.Balance.Add(amount)
+		case protocol.LedgerBucketFrozen:
+			afterAsset.FrozenBalance = afterAsset.FrozenBalance.Add(amount)
+		case protocol.LedgerBucketMargin:
+			afterAsset.MarginBalance = afterAsset.MarginBalance.Add(amount)
+		case protocol.LedgerBucketReserve:
+			afterAsset.ReserveBalance = afterAsset.ReserveBalance.Add(amount)
+		default:
+			return protocol.InvalidBalanceType
+		}
+	}
+	if account.UserType != protocol.System {
+		for _, leg := range item.legs {
+			if leg.Side != protocol.LedgerSideDebit {
+				continue
+			}
+			switch {
+			case leg.Bucket == protocol.LedgerBucketBalance && afterAsset.Balance.IsNegative():
+				return protocol.AccountErrorInsufficientBalance
+			case leg.Bucket == protocol.LedgerBucketFrozen && afterAsset.FrozenBalance.IsNegative():
+				return protocol.AccountErrorInsufficientFrozenBalance
+			case leg.Bucket == protocol.LedgerBucketMargin && afterAsset.MarginBalance.IsNegative():
+				return protocol.AccountErrorInsufficientMarginBalance
+			case leg.Bucket == protocol.LedgerBucketReserve && afterAsset.ReserveBalance.IsNegative():
+				return protocol.AccountErrorInsufficientReserve
+			}
+		}
+	}
+	afterAsset.AvailableBalance = afterAsset.Balance.Sub(afterAsset.FrozenBalance)
+	afterAsset.UpdatedAt = utils.TimeNowMilli()
+
+	// 流水金额以余额变动为准，余额不变时取各资金属性的合计变动
+	change := afterAsset.Balance.Sub(beforeAsset.Balance)
+	if change.IsZero() {
+		change = afterAsset.FrozenBalance.Add(afterAsset.MarginBalance).Add(afterAsset.ReserveBalance).
+			Sub(beforeAsset.FrozenBalance).Sub(beforeAsset.MarginBalance).Sub(beforeAsset.ReserveBalance)
+	}
+	direction := protocol.DirectionIn
+	if change.IsNegative() {
+		direction = protocol.DirectionOut
+	}
+	amount := change.Abs()
+	fundFlow := &models.FundFlow{
+		FlowNo:         utils.GenerateFlowNo(),
+		Type:           posting.FlowType,
+		OriFlowNo:      posting.OriFlowNo,
+		Direction:      direction,
+		UserID:         account.UserID,
+		UserType:       account.UserType,
+		AccountID:      account.AccountID,
+		AccountVersion: account.GetVersion(),
+		TrxID:          posting.TrxID,
+		TrxType:        posting.TrxType,
+		Ccy:            posting.Ccy,
+		Amount:         &amount,
+		BeforeAsset:    &beforeAsset,
+		AfterAsset:     afterAsset,
+		Remark:         posting.Remark,
+		OperatorId:     posting.OperatorID,
+		CreatedAt:      utils.TimeNowMilli(),
+		EntryID:        entry.EntryID,
+	}
+	if err := tx.Create(fundFlow).Error; err != nil {
+		return protocol.DatabaseError
+	}
+	values := &models.AccountValues{
+		Asset: afterAsset,
+	}
+	values.SetVersion(account.GetVersion() + 1)
+	if err := tx.Model(account).UpdateColumns(values).Error; err != nil {
+		return protocol.AccountErrorUpdateFailed
+	}
+	return protocol.Success
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// MerchantApprovalService 大额代付复核服务（maker-checker）
+type MerchantApprovalService struct{}
+
+var (
+	merchantApprovalService     *MerchantApprovalService
+	merchantApprovalServiceOnce sync.Once
+)
+
+func SetupMerchantApprovalService() {
+	merchantApprovalServiceOnce.Do(func() {
+		merchantApprovalService = &MerchantApprovalService{}
+	})
+}
+
+// GetMerchantApprovalService 获取复核服务单例
+func GetMerchantApprovalService() *MerchantApprovalService {
+	if merchantApprovalService == nil {
+		SetupMerchantApprovalService()
+	}
+	return merchantApprovalService
+}
+
+// EvaluatePayout 根据商户代付配置评估是否需要复核，返回命中的风险标记
+func (s *MerchantApprovalService) EvaluatePayout(trx *models.Transaction) []string {
+	if trx.Amount == nil {
+		return nil
+	}
+	amount := *trx.Amount
+	cfg := GetConfigService().GetTrxConfigByMerchantID(trx.Mid, models.TrxTypePayment)
+
+	var flags []string
+	if threshold := cfg.GetApprovalThreshold(trx.Ccy); threshold.IsPositive() && amount.GreaterThan(threshold) {
+		flags = append(flags, protocol.ApprovalFlagOverThreshold)
+	}
+	if cfg.HasApprovalRiskFlag(protocol.ApprovalFlagNewAccount) && trx.AccountNo != "" {
+		var count int64
+		models.GetTransactionQueryByType(protocol.TrxTypePayout).
+			Where("mid = ? AND account_no = ? AND status = ?", trx.Mid, trx.AccountNo, protocol.StatusSuccess).
+			Count(&count)
+		if count == 0 {
+			flags = append(flags, protocol.ApprovalFlagNewAccount)
+		}
+	}
+	if cfg.HasApprovalRiskFlag(protocol.ApprovalFlagOverDailyLimit) {
+		if limit := cfg.GetDailyLimit(trx.Ccy); limit.IsPositive() {
+			var total decimal.NullDecimal
+			models.GetTransactionQueryByType(protocol.TrxTypePayout).
+				Where("mid = ? AND ccy = ? AND created_at >= ? AND status <> ?", trx.Mid, trx.Ccy, utils.TodayZeroTimeMilli(), protocol.StatusFailed).
+				Select("SUM(amount)").
+				Scan(&total)
+			if total.Decimal.Add(amount).GreaterThan(limit) {
+				flags = append(flags, protocol.ApprovalFlagOverDailyLimit)
+			}
+		}
+	}
+	return flags
+}
+
+// HoldPayout 代付进入待复核状态：冻结商户资金并创建审批记录，不请求渠道；requestedBy为提交代付的子账号ID
+func (s *MerchantApprovalService) HoldPayout(payout *models.MerchantPayout, flags []string, requestedBy string) (info *protocol.Transaction, code protocol.ErrorCode) {
+	code = protocol.Success
+	payout.SetStatus(protocol.StatusPendingApproval)
+
+	approval := &models.Approval{
+		ApprovalID:     utils.GenerateApprovalID(),
+		Mid:            payout.Mid,
+		BizType:        protocol.ApprovalBizTypePayout,
+		BizID:          payout.TrxID,
+		ApprovalValues: &models.ApprovalValues{},
+	}
+	approval.SetStatus(protocol.StatusPending).
+		SetCcy(payout.Ccy).
+		SetAmount(*payout.Amount).
+		SetRiskFlags(flags).
+		SetRequestedBy(requestedBy)
+
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payout).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      payout.Mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         payout.Ccy,
+			Amount:      *payout.Amount,
+			TrxID:       payout.TrxID,
+			TrxType:     protocol.TrxTypeFreeze,
+			Description: "payout pending approval",
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "freeze payout amount failed")
+		}
+		if err := tx.Create(approval).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("HoldPayout: trx_id=%s, err=%v", payout.TrxID, err)
+		return
+	}
+	trans := payout.ToTransaction()
+	AfterTransactionCreate(trans)
+	info = trans.Protocol()
+	return
+}
+
+// List 审批列表
+func (s *MerchantApprovalService) List(mid string, req *protocol.ApprovalListRequest) ([]*protocol.Approval, int64, protocol.ErrorCode) {
+	approvals, total, err := models.ListApprovalByQuery(&models.ApprovalQuery{
+		Mid:            mid,
+		BizType:        req.BizType,
+		BizID:          req.BizID,
+		Status:         req.Status,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.Approval, 0, len(approvals))
+	for _, approval := range approvals {
+		list = append(list, approval.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Approve 复核通过：按正常流程请求渠道出款，资金继续冻结至代付进入终态
+func (s *MerchantApprovalService) Approve(ctx context.Context, mid, approverID string, req *protocol.ApprovalDecisionRequest) (*protocol.Approval, protocol.ErrorCode) {
+	approval, approver, code := s.checkDecision(mid, approverID, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	trx := models.GetTransactionByMidAndTrxID(mid, approval.BizID, protocol.TrxTypePayout)
+	if trx == nil {
+		return nil, protocol.TransactionNotFound
+	}
+	if trx.GetStatus() != protocol.StatusPendingApproval {
+		return nil, protocol.ApprovalAlreadyDecided
+	}
+	routerInfo := GetChannelRouterByMerchant(trx)
+	if routerInfo == nil {
+		return nil, protocol.ChannelNotFound
+	}
+
+	history := models.NewTrxHistoryByTransaction(trx)
+	decision := &models.ApprovalValues{}
+	decision.SetStatus(protocol.StatusApproved).
+		SetApprovedBy(approver.UserID).
+		SetDecisionReason(req.Reason).
+		SetDecidedAt(utils.TimeNowMilli())
+	values := models.NewTrxValues()
+	code = protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		// 复核时冻结的资金继续冻结至代付进入终态
+		result, errCode := RequestByRouter(ctx, tx, trx, routerInfo)
+		if errCode != protocol.Success {
+			code = errCode
+			return protocol.NewServiceError(errCode, "channel request error")
+		}
+		if code = applyPayoutResult(tx, trx, protocol.StatusPendingApproval, result.Status, ""); code != protocol.Success {
+			return protocol.NewServiceError(code, "apply payout result failed")
+		}
+		values.SetStatus(result.Status).
+			SetChannelStatus(result.ChannelStatus).
+			SetChannelCode(result.ChannelCode).
+			SetChannelAccount(result.ChannelAccountID).
+			SetChannelTrxID(result.ChannelTrxID).
+			SetLink(result.Link).
+			SetResCode(result.ResCode).
+			SetResMsg(result.ResMsg).
+			SetChannelFeeCcy(result.ChannelFeeCcy)
+		values.ChannelFeeAmount = result.ChannelFeeAmount
+		if result.Status == protocol.StatusFailed || result.Status == protocol.StatusSuccess {
+			values.SetCompletedAt(utils.TimeNowMilli())
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Approve payout: approval_id=%s, err=%v", approval.ApprovalID, err)
+		return nil, code
+	}
+	GetFxRateService().SnapshotCompletion(trx, values)
+	if _err := SaveTransactionResult(trx, values); _err != nil {
+		log.Get().Errorf("SaveTransactionResult error: %v", _err)
+	}
+	s.saveDecisionHistory(history, trx, approver.UserID, protocol.StatusApproved, req.Reason)
+	return approval.Protocol(), protocol.Success
+}
+
+// Reject 复核驳回：解冻资金，代付置为失败
+func (s *MerchantApprovalService) Reject(mid, approverID string, req *protocol.ApprovalDecisionRequest) (*protocol.Approval, protocol.ErrorCode) {
+	approval, approver, code := s.checkDecision(mid, approverID, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	trx := models.GetTransactionByMidAndTrxID(mid, approval.BizID, protocol.TrxTypePayout)
+	if trx == nil {
+		return nil, protocol.TransactionNotFound
+	}
+	if trx.GetStatus() != protocol.StatusPendingApproval {
+		return nil, protocol.ApprovalAlreadyDecided
+	}
+
+	history := models.NewTrxHistoryByTransaction(trx)
+	decision := &models.ApprovalValues{}
+	decision.SetStatus(protocol.StatusRejected).
+		SetApprovedBy(approver.UserID).
+		SetDecisionReason(req.Reason).
+		SetDecidedAt(utils.TimeNowMilli())
+	values := models.NewTrxValues()
+	values.SetStatus(protocol.StatusFailed).
+		SetResCode(protocol.ResCodeApprovalRejected).
+		SetResMsg(req.Reason).
+		SetReason(req.Reason).
+		SetCompletedAt(utils.TimeNowMilli())
+	GetFxRateService().SnapshotCompletion(trx, values)
+	code = protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.DecideApproval(tx, approval, decision)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ApprovalAlreadyDecided
+			return protocol.NewServiceError(code, "approval already decided")
+		}
+		if code = s.unfreezePayout(tx, trx); code != protocol.Success {
+			return protocol.NewServiceError(code, "unfreeze payout amount failed")
+		}
+		if err := models.SaveTransactionValues(tx, trx, values); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if err := CreateTransactionWebhook(tx, trx); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Reject payout: approval_id=%s, err=%v", approval.ApprovalID, err)
+		return nil, code
+	}
+	s.saveDecisionHistory(history, trx, approver.UserID, protocol.StatusRejected, req.Reason)
+	return approval.Protocol(), protocol.Success
+}
+
+// checkDecision 校验审批记录、复核员身份、角色及G2FA，复核员为当前登录的子账号，不能复核自己提交的代付
+func (s *MerchantApprovalService) checkDecision(mid, approverID string, req *protocol.ApprovalDecisionRequest) (*models.Approval, *models.MerchantUser, protocol.ErrorCode) {
+	approval := models.GetApprovalByID(mid, req.ApprovalID)
+	if approval == nil {
+		return nil, nil, protocol.ApprovalNotFound
+	}
+	if approval.GetStatus() != protocol.StatusPending {
+		return nil, nil, protocol.ApprovalAlreadyDecided
+	}
+	if approverID == "" {
+		return nil, nil, protocol.MerchantUserNotApprover
+	}
+	approver := models.GetMerchantUser(mid, approverID)
+	if approver == nil {
+		return nil, nil, protocol.MerchantUserNotFound
+	}
+	if !approver.IsActive() {
+		return nil, nil, protocol.MerchantUserDisabled
+	}
+	if !approver.IsApprover() {
+		return nil, nil, protocol.MerchantUserNotApprover
+	}
+	if approver.UserID == approval.GetRequestedBy() {
+		return nil, nil, protocol.ApprovalSelfReview
+	}
+	if !VerifyG2FACode(approver.GetG2FA(), req.Code) {
+		return nil, nil, protocol.InvalidTwoFactorCode
+	}
+	return approval, approver, protocol.Success
+}
+
+func (s *MerchantApprovalService) unfreezePayout(tx *gorm.DB, trx *models.Transaction) protocol.ErrorCode {
+	return GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+		UserID:      trx.Mid,
+		UserType:    protocol.UserTypeMerchant,
+		Ccy:         trx.Ccy,
+		Amount:      *trx.Amount,
+		TrxID:       trx.TrxID,
+		TrxType:     protocol.TrxTypeUnfreeze,
+		Description: "payout approval decided",
+	})
+}
+
+// saveDecisionHistory 记录复核决定、复核人和审批意见
+func (s *MerchantApprovalService) saveDecisionHistory(history *models.TrxHistory, trx *models.Transaction, approverID, decision, reason string) {
+	history.TrxType = trx.TrxType
+	history.FillValues(trx.TransactionValues)
+	history.ChangedBy = approverID
+	history.Remark = fmt.Sprintf("%s: %s", decision, reason)
+	if err := models.CreateHistory(history); err != nil {
+		log.Get().Errorf("Save approval history error: trx_id=%s, err=%v", trx.TrxID, err)
+	}
+}
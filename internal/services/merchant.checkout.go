@@ -55,6 +55,9 @@ func (s *CheckoutService) Create(req *protocol.CreateCheckoutRequest) (info *pro
 	checkoutID := utils.GenerateCheckoutID()
 	ckCfg := config.Get().MerchantCheckout
 	expiredAt := time.Now().Add(time.Duration(ckCfg.ExpiryMinutes) * time.Minute).UnixMilli()
+	if req.ExpiredAt > 0 && req.ExpiredAt < expiredAt {
+		expiredAt = req.ExpiredAt
+	}
 
 	// 6. 创建收银台记录
 	checkout = &models.MerchantCheckout{
@@ -62,6 +65,7 @@ func (s *CheckoutService) Create(req *protocol.CreateCheckoutRequest) (info *pro
 		Mid:        req.Mid,
 		ReqID:      req.ReqID,
 		TrxType:    protocol.TrxTypePayin, // 默认代收类型
+		LinkID:     req.LinkID,
 		MerchantCheckoutValues: &models.MerchantCheckoutValues{
 			Ccy:          &req.Ccy,
 			Amount:       amt,
@@ -75,6 +79,9 @@ func (s *CheckoutService) Create(req *protocol.CreateCheckoutRequest) (info *pro
 		},
 	}
 	checkout.SetStatus(protocol.StatusPending)
+	if ckCfg.PageURL != "" {
+		checkout.SetCheckoutURL(ckCfg.PageURL + "/" + checkoutID)
+	}
 
 	// 7. 保存到数据库
 	dbErr := models.WriteDB.Transaction(func(tx *gorm.DB) error {
@@ -133,6 +140,7 @@ func (s *CheckoutService) Submit(req *protocol.SubmitCheckoutRequest) (trx *prot
 		TrxMethod: req.TrxMethod,
 		ReturnURL: checkout.GetReturnURL(),
 		AccountNo: req.AccountNo,
+		LinkID:    checkout.LinkID,
 		TransactionValues: &models.TransactionValues{
 			Status:    &status,
 			NotifyURL: &notifyURL,
@@ -199,6 +207,12 @@ func (s *CheckoutService) Confirm(req *protocol.ConfirmCheckoutRequest) (trx *pr
 	//更新交易记录
 	transaction.SetStatus(protocol.StatusConfirming).
 		SetSubmitedAt(now)
+	// 快照入库时的美元汇率与金额
+	transaction.UsdRate, transaction.UsdAmount = GetFxRateService().UsdSnapshot(transaction.Ccy, transaction.Amount, now)
+	// 按商户费率配置预计算手续费
+	if fee, feeCcy, ok := GetFeeConfigService().CalculateFee(transaction); ok {
+		transaction.SetFeeCcy(feeCcy).SetFeeAmount(fee)
+	}
 
 	// 更新收银台状态
 	checkoutValues := &models.MerchantCheckoutValues{}
@@ -210,7 +224,14 @@ func (s *CheckoutService) Confirm(req *protocol.ConfirmCheckoutRequest) (trx *pr
 		SetTransactions(checkout.GetTransactions())
 
 	// 使用数据库事务确保数据一致性
+	linkCode := protocol.Success
 	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		// 来自支付链接的会话需校验链接状态与使用次数
+		if checkout.LinkID != "" {
+			if linkCode = GetPaymentLinkService().ReserveUse(tx, checkout.LinkID); linkCode != protocol.Success {
+				return errPaymentLinkUnusable
+			}
+		}
 		// 先检查代收表中是否已存在该交易
 		if trx := models.GetTransactionByMidAndTrxID(req.Mid, transaction.TrxID, transaction.TrxType); trx == nil {
 			// 保存代收记录到数据库
@@ -221,6 +242,10 @@ func (s *CheckoutService) Confirm(req *protocol.ConfirmCheckoutRequest) (trx *pr
 		return models.SaveMerchantCheckout(tx, checkout, checkoutValues)
 	})
 
+	if linkCode != protocol.Success {
+		code = linkCode
+		return
+	}
 	if err != nil {
 		log.Get().Errorf("Confirm checkout failed: %v", err)
 		code = protocol.SystemError
@@ -319,6 +344,25 @@ func (s *CheckoutService) Configs(checkoutID string) (result *protocol.MerchantC
 	// 6. 将国家配置添加到结果中
 	result.Configs = countryMap
 
+	// 7. 附带收银台地址及UPI意图二维码，渲染失败不影响配置返回
+	if checkoutURL := checkout.GetCheckoutURL(); checkoutURL != "" {
+		result.QRCode, _ = GetQRCodeService().Render(checkoutURL, "", 0)
+	}
+	if checkout.GetCcy() == protocol.CcyINR {
+		for _, countryConfig := range countryMap {
+			methodConfig, ok := countryConfig.Configs[protocol.TrxMethodUPI]
+			if !ok {
+				continue
+			}
+			methodConfig.UpiIntent, _ = GetQRCodeService().UpiIntent(&protocol.UpiIntentRequest{
+				Mid:    checkout.Mid,
+				Amount: checkout.GetAmount().String(),
+				Ref:    checkout.CheckoutID,
+				QRCode: true,
+			})
+		}
+	}
+
 	return
 }
 
@@ -367,7 +411,14 @@ func (s *CheckoutService) Cancel(checkoutID string) (trx *protocol.Checkout, cod
 	values.SetStatus(protocol.StatusCancelled).
 		SetCanceledAt(utils.TimeNowMilli())
 
-	if _err := models.SaveMerchantCheckout(models.WriteDB, checkout, values); _err != nil {
+	_err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := models.SaveMerchantCheckout(tx, checkout, values); err != nil {
+			return err
+		}
+		return CreateWebhooks(tx, NewCheckoutWebhook(checkout))
+	})
+	if _err != nil {
+		log.Get().Errorf("Cancel checkout %s failed: %v", checkoutID, _err)
 		return nil, protocol.SystemError
 	}
 
@@ -36,6 +36,7 @@ type DashboardAccountBalance struct {
 	Balance      string `json:"balance"`       // 余额
 	FrozenAmt    string `json:"frozen_amt"`    // 冻结金额
 	AvailableAmt string `json:"available_amt"` // 可用金额
+	ReserveAmt   string `json:"reserve_amt"`   // 结算准备金扣留金额
 }
 
 // DashboardOverview Dashboard概览数据
@@ -50,7 +51,7 @@ func GetTodayStats(mid string, currency string) (*DashboardTodayStats, error) {
 		return nil, errors.New("invalid merchant ID")
 	}
 
-	fmt.Printf("GetTodayStats called with merchantID: %d\n", mid)
+	fmt.Printf("GetTodayStats called with merchantID: %s\n", mid)
 
 	db := models.GetDB()
 
@@ -115,8 +116,8 @@ func GetTodayStats(mid string, currency string) (*DashboardTodayStats, error) {
 	var recentPayins []models.MerchantPayin
 	db.Where("mid = ?", merchant.Mid).Order("created_at DESC").Limit(3).Find(&recentPayins)
 	for i, payin := range recentPayins {
-		fmt.Printf("Recent payin %d: ID=%d, Amount=%.2f, Status=%s, CreatedAt=%d\n",
-			i+1, payin.ID, payin.Amount, payin.Status, payin.CreatedAt)
+		fmt.Printf("Recent payin %d: ID=%d, Amount=%s, Status=%s, CreatedAt=%d\n",
+			i+1, payin.ID, payin.Amount, payin.GetStatus(), payin.CreatedAt)
 	}
 
 	// 昨日代收 - 临时设为0，专注今日数据
@@ -229,12 +230,14 @@ func GetAccountBalance(merchantID int64) ([]DashboardAccountBalance, error) {
 		balance := account.Asset.Balance.InexactFloat64()
 		frozenAmt := account.Asset.FrozenBalance.InexactFloat64()
 		availableAmt := account.Asset.AvailableBalance.InexactFloat64()
+		reserveAmt := account.Asset.ReserveBalance.InexactFloat64()
 
 		balances = append(balances, DashboardAccountBalance{
 			Currency:     account.Ccy,
 			Balance:      fmt.Sprintf("%.2f", balance),
 			FrozenAmt:    fmt.Sprintf("%.2f", frozenAmt),
 			AvailableAmt: fmt.Sprintf("%.2f", availableAmt),
+			ReserveAmt:   fmt.Sprintf("%.2f", reserveAmt),
 		})
 	}
 
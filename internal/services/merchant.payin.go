@@ -72,6 +72,12 @@ func (s *MerchantPayinService) Create(ctx *gin.Context, req *protocol.MerchantPa
 		MerchantPayinValues: &models.MerchantPayinValues{},
 	}
 	payin.SetVersion(1)
+	// 快照创建时的美元汇率与金额
+	payin.UsdRate, payin.UsdAmount = GetFxRateService().UsdSnapshot(payin.Ccy, payin.Amount, now.UnixMilli())
+	// 按商户费率配置预计算手续费
+	if fee, feeCcy, ok := GetFeeConfigService().CalculateFee(payin.ToTransaction()); ok {
+		payin.SetFeeCcy(feeCcy).SetFeeAmount(fee)
+	}
 	payinCfg := config.Get().MerchantPayin
 	payin.SetStatus(protocol.StatusPending).
 		SetExpiredAt(now.Add(time.Duration(payinCfg.ExpiryMinutes) * time.Minute).UnixMilli()) //过期时间
@@ -124,8 +130,9 @@ func (s *MerchantPayinService) Create(ctx *gin.Context, req *protocol.MerchantPa
 	if er != nil {
 		return
 	}
-	if _err := models.SaveTransactionValues(models.WriteDB, trans, values); _err != nil {
-		log.Get().Errorf("SaveTransactionValues error: %v", _err)
+	GetFxRateService().SnapshotCompletion(trans, values)
+	if _err := SaveTransactionResult(trans, values); _err != nil {
+		log.Get().Errorf("SaveTransactionResult error: %v", _err)
 	}
 	AfterTransactionCreate(trans)
 	info = trans.Protocol()
@@ -1,12 +1,14 @@
 package services
 
 import (
+	"fmt"
 	"inpayos/internal/config"
 	"inpayos/internal/log"
 	"inpayos/internal/middleware"
 	"inpayos/internal/models"
 	"inpayos/internal/protocol"
 	"inpayos/internal/utils"
+	"slices"
 	"sync"
 	"time"
 
@@ -52,6 +54,33 @@ func (s *MerchantPayoutService) Create(ctx *gin.Context, req *protocol.MerchantP
 		code = protocol.InvalidParams
 		return
 	}
+	// 引用收款人时收款账户信息取自收款人，否则按账户指纹复用已保存的收款人
+	var beneficiary *models.MerchantBeneficiary
+	if req.BeneficiaryID != "" {
+		beneficiary, code = GetBeneficiaryService().ResolveForPayout(req.Mid, req.BeneficiaryID, req.Ccy)
+		if code != protocol.Success {
+			return
+		}
+		req.AccountNo, req.AccountName, req.AccountType = beneficiary.AccountNo, beneficiary.AccountName, beneficiary.AccountType
+		req.BankCode, req.BankName = beneficiary.BankCode, beneficiary.BankName
+	} else {
+		// 按银行目录校验并规范化银行编码，未填写银行名称时自动补全
+		if req.BankCode != "" {
+			var bank *protocol.BankInfo
+			if bank, code = GetBankDirectoryService().ResolveByCcy(req.Ccy, req.BankCode); code != protocol.Success {
+				return
+			}
+			req.BankCode = bank.Code
+			if req.BankName == "" {
+				req.BankName = bank.BankName
+			}
+		}
+		beneficiary = GetBeneficiaryService().MatchForPayout(req.Mid, req.Ccy, req.AccountType, req.AccountNo, req.BankCode)
+		if beneficiary != nil && beneficiary.GetVerifyStatus() == protocol.VerifyStatusFailed {
+			code = protocol.BeneficiaryVerifyFailed
+			return
+		}
+	}
 	now := time.Now()
 	// 直接创建Transaction实体
 	payout = &models.MerchantPayout{
@@ -69,9 +98,23 @@ func (s *MerchantPayoutService) Create(ctx *gin.Context, req *protocol.MerchantP
 		ProductID:            req.ProductID,
 		UserIP:               req.UserIP,
 		ReturnURL:            req.ReturnURL,
+		AccountNo:            req.AccountNo,
+		AccountName:          req.AccountName,
+		AccountType:          req.AccountType,
+		BankCode:             req.BankCode,
+		BankName:             req.BankName,
 		MerchantPayoutValues: &models.MerchantPayoutValues{},
 	}
+	if beneficiary != nil {
+		payout.BeneficiaryID = beneficiary.BeneficiaryID
+	}
 	payout.SetVersion(1)
+	// 快照创建时的美元汇率与金额
+	payout.UsdRate, payout.UsdAmount = GetFxRateService().UsdSnapshot(payout.Ccy, payout.Amount, now.UnixMilli())
+	// 按商户费率配置预计算手续费
+	if fee, feeCcy, ok := GetFeeConfigService().CalculateFee(payout.ToTransaction()); ok {
+		payout.SetFeeCcy(feeCcy).SetFeeAmount(fee)
+	}
 	payoutCfg := config.Get().MerchantPayout
 	payout.SetStatus(protocol.StatusPending).
 		SetExpiredAt(now.Add(time.Duration(payoutCfg.ExpiryMinutes) * time.Minute).UnixMilli()) //过期时间
@@ -89,6 +132,15 @@ func (s *MerchantPayoutService) Create(ctx *gin.Context, req *protocol.MerchantP
 	}
 	// 设置渠道信息
 	payout.SetChannelGroup(routerInfo.ChannelGroup)
+	// 命中复核规则的代付先冻结资金，待复核员审批后再出款
+	if flags := GetMerchantApprovalService().EvaluatePayout(payout.ToTransaction()); len(flags) > 0 {
+		// 门户子账号提交时记录子账号ID，OpenAPI提交时记录商户ID，复核员不能复核自己提交的代付
+		requestedBy := middleware.GetOperatorIDFromContext(ctx)
+		if requestedBy == "" {
+			requestedBy = req.Mid
+		}
+		return GetMerchantApprovalService().HoldPayout(payout, flags, requestedBy)
+	}
 	values := models.NewTrxValues()
 	var trans *models.Transaction
 	er := models.WriteDB.Transaction(func(tx *gorm.DB) error {
@@ -96,13 +148,20 @@ func (s *MerchantPayoutService) Create(ctx *gin.Context, req *protocol.MerchantP
 		if err := tx.Create(payout).Error; err != nil {
 			return err
 		}
-		// 执行渠道请求
+		// 提交渠道前冻结代付金额，余额不足时不出款
 		trans = payout.ToTransaction()
+		if code = freezePayout(tx, trans, "payout submitted"); code != protocol.Success {
+			return protocol.NewServiceError(code, "freeze payout amount failed")
+		}
+		// 执行渠道请求
 		result, errCode := RequestByRouter(ctx, tx, trans, routerInfo)
 		if errCode != protocol.Success {
 			code = errCode
 			return protocol.NewServiceError(errCode, "channel request error")
 		}
+		if code = applyPayoutResult(tx, trans, protocol.StatusPending, result.Status, ""); code != protocol.Success {
+			return protocol.NewServiceError(code, "apply payout result failed")
+		}
 		values.SetStatus(result.Status).
 			SetChannelStatus(result.ChannelStatus).
 			SetChannelCode(result.ChannelCode).
@@ -124,10 +183,55 @@ func (s *MerchantPayoutService) Create(ctx *gin.Context, req *protocol.MerchantP
 	if er != nil {
 		return
 	}
-	if _err := models.SaveTransactionValues(models.WriteDB, trans, values); _err != nil {
-		log.Get().Errorf("SaveTransactionValues error: %v", _err)
+	if beneficiary != nil {
+		GetBeneficiaryService().MarkUsed(beneficiary)
+	}
+	GetFxRateService().SnapshotCompletion(trans, values)
+	if _err := SaveTransactionResult(trans, values); _err != nil {
+		log.Get().Errorf("SaveTransactionResult error: %v", _err)
 	}
 	AfterTransactionCreate(trans)
 	info = trans.Protocol()
 	return
 }
+
+// freezePayout 代付出款前冻结代付金额，代付进入终态时由 applyPayoutResult 解冻并扣款
+func freezePayout(tx *gorm.DB, trx *models.Transaction, description string) protocol.ErrorCode {
+	return GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+		UserID:      trx.Mid,
+		UserType:    protocol.UserTypeMerchant,
+		Ccy:         trx.Ccy,
+		Amount:      trx.GetAmount(),
+		TrxID:       trx.TrxID,
+		TrxType:     protocol.TrxTypeFreeze,
+		Description: description,
+	})
+}
+
+// applyPayoutResult 代付进入终态时的资金处理，渠道返回结果与人工修改状态共用：
+// 出款中的代付先解冻，成功时再从商户余额扣除代付金额转入渠道清算；已失败的代付资金已解冻，改为成功时直接扣款
+func applyPayoutResult(tx *gorm.DB, trx *models.Transaction, fromStatus, status, operatorID string) protocol.ErrorCode {
+	if trx.TrxType != protocol.TrxTypePayout || !slices.Contains(protocol.TrxFinalStatusList, status) {
+		return protocol.Success
+	}
+	balanceReq := &protocol.UpdateBalanceRequest{
+		UserID:      trx.Mid,
+		UserType:    protocol.UserTypeMerchant,
+		Ccy:         trx.Ccy,
+		Amount:      trx.GetAmount(),
+		TrxID:       trx.TrxID,
+		TrxType:     protocol.TrxTypeUnfreeze,
+		OperatorID:  operatorID,
+		Description: fmt.Sprintf("payout %s", status),
+	}
+	if !slices.Contains(protocol.TrxFinalStatusList, fromStatus) {
+		if code := GetAccountService().UpdateBalanceWithTx(tx, balanceReq); code != protocol.Success {
+			return code
+		}
+	}
+	if status != protocol.StatusSuccess {
+		return protocol.Success
+	}
+	balanceReq.TrxType = protocol.TrxTypePayout
+	return GetAccountService().UpdateBalanceWithTx(tx, balanceReq)
+}
@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// MerchantReserveService 商户结算准备金服务：结算入账时按商户规则扣留一部分金额转入准备金，
+// 每笔扣留单独记录并在持有天数后由定时任务释放回可用余额
+type MerchantReserveService struct{}
+
+var (
+	merchantReserveService     *MerchantReserveService
+	merchantReserveServiceOnce sync.Once
+)
+
+const reserveReleaseBatchSize = 100
+
+func init() {
+	task.RegisterHandler(protocol.MerchantReserveRelease, HandleMerchantReserveRelease)
+}
+
+func SetupMerchantReserveService() {
+	merchantReserveServiceOnce.Do(func() {
+		merchantReserveService = &MerchantReserveService{}
+	})
+}
+
+// GetMerchantReserveService 获取商户结算准备金服务单例
+func GetMerchantReserveService() *MerchantReserveService {
+	if merchantReserveService == nil {
+		SetupMerchantReserveService()
+	}
+	return merchantReserveService
+}
+
+// SaveRule 保存商户准备金规则，比例取值0到1，最低扣留金额不能为负
+func (s *MerchantReserveService) SaveRule(operatorID string, req *protocol.SaveReserveRuleRequest) (*protocol.MerchantReserveRule, protocol.ErrorCode) {
+	ccy := strings.ToUpper(req.Ccy)
+	if !protocol.IsValidCurrency(ccy) {
+		return nil, protocol.InvalidCurrency
+	}
+	if models.GetMerchantByMID(req.Mid) == nil {
+		return nil, protocol.MerchantNotFound
+	}
+	percentage, err := decimal.NewFromString(req.Percentage)
+	if err != nil || percentage.IsNegative() || percentage.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, protocol.ReserveRuleInvalid
+	}
+	floor := decimal.Zero
+	if req.FixedFloor != "" {
+		if floor, err = decimal.NewFromString(req.FixedFloor); err != nil || floor.IsNegative() {
+			return nil, protocol.ReserveRuleInvalid
+		}
+	}
+	if percentage.IsZero() && floor.IsZero() {
+		return nil, protocol.ReserveRuleInvalid
+	}
+	rule := &models.MerchantReserveRule{
+		Mid:        req.Mid,
+		Ccy:        ccy,
+		Percentage: percentage,
+		FixedFloor: floor,
+		HoldDays:   req.HoldDays,
+		Status:     req.Status,
+		UpdatedBy:  operatorID,
+	}
+	if err := models.SaveMerchantReserveRule(models.WriteDB, rule); err != nil {
+		log.Get().Errorf("Save merchant reserve rule failed: mid=%s, ccy=%s, err=%v", req.Mid, ccy, err)
+		return nil, protocol.DatabaseError
+	}
+	return rule.Protocol(), protocol.Success
+}
+
+// ListRules 分页查询准备金规则
+func (s *MerchantReserveService) ListRules(req *protocol.ReserveRuleListRequest) ([]*protocol.MerchantReserveRule, int64, protocol.ErrorCode) {
+	rules, total, err := models.ListMerchantReserveRuleByQuery(&models.MerchantReserveRuleQuery{
+		Mid:    req.Mid,
+		Ccy:    strings.ToUpper(req.Ccy),
+		Status: req.Status,
+		Page:   req.Page,
+		Size:   req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.MerchantReserveRule, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, rule.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// hold 结算入账后按规则扣留准备金：扣留金额取结算金额乘以比例与最低扣留金额中的较大者，不超过结算金额
+func (s *MerchantReserveService) hold(tx *gorm.DB, settleLog *models.MerchantSettle, settleAmount decimal.Decimal) protocol.ErrorCode {
+	rule := models.GetActiveMerchantReserveRule(settleLog.Mid, settleLog.SettleCcy)
+	if rule == nil || rule.HoldDays <= 0 {
+		return protocol.Success
+	}
+	decimals := int32(2)
+	if info, ok := protocol.GetCurrencyInfo(settleLog.SettleCcy); ok {
+		decimals = int32(info.Decimals)
+	}
+	amount := decimal.Min(decimal.Max(settleAmount.Mul(rule.Percentage), rule.FixedFloor), settleAmount).Truncate(decimals)
+	if !amount.IsPositive() {
+		return protocol.Success
+	}
+
+	now := utils.TimeNowMilli()
+	reserve := &models.MerchantReserve{
+		ReserveID: utils.GenerateReserveID(),
+		Mid:       settleLog.Mid,
+		SettleID:  settleLog.SettleID,
+		Ccy:       settleLog.SettleCcy,
+		Amount:    amount,
+		HoldDays:  rule.HoldDays,
+		ReleaseAt: now + int64(rule.HoldDays)*86400000,
+		MerchantReserveValues: (&models.MerchantReserveValues{}).
+			SetStatus(protocol.ReserveStatusHeld),
+	}
+	if err := tx.Create(reserve).Error; err != nil {
+		log.Get().Errorf("Create merchant reserve failed: settle_id=%s, err=%v", settleLog.SettleID, err)
+		return protocol.DatabaseError
+	}
+	oriFlowNo := ""
+	if flow := models.GetUserFundFlowByTrx(tx, settleLog.SettleID, protocol.TrxTypeDeposit, settleLog.Mid, protocol.UserTypeMerchant); flow != nil {
+		oriFlowNo = flow.FlowNo
+	}
+	return GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+		UserID:      settleLog.Mid,
+		UserType:    protocol.UserTypeMerchant,
+		Ccy:         settleLog.SettleCcy,
+		Amount:      amount,
+		TrxID:       reserve.ReserveID,
+		TrxType:     protocol.TrxTypeRsvHold,
+		OriFlowNo:   oriFlowNo,
+		OperatorID:  protocol.System,
+		Description: fmt.Sprintf("rolling reserve for settlement %s", settleLog.SettleID),
+	})
+}
+
+// release 释放到期的准备金，状态变更与余额入账在同一事务内完成
+func (s *MerchantReserveService) release(reserve *models.MerchantReserve) protocol.ErrorCode {
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		values := &models.MerchantReserveValues{}
+		values.SetStatus(protocol.ReserveStatusReleased).
+			SetReleasedAt(utils.TimeNowMilli())
+		ok, err := models.UpdateMerchantReserveValues(tx, reserve, protocol.ReserveStatusHeld, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      reserve.Mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         reserve.Ccy,
+			Amount:      reserve.Amount,
+			TrxID:       reserve.ReserveID,
+			TrxType:     protocol.TrxTypeRsvRelease,
+			OperatorID:  protocol.System,
+			Description: fmt.Sprintf("rolling reserve released for settlement %s", reserve.SettleID),
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "post reserve release failed")
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Release merchant reserve: reserve_id=%s, err=%v", reserve.ReserveID, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+	}
+	return code
+}
+
+// List 分页查询准备金记录，商户端仅能查询自己的记录
+func (s *MerchantReserveService) List(mid string, req *protocol.ReserveListRequest) ([]*protocol.MerchantReserve, int64, protocol.ErrorCode) {
+	if mid == "" {
+		mid = req.Mid
+	}
+	reserves, total, err := models.ListMerchantReserveByQuery(&models.MerchantReserveQuery{
+		Mid:            mid,
+		SettleID:       req.SettleID,
+		Status:         req.Status,
+		Ccy:            strings.ToUpper(req.Ccy),
+		ReleaseAtStart: req.ReleaseAtStart,
+		ReleaseAtEnd:   req.ReleaseAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.MerchantReserve, 0, len(reserves))
+	for _, reserve := range reserves {
+		list = append(list, reserve.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 获取准备金记录详情
+func (s *MerchantReserveService) Get(mid, reserveID string) (*protocol.MerchantReserve, protocol.ErrorCode) {
+	reserve := models.GetMerchantReserve(mid, reserveID)
+	if reserve == nil {
+		return nil, protocol.ReserveNotFound
+	}
+	return reserve.Protocol(), protocol.Success
+}
+
+// Schedule 商户各币种扣留中的准备金及按到期日汇总的释放计划
+func (s *MerchantReserveService) Schedule(mid string) ([]*protocol.ReserveSchedule, protocol.ErrorCode) {
+	reserves, err := models.ListHeldMerchantReserves(mid)
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	schedules := make([]*protocol.ReserveSchedule, 0)
+	byCcy := make(map[string]*protocol.ReserveSchedule)
+	held := make(map[string]decimal.Decimal)
+	items := make(map[string]*protocol.ReserveScheduleItem)
+	released := make(map[*protocol.ReserveScheduleItem]decimal.Decimal)
+	for _, reserve := range reserves {
+		schedule, ok := byCcy[reserve.Ccy]
+		if !ok {
+			schedule = &protocol.ReserveSchedule{Ccy: reserve.Ccy, Releases: []*protocol.ReserveScheduleItem{}}
+			byCcy[reserve.Ccy] = schedule
+			schedules = append(schedules, schedule)
+		}
+		held[reserve.Ccy] = held[reserve.Ccy].Add(reserve.Amount)
+		date := time.UnixMilli(reserve.ReleaseAt).Format("2006-01-02")
+		item, ok := items[reserve.Ccy+"|"+date]
+		if !ok {
+			item = &protocol.ReserveScheduleItem{Date: date}
+			items[reserve.Ccy+"|"+date] = item
+			schedule.Releases = append(schedule.Releases, item)
+		}
+		released[item] = released[item].Add(reserve.Amount)
+		item.Count++
+	}
+	for _, schedule := range schedules {
+		schedule.HeldAmount = held[schedule.Ccy].String()
+		for _, item := range schedule.Releases {
+			item.Amount = released[item].String()
+		}
+		sort.Slice(schedule.Releases, func(i, j int) bool {
+			return schedule.Releases[i].Date < schedule.Releases[j].Date
+		})
+	}
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Ccy < schedules[j].Ccy
+	})
+	return schedules, protocol.Success
+}
+
+// RegisterReserveTasks 注册结算准备金释放任务
+func RegisterReserveTasks() {
+	log.Get().Info("注册结算准备金任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "merchant_reserve_release",
+			Type:       protocol.MerchantReserveRelease,
+			HandlerKey: protocol.MerchantReserveRelease,
+			Name:       "到期结算准备金释放",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 10m"}[0], // 每10分钟执行一次
+				Timeout: &[]int{600}[0],            // 10分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("结算准备金任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleMerchantReserveRelease 释放已到期的结算准备金
+func HandleMerchantReserveRelease(ctx context.Context, params protocol.MapData) error {
+	service := GetMerchantReserveService()
+	now := utils.TimeNowMilli()
+	var lastID uint64
+	for {
+		reserves, err := models.ListMaturedMerchantReserves(now, lastID, reserveReleaseBatchSize)
+		if err != nil {
+			return fmt.Errorf("查询到期准备金失败: %v", err)
+		}
+		for _, reserve := range reserves {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			service.release(reserve)
+		}
+		if len(reserves) < reserveReleaseBatchSize {
+			return nil
+		}
+		lastID = reserves[len(reserves)-1].ID
+	}
+}
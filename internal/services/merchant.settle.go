@@ -269,6 +269,7 @@ func (s *MerchantSettleService) SettleTransaction(ctx *protocol.MerchantSettleCo
 		SettleStrategy:  matchedStrategy,
 		SettleRule:      matchedRule,
 	}
+	s.ReconcileFee(trx, settlementResult, settleTransaction.MerchantSettleTransactionValues)
 	settleTransaction.SetStatus(protocol.StatusSuccess).
 		SetSettledAt(utils.TimeNowMilli())
 
@@ -285,7 +286,10 @@ func (s *MerchantSettleService) SettleTransaction(ctx *protocol.MerchantSettleCo
 				return err
 			}
 		}
-		return nil
+		if err := CreateWebhooks(tx, NewSettleWebhook(trx, settleTransaction)); err != nil {
+			return err
+		}
+		return CreateCashierSettledWebhook(tx, trx, settleTransaction.GetSettleLogID())
 	})
 	if newSettle || updateSettle {
 		// 更新交易状态
@@ -335,7 +339,7 @@ func (s *MerchantSettleService) GetSettleStrategies(ctx *protocol.MerchantSettle
 		strategies = s.GetSettleStrategiesByCodes(strategyCodes)
 	} else {
 		// 回退：查询在周期时间有效的合同
-		log.Get().Debugf("GetSettleStrategiesWithPeriodCache: no preset strategies, falling back to contract query for merchant %d at time %d", settleLog.Mid, settleLog.TrxStartAt)
+		log.Get().Debugf("GetSettleStrategiesWithPeriodCache: no preset strategies, falling back to contract query for merchant %s at time %d", settleLog.Mid, settleLog.TrxStartAt)
 		contract := models.GetValidContractsAtTime(settleLog.Mid, settleLog.TrxStartAt)
 		if contract != nil {
 			strategies = s.GenerateStrategiesFromContract(contract)
@@ -399,17 +403,44 @@ func (s *MerchantSettleService) IsRuleApplicable(trx *models.Transaction, rule *
 		return false
 	}
 
-	// 检查金额范围
-	if rule.MinAmount != nil && trx.Amount != nil && trx.Amount.LessThan(*rule.MinAmount) {
-		return false
-	}
-	if rule.MaxAmount != nil && trx.Amount != nil && trx.Amount.GreaterThan(*rule.MaxAmount) {
-		return false
+	// 检查金额范围，按实收金额判断
+	if trx.Amount != nil {
+		amount := trx.GetSettleAmount()
+		if rule.MinAmount != nil && amount.LessThan(*rule.MinAmount) {
+			return false
+		}
+		if rule.MaxAmount != nil && amount.GreaterThan(*rule.MaxAmount) {
+			return false
+		}
 	}
 
 	return true
 }
 
+// ReconcileFee 核对交易创建时预计算的手续费与结算规则计算的手续费，不一致时标记并告警
+func (s *MerchantSettleService) ReconcileFee(trx *models.Transaction, result *protocol.SettlementResult, values *models.MerchantSettleTransactionValues) {
+	if trx.FeeAmount == nil {
+		return
+	}
+	preFee := trx.GetFeeAmount()
+	diff := result.Fee.Add(result.FixedFee).Sub(preFee)
+	if ccy := trx.GetFeeCcy(); ccy != "" && ccy != result.FeeCcy {
+		// 币种不同无法直接比较，按不一致处理
+		values.SetPreFee(preFee).SetFeeMismatch(true)
+		log.Get().Warnf("ReconcileFee: fee ccy mismatch for transaction %s, pre=%s %s, settle=%s", trx.TrxID, preFee, ccy, result.FeeCcy)
+		return
+	}
+	places := int32(2)
+	if info, ok := protocol.GetCurrencyInfo(result.FeeCcy); ok {
+		places = int32(info.Decimals)
+	}
+	mismatch := !diff.Round(places).IsZero()
+	values.SetPreFee(preFee).SetFeeDiff(diff).SetFeeMismatch(mismatch)
+	if mismatch {
+		log.Get().Warnf("ReconcileFee: fee mismatch for transaction %s, pre=%s, settle=%s, diff=%s", trx.TrxID, preFee, result.Fee.Add(result.FixedFee), diff)
+	}
+}
+
 // CalculateSettlement 计算结算金额和费用
 func (s *MerchantSettleService) CalculateSettlement(trx *models.Transaction, rule *protocol.SettleRule) *protocol.SettlementResult {
 	if trx == nil || rule == nil {
@@ -420,14 +451,9 @@ func (s *MerchantSettleService) CalculateSettlement(trx *models.Transaction, rul
 		FeeCcy: trx.Ccy,
 	}
 
-	// 获取交易金额
-	var trxAmount, trxUsdAmount decimal.Decimal
-	if trx.Amount != nil {
-		trxAmount = *trx.Amount
-	}
-	if trx.UsdAmount != nil {
-		trxUsdAmount = *trx.UsdAmount
-	}
+	// 获取交易金额，记录了实收金额时按实收金额结算
+	trxAmount := trx.GetSettleAmount()
+	trxUsdAmount := trx.GetSettleUsdAmount()
 
 	// 计算费率
 	if rule.Rate != nil {
@@ -566,6 +592,30 @@ func (s *MerchantSettleService) GetOrCreateTransactionSettleLog(trx *models.Tran
 	return settleLog, nil
 }
 
+// newSettleBalanceRequest 生成结算记账请求：结算金额为正时按充值入账，与结算同币种的手续费计入手续费收入；
+// 手续费超过交易金额导致结算金额为负时，按代付从商户余额扣除差额转入渠道清算
+func newSettleBalanceRequest(settleLog *models.MerchantSettle) *protocol.UpdateBalanceRequest {
+	settleAmount := settleLog.GetSettleAmount()
+	req := &protocol.UpdateBalanceRequest{
+		UserID:      cast.ToString(settleLog.Mid),
+		UserType:    protocol.UserTypeMerchant,
+		Ccy:         settleLog.SettleCcy,
+		TrxType:     protocol.TrxTypeDeposit, // 结算入账使用充值类型
+		Amount:      settleAmount,
+		TrxID:       settleLog.SettleID, // 使用结算ID作为交易ID
+		Description: fmt.Sprintf("结算周期记账，周期: %d, 类型: %s", settleLog.Period, settleLog.PeriodType),
+	}
+	if settleAmount.IsNegative() {
+		req.TrxType = protocol.TrxTypePayout
+		req.Amount = settleAmount.Abs()
+		return req
+	}
+	if feeCcy := settleLog.GetFeeCcy(); feeCcy == "" || feeCcy == settleLog.SettleCcy {
+		req.Fee = settleLog.GetFee().Add(settleLog.GetFixedFee())
+	}
+	return req
+}
+
 // ProcessSettleLogAccounting 处理单个结算记录的记账操作
 func (s *MerchantSettleService) ProcessSettleLogAccounting(settleLog *models.MerchantSettle) error {
 	if settleLog == nil {
@@ -581,32 +631,33 @@ func (s *MerchantSettleService) ProcessSettleLogAccounting(settleLog *models.Mer
 
 	// 调用账户服务更新余额
 	accountService := GetAccountService()
-	balanceReq := &protocol.UpdateBalanceRequest{
-		UserID:      cast.ToString(settleLog.Mid),
-		UserType:    protocol.UserTypeMerchant,
-		Ccy:         settleLog.SettleCcy,
-		TrxType:     protocol.TrxTypeDeposit, // 结算入账使用充值类型
-		Amount:      settleAmount,
-		TrxID:       settleLog.SettleID, // 使用结算ID作为交易ID
-		Description: fmt.Sprintf("结算周期记账，周期: %d, 类型: %s", settleLog.Period, settleLog.PeriodType),
-	}
-
-	errCode := accountService.UpdateBalance(balanceReq)
-	if errCode != protocol.Success {
-		return fmt.Errorf("failed to update merchant balance for settle_id %s: %s", settleLog.SettleID, errCode)
-	}
-
-	// 更新结算记录的完成时间，标记为已记账
-	err := models.WriteDB.Model(settleLog).Updates(map[string]interface{}{
-		"completed_at": time.Now().UnixMilli(),
-		"updated_at":   time.Now().UnixMilli(),
-	}).Error
+	balanceReq := newSettleBalanceRequest(settleLog)
 
+	// 结算入账、准备金扣留及结算记录完成时间在同一事务内更新
+	errCode := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if errCode = accountService.UpdateBalanceWithTx(tx, balanceReq); errCode != protocol.Success {
+			return protocol.NewServiceError(errCode, "update merchant balance failed")
+		}
+		if settleAmount.IsPositive() {
+			if errCode = GetMerchantReserveService().hold(tx, settleLog, settleAmount); errCode != protocol.Success {
+				return protocol.NewServiceError(errCode, "hold merchant reserve failed")
+			}
+		}
+		// 更新结算记录的完成时间，标记为已记账
+		return tx.Model(settleLog).Updates(map[string]interface{}{
+			"completed_at": time.Now().UnixMilli(),
+			"updated_at":   time.Now().UnixMilli(),
+		}).Error
+	})
 	if err != nil {
+		if errCode != protocol.Success {
+			return fmt.Errorf("failed to post settle accounting for settle_id %s: %s", settleLog.SettleID, errCode)
+		}
 		return fmt.Errorf("failed to update settle log completed_at for settle_id %s: %v", settleLog.SettleID, err)
 	}
 
-	log.Get().Infof("ProcessSettleLogAccounting: successfully processed accounting for settle_id %s, merchant %d, amount %s %s",
+	log.Get().Infof("ProcessSettleLogAccounting: successfully processed accounting for settle_id %s, merchant %s, amount %s %s",
 		settleLog.SettleID, settleLog.Mid, settleAmount.String(), settleLog.SettleCcy)
 
 	return nil
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+
+	"github.com/shopspring/decimal"
+)
+
+func newTestSettleLog(amount, fee string) *models.MerchantSettle {
+	settleLog := &models.MerchantSettle{
+		SettleID:                "S0001",
+		Mid:                     "M0001",
+		SettleCcy:               "INR",
+		MerchantSettleLogValues: &models.MerchantSettleLogValues{},
+	}
+	settleLog.SetSettleAmount(decimal.RequireFromString(amount)).
+		SetFeeCcy("INR").
+		SetFee(decimal.RequireFromString(fee)).
+		SetFixedFee(decimal.Zero)
+	return settleLog
+}
+
+// checkSettlePosting 校验结算记账生成的凭证分录金额均为正且借贷平衡，否则过账时会被拒绝
+func checkSettlePosting(t *testing.T, req *protocol.UpdateBalanceRequest) {
+	t.Helper()
+	posting, code := (&AccountService{}).newLedgerPosting(req)
+	if code != protocol.Success {
+		t.Fatalf("newLedgerPosting: %s", code)
+	}
+	debit, credit := decimal.Zero, decimal.Zero
+	for _, leg := range posting.Legs {
+		if !leg.Amount.IsPositive() {
+			t.Fatalf("leg %s %s %s has non-positive amount %s", leg.Side, leg.UserID, leg.Bucket, leg.Amount)
+		}
+		if leg.Side == protocol.LedgerSideDebit {
+			debit = debit.Add(leg.Amount)
+		} else {
+			credit = credit.Add(leg.Amount)
+		}
+	}
+	if !debit.Equal(credit) {
+		t.Fatalf("posting unbalanced: debit %s, credit %s", debit, credit)
+	}
+}
+
+func TestSettleBalanceRequestPositive(t *testing.T) {
+	req := newSettleBalanceRequest(newTestSettleLog("97", "3"))
+	if req.TrxType != protocol.TrxTypeDeposit {
+		t.Fatalf("trx type = %s, want %s", req.TrxType, protocol.TrxTypeDeposit)
+	}
+	if !req.Amount.Equal(decimal.NewFromInt(97)) || !req.Fee.Equal(decimal.NewFromInt(3)) {
+		t.Fatalf("amount = %s, fee = %s, want 97 and 3", req.Amount, req.Fee)
+	}
+	checkSettlePosting(t, req)
+}
+
+func TestSettleBalanceRequestNegative(t *testing.T) {
+	req := newSettleBalanceRequest(newTestSettleLog("-5", "15"))
+	if req.TrxType != protocol.TrxTypePayout {
+		t.Fatalf("trx type = %s, want %s", req.TrxType, protocol.TrxTypePayout)
+	}
+	if !req.Amount.Equal(decimal.NewFromInt(5)) || !req.Fee.IsZero() {
+		t.Fatalf("amount = %s, fee = %s, want 5 and 0", req.Amount, req.Fee)
+	}
+	checkSettlePosting(t, req)
+
+	posting, _ := (&AccountService{}).newLedgerPosting(req)
+	debit := posting.Legs[0]
+	if debit.Side != protocol.LedgerSideDebit || debit.UserID != "M0001" || debit.Bucket != protocol.LedgerBucketBalance {
+		t.Fatalf("first leg = %s %s %s, want debit of merchant balance", debit.Side, debit.UserID, debit.Bucket)
+	}
+	flow := ledgerSettleFlow(newTestSettleLog("-5", "15"))
+	if flow == nil || flow.TrxType != protocol.TrxTypePayout || !flow.Amount.Equal(decimal.NewFromInt(5)) {
+		t.Fatalf("ledger check flow = %+v, want payout of 5", flow)
+	}
+}
@@ -87,6 +87,14 @@ func RequestByRouter(ctx context.Context, tx *gorm.DB, trx *models.Transaction,
 		if err != protocol.Success {
 			continue
 		}
+		// 由收银团队承接的代收，未结算金额需在团队保证金额度内，超出时改由下一个渠道处理
+		if trx.TrxType == protocol.TrxTypePayin && trx.Tid != "" {
+			if err = GetCashierMarginService().CheckPayinCapacity(trx.Tid, trx.Ccy, trx.GetAmount()); err != protocol.Success {
+				log.Get().Warnf("Cashier team %s capacity check failed for %s: %s", trx.Tid, trx.TrxID, err)
+				trx.Tid = ""
+				continue
+			}
+		}
 		if !isAll || result.Status != protocol.StatusFailed {
 			result.ChannelAccountID = trx.GetChannelCode()
 			result.ChannelAccountID = trx.GetChannelAccount()
@@ -147,6 +155,19 @@ func RequestChannelPayout(ctx context.Context, trx *models.Transaction) (result
 	return
 }
 
+// SaveTransactionResult 保存渠道处理结果，在同一事务内通知承接交易的收银团队，交易进入终态时生成商户通知
+func SaveTransactionResult(trx *models.Transaction, values *models.TransactionValues) error {
+	return models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := models.SaveTransactionValues(tx, trx, values); err != nil {
+			return err
+		}
+		if err := CreateCashierAssignedWebhook(tx, trx); err != nil {
+			return err
+		}
+		return CreateTransactionWebhook(tx, trx)
+	})
+}
+
 func RefreshTrxFlag(trx *models.Transaction) {
 
 }
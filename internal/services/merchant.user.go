@@ -0,0 +1,109 @@
+package services
+
+import (
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sync"
+)
+
+// MerchantUserService 商户子账号服务（复核员、操作员）
+type MerchantUserService struct{}
+
+var (
+	merchantUserService     *MerchantUserService
+	merchantUserServiceOnce sync.Once
+)
+
+func SetupMerchantUserService() {
+	merchantUserServiceOnce.Do(func() {
+		merchantUserService = &MerchantUserService{}
+	})
+}
+
+// GetMerchantUserService 获取商户子账号服务单例
+func GetMerchantUserService() *MerchantUserService {
+	if merchantUserService == nil {
+		SetupMerchantUserService()
+	}
+	return merchantUserService
+}
+
+// Create 创建子账号，需商户主账号G2FA确认；子账号的G2FA密钥仅在创建时返回一次
+func (s *MerchantUserService) Create(merchant *models.Merchant, req *protocol.CreateMerchantUserRequest) (*protocol.CreateMerchantUserResponse, protocol.ErrorCode) {
+	if code := verifyMerchantG2FA(merchant, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	if models.GetMerchantUserByEmail(merchant.Mid, req.Email) != nil {
+		return nil, protocol.MerchantUserExists
+	}
+
+	g2faKey := GenerateG2FAKey()
+	if g2faKey == "" {
+		return nil, protocol.SystemError
+	}
+	user := models.NewMerchantUser(merchant.Mid)
+	password, err := utils.HashPassword(req.Password, user.UserID)
+	if err != nil {
+		return nil, protocol.SystemError
+	}
+	user.SetName(req.Name).
+		SetEmail(req.Email).
+		SetPassword(password).
+		SetRole(req.Role).
+		SetG2FA(g2faKey)
+	if err := models.WriteDB.Create(user).Error; err != nil {
+		return nil, protocol.DatabaseError
+	}
+
+	return &protocol.CreateMerchantUserResponse{
+		MerchantUser: user.Protocol(),
+		G2FAKey:      g2faKey,
+		QRCode:       GenerateG2FAQRCode(user.UserID, g2faKey),
+	}, protocol.Success
+}
+
+// List 获取商户全部子账号
+func (s *MerchantUserService) List(mid string) ([]*protocol.MerchantUser, protocol.ErrorCode) {
+	users, err := models.ListMerchantUsers(mid)
+	if err != nil {
+		return nil, protocol.DatabaseError
+	}
+	list := make([]*protocol.MerchantUser, 0, len(users))
+	for _, user := range users {
+		list = append(list, user.Protocol())
+	}
+	return list, protocol.Success
+}
+
+// UpdateStatus 启用或停用子账号
+func (s *MerchantUserService) UpdateStatus(merchant *models.Merchant, req *protocol.UpdateMerchantUserStatusRequest) (*protocol.MerchantUser, protocol.ErrorCode) {
+	if code := verifyMerchantG2FA(merchant, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	user := models.GetMerchantUser(merchant.Mid, req.UserID)
+	if user == nil {
+		return nil, protocol.MerchantUserNotFound
+	}
+	values := &models.MerchantUserValues{}
+	values.SetStatus(req.Status)
+	if err := models.WriteDB.Model(user).UpdateColumns(values).Error; err != nil {
+		return nil, protocol.DatabaseError
+	}
+	user.SetValues(values)
+	return user.Protocol(), protocol.Success
+}
+
+// verifyMerchantG2FA 校验商户主账号G2FA
+func verifyMerchantG2FA(merchant *models.Merchant, code string) protocol.ErrorCode {
+	if merchant == nil {
+		return protocol.MerchantNotFound
+	}
+	if merchant.GetG2FA() == "" {
+		return protocol.TwoFactorRequired
+	}
+	if !VerifyG2FACode(merchant.GetG2FA(), code) {
+		return protocol.InvalidTwoFactorCode
+	}
+	return protocol.Success
+}
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"inpayos/internal/channels"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// MerchantWithdrawService 商户提现服务：申请时冻结提现金额及手续费，管理员审核后通过代付渠道出款或人工转账，
+// 出款成功从冻结资金中扣除，驳回、撤销或失败时解冻
+type MerchantWithdrawService struct{}
+
+var (
+	merchantWithdrawService     *MerchantWithdrawService
+	merchantWithdrawServiceOnce sync.Once
+)
+
+func init() {
+	task.RegisterHandler(protocol.WithdrawSync, HandleWithdrawSync)
+}
+
+func SetupMerchantWithdrawService() {
+	merchantWithdrawServiceOnce.Do(func() {
+		merchantWithdrawService = &MerchantWithdrawService{}
+	})
+}
+
+// GetMerchantWithdrawService 获取提现服务单例
+func GetMerchantWithdrawService() *MerchantWithdrawService {
+	if merchantWithdrawService == nil {
+		SetupMerchantWithdrawService()
+	}
+	return merchantWithdrawService
+}
+
+// QuoteFee 按商户提现费率配置试算手续费
+func (s *MerchantWithdrawService) QuoteFee(mid string, req *protocol.WithdrawFeeRequest) (*protocol.WithdrawFee, protocol.ErrorCode) {
+	amount, code := s.parseAmount(mid, req.Ccy, req.Amount)
+	if code != protocol.Success {
+		return nil, code
+	}
+	fee := s.calculateFee(mid, req.Ccy, "", amount)
+	return &protocol.WithdrawFee{
+		Ccy:          req.Ccy,
+		Amount:       amount.String(),
+		Fee:          fee.String(),
+		FrozenAmount: amount.Add(fee).String(),
+	}, protocol.Success
+}
+
+// Create 商户申请提现：校验G2FA及已验证的收款人，冻结提现金额及手续费后进入待审核
+func (s *MerchantWithdrawService) Create(merchant *models.Merchant, req *protocol.CreateWithdrawRequest) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	if code := verifyMerchantG2FA(merchant, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	beneficiary, code := GetBeneficiaryService().ResolveForPayout(merchant.Mid, req.BeneficiaryID, req.Ccy)
+	if code != protocol.Success {
+		return nil, code
+	}
+	if beneficiary.GetVerifyStatus() != protocol.VerifyStatusVerified {
+		return nil, protocol.WithdrawBeneficiaryUnverified
+	}
+	amount, code := s.parseAmount(merchant.Mid, req.Ccy, req.Amount)
+	if code != protocol.Success {
+		return nil, code
+	}
+	account, err := models.GetAccountByUserIDAndCurrency(merchant.Mid, protocol.UserTypeMerchant, req.Ccy)
+	if err != nil {
+		return nil, protocol.AccountErrorAccountNotFound
+	}
+
+	withdraw := &models.MerchantWithdraw{
+		TrxID:                  utils.GenerateWithdrawID(),
+		Mid:                    merchant.Mid,
+		AccountID:              account.AccountID,
+		BeneficiaryID:          beneficiary.BeneficiaryID,
+		AccountType:            beneficiary.AccountType,
+		AccountNo:              beneficiary.AccountNo,
+		AccountName:            beneficiary.AccountName,
+		BankCode:               beneficiary.BankCode,
+		BankName:               beneficiary.BankName,
+		MerchantWithdrawValues: &models.MerchantWithdrawValues{},
+	}
+	withdraw.SetStatus(protocol.StatusPending).
+		SetCcy(req.Ccy).
+		SetAmount(amount).
+		SetFee(s.calculateFee(merchant.Mid, req.Ccy, beneficiary.Country, amount)).
+		SetCountry(beneficiary.Country).
+		SetNotifyURL(merchant.GetNotifyURL()).
+		SetRemark(req.Remark).
+		SetRequestedBy(merchant.Mid)
+
+	code = protocol.Success
+	err = models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(withdraw).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		code = GetAccountService().UpdateBalanceWithTx(tx, &protocol.UpdateBalanceRequest{
+			UserID:      withdraw.Mid,
+			UserType:    protocol.UserTypeMerchant,
+			Ccy:         withdraw.GetCcy(),
+			Amount:      withdraw.GetFrozenAmount(),
+			TrxID:       withdraw.TrxID,
+			TrxType:     protocol.TrxTypeFreeze,
+			OperatorID:  merchant.Mid,
+			Description: "withdraw requested",
+		})
+		if code != protocol.Success {
+			return protocol.NewServiceError(code, "freeze withdraw amount failed")
+		}
+		if err := CreateWebhooks(tx, NewWithdrawWebhook(withdraw)); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Create withdraw: mid=%s, err=%v", merchant.Mid, err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+		return nil, code
+	}
+	GetBeneficiaryService().MarkUsed(beneficiary)
+	return withdraw.Protocol(), protocol.Success
+}
+
+// List 提现列表，mid为空时查询全部商户
+func (s *MerchantWithdrawService) List(mid string, req *protocol.WithdrawListRequest) ([]*protocol.MerchantWithdraw, int64, protocol.ErrorCode) {
+	if mid == "" {
+		mid = req.Mid
+	}
+	withdraws, total, err := models.ListMerchantWithdrawByQuery(&models.MerchantWithdrawQuery{
+		Mid:            mid,
+		TrxID:          req.TrxID,
+		Status:         req.Status,
+		Ccy:            req.Ccy,
+		ExecMethod:     req.ExecMethod,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.MerchantWithdraw, 0, len(withdraws))
+	for _, withdraw := range withdraws {
+		list = append(list, withdraw.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// Get 提现详情，mid为空时不限制商户
+func (s *MerchantWithdrawService) Get(mid, trxID string) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	withdraw := models.GetMerchantWithdraw(mid, trxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// Cancel 商户撤销待审核的提现并解冻资金
+func (s *MerchantWithdrawService) Cancel(mid, trxID string) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	withdraw := models.GetMerchantWithdraw(mid, trxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	values := &models.MerchantWithdrawValues{}
+	values.SetStatus(protocol.StatusCancelled).
+		SetCanceledAt(utils.TimeNowMilli())
+	if code := s.transit(withdraw, protocol.StatusPending, values, protocol.TrxTypeUnfreeze, mid, "withdraw cancelled"); code != protocol.Success {
+		return nil, code
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// Approve 管理员审核通过：渠道方式立即请求代付渠道出款，人工方式等待登记转账结果
+func (s *MerchantWithdrawService) Approve(ctx context.Context, admin *models.Admin, req *protocol.ApproveWithdrawRequest) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	if code := GetAdminAdjustmentService().verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	withdraw := models.GetMerchantWithdraw("", req.TrxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	if withdraw.GetStatus() != protocol.StatusPending {
+		return nil, protocol.WithdrawStatusInvalid
+	}
+	var (
+		trx        *models.Transaction
+		routerInfo *protocol.RouterInfo
+	)
+	if req.Method == protocol.WithdrawMethodChannel {
+		trx = s.toPayoutTransaction(withdraw)
+		if routerInfo = GetChannelRouterByMerchant(trx); routerInfo == nil {
+			return nil, protocol.ChannelNotFound
+		}
+	}
+
+	values := &models.MerchantWithdrawValues{}
+	values.SetStatus(protocol.StatusProcessing).
+		SetExecMethod(req.Method).
+		SetReviewedBy(admin.UserID).
+		SetConfirmedAt(utils.TimeNowMilli())
+	if code := s.transit(withdraw, protocol.StatusPending, values, "", admin.UserID, ""); code != protocol.Success {
+		return nil, code
+	}
+	if trx == nil {
+		return withdraw.Protocol(), protocol.Success
+	}
+
+	// 先提交处理中状态再请求渠道，避免渠道已受理而本地状态丢失
+	result, errCode := RequestByRouter(ctx, models.WriteDB, trx, routerInfo)
+	if errCode != protocol.Success {
+		result = &protocol.ChannelResult{
+			Status:  protocol.StatusFailed,
+			ResCode: string(errCode),
+			ResMsg:  "channel request error",
+		}
+	}
+	if result.ChannelCode == "" {
+		result.ChannelCode = trx.GetChannelCode()
+	}
+	if result.ChannelAccountID == "" {
+		result.ChannelAccountID = trx.GetChannelAccount()
+	}
+	if code := s.applyChannelResult(withdraw, result, admin.UserID); code != protocol.Success {
+		log.Get().Errorf("Apply withdraw channel result: trx_id=%s, code=%s", withdraw.TrxID, code)
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// Reject 管理员驳回待审核的提现并解冻资金
+func (s *MerchantWithdrawService) Reject(operatorID string, req *protocol.RejectWithdrawRequest) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	withdraw := models.GetMerchantWithdraw("", req.TrxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	now := utils.TimeNowMilli()
+	values := &models.MerchantWithdrawValues{}
+	values.SetStatus(protocol.StatusRejected).
+		SetReason(req.Reason).
+		SetReviewedBy(operatorID).
+		SetConfirmedAt(now).
+		SetCompletedAt(now)
+	if code := s.transit(withdraw, protocol.StatusPending, values, protocol.TrxTypeUnfreeze, operatorID, "withdraw rejected"); code != protocol.Success {
+		return nil, code
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// Complete 登记人工转账完成，记录银行流水号并从冻结资金中扣除
+func (s *MerchantWithdrawService) Complete(admin *models.Admin, req *protocol.CompleteWithdrawRequest) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	if code := GetAdminAdjustmentService().verifyAdminG2FA(admin, req.Code); code != protocol.Success {
+		return nil, code
+	}
+	withdraw := models.GetMerchantWithdraw("", req.TrxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	if withdraw.GetExecMethod() != protocol.WithdrawMethodManual {
+		return nil, protocol.WithdrawStatusInvalid
+	}
+	values := &models.MerchantWithdrawValues{}
+	values.SetStatus(protocol.StatusSuccess).
+		SetTransferRef(req.Reference).
+		SetCompletedAt(utils.TimeNowMilli())
+	if code := s.transit(withdraw, protocol.StatusProcessing, values, protocol.TrxTypeWithdraw, admin.UserID, "withdraw completed"); code != protocol.Success {
+		return nil, code
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// Fail 将处理中的提现置为失败并解冻资金，用于人工转账失败或渠道线下确认失败
+func (s *MerchantWithdrawService) Fail(operatorID string, req *protocol.FailWithdrawRequest) (*protocol.MerchantWithdraw, protocol.ErrorCode) {
+	withdraw := models.GetMerchantWithdraw("", req.TrxID)
+	if withdraw == nil {
+		return nil, protocol.WithdrawNotFound
+	}
+	values := &models.MerchantWithdrawValues{}
+	values.SetStatus(protocol.StatusFailed).
+		SetReason(req.Reason).
+		SetCompletedAt(utils.TimeNowMilli())
+	if code := s.transit(withdraw, protocol.StatusProcessing, values, protocol.TrxTypeUnfreeze, operatorID, "withdraw failed"); code != protocol.Success {
+		return nil, code
+	}
+	return withdraw.Protocol(), protocol.Success
+}
+
+// transit 以当前状态为条件更新提现，并在同一事务中解冻或扣除冻结资金、生成商户通知
+// balanceTrxType为空时不调整余额
+func (s *MerchantWithdrawService) transit(withdraw *models.MerchantWithdraw, fromStatus string, values *models.MerchantWithdrawValues, balanceTrxType, operatorID, description string) protocol.ErrorCode {
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdateMerchantWithdrawValues(tx, withdraw, []string{fromStatus}, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.WithdrawStatusInvalid
+			return protocol.NewServiceError(code, "withdraw status changed")
+		}
+		if balanceTrxType != "" {
+			balanceReq := &protocol.UpdateBalanceRequest{
+				UserID:      withdraw.Mid,
+				UserType:    protocol.UserTypeMerchant,
+				Ccy:         withdraw.GetCcy(),
+				Amount:      withdraw.GetFrozenAmount(),
+				TrxID:       withdraw.TrxID,
+				TrxType:     balanceTrxType,
+				OperatorID:  operatorID,
+				Description: description,
+			}
+			if balanceTrxType == protocol.TrxTypeWithdraw {
+				balanceReq.Amount = withdraw.GetAmount()
+				balanceReq.Fee = withdraw.GetFee()
+			}
+			if code = GetAccountService().UpdateBalanceWithTx(tx, balanceReq); code != protocol.Success {
+				return protocol.NewServiceError(code, "update withdraw balance failed")
+			}
+		}
+		if err := CreateWebhooks(tx, NewWithdrawWebhook(withdraw)); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Update withdraw: trx_id=%s, status=%s, err=%v", withdraw.TrxID, values.GetStatus(), err)
+		if code == protocol.Success {
+			code = protocol.DatabaseError
+		}
+	}
+	return code
+}
+
+// applyChannelResult 按渠道结果完成或失败处理中的提现，渠道仍在处理时仅记录渠道信息
+func (s *MerchantWithdrawService) applyChannelResult(withdraw *models.MerchantWithdraw, result *protocol.ChannelResult, operatorID string) protocol.ErrorCode {
+	values := &models.MerchantWithdrawValues{}
+	if result.ChannelCode != "" {
+		values.SetChannelCode(result.ChannelCode)
+	}
+	if result.ChannelAccountID != "" {
+		values.SetChannelAccount(result.ChannelAccountID)
+	}
+	if result.ChannelTrxID != "" {
+		values.SetChannelTrxID(result.ChannelTrxID)
+	}
+	if result.ResCode != "" {
+		values.SetResCode(result.ResCode)
+	}
+	if result.ResMsg != "" {
+		values.SetResMsg(result.ResMsg)
+	}
+	switch result.Status {
+	case protocol.StatusSuccess:
+		values.SetStatus(protocol.StatusSuccess).
+			SetCompletedAt(utils.TimeNowMilli())
+		return s.transit(withdraw, protocol.StatusProcessing, values, protocol.TrxTypeWithdraw, operatorID, "withdraw completed")
+	case protocol.StatusFailed:
+		values.SetStatus(protocol.StatusFailed).
+			SetReason(result.ResMsg).
+			SetCompletedAt(utils.TimeNowMilli())
+		return s.transit(withdraw, protocol.StatusProcessing, values, protocol.TrxTypeUnfreeze, operatorID, "withdraw failed")
+	}
+	if values.ChannelTrxID == nil && values.ResCode == nil && values.ChannelAccount == nil {
+		return protocol.Success
+	}
+	if _, err := models.UpdateMerchantWithdrawValues(models.WriteDB, withdraw, []string{protocol.StatusProcessing}, values); err != nil {
+		return protocol.DatabaseError
+	}
+	return protocol.Success
+}
+
+// calculateFee 按商户提现费率配置计算手续费，未配置时不收取
+func (s *MerchantWithdrawService) calculateFee(mid, ccy, country string, amount decimal.Decimal) decimal.Decimal {
+	trx := &models.Transaction{
+		Mid:               mid,
+		TrxType:           protocol.TrxTypeWithdraw,
+		Ccy:               ccy,
+		Amount:            &amount,
+		TransactionValues: models.NewTrxValues(),
+	}
+	trx.SetCountry(country)
+	if fee, _, ok := GetFeeConfigService().CalculateFee(trx); ok {
+		return fee
+	}
+	return decimal.Zero
+}
+
+// parseAmount 解析提现金额并按商户提现配置校验上下限
+func (s *MerchantWithdrawService) parseAmount(mid, ccy, value string) (decimal.Decimal, protocol.ErrorCode) {
+	amount, err := decimal.NewFromString(value)
+	if err != nil || !amount.IsPositive() {
+		return decimal.Zero, protocol.InvalidAmount
+	}
+	if err := GetConfigService().ValidateAmount(mid, models.TrxTypeWithdraw, ccy, amount); err != nil {
+		return decimal.Zero, protocol.WithdrawAmountInvalid
+	}
+	return amount, protocol.Success
+}
+
+// toPayoutTransaction 将提现转换为代付交易，用于选择代付渠道及请求渠道出款
+func (s *MerchantWithdrawService) toPayoutTransaction(withdraw *models.MerchantWithdraw) *models.Transaction {
+	amount := withdraw.GetAmount()
+	trxMethod := protocol.TrxMethodBankTransfer
+	if withdraw.AccountType == protocol.BeneficiaryAccountUPI {
+		trxMethod = protocol.TrxMethodUPI
+	}
+	trx := &models.Transaction{
+		Mid:               withdraw.Mid,
+		TrxID:             withdraw.TrxID,
+		TrxType:           protocol.TrxTypePayout,
+		ReqID:             withdraw.TrxID,
+		TrxMethod:         trxMethod,
+		Ccy:               withdraw.GetCcy(),
+		Amount:            &amount,
+		AccountNo:         withdraw.AccountNo,
+		AccountName:       withdraw.AccountName,
+		AccountType:       withdraw.AccountType,
+		BankCode:          withdraw.BankCode,
+		BankName:          withdraw.BankName,
+		BeneficiaryID:     withdraw.BeneficiaryID,
+		TransactionValues: models.NewTrxValues(),
+		CreatedAt:         withdraw.CreatedAt,
+	}
+	trx.SetCountry(withdraw.GetCountry()).
+		SetStatus(withdraw.GetStatus())
+	if withdraw.GetChannelAccount() != "" {
+		trx.SetChannelCode(withdraw.GetChannelCode()).
+			SetChannelAccount(withdraw.GetChannelAccount()).
+			SetChannelTrxID(withdraw.GetChannelTrxID())
+	}
+	return trx
+}
+
+// syncChannel 向渠道查询处理中的提现结果
+func (s *MerchantWithdrawService) syncChannel(withdraw *models.MerchantWithdraw) protocol.ErrorCode {
+	svc, ok := channels.GetOpenApiChannelService(withdraw.GetChannelAccount())
+	if !ok {
+		return protocol.ChannelNotSupported
+	}
+	result := svc.Query(&channels.ChannelTrxRequest{Transaction: s.toPayoutTransaction(withdraw)})
+	if result == nil {
+		return protocol.Success
+	}
+	return s.applyChannelResult(withdraw, result, protocol.System)
+}
+
+func RegisterWithdrawTasks() {
+	log.Get().Info("注册提现任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "withdraw_sync",
+			Type:       protocol.WithdrawSync,
+			HandlerKey: protocol.WithdrawSync,
+			Name:       "提现渠道结果同步",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 1m"}[0], // 每分钟执行一次
+				Timeout: &[]int{300}[0],           // 5分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("提现任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleWithdrawSync 查询渠道出款中的提现，按渠道结果完成或失败
+func HandleWithdrawSync(ctx context.Context, params protocol.MapData) error {
+	withdraws, err := models.ListProcessingChannelWithdraws(100)
+	if err != nil {
+		return fmt.Errorf("查询处理中提现失败: %v", err)
+	}
+	service := GetMerchantWithdrawService()
+	for _, withdraw := range withdraws {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if code := service.syncChannel(withdraw); code != protocol.Success {
+			log.Get().Errorf("Sync withdraw %s failed: %s", withdraw.TrxID, code)
+		}
+	}
+	return nil
+}
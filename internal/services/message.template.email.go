@@ -91,6 +91,89 @@ var (
 		Description: "Registration success email template - Kinyarwanda",
 	}
 
+	// 导出文件就绪Email模板
+	DefaultExportReadyEmailEN = &models.MessageTemplate{
+		Type:        protocol.MsgTypeExportReady,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangEnglish,
+		Title:       "Your export {{.file_name}} is ready",
+		Content:     "Your transaction export ({{.row_count}} rows) is ready. Download it here: {{.url}} . The link expires in {{.expire_minutes}} minutes; you can request a new link from the exports page.",
+		Status:      protocol.StatusActive,
+		Description: "Export ready email template - English",
+	}
+
+	DefaultExportReadyEmailZH = &models.MessageTemplate{
+		Type:        protocol.MsgTypeExportReady,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangChinese,
+		Title:       "导出文件 {{.file_name}} 已生成",
+		Content:     "您的交易导出（共 {{.row_count}} 行）已生成，下载地址：{{.url}} 。链接 {{.expire_minutes}} 分钟内有效，过期后可在导出列表重新获取。",
+		Status:      protocol.StatusActive,
+		Description: "导出文件就绪邮件模板 - 中文",
+	}
+
+	// 争议通知Email模板
+	DefaultDisputeOpenedEmailEN = &models.MessageTemplate{
+		Type:        protocol.MsgTypeDisputeOpened,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangEnglish,
+		Title:       "New {{.type}} on transaction {{.trx_id}}",
+		Content:     "A {{.type}} ({{.dispute_id}}, reason: {{.reason_code}}) was opened on transaction {{.trx_id}}. {{.amount}} {{.ccy}} has been frozen on your account. Please submit evidence or accept it in the merchant portal before {{.deadline}}.",
+		Status:      protocol.StatusActive,
+		Description: "Dispute opened email template - English",
+	}
+
+	DefaultDisputeOpenedEmailZH = &models.MessageTemplate{
+		Type:        protocol.MsgTypeDisputeOpened,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangChinese,
+		Title:       "交易 {{.trx_id}} 收到新的争议",
+		Content:     "交易 {{.trx_id}} 收到争议 {{.dispute_id}}（类型：{{.type}}，原因：{{.reason_code}}），争议金额 {{.amount}} {{.ccy}} 已在您的账户冻结。请在 {{.deadline}} 前登录商户后台提交证据或接受退单。",
+		Status:      protocol.StatusActive,
+		Description: "新争议通知邮件模板 - 中文",
+	}
+
+	DefaultDisputeResolvedEmailEN = &models.MessageTemplate{
+		Type:        protocol.MsgTypeDisputeResolved,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangEnglish,
+		Title:       "Dispute {{.dispute_id}} resolved: {{.status}}",
+		Content:     "Dispute {{.dispute_id}} on transaction {{.trx_id}} was resolved as {{.status}}. Amount: {{.amount}} {{.ccy}}. Remark: {{.remark}}",
+		Status:      protocol.StatusActive,
+		Description: "Dispute resolved email template - English",
+	}
+
+	DefaultDisputeResolvedEmailZH = &models.MessageTemplate{
+		Type:        protocol.MsgTypeDisputeResolved,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangChinese,
+		Title:       "争议 {{.dispute_id}} 已裁决：{{.status}}",
+		Content:     "交易 {{.trx_id}} 的争议 {{.dispute_id}} 裁决结果为 {{.status}}，金额 {{.amount}} {{.ccy}}。说明：{{.remark}}",
+		Status:      protocol.StatusActive,
+		Description: "争议裁决通知邮件模板 - 中文",
+	}
+
+	// 对账单就绪Email模板
+	DefaultStatementReadyEmailEN = &models.MessageTemplate{
+		Type:        protocol.MsgTypeStatementReady,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangEnglish,
+		Title:       "Your account statements for {{.period}}",
+		Content:     "Your account statements for {{.period}} are ready:<br/>{{.links}}<br/>The links expire in {{.expire_hours}} hours; statements can also be downloaded from the portal at any time.",
+		Status:      protocol.StatusActive,
+		Description: "Statement ready email template - English",
+	}
+
+	DefaultStatementReadyEmailZH = &models.MessageTemplate{
+		Type:        protocol.MsgTypeStatementReady,
+		Channel:     protocol.MsgChannelEmail,
+		Language:    protocol.LangChinese,
+		Title:       "{{.period}} 账户对账单",
+		Content:     "您 {{.period}} 的账户对账单已生成：<br/>{{.links}}<br/>链接 {{.expire_hours}} 小时内有效，也可随时在后台下载对账单。",
+		Status:      protocol.StatusActive,
+		Description: "对账单就绪邮件模板 - 中文",
+	}
+
 	// 默认Email模板集合
 	DefaultEmailTemplates = []*models.MessageTemplate{
 		// 英文模板
@@ -108,5 +191,15 @@ var (
 		// 卢旺达语模板
 		DefaultVerifyCodeEmailRW,
 		DefaultRegisterSuccessEmailRW,
+
+		// 导出通知模板
+		DefaultExportReadyEmailEN,
+		DefaultExportReadyEmailZH,
+		DefaultDisputeOpenedEmailEN,
+		DefaultDisputeOpenedEmailZH,
+		DefaultDisputeResolvedEmailEN,
+		DefaultDisputeResolvedEmailZH,
+		DefaultStatementReadyEmailEN,
+		DefaultStatementReadyEmailZH,
 	}
 )
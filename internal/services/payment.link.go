@@ -0,0 +1,272 @@
+package services
+
+import (
+	"errors"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"regexp"
+	"sync"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PaymentLinkService 支付链接服务，链接本身不产生交易，付款人每次访问创建一个收银台会话
+type PaymentLinkService struct{}
+
+var (
+	paymentLinkService     *PaymentLinkService
+	paymentLinkServiceOnce sync.Once
+
+	// reqIDPrefixPattern 会话请求ID前缀，拼接后需满足req_id长度限制
+	reqIDPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,24}$`)
+
+	errPaymentLinkUnusable = errors.New("payment link unusable")
+)
+
+func SetupPaymentLinkService() {
+	paymentLinkServiceOnce.Do(func() {
+		paymentLinkService = &PaymentLinkService{}
+	})
+}
+
+// GetPaymentLinkService 获取支付链接服务单例
+func GetPaymentLinkService() *PaymentLinkService {
+	if paymentLinkService == nil {
+		SetupPaymentLinkService()
+	}
+	return paymentLinkService
+}
+
+// Create 创建支付链接，金额为空时为开放金额，可限定上下限
+func (s *PaymentLinkService) Create(req *protocol.CreatePaymentLinkRequest) (*protocol.PaymentLink, protocol.ErrorCode) {
+	if !protocol.IsValidCurrency(req.Ccy) {
+		return nil, protocol.InvalidCurrency
+	}
+	if req.ReqIDPrefix != "" && !reqIDPrefixPattern.MatchString(req.ReqIDPrefix) {
+		return nil, protocol.InvalidParams
+	}
+	if req.ExpiredAt > 0 && req.ExpiredAt <= utils.TimeNowMilli() {
+		return nil, protocol.InvalidParams
+	}
+
+	link := &models.PaymentLink{
+		LinkID:            utils.GeneratePaymentLinkID(),
+		Mid:               req.Mid,
+		Ccy:               req.Ccy,
+		Description:       req.Description,
+		ReqIDPrefix:       req.ReqIDPrefix,
+		MaxUses:           req.MaxUses,
+		ExpiredAt:         req.ExpiredAt,
+		NotifyURL:         req.NotifyURL,
+		ReturnURL:         req.ReturnURL,
+		PaymentLinkValues: &models.PaymentLinkValues{},
+	}
+	link.SetStatus(protocol.StatusActive)
+
+	if req.Amount != "" {
+		amount, err := utils.ValidateAmount(req.Amount)
+		if err != nil {
+			return nil, protocol.PaymentLinkAmountInvalid
+		}
+		link.Amount = amount
+	} else {
+		if req.MinAmount != "" {
+			minAmount, err := utils.ValidateAmount(req.MinAmount)
+			if err != nil {
+				return nil, protocol.PaymentLinkAmountInvalid
+			}
+			link.MinAmount = minAmount
+		}
+		if req.MaxAmount != "" {
+			maxAmount, err := utils.ValidateAmount(req.MaxAmount)
+			if err != nil {
+				return nil, protocol.PaymentLinkAmountInvalid
+			}
+			link.MaxAmount = maxAmount
+		}
+		if link.MinAmount != nil && link.MaxAmount != nil && link.MinAmount.GreaterThan(*link.MaxAmount) {
+			return nil, protocol.PaymentLinkAmountInvalid
+		}
+	}
+
+	if err := models.WriteDB.Create(link).Error; err != nil {
+		log.Get().Errorf("Create payment link failed: %v", err)
+		return nil, protocol.SystemError
+	}
+	return s.toProtocol(link), protocol.Success
+}
+
+// List 商户支付链接列表
+func (s *PaymentLinkService) List(mid string, req *protocol.PaymentLinkListRequest) ([]*protocol.PaymentLink, int64, protocol.ErrorCode) {
+	links, total, err := models.ListPaymentLinkByQuery(&models.PaymentLinkQuery{
+		Mid:    mid,
+		Status: req.Status,
+		Ccy:    req.Ccy,
+		Page:   req.Page,
+		Size:   req.Size,
+	})
+	if err != nil {
+		log.Get().Errorf("List payment links failed: %v", err)
+		return nil, 0, protocol.SystemError
+	}
+	list := make([]*protocol.PaymentLink, 0, len(links))
+	for _, link := range links {
+		list = append(list, s.toProtocol(link))
+	}
+	return list, total, protocol.Success
+}
+
+// Disable 停用支付链接，已创建的会话不受影响，但不能再确认支付
+func (s *PaymentLinkService) Disable(mid, linkID string) (*protocol.PaymentLink, protocol.ErrorCode) {
+	link := models.GetPaymentLinkByID(mid, linkID)
+	if link == nil {
+		return nil, protocol.PaymentLinkNotFound
+	}
+	if link.GetStatus() == protocol.StatusDisabled {
+		return s.toProtocol(link), protocol.Success
+	}
+	values := &models.PaymentLinkValues{}
+	values.SetStatus(protocol.StatusDisabled).SetDisabledAt(utils.TimeNowMilli())
+	ok, err := models.UpdatePaymentLinkValues(models.WriteDB, link, []string{protocol.StatusActive}, values)
+	if err != nil {
+		log.Get().Errorf("Disable payment link %s failed: %v", linkID, err)
+		return nil, protocol.SystemError
+	}
+	if !ok {
+		return nil, protocol.PaymentLinkInactive
+	}
+	return s.toProtocol(link), protocol.Success
+}
+
+// Stats 支付链接统计，访问数按会话统计，成功金额按实收金额累计
+func (s *PaymentLinkService) Stats(mid, linkID string) (*protocol.PaymentLinkStats, protocol.ErrorCode) {
+	link := models.GetPaymentLinkByID(mid, linkID)
+	if link == nil {
+		return nil, protocol.PaymentLinkNotFound
+	}
+	checkoutStats, err := models.GetPaymentLinkCheckoutStats(linkID)
+	if err != nil {
+		log.Get().Errorf("Query payment link %s checkout stats failed: %v", linkID, err)
+		return nil, protocol.SystemError
+	}
+	payinStats, err := models.GetPaymentLinkPayinStats(linkID)
+	if err != nil {
+		log.Get().Errorf("Query payment link %s payin stats failed: %v", linkID, err)
+		return nil, protocol.SystemError
+	}
+
+	remaining := -1
+	if link.MaxUses > 0 {
+		remaining = max(link.MaxUses-int(payinStats.PaidCount+payinStats.PendingCount), 0)
+	}
+	return &protocol.PaymentLinkStats{
+		LinkID:        link.LinkID,
+		Ccy:           link.Ccy,
+		VisitCount:    checkoutStats.VisitCount,
+		ConfirmCount:  checkoutStats.ConfirmCount,
+		PaidCount:     payinStats.PaidCount,
+		PaidAmount:    payinStats.PaidAmount.String(),
+		PendingCount:  payinStats.PendingCount,
+		FailedCount:   payinStats.FailedCount,
+		RemainingUses: remaining,
+		LastPaidAt:    payinStats.LastPaidAt,
+	}, protocol.Success
+}
+
+// Info 付款人查看支付链接，用于落地页展示金额与说明
+func (s *PaymentLinkService) Info(linkID string) (*protocol.PaymentLink, protocol.ErrorCode) {
+	link := models.GetPaymentLinkByID("", linkID)
+	if link == nil {
+		return nil, protocol.PaymentLinkNotFound
+	}
+	info := s.toProtocol(link)
+	// 回调地址仅商户可见
+	info.NotifyURL = ""
+	return info, protocol.Success
+}
+
+// Visit 付款人访问支付链接，校验链接后创建收银台会话并返回会话令牌
+func (s *PaymentLinkService) Visit(req *protocol.PaymentLinkVisitRequest) (*protocol.Checkout, protocol.ErrorCode) {
+	link := models.GetPaymentLinkByID("", req.LinkID)
+	if link == nil {
+		return nil, protocol.PaymentLinkNotFound
+	}
+	if code := s.checkUsable(models.ReadDB, link); code != protocol.Success {
+		return nil, code
+	}
+	amount, code := s.resolveAmount(link, req.Amount)
+	if code != protocol.Success {
+		return nil, code
+	}
+
+	prefix := link.ReqIDPrefix
+	if prefix == "" {
+		prefix = link.LinkID
+	}
+	checkout, code := GetCheckoutService().Create(&protocol.CreateCheckoutRequest{
+		Mid:       link.Mid,
+		ReqID:     prefix + "-" + utils.GenerateID(),
+		Ccy:       link.Ccy,
+		Amount:    amount.String(),
+		Country:   req.Country,
+		TrxMethod: req.TrxMethod,
+		NotifyURL: link.NotifyURL,
+		ReturnURL: link.ReturnURL,
+		LinkID:    link.LinkID,
+		ExpiredAt: link.ExpiredAt,
+	})
+	if code != protocol.Success {
+		return nil, code
+	}
+	return GetCheckoutService().Info(checkout.CheckoutID)
+}
+
+// ReserveUse 收银台确认支付时在事务内锁定链接并校验状态与使用次数
+func (s *PaymentLinkService) ReserveUse(tx *gorm.DB, linkID string) protocol.ErrorCode {
+	link := models.LockPaymentLink(tx, linkID)
+	if link == nil {
+		return protocol.PaymentLinkNotFound
+	}
+	return s.checkUsable(tx, link)
+}
+
+// checkUsable 校验链接可用：未停用、未过期且未达到使用次数上限
+func (s *PaymentLinkService) checkUsable(db *gorm.DB, link *models.PaymentLink) protocol.ErrorCode {
+	if link.GetStatus() != protocol.StatusActive || link.IsExpired() {
+		return protocol.PaymentLinkInactive
+	}
+	if link.MaxUses > 0 && models.CountPaymentLinkUses(db, link.LinkID) >= int64(link.MaxUses) {
+		return protocol.PaymentLinkExhausted
+	}
+	return protocol.Success
+}
+
+// resolveAmount 固定金额链接忽略付款人输入，开放金额链接校验输入金额及上下限
+func (s *PaymentLinkService) resolveAmount(link *models.PaymentLink, input string) (decimal.Decimal, protocol.ErrorCode) {
+	if !link.IsOpenAmount() {
+		return *link.Amount, protocol.Success
+	}
+	amount, err := utils.ValidateAmount(input)
+	if err != nil {
+		return decimal.Zero, protocol.PaymentLinkAmountInvalid
+	}
+	if link.MinAmount != nil && amount.LessThan(*link.MinAmount) {
+		return decimal.Zero, protocol.PaymentLinkAmountInvalid
+	}
+	if link.MaxAmount != nil && amount.GreaterThan(*link.MaxAmount) {
+		return decimal.Zero, protocol.PaymentLinkAmountInvalid
+	}
+	return *amount, protocol.Success
+}
+
+func (s *PaymentLinkService) toProtocol(link *models.PaymentLink) *protocol.PaymentLink {
+	info := link.Protocol()
+	if pageURL := config.Get().PaymentLink.PageURL; pageURL != "" {
+		info.URL = pageURL + "/" + link.LinkID
+	}
+	return info
+}
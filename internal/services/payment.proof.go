@@ -0,0 +1,589 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PaymentProofService 支付凭证服务：付款人提交UTR与截图，按UTR与银行流水自动匹配，未匹配的进入收银团队或运营审核队列
+type PaymentProofService struct{}
+
+var (
+	paymentProofService     *PaymentProofService
+	paymentProofServiceOnce sync.Once
+)
+
+// proofPayableStatuses 可提交凭证并确认到账的交易状态
+var proofPayableStatuses = []string{protocol.StatusPending, protocol.StatusProcessing, protocol.StatusConfirming}
+
+// utrPattern UTR格式，IMPS/UPI为12位数字，NEFT/RTGS为16-22位字母数字
+var utrPattern = regexp.MustCompile(`^[A-Z0-9]{6,32}$`)
+
+// proofImageExts 允许上传的凭证图片类型
+var proofImageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+const proofMatchBatchSize = 100
+
+func init() {
+	task.RegisterHandler(protocol.ProofStatementMatch, HandleProofStatementMatch)
+}
+
+func SetupPaymentProofService() {
+	paymentProofServiceOnce.Do(func() {
+		paymentProofService = &PaymentProofService{}
+	})
+}
+
+// GetPaymentProofService 获取支付凭证服务单例
+func GetPaymentProofService() *PaymentProofService {
+	if paymentProofService == nil {
+		SetupPaymentProofService()
+	}
+	return paymentProofService
+}
+
+// NormalizeUtr 统一UTR格式，去除空白并转大写
+func NormalizeUtr(utr string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(utr), ""))
+}
+
+// Submit 付款人通过收银台提交UTR与可选的付款截图
+func (s *PaymentProofService) Submit(req *protocol.SubmitProofRequest, image *multipart.FileHeader) (*protocol.PaymentProof, protocol.ErrorCode) {
+	utr := NormalizeUtr(req.Utr)
+	if !utrPattern.MatchString(utr) {
+		return nil, protocol.InvalidParams
+	}
+	checkout := models.GetMerchantCheckoutByCheckoutID(req.CheckoutID)
+	if checkout == nil || checkout.Mid != req.Mid || checkout.FindTransactionByTrxID(req.TrxID) == nil {
+		return nil, protocol.TransactionNotFound
+	}
+	if checkout.TrxType != protocol.TrxTypePayin {
+		return nil, protocol.ProofTrxNotPayable
+	}
+	// 收银台确认后交易才写入代收表
+	trx := models.GetTransactionByMidAndTrxID(req.Mid, req.TrxID, checkout.TrxType)
+	if trx == nil || !slices.Contains(proofPayableStatuses, trx.GetStatus()) {
+		return nil, protocol.ProofTrxNotPayable
+	}
+	if models.CountPendingProofByTrxID(trx.TrxID) > 0 {
+		return nil, protocol.ProofAlreadySubmitted
+	}
+	if models.CountActiveProofByUtr(utr, trx.TrxID) > 0 || models.CountTransactionByProofID(trx.TrxType, utr, trx.TrxID) > 0 {
+		return nil, protocol.ProofDuplicateUtr
+	}
+
+	proof := &models.PaymentProof{
+		ProofID:            utils.GenerateProofID(),
+		TrxID:              trx.TrxID,
+		TrxType:            trx.TrxType,
+		Mid:                trx.Mid,
+		CheckoutID:         checkout.CheckoutID,
+		Tid:                trx.Tid,
+		Utr:                utr,
+		Ccy:                trx.Ccy,
+		Amount:             trx.GetAmount(),
+		PaymentProofValues: (&models.PaymentProofValues{}).SetStatus(protocol.ProofStatusPending),
+	}
+	if image != nil {
+		path, code := s.saveImage(proof.ProofID, image)
+		if code != protocol.Success {
+			return nil, code
+		}
+		proof.ImagePath = path
+	}
+	if err := models.WriteDB.Create(proof).Error; err != nil {
+		log.Get().Errorf("Create payment proof failed: trx_id=%s, err=%v", trx.TrxID, err)
+		return nil, protocol.DatabaseError
+	}
+
+	// 已导入的银行流水可直接确认到账
+	s.tryMatch(proof)
+	return proof.Protocol(), protocol.Success
+}
+
+// saveImage 校验并保存凭证图片，按内容识别类型而非文件扩展名
+func (s *PaymentProofService) saveImage(proofID string, image *multipart.FileHeader) (string, protocol.ErrorCode) {
+	cfg := config.Get().Proof
+	if image.Size <= 0 || image.Size > cfg.MaxImageSize {
+		return "", protocol.ProofImageInvalid
+	}
+	src, err := image.Open()
+	if err != nil {
+		return "", protocol.FileError
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", protocol.ProofImageInvalid
+	}
+	head = head[:n]
+	ext, ok := proofImageExts[http.DetectContentType(head)]
+	if !ok {
+		return "", protocol.ProofImageInvalid
+	}
+
+	dir := filepath.Join(cfg.StorageDir, time.Now().Format("20060102"))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		log.Get().Errorf("Create proof dir failed: %v", err)
+		return "", protocol.FileError
+	}
+	path := filepath.Join(dir, proofID+ext)
+	dst, err := os.Create(path)
+	if err != nil {
+		log.Get().Errorf("Create proof image failed: %v", err)
+		return "", protocol.FileError
+	}
+	defer dst.Close()
+	if _, err := dst.Write(head); err != nil {
+		return "", protocol.FileError
+	}
+	if _, err := io.Copy(dst, io.LimitReader(src, cfg.MaxImageSize)); err != nil {
+		return "", protocol.FileError
+	}
+	return path, protocol.Success
+}
+
+// findStatement 查找与凭证UTR、币种一致的未匹配银行流水，优先已关联的流水，其次金额一致的流水
+func (s *PaymentProofService) findStatement(proof *models.PaymentProof) *models.BankStatement {
+	statements, err := models.ListUnmatchedStatementsByUtr(proof.Utr)
+	if err != nil {
+		log.Get().Errorf("List bank statements failed: utr=%s, err=%v", proof.Utr, err)
+		return nil
+	}
+	var matched *models.BankStatement
+	for _, statement := range statements {
+		if statement.Ccy != proof.Ccy {
+			continue
+		}
+		if statement.StatementID == proof.GetStatementID() {
+			return statement
+		}
+		if matched == nil || (!matched.Amount.Equal(proof.Amount) && statement.Amount.Equal(proof.Amount)) {
+			matched = statement
+		}
+	}
+	return matched
+}
+
+// ResolveAmountAction 按商户代收配置判断实收金额的处理方式，容差内按实收金额接受
+func (s *PaymentProofService) ResolveAmountAction(mid, ccy string, requested, received decimal.Decimal) string {
+	if received.Equal(requested) {
+		return protocol.AmountActionExact
+	}
+	cfg := GetConfigService().GetTrxConfigByMerchantID(mid, models.TrxTypeReceipt)
+	if received.Sub(requested).Abs().LessThanOrEqual(cfg.GetAmountTolerance(ccy, requested)) {
+		return protocol.AmountActionAccept
+	}
+	action := cfg.GetOverpaidAction()
+	if received.LessThan(requested) {
+		action = cfg.GetUnderpaidAction()
+	}
+	switch action {
+	case protocol.AmountActionAccept, protocol.AmountActionReject:
+		return action
+	}
+	return protocol.AmountActionReview
+}
+
+// tryMatch 凭证与银行流水自动匹配，实收金额与订单金额不一致时按商户策略接受、拒绝或挂起人工审核
+// 返回凭证是否已处理完毕
+func (s *PaymentProofService) tryMatch(proof *models.PaymentProof) bool {
+	// 已因金额差异挂起的凭证等待人工审核
+	if proof.GetStatementID() != "" {
+		return false
+	}
+	statement := s.findStatement(proof)
+	if statement == nil {
+		return false
+	}
+	var code protocol.ErrorCode
+	switch s.ResolveAmountAction(proof.Mid, proof.Ccy, proof.Amount, statement.Amount) {
+	case protocol.AmountActionExact, protocol.AmountActionAccept:
+		code = s.complete(proof, statement, protocol.ProofStatusMatched, protocol.StatusSuccess, protocol.System,
+			"matched with bank statement "+statement.StatementID, statement.Amount)
+	case protocol.AmountActionReject:
+		code = s.complete(proof, statement, protocol.ProofStatusRejected, protocol.StatusFailed, protocol.System,
+			fmt.Sprintf("received amount %s differs from requested %s", statement.Amount, proof.Amount), statement.Amount)
+	default:
+		s.hold(proof, statement)
+		return false
+	}
+	if code != protocol.Success {
+		log.Get().Warnf("Auto match payment proof %s failed: %s", proof.ProofID, code)
+		return false
+	}
+	return true
+}
+
+// hold 金额差异需人工审核，关联流水并记录实收金额
+func (s *PaymentProofService) hold(proof *models.PaymentProof, statement *models.BankStatement) {
+	values := &models.PaymentProofValues{}
+	values.SetStatementID(statement.StatementID).
+		SetReceivedAmount(statement.Amount).
+		SetReviewRemark(fmt.Sprintf("received amount %s differs from requested %s, held for review", statement.Amount, proof.Amount))
+	if _, err := models.UpdatePaymentProofValues(models.WriteDB, proof, []string{protocol.ProofStatusPending}, values); err != nil {
+		log.Get().Errorf("Hold payment proof: proof_id=%s, err=%v", proof.ProofID, err)
+	}
+}
+
+// complete 完成凭证处理并更新交易状态，流水为空表示人工审核，trxStatus为success时按实收金额重算手续费
+func (s *PaymentProofService) complete(proof *models.PaymentProof, statement *models.BankStatement, proofStatus, trxStatus, operator, remark string, received decimal.Decimal) protocol.ErrorCode {
+	trx := models.GetTransactionByTrxID(proof.TrxID, proof.TrxType)
+	if trx == nil {
+		return protocol.TransactionNotFound
+	}
+	if !slices.Contains(proofPayableStatuses, trx.GetStatus()) {
+		return protocol.ProofTrxNotPayable
+	}
+	if models.CountTransactionByProofID(trx.TrxType, proof.Utr, trx.TrxID) > 0 {
+		return protocol.ProofDuplicateUtr
+	}
+
+	now := utils.TimeNowMilli()
+	proofValues := &models.PaymentProofValues{}
+	proofValues.SetStatus(proofStatus).
+		SetReceivedAmount(received).
+		SetReviewedBy(operator).
+		SetReviewRemark(remark).
+		SetReviewedAt(now)
+	if statement != nil {
+		proofValues.SetStatementID(statement.StatementID)
+	}
+
+	history := models.NewTrxHistoryByTransaction(trx)
+	values := models.NewTrxValues()
+	values.SetStatus(trxStatus).
+		SetProofID(proof.Utr).
+		SetReceivedAmount(received).
+		SetCompletedAt(now)
+	if trxStatus == protocol.StatusSuccess {
+		values.SetSettleStatus(protocol.StatusPending)
+		if !received.Equal(trx.GetAmount()) {
+			if fee, feeCcy, ok := GetFeeConfigService().CalculateFeeForAmount(trx, received); ok {
+				values.SetFeeCcy(feeCcy).SetFeeAmount(fee)
+			}
+		}
+	} else {
+		values.SetReason(remark)
+	}
+	GetFxRateService().SnapshotCompletion(trx, values)
+
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		ok, err := models.UpdatePaymentProofValues(tx, proof, []string{protocol.ProofStatusPending}, proofValues)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ProofAlreadyReviewed
+			return protocol.NewServiceError(code, "payment proof already reviewed")
+		}
+		if statement != nil {
+			statementValues := &models.BankStatementValues{}
+			statementValues.SetStatus(protocol.StatementStatusMatched).
+				SetMatchedTrxID(trx.TrxID).
+				SetMatchedAt(now)
+			ok, err := models.UpdateBankStatementValues(tx, statement, []string{protocol.StatementStatusUnmatched}, statementValues)
+			if err != nil {
+				code = protocol.DatabaseError
+				return err
+			}
+			if !ok {
+				code = protocol.ProofDuplicateUtr
+				return protocol.NewServiceError(code, "bank statement already matched")
+			}
+		}
+		ok, err = models.UpdateTransactionValuesWithStatus(tx, trx, proofPayableStatuses, values)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if !ok {
+			code = protocol.ProofTrxNotPayable
+			return protocol.NewServiceError(code, "transaction status changed")
+		}
+		if err := CreateWebhooks(tx, NewTransactionWebhook(trx)); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if err := CreateCashierCompletedWebhook(tx, trx); err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Complete payment proof: proof_id=%s, err=%v", proof.ProofID, err)
+		return code
+	}
+	if trxStatus == protocol.StatusSuccess {
+		GetMerchantTransactionService().AfterPayinSuccess(trx)
+	}
+
+	history.TrxType = trx.TrxType
+	history.FillValues(trx.TransactionValues)
+	history.ChangedBy = operator
+	history.Remark = fmt.Sprintf("payment proof %s utr %s %s: %s", proof.ProofID, proof.Utr, proofStatus, remark)
+	if err := models.CreateHistory(history); err != nil {
+		log.Get().Errorf("Save proof history error: trx_id=%s, err=%v", trx.TrxID, err)
+	}
+	return protocol.Success
+}
+
+// Approve 人工审核通过，审核结论优先于商户金额策略，tid不为空时仅可审核本收银团队的凭证
+func (s *PaymentProofService) Approve(operator, tid string, req *protocol.ProofReviewRequest) (*protocol.PaymentProof, protocol.ErrorCode) {
+	proof := models.GetPaymentProofByID(tid, req.ProofID)
+	if proof == nil {
+		return nil, protocol.ProofNotFound
+	}
+	if proof.GetStatus() != protocol.ProofStatusPending {
+		return nil, protocol.ProofAlreadyReviewed
+	}
+	received := proof.GetReceivedAmount()
+	if req.ReceivedAmount != "" {
+		amount, err := decimal.NewFromString(req.ReceivedAmount)
+		if err != nil || !amount.IsPositive() {
+			return nil, protocol.InvalidParams
+		}
+		received = amount
+	}
+	// 存在对应流水时一并核销，避免同一笔入账再被其他凭证匹配
+	statement := s.findStatement(proof)
+	if code := s.complete(proof, statement, protocol.ProofStatusApproved, protocol.StatusSuccess, operator, req.Remark, received); code != protocol.Success {
+		return nil, code
+	}
+	return proof.Protocol(), protocol.Success
+}
+
+// Reject 人工审核驳回，交易保持待支付，付款人可重新提交
+func (s *PaymentProofService) Reject(operator, tid string, req *protocol.ProofReviewRequest) (*protocol.PaymentProof, protocol.ErrorCode) {
+	proof := models.GetPaymentProofByID(tid, req.ProofID)
+	if proof == nil {
+		return nil, protocol.ProofNotFound
+	}
+	values := &models.PaymentProofValues{}
+	values.SetStatus(protocol.ProofStatusRejected).
+		SetReviewedBy(operator).
+		SetReviewRemark(req.Remark).
+		SetReviewedAt(utils.TimeNowMilli())
+	ok, err := models.UpdatePaymentProofValues(models.WriteDB, proof, []string{protocol.ProofStatusPending}, values)
+	if err != nil {
+		log.Get().Errorf("Reject payment proof: proof_id=%s, err=%v", proof.ProofID, err)
+		return nil, protocol.DatabaseError
+	}
+	if !ok {
+		return nil, protocol.ProofAlreadyReviewed
+	}
+	return proof.Protocol(), protocol.Success
+}
+
+// Detail 凭证详情，tid不为空时仅可查看本收银团队的凭证
+func (s *PaymentProofService) Detail(tid, proofID string) (*protocol.PaymentProof, protocol.ErrorCode) {
+	proof := models.GetPaymentProofByID(tid, proofID)
+	if proof == nil {
+		return nil, protocol.ProofNotFound
+	}
+	return proof.Protocol(), protocol.Success
+}
+
+// ImagePath 获取凭证图片路径，供审核人查看
+func (s *PaymentProofService) ImagePath(tid, proofID string) (string, protocol.ErrorCode) {
+	proof := models.GetPaymentProofByID(tid, proofID)
+	if proof == nil || proof.ImagePath == "" {
+		return "", protocol.ProofNotFound
+	}
+	return proof.ImagePath, protocol.Success
+}
+
+// List 凭证审核队列
+func (s *PaymentProofService) List(req *protocol.ProofListRequest) ([]*protocol.PaymentProof, int64, protocol.ErrorCode) {
+	list, total, err := models.ListPaymentProofByQuery(&models.PaymentProofQuery{
+		Tid:            req.Tid,
+		Mid:            req.Mid,
+		TrxID:          req.TrxID,
+		Utr:            NormalizeUtr(req.Utr),
+		Status:         req.Status,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+	})
+	if err != nil {
+		log.Get().Errorf("List payment proofs failed: %v", err)
+		return nil, 0, protocol.DatabaseError
+	}
+	items := make([]*protocol.PaymentProof, 0, len(list))
+	for _, proof := range list {
+		items = append(items, proof.Protocol())
+	}
+	return items, total, protocol.Success
+}
+
+// ImportStatements 导入银行流水CSV并自动匹配待审核凭证，列顺序：utr,amount,ccy,txn_at,account_no,narration
+func (s *PaymentProofService) ImportStatements(adminID string, reader io.Reader) (*protocol.BankStatementImportResult, protocol.ErrorCode) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, protocol.StatementFileInvalid
+	}
+	batchID := utils.GenerateID()
+	statements := make([]*models.BankStatement, 0, len(records))
+	utrs := make([]string, 0, len(records))
+	for i, record := range records {
+		if len(record) < 4 {
+			return nil, protocol.StatementFileInvalid
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "utr") {
+			continue
+		}
+		utr := NormalizeUtr(record[0])
+		amount, err := decimal.NewFromString(strings.TrimSpace(record[1]))
+		ccy := strings.ToUpper(strings.TrimSpace(record[2]))
+		txnAt := parseFxEffectiveAt(strings.TrimSpace(record[3]))
+		if !utrPattern.MatchString(utr) || err != nil || !amount.IsPositive() || !protocol.IsValidCurrency(ccy) || txnAt <= 0 {
+			return nil, protocol.StatementFileInvalid
+		}
+		statement := &models.BankStatement{
+			StatementID:         utils.GenerateStatementID(),
+			BatchID:             batchID,
+			Utr:                 utr,
+			Ccy:                 ccy,
+			Amount:              amount,
+			TxnAt:               txnAt,
+			ImportedBy:          adminID,
+			BankStatementValues: (&models.BankStatementValues{}).SetStatus(protocol.StatementStatusUnmatched),
+		}
+		if len(record) > 4 {
+			statement.AccountNo = strings.TrimSpace(record[4])
+		}
+		if len(record) > 5 {
+			statement.Narration = strings.TrimSpace(record[5])
+		}
+		statements = append(statements, statement)
+		utrs = append(utrs, utr)
+	}
+	if len(statements) == 0 {
+		return nil, protocol.StatementFileInvalid
+	}
+	imported, err := models.SaveBankStatements(models.WriteDB, statements)
+	if err != nil {
+		log.Get().Errorf("Import bank statements failed: %v", err)
+		return nil, protocol.DatabaseError
+	}
+
+	result := &protocol.BankStatementImportResult{
+		BatchID:  batchID,
+		Imported: int(imported),
+		Skipped:  len(statements) - int(imported),
+	}
+	slices.Sort(utrs)
+	for _, utr := range slices.Compact(utrs) {
+		proofs, err := models.ListPendingProofsByUtr(utr)
+		if err != nil {
+			log.Get().Errorf("List pending proofs failed: utr=%s, err=%v", utr, err)
+			continue
+		}
+		for _, proof := range proofs {
+			if s.tryMatch(proof) {
+				result.Matched++
+				break
+			}
+		}
+	}
+	return result, protocol.Success
+}
+
+// ListStatements 银行流水列表
+func (s *PaymentProofService) ListStatements(req *protocol.BankStatementListRequest) ([]*protocol.BankStatement, int64, protocol.ErrorCode) {
+	list, total, err := models.ListBankStatementByQuery(&models.BankStatementQuery{
+		BatchID:    req.BatchID,
+		AccountNo:  req.AccountNo,
+		Utr:        NormalizeUtr(req.Utr),
+		Status:     req.Status,
+		TxnAtStart: req.TxnAtStart,
+		TxnAtEnd:   req.TxnAtEnd,
+		Page:       req.Page,
+		Size:       req.Size,
+	})
+	if err != nil {
+		log.Get().Errorf("List bank statements failed: %v", err)
+		return nil, 0, protocol.DatabaseError
+	}
+	items := make([]*protocol.BankStatement, 0, len(list))
+	for _, statement := range list {
+		items = append(items, statement.Protocol())
+	}
+	return items, total, protocol.Success
+}
+
+// RegisterProofTasks 注册支付凭证任务
+func RegisterProofTasks() {
+	log.Get().Info("注册支付凭证任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "proof_statement_match",
+			Type:       protocol.ProofStatementMatch,
+			HandlerKey: protocol.ProofStatementMatch,
+			Name:       "支付凭证流水匹配",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 5m"}[0], // 每5分钟执行一次
+				Timeout: &[]int{600}[0],           // 10分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("支付凭证任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleProofStatementMatch 补偿匹配：凭证提交与流水导入并发时可能错过即时匹配
+func HandleProofStatementMatch(ctx context.Context, params protocol.MapData) error {
+	service := GetPaymentProofService()
+	var afterID int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		proofs, err := models.ListPendingProofsWithStatement(afterID, proofMatchBatchSize)
+		if err != nil {
+			return fmt.Errorf("查询待匹配凭证失败: %v", err)
+		}
+		for _, proof := range proofs {
+			afterID = proof.ID
+			service.tryMatch(proof)
+		}
+		if len(proofs) < proofMatchBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"encoding/base64"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"strings"
+	"sync"
+)
+
+// QRCodeService 二维码服务，渲染支付链接、收银台地址及UPI意图二维码
+type QRCodeService struct{}
+
+var (
+	qrCodeService     *QRCodeService
+	qrCodeServiceOnce sync.Once
+)
+
+func SetupQRCodeService() {
+	qrCodeServiceOnce.Do(func() {
+		qrCodeService = &QRCodeService{}
+	})
+}
+
+// GetQRCodeService 获取二维码服务单例
+func GetQRCodeService() *QRCodeService {
+	if qrCodeService == nil {
+		SetupQRCodeService()
+	}
+	return qrCodeService
+}
+
+// Render 渲染二维码，格式为空时使用PNG，尺寸为空时使用默认尺寸
+func (s *QRCodeService) Render(content, format string, size int) (*protocol.QRCode, protocol.ErrorCode) {
+	cfg := config.Get().QRCode
+	if size == 0 {
+		size = cfg.DefaultSize
+	}
+	if size < cfg.MinSize || size > cfg.MaxSize {
+		return nil, protocol.InvalidParams
+	}
+	if format == "" {
+		format = protocol.QRCodeFormatPNG
+	}
+
+	var (
+		data     []byte
+		err      error
+		mimeType string
+	)
+	switch format {
+	case protocol.QRCodeFormatPNG:
+		data, err = utils.RenderQRCodePNG(content, size)
+		mimeType = "image/png"
+	case protocol.QRCodeFormatSVG:
+		data, err = utils.RenderQRCodeSVG(content, size)
+		mimeType = "image/svg+xml"
+	default:
+		return nil, protocol.InvalidParams
+	}
+	if err != nil {
+		// 内容过长或尺寸不足以容纳全部模块
+		log.Get().Warnf("Render qrcode failed: %v", err)
+		return nil, protocol.InvalidParams
+	}
+	return &protocol.QRCode{
+		Content: content,
+		Format:  format,
+		Size:    size,
+		DataURI: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data),
+	}, protocol.Success
+}
+
+// TrxQRCode 渲染商户代收交易的支付链接或收银台地址二维码
+func (s *QRCodeService) TrxQRCode(req *protocol.QRCodeRequest) (*protocol.QRCode, protocol.ErrorCode) {
+	var content string
+	switch {
+	case req.TrxID != "":
+		trx := models.GetTransactionByMidAndTrxID(req.Mid, req.TrxID, protocol.TrxTypePayin)
+		if trx == nil {
+			return nil, protocol.TransactionNotFound
+		}
+		if trx.TransactionValues != nil {
+			content = trx.GetLink()
+		}
+	case req.CheckoutID != "":
+		checkout := models.GetMerchantCheckoutByCheckoutID(req.CheckoutID)
+		if checkout == nil || checkout.Mid != req.Mid {
+			return nil, protocol.TransactionNotFound
+		}
+		content = checkout.GetCheckoutURL()
+	default:
+		return nil, protocol.InvalidParams
+	}
+	if content == "" {
+		return nil, protocol.QRCodeContentUnavailable
+	}
+	return s.Render(content, req.Format, req.Size)
+}
+
+// UpiIntent 生成 upi://pay 意图串，指定交易时金额与参考号取自交易
+func (s *QRCodeService) UpiIntent(req *protocol.UpiIntentRequest) (*protocol.UpiIntent, protocol.ErrorCode) {
+	intent := &utils.UpiIntent{
+		Vpa:       strings.TrimSpace(req.Vpa),
+		PayeeName: req.PayeeName,
+		Ref:       req.Ref,
+		Note:      req.Note,
+		Ccy:       protocol.CcyINR,
+	}
+	cashierID := ""
+	if req.TrxID != "" {
+		trx := models.GetTransactionByMidAndTrxID(req.Mid, req.TrxID, protocol.TrxTypePayin)
+		if trx == nil {
+			return nil, protocol.TransactionNotFound
+		}
+		if trx.Ccy != protocol.CcyINR {
+			return nil, protocol.InvalidCurrency
+		}
+		if trx.Amount != nil {
+			intent.Amount = trx.Amount.StringFixed(2)
+		}
+		if intent.Ref == "" {
+			intent.Ref = trx.TrxID
+		}
+		cashierID = trx.CashierID
+	} else if req.Amount != "" {
+		amount, err := utils.ValidateAmount(req.Amount)
+		if err != nil {
+			return nil, protocol.InvalidAmount
+		}
+		intent.Amount = amount.StringFixed(2)
+	}
+
+	if intent.Vpa == "" {
+		intent.Vpa, intent.PayeeName = s.resolveVpa(req.Mid, cashierID, intent.PayeeName)
+	}
+	if intent.Vpa == "" {
+		return nil, protocol.UpiVpaNotConfigured
+	}
+	if !utils.IsValidUpiVpa(intent.Vpa) {
+		return nil, protocol.UpiVpaInvalid
+	}
+
+	result := &protocol.UpiIntent{
+		Intent:    utils.BuildUpiIntent(intent),
+		Vpa:       intent.Vpa,
+		PayeeName: intent.PayeeName,
+		Amount:    intent.Amount,
+		Ccy:       intent.Ccy,
+		Ref:       intent.Ref,
+		Note:      intent.Note,
+	}
+	if req.QRCode {
+		qrCode, code := s.Render(result.Intent, req.Format, req.Size)
+		if code != protocol.Success {
+			return nil, code
+		}
+		result.QRCode = qrCode
+	}
+	return result, protocol.Success
+}
+
+// resolveVpa 依次取受理出纳员、商户代收配置中的VPA，请求已指定收款方名称时保留
+func (s *QRCodeService) resolveVpa(mid, cashierID, payeeName string) (string, string) {
+	vpa, name := "", ""
+	if cashierID != "" {
+		if cashier := models.GetCashierByCashierID(cashierID); cashier != nil && cashier.CashierValues != nil {
+			vpa, name = cashier.GetPayinVpa()
+		}
+	}
+	if vpa == "" {
+		trxConfig := GetConfigService().GetTrxConfigByMerchantID(mid, models.TrxTypeReceipt)
+		vpa, name = trxConfig.UpiVpa, trxConfig.UpiPayeeName
+	}
+	if payeeName != "" {
+		name = payeeName
+	}
+	return vpa, name
+}
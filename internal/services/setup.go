@@ -6,11 +6,42 @@ func InitializeMerchantServices() error {
 	GetMerchantPayinService()
 	GetMerchantPayoutService()
 	GetAccountService()
+	GetLedgerService()
 	GetCashierService()
 	GetCheckoutService()
 	GetMerchantTransactionService()
+	GetMerchantUserService()
+	GetMerchantApprovalService()
+	GetMerchantWithdrawService()
+	GetCashierMarginService()
+	GetTransactionExportService()
+	GetStatementService()
+	GetAdminAdjustmentService()
+	GetDisputeService()
+	GetFxRateService()
+	GetFxConversionService()
+	GetInternalTransferService()
+	GetMerchantReserveService()
+	GetPaymentProofService()
+	GetPaymentLinkService()
+	GetQRCodeService()
+	GetBeneficiaryService()
+	GetBankDirectoryService()
+	GetWebhookService()
+	GetWebhookSecretService()
+	GetWebhookEndpointService()
 
 	RegisterSettleTasks()
 	RegisterSummaryTasks()
+	RegisterExportTasks()
+	RegisterDisputeTasks()
+	RegisterFxTasks()
+	RegisterProofTasks()
+	RegisterWebhookTasks()
+	RegisterLedgerTasks()
+	RegisterStatementTasks()
+	RegisterWithdrawTasks()
+	RegisterCashierMarginTasks()
+	RegisterReserveTasks()
 	return nil
 }
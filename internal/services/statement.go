@@ -0,0 +1,663 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"html/template"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	statementMonthLayout = "2006-01"
+	statementDateLayout  = "2006-01-02"
+)
+
+// StatementService 账户对账单服务，按周期从资金流水实时生成，不落地文件
+type StatementService struct{}
+
+var (
+	statementService     *StatementService
+	statementServiceOnce sync.Once
+)
+
+func init() {
+	task.RegisterHandler(protocol.StatementDelivery, HandleStatementDelivery)
+}
+
+func SetupStatementService() {
+	statementServiceOnce.Do(func() {
+		statementService = &StatementService{}
+	})
+}
+
+// GetStatementService 获取对账单服务单例
+func GetStatementService() *StatementService {
+	if statementService == nil {
+		SetupStatementService()
+	}
+	return statementService
+}
+
+// StatementPeriod 对账单周期，时间区间为[Start, End)
+type StatementPeriod struct {
+	Start    int64
+	End      int64
+	Timezone string
+	Loc      *time.Location
+}
+
+// statementLine 对账单明细行，借贷方向按账户余额变动计算
+type statementLine struct {
+	Flow    *models.FundFlow
+	Debit   decimal.Decimal
+	Credit  decimal.Decimal
+	Balance decimal.Decimal // 变动后余额
+	Frozen  decimal.Decimal // 变动后冻结金额
+}
+
+// statementRenderer 对账单文件渲染接口，CSV与PDF共用
+type statementRenderer interface {
+	Begin(st *protocol.Statement) error
+	Movement(line *statementLine) error
+	End(st *protocol.Statement) error
+}
+
+// ResolvePeriod 解析对账单周期：自然月或日期区间，按指定时区计算起止时间
+func (s *StatementService) ResolvePeriod(req *protocol.StatementRequest) (*StatementPeriod, protocol.ErrorCode) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, protocol.InvalidParams
+	}
+	var start, end time.Time
+	switch {
+	case req.Month != "":
+		if start, err = time.ParseInLocation(statementMonthLayout, req.Month, loc); err != nil {
+			return nil, protocol.StatementPeriodInvalid
+		}
+		end = start.AddDate(0, 1, 0)
+	case req.StartDate != "" && req.EndDate != "":
+		if start, err = time.ParseInLocation(statementDateLayout, req.StartDate, loc); err != nil {
+			return nil, protocol.StatementPeriodInvalid
+		}
+		if end, err = time.ParseInLocation(statementDateLayout, req.EndDate, loc); err != nil {
+			return nil, protocol.StatementPeriodInvalid
+		}
+		end = end.AddDate(0, 0, 1) // 结束日期包含当天
+	default:
+		return nil, protocol.StatementPeriodInvalid
+	}
+	if !end.After(start) || start.After(time.Now()) {
+		return nil, protocol.StatementPeriodInvalid
+	}
+	if start.AddDate(0, 0, config.Get().Statement.MaxPeriodDays).Before(end) {
+		return nil, protocol.StatementPeriodInvalid
+	}
+	return &StatementPeriod{
+		Start:    start.UnixMilli(),
+		End:      end.UnixMilli(),
+		Timezone: timezone,
+		Loc:      loc,
+	}, protocol.Success
+}
+
+// Summary 获取对账单摘要：期初、期末余额及按业务类型汇总
+func (s *StatementService) Summary(userID, userType string, req *protocol.StatementRequest) (*protocol.Statement, protocol.ErrorCode) {
+	account, period, code := s.prepare(userID, userType, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	st, err := s.build(account, period, nil)
+	if err != nil {
+		log.Get().Errorf("Build statement for account %s failed: %v", account.AccountID, err)
+		return nil, protocol.DatabaseError
+	}
+	return st, protocol.Success
+}
+
+// GetDownloadLink 生成对账单限时签名下载链接
+func (s *StatementService) GetDownloadLink(userID, userType string, req *protocol.StatementRequest) (*protocol.StatementLink, protocol.ErrorCode) {
+	account, period, code := s.prepare(userID, userType, req)
+	if code != protocol.Success {
+		return nil, code
+	}
+	if code = s.checkSize(account, period); code != protocol.Success {
+		return nil, code
+	}
+	format := req.Format
+	if format == "" {
+		format = protocol.StatementFormatCSV
+	}
+	return s.signDownloadLink(account, period, format), protocol.Success
+}
+
+// ResolveDownload 校验签名下载参数，返回待生成对账单的账户和周期
+func (s *StatementService) ResolveDownload(req *protocol.StatementDownloadRequest) (*models.Account, *StatementPeriod, protocol.ErrorCode) {
+	expected := s.sign(req.AccountID, req.Start, req.End, req.Timezone, req.Format, req.Expires)
+	if !hmac.Equal([]byte(expected), []byte(req.Sign)) || req.Expires < time.Now().Unix() {
+		return nil, nil, protocol.StatementLinkInvalid
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, nil, protocol.StatementLinkInvalid
+	}
+	account, err := models.GetAccountByAccountID(req.AccountID)
+	if err != nil || account == nil {
+		return nil, nil, protocol.AccountErrorAccountNotFound
+	}
+	period := &StatementPeriod{Start: req.Start, End: req.End, Timezone: req.Timezone, Loc: loc}
+	if code := s.checkSize(account, period); code != protocol.Success {
+		return nil, nil, code
+	}
+	return account, period, protocol.Success
+}
+
+// FileName 对账单文件名
+func (s *StatementService) FileName(account *models.Account, period *StatementPeriod, format string) string {
+	start := time.UnixMilli(period.Start).In(period.Loc)
+	end := time.UnixMilli(period.End).In(period.Loc).AddDate(0, 0, -1)
+	return fmt.Sprintf("statement_%s_%s_%s.%s", account.Ccy, start.Format("20060102"), end.Format("20060102"), format)
+}
+
+// Write 生成对账单文件并写入w
+func (s *StatementService) Write(w io.Writer, account *models.Account, period *StatementPeriod, format string) error {
+	var renderer statementRenderer
+	if format == protocol.StatementFormatPDF {
+		pdf, err := utils.NewPDFWriter(w)
+		if err != nil {
+			return err
+		}
+		renderer = &pdfStatementRenderer{w: pdf, loc: period.Loc}
+	} else {
+		renderer = &csvStatementRenderer{w: csv.NewWriter(w), loc: period.Loc}
+	}
+	_, err := s.build(account, period, renderer)
+	return err
+}
+
+func (s *StatementService) prepare(userID, userType string, req *protocol.StatementRequest) (*models.Account, *StatementPeriod, protocol.ErrorCode) {
+	period, code := s.ResolvePeriod(req)
+	if code != protocol.Success {
+		return nil, nil, code
+	}
+	account, err := models.GetAccountByUserIDAndCurrency(userID, userType, req.Ccy)
+	if err != nil || account == nil {
+		return nil, nil, protocol.AccountErrorAccountNotFound
+	}
+	return account, period, protocol.Success
+}
+
+// checkSize 校验周期内流水条数不超过单份对账单上限
+func (s *StatementService) checkSize(account *models.Account, period *StatementPeriod) protocol.ErrorCode {
+	count, err := models.CountAccountFundFlowsBetween(models.ReadDB, account.AccountID, period.Start, period.End)
+	if err != nil {
+		log.Get().Errorf("Count statement flows for account %s failed: %v", account.AccountID, err)
+		return protocol.DatabaseError
+	}
+	if count > int64(config.Get().Statement.MaxRows) {
+		return protocol.StatementTooLarge
+	}
+	return protocol.Success
+}
+
+// build 按账户版本号顺序分批读取周期内流水，计算期初期末余额和分类汇总，renderer不为空时同时输出文件
+func (s *StatementService) build(account *models.Account, period *StatementPeriod, renderer statementRenderer) (*protocol.Statement, error) {
+	st := &protocol.Statement{
+		AccountID:   account.AccountID,
+		UserID:      account.UserID,
+		UserType:    account.UserType,
+		Ccy:         account.Ccy,
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+		Timezone:    period.Timezone,
+		Subtotals:   []*protocol.StatementSubtotal{},
+	}
+	batch := config.Get().Statement.BatchSize
+	subtotals := map[string]*protocol.StatementSubtotal{}
+	var (
+		lastVersion int64
+		lastID      uint64
+		balance     decimal.Decimal
+	)
+	for first := true; ; first = false {
+		flows, err := models.ListAccountFundFlowsBetween(models.ReadDB, account.AccountID, period.Start, period.End, lastVersion, lastID, batch)
+		if err != nil {
+			return nil, err
+		}
+		if first {
+			// 期初余额取周期内首条流水的变动前余额，周期内无流水时取周期前最后一条流水的变动后余额
+			if len(flows) > 0 {
+				if flows[0].BeforeAsset != nil {
+					balance = flows[0].BeforeAsset.Balance
+				}
+			} else if prev := models.GetLastAccountFundFlowBefore(models.ReadDB, account.AccountID, period.Start); prev != nil && prev.AfterAsset != nil {
+				balance = prev.AfterAsset.Balance
+			}
+			st.OpeningBalance = balance
+			if renderer != nil {
+				if err := renderer.Begin(st); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, flow := range flows {
+			line := &statementLine{Flow: flow, Balance: balance}
+			if flow.BeforeAsset != nil && flow.AfterAsset != nil {
+				change := flow.AfterAsset.Balance.Sub(flow.BeforeAsset.Balance)
+				if change.IsPositive() {
+					line.Credit = change
+				} else {
+					line.Debit = change.Neg()
+				}
+			}
+			if flow.AfterAsset != nil {
+				line.Balance = flow.AfterAsset.Balance
+				line.Frozen = flow.AfterAsset.FrozenBalance
+			}
+			balance = line.Balance
+
+			subtotal, ok := subtotals[flow.TrxType]
+			if !ok {
+				subtotal = &protocol.StatementSubtotal{TrxType: flow.TrxType}
+				subtotals[flow.TrxType] = subtotal
+			}
+			subtotal.Count++
+			subtotal.Credit = subtotal.Credit.Add(line.Credit)
+			subtotal.Debit = subtotal.Debit.Add(line.Debit)
+			st.TotalCredit = st.TotalCredit.Add(line.Credit)
+			st.TotalDebit = st.TotalDebit.Add(line.Debit)
+
+			if renderer != nil {
+				if err := renderer.Movement(line); err != nil {
+					return nil, err
+				}
+			}
+			lastVersion, lastID = flow.AccountVersion, flow.ID
+		}
+		st.Count += int64(len(flows))
+		if len(flows) < batch {
+			break
+		}
+	}
+	st.ClosingBalance = balance
+	for _, subtotal := range subtotals {
+		st.Subtotals = append(st.Subtotals, subtotal)
+	}
+	sort.Slice(st.Subtotals, func(i, j int) bool { return st.Subtotals[i].TrxType < st.Subtotals[j].TrxType })
+	if renderer != nil {
+		if err := renderer.End(st); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+func (s *StatementService) sign(accountID string, start, end int64, timezone, format string, expires int64) string {
+	payload := fmt.Sprintf("%s:%d:%d:%s:%s:%d", accountID, start, end, timezone, format, expires)
+	return utils.GetHmacSha256Hex(payload, config.Get().Statement.SignSecret)
+}
+
+func (s *StatementService) signDownloadLink(account *models.Account, period *StatementPeriod, format string) *protocol.StatementLink {
+	cfg := config.Get().Statement
+	expires := time.Now().Add(cfg.GetLinkExpire()).Unix()
+	params := url.Values{}
+	params.Set("account_id", account.AccountID)
+	params.Set("start", fmt.Sprintf("%d", period.Start))
+	params.Set("end", fmt.Sprintf("%d", period.End))
+	params.Set("timezone", period.Timezone)
+	params.Set("format", format)
+	params.Set("expires", fmt.Sprintf("%d", expires))
+	params.Set("sign", s.sign(account.AccountID, period.Start, period.End, period.Timezone, format, expires))
+	return &protocol.StatementLink{
+		FileName:  s.FileName(account, period, format),
+		URL:       fmt.Sprintf("%s?%s", cfg.DownloadURL, params.Encode()),
+		ExpiredAt: expires * 1000,
+	}
+}
+
+// GetSubscription 获取对账单邮件订阅，未配置时返回默认关闭状态
+func (s *StatementService) GetSubscription(userID, userType string) (*protocol.StatementSubscription, protocol.ErrorCode) {
+	subscription := models.GetStatementSubscription(models.ReadDB, userType, userID)
+	if subscription == nil {
+		subscription = &models.StatementSubscription{StatementSubscriptionValues: &models.StatementSubscriptionValues{}}
+		subscription.SetStatus(protocol.StatusInactive)
+	}
+	return subscription.Protocol(), protocol.Success
+}
+
+// UpdateSubscription 修改对账单邮件订阅
+func (s *StatementService) UpdateSubscription(userID, userType, operator string, req *protocol.StatementSubscriptionRequest) (*protocol.StatementSubscription, protocol.ErrorCode) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, protocol.InvalidParams
+	}
+	emails := req.Emails
+	if emails == nil {
+		emails = []string{}
+	}
+	formats := req.Formats
+	if len(formats) == 0 {
+		formats = []string{protocol.StatementFormatCSV}
+	}
+	values := &models.StatementSubscriptionValues{}
+	values.SetStatus(req.Status).
+		SetEmails(emails).
+		SetFormats(formats).
+		SetTimezone(timezone).
+		SetUpdatedBy(operator)
+
+	subscription := models.GetStatementSubscription(models.WriteDB, userType, userID)
+	if subscription == nil {
+		subscription = &models.StatementSubscription{UserID: userID, UserType: userType}
+		subscription.SetValues(values)
+		if err := models.WriteDB.Create(subscription).Error; err != nil {
+			log.Get().Errorf("Create statement subscription for %s %s failed: %v", userType, userID, err)
+			return nil, protocol.DatabaseError
+		}
+		return subscription.Protocol(), protocol.Success
+	}
+	if err := models.UpdateStatementSubscriptionValues(models.WriteDB, subscription, values); err != nil {
+		log.Get().Errorf("Update statement subscription for %s %s failed: %v", userType, userID, err)
+		return nil, protocol.DatabaseError
+	}
+	return subscription.Protocol(), protocol.Success
+}
+
+// Deliver 为启用订阅的用户发送上月对账单下载链接，按订阅时区判断月份，已发送的月份不重复发送，未开启邮件发送时跳过
+func (s *StatementService) Deliver(ctx context.Context) (int, error) {
+	if !config.Get().Statement.EmailEnabled {
+		return 0, nil
+	}
+	const batch = 100
+	var (
+		lastID uint64
+		sent   int
+	)
+	for {
+		list, err := models.ListActiveStatementSubscriptions(models.ReadDB, lastID, batch)
+		if err != nil {
+			return sent, err
+		}
+		for _, subscription := range list {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			lastID = subscription.ID
+			ok, err := s.deliver(subscription)
+			if err != nil {
+				log.Get().Errorf("Deliver statement to %s %s failed: %v", subscription.UserType, subscription.UserID, err)
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(list) < batch {
+			return sent, nil
+		}
+	}
+}
+
+func (s *StatementService) deliver(subscription *models.StatementSubscription) (bool, error) {
+	loc, err := time.LoadLocation(subscription.GetTimezone())
+	if err != nil {
+		return false, err
+	}
+	now := time.Now().In(loc)
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	start := end.AddDate(0, -1, 0)
+	month := start.Format(statementMonthLayout)
+	if subscription.GetLastPeriod() >= month {
+		return false, nil
+	}
+	recipients := subscription.GetEmails()
+	if len(recipients) == 0 {
+		if email := s.ownerEmail(subscription.UserID, subscription.UserType); email != "" {
+			recipients = []string{email}
+		}
+	}
+
+	period := &StatementPeriod{
+		Start:    start.UnixMilli(),
+		End:      end.UnixMilli(),
+		Timezone: subscription.GetTimezone(),
+		Loc:      loc,
+	}
+	var links []string
+	for _, account := range models.GetAccountsByUserID(subscription.UserID, subscription.UserType) {
+		if code := s.checkSize(account, period); code != protocol.Success {
+			log.Get().Warnf("Skip statement of account %s for %s: %s", account.AccountID, month, code)
+			continue
+		}
+		for _, format := range subscription.GetFormats() {
+			link := s.signDownloadLink(account, period, format)
+			links = append(links, fmt.Sprintf(`%s: <a href="%s">%s</a>`,
+				html.EscapeString(account.Ccy), html.EscapeString(link.URL), html.EscapeString(link.FileName)))
+		}
+	}
+
+	if len(links) > 0 {
+		for _, to := range recipients {
+			msg := &Message{
+				Type:     protocol.MsgTypeStatementReady,
+				To:       to,
+				Language: protocol.LangEnglish,
+				Params: map[string]any{
+					"to":           to,
+					"period":       month,
+					"links":        template.HTML(strings.Join(links, "<br/>")), // 已转义，模板中原样输出
+					"expire_hours": config.Get().Statement.LinkExpireHours,
+				},
+			}
+			if err := GetMessageService().SendEmailMessage(msg); err != nil {
+				log.Get().Errorf("Send statement email to %s failed: %v", to, err)
+			}
+		}
+	}
+
+	values := &models.StatementSubscriptionValues{}
+	values.SetLastPeriod(month)
+	if err := models.UpdateStatementSubscriptionValues(models.WriteDB, subscription, values); err != nil {
+		return false, err
+	}
+	return len(links) > 0 && len(recipients) > 0, nil
+}
+
+// ownerEmail 获取商户或出纳团队的账号邮箱
+func (s *StatementService) ownerEmail(userID, userType string) string {
+	switch userType {
+	case protocol.UserTypeMerchant:
+		if merchant := models.GetMerchantByMID(userID); merchant != nil {
+			return merchant.GetEmail()
+		}
+	case protocol.UserTypeCashierTeam:
+		if team := models.GetCashierTeamByTid(userID); team != nil {
+			return team.GetEmail()
+		}
+	}
+	return ""
+}
+
+// statementHeader 对账单明细列
+var statementHeader = []string{"Time", "Flow No", "Trx Type", "Trx ID", "Debit", "Credit", "Balance", "Frozen", "Remark"}
+
+// csvStatementRenderer CSV对账单：账户信息、期初余额、明细、分类汇总、期末余额依次输出
+type csvStatementRenderer struct {
+	w   *csv.Writer
+	loc *time.Location
+}
+
+func (r *csvStatementRenderer) Begin(st *protocol.Statement) error {
+	rows := [][]string{
+		{"Account Statement"},
+		{"Account ID", st.AccountID},
+		{"Account Holder", fmt.Sprintf("%s %s", st.UserType, st.UserID)},
+		{"Currency", st.Ccy},
+		{"Period", formatStatementPeriod(st, r.loc)},
+		{"Opening Balance", st.OpeningBalance.String()},
+		{},
+		statementHeader,
+	}
+	return r.w.WriteAll(rows)
+}
+
+func (r *csvStatementRenderer) Movement(line *statementLine) error {
+	return r.w.Write([]string{
+		formatExportTime(line.Flow.CreatedAt, r.loc),
+		line.Flow.FlowNo,
+		line.Flow.TrxType,
+		line.Flow.TrxID,
+		formatStatementAmount(line.Debit),
+		formatStatementAmount(line.Credit),
+		line.Balance.String(),
+		line.Frozen.String(),
+		line.Flow.Remark,
+	})
+}
+
+func (r *csvStatementRenderer) End(st *protocol.Statement) error {
+	rows := [][]string{{}, {"Subtotals"}, {"Trx Type", "Count", "Debit", "Credit"}}
+	for _, subtotal := range st.Subtotals {
+		rows = append(rows, []string{subtotal.TrxType, fmt.Sprintf("%d", subtotal.Count), subtotal.Debit.String(), subtotal.Credit.String()})
+	}
+	rows = append(rows,
+		[]string{"Total", fmt.Sprintf("%d", st.Count), st.TotalDebit.String(), st.TotalCredit.String()},
+		[]string{},
+		[]string{"Closing Balance", st.ClosingBalance.String()},
+	)
+	return r.w.WriteAll(rows)
+}
+
+// pdfStatementRenderer PDF对账单，按固定列宽输出文本表格
+type pdfStatementRenderer struct {
+	w   *utils.PDFWriter
+	loc *time.Location
+}
+
+const pdfStatementRowFormat = "%-19s %-24s %-14s %-28s %16s %16s %18s %s"
+
+func (r *pdfStatementRenderer) Begin(st *protocol.Statement) error {
+	lines := []string{
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Account ID:      %s", st.AccountID),
+		fmt.Sprintf("Account Holder:  %s %s", st.UserType, st.UserID),
+		fmt.Sprintf("Currency:        %s", st.Ccy),
+		fmt.Sprintf("Period:          %s", formatStatementPeriod(st, r.loc)),
+		fmt.Sprintf("Opening Balance: %s", st.OpeningBalance.String()),
+		"",
+		fmt.Sprintf(pdfStatementRowFormat, "Time", "Flow No", "Trx Type", "Trx ID", "Debit", "Credit", "Balance", "Remark"),
+		strings.Repeat("-", utils.PDFCharsPerLine),
+	}
+	return r.writeLines(lines)
+}
+
+func (r *pdfStatementRenderer) Movement(line *statementLine) error {
+	return r.w.WriteLine(fmt.Sprintf(pdfStatementRowFormat,
+		formatExportTime(line.Flow.CreatedAt, r.loc),
+		line.Flow.FlowNo,
+		line.Flow.TrxType,
+		line.Flow.TrxID,
+		formatStatementAmount(line.Debit),
+		formatStatementAmount(line.Credit),
+		line.Balance.String(),
+		line.Flow.Remark,
+	))
+}
+
+func (r *pdfStatementRenderer) End(st *protocol.Statement) error {
+	lines := []string{
+		strings.Repeat("-", utils.PDFCharsPerLine),
+		"",
+		"SUBTOTALS",
+		fmt.Sprintf("%-20s %10s %20s %20s", "Trx Type", "Count", "Debit", "Credit"),
+	}
+	for _, subtotal := range st.Subtotals {
+		lines = append(lines, fmt.Sprintf("%-20s %10d %20s %20s", subtotal.TrxType, subtotal.Count, subtotal.Debit.String(), subtotal.Credit.String()))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%-20s %10d %20s %20s", "Total", st.Count, st.TotalDebit.String(), st.TotalCredit.String()),
+		"",
+		fmt.Sprintf("Closing Balance: %s", st.ClosingBalance.String()),
+	)
+	if err := r.writeLines(lines); err != nil {
+		return err
+	}
+	return r.w.Close()
+}
+
+func (r *pdfStatementRenderer) writeLines(lines []string) error {
+	for _, line := range lines {
+		if err := r.w.WriteLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatStatementPeriod(st *protocol.Statement, loc *time.Location) string {
+	start := time.UnixMilli(st.PeriodStart).In(loc)
+	end := time.UnixMilli(st.PeriodEnd).In(loc).AddDate(0, 0, -1)
+	return fmt.Sprintf("%s to %s (%s)", start.Format(statementDateLayout), end.Format(statementDateLayout), st.Timezone)
+}
+
+// formatStatementAmount 金额为零时留空，便于区分借贷列
+func formatStatementAmount(amount decimal.Decimal) string {
+	if amount.IsZero() {
+		return ""
+	}
+	return amount.String()
+}
+
+// RegisterStatementTasks 注册对账单任务
+func RegisterStatementTasks() {
+	log.Get().Info("注册对账单任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "statement_delivery",
+			Type:       protocol.StatementDelivery,
+			HandlerKey: protocol.StatementDelivery,
+			Name:       "对账单邮件发送",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"5 * * * *"}[0], // 每小时执行，按订阅时区在月初发送上月对账单
+				Timeout: &[]int{1800}[0],           // 30分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("对账单任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleStatementDelivery 发送上月对账单邮件
+func HandleStatementDelivery(ctx context.Context, params protocol.MapData) error {
+	sent, err := GetStatementService().Deliver(ctx)
+	if err != nil {
+		return fmt.Errorf("发送对账单邮件失败: %v", err)
+	}
+	log.Get().Infof("对账单邮件发送完成，共 %d 个用户", sent)
+	return nil
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"encoding/csv"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// exportTimeLayout 导出文件中时间列格式
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+// exportColumnValue 导出列取值函数
+type exportColumnValue func(trx *protocol.Transaction, loc *time.Location) string
+
+// exportColumns 可选导出列
+var exportColumns = map[string]exportColumnValue{
+	"trx_id":             func(t *protocol.Transaction, _ *time.Location) string { return t.TrxID },
+	"req_id":             func(t *protocol.Transaction, _ *time.Location) string { return t.ReqID },
+	"trx_type":           func(t *protocol.Transaction, _ *time.Location) string { return t.TrxType },
+	"mid":                func(t *protocol.Transaction, _ *time.Location) string { return t.Mid },
+	"tid":                func(t *protocol.Transaction, _ *time.Location) string { return t.Tid },
+	"cashier_id":         func(t *protocol.Transaction, _ *time.Location) string { return t.CashierID },
+	"trx_method":         func(t *protocol.Transaction, _ *time.Location) string { return t.TrxMethod },
+	"trx_mode":           func(t *protocol.Transaction, _ *time.Location) string { return t.TrxMode },
+	"ccy":                func(t *protocol.Transaction, _ *time.Location) string { return t.Ccy },
+	"amount":             func(t *protocol.Transaction, _ *time.Location) string { return t.Amount },
+	"usd_amount":         func(t *protocol.Transaction, _ *time.Location) string { return t.UsdAmount },
+	"fee_ccy":            func(t *protocol.Transaction, _ *time.Location) string { return t.FeeCcy },
+	"fee_amount":         func(t *protocol.Transaction, _ *time.Location) string { return t.FeeAmount },
+	"status":             func(t *protocol.Transaction, _ *time.Location) string { return t.Status },
+	"res_code":           func(t *protocol.Transaction, _ *time.Location) string { return t.ResCode },
+	"res_msg":            func(t *protocol.Transaction, _ *time.Location) string { return t.ResMsg },
+	"reason":             func(t *protocol.Transaction, _ *time.Location) string { return t.Reason },
+	"flow_no":            func(t *protocol.Transaction, _ *time.Location) string { return t.FlowNo },
+	"channel_code":       func(t *protocol.Transaction, _ *time.Location) string { return t.ChannelCode },
+	"channel_trx_id":     func(t *protocol.Transaction, _ *time.Location) string { return t.ChannelTrxID },
+	"channel_fee_amount": func(t *protocol.Transaction, _ *time.Location) string { return t.ChannelFeeAmount },
+	"account_no":         func(t *protocol.Transaction, _ *time.Location) string { return t.AccountNo },
+	"account_name":       func(t *protocol.Transaction, _ *time.Location) string { return t.AccountName },
+	"bank_code":          func(t *protocol.Transaction, _ *time.Location) string { return t.BankCode },
+	"bank_name":          func(t *protocol.Transaction, _ *time.Location) string { return t.BankName },
+	"settle_status":      func(t *protocol.Transaction, _ *time.Location) string { return t.SettleStatus },
+	"settle_id":          func(t *protocol.Transaction, _ *time.Location) string { return t.SettleID },
+	"refunded_amount":    func(t *protocol.Transaction, _ *time.Location) string { return t.RefundedAmount },
+	"remark":             func(t *protocol.Transaction, _ *time.Location) string { return t.Remark },
+	"created_at":         func(t *protocol.Transaction, loc *time.Location) string { return formatExportTime(t.CreatedAt, loc) },
+	"completed_at":       func(t *protocol.Transaction, loc *time.Location) string { return formatExportTime(t.CompletedAt, loc) },
+	"settled_at":         func(t *protocol.Transaction, loc *time.Location) string { return formatExportTime(t.SettledAt, loc) },
+	"updated_at":         func(t *protocol.Transaction, loc *time.Location) string { return formatExportTime(t.UpdatedAt, loc) },
+}
+
+// defaultExportColumns 未指定导出列时使用的默认列
+var defaultExportColumns = []string{
+	"trx_id", "req_id", "trx_type", "trx_method", "ccy", "amount", "fee_amount",
+	"status", "channel_trx_id", "flow_no", "created_at", "completed_at",
+}
+
+func formatExportTime(ms int64, loc *time.Location) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).In(loc).Format(exportTimeLayout)
+}
+
+// exportRowWriter 导出文件行写入接口，CSV与XLSX共用
+type exportRowWriter interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(cells []string) error {
+	return c.w.Write(cells)
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// TransactionExportService 交易异步导出服务
+type TransactionExportService struct{}
+
+var (
+	transactionExportService     *TransactionExportService
+	transactionExportServiceOnce sync.Once
+)
+
+func init() {
+	task.RegisterHandler(protocol.TransactionExportProcess, HandleTransactionExportProcess)
+	task.RegisterHandler(protocol.TransactionExportCleanup, HandleTransactionExportCleanup)
+}
+
+func SetupTransactionExportService() {
+	transactionExportServiceOnce.Do(func() {
+		transactionExportService = &TransactionExportService{}
+	})
+}
+
+// GetTransactionExportService 获取交易导出服务单例
+func GetTransactionExportService() *TransactionExportService {
+	if transactionExportService == nil {
+		SetupTransactionExportService()
+	}
+	return transactionExportService
+}
+
+// ExportOwner 导出任务发起人
+type ExportOwner struct {
+	UserID   string
+	UserType string
+	Email    string // 完成通知邮箱，为空则不发送
+	Lang     string
+}
+
+// NewExportTrxQuery 将导出请求转换为交易查询条件
+func NewExportTrxQuery(req *protocol.TransactionExportRequest) *models.TrxQuery {
+	return &models.TrxQuery{
+		Mid:              req.Mid,
+		Tid:              req.Tid,
+		TrxType:          req.TrxType,
+		TrxID:            req.TrxID,
+		ReqID:            req.ReqID,
+		TrxMethod:        req.TrxMethod,
+		TrxMode:          req.TrxMode,
+		Status:           req.Status,
+		StatusList:       req.StatusList,
+		FlowNo:           req.FlowNo,
+		ChannelCode:      req.ChannelCode,
+		ChannelAccount:   req.ChannelAccount,
+		ChannelGroup:     req.ChannelGroup,
+		ChannelTrxID:     req.ChannelTrxID,
+		SettleStatus:     req.SettleStatus,
+		CreatedAtStart:   req.CreatedAtStart,
+		CreatedAtEnd:     req.CreatedAtEnd,
+		CompletedAtStart: req.CompletedAtStart,
+		CompletedAtEnd:   req.CompletedAtEnd,
+		SettledAtStart:   req.SettledAtStart,
+		SettledAtEnd:     req.SettledAtEnd,
+	}
+}
+
+// Create 创建导出任务，文件由后台异步生成
+func (s *TransactionExportService) Create(owner *ExportOwner, query *models.TrxQuery, req *protocol.TransactionExportRequest) (*protocol.ExportJob, protocol.ErrorCode) {
+	if _, ok := models.TrxTypeTableMap[query.TrxType]; !ok {
+		return nil, protocol.InvalidParams
+	}
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, protocol.InvalidParams
+	}
+	columns := req.Columns
+	if len(columns) == 0 {
+		columns = defaultExportColumns
+	}
+	for _, column := range columns {
+		if _, ok := exportColumns[column]; !ok {
+			return nil, protocol.InvalidParams
+		}
+	}
+
+	job := &models.ExportJob{
+		JobID:           utils.GenerateExportJobID(),
+		UserID:          owner.UserID,
+		UserType:        owner.UserType,
+		TrxType:         query.TrxType,
+		Format:          req.Format,
+		Columns:         columns,
+		Timezone:        timezone,
+		Query:           query,
+		Email:           owner.Email,
+		Lang:            owner.Lang,
+		ExportJobValues: &models.ExportJobValues{},
+	}
+	job.SetStatus(protocol.StatusPending)
+	if err := models.WriteDB.Create(job).Error; err != nil {
+		log.Get().Errorf("Create export job failed: %v", err)
+		return nil, protocol.DatabaseError
+	}
+
+	go s.Process(job)
+	return job.Protocol(), protocol.Success
+}
+
+// List 导出任务列表
+func (s *TransactionExportService) List(owner *ExportOwner, req *protocol.ExportJobListRequest) ([]*protocol.ExportJob, int64, protocol.ErrorCode) {
+	jobs, total, err := models.ListExportJobs(owner.UserID, owner.UserType, req.Status, req.Page, req.Size)
+	if err != nil {
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.ExportJob, 0, len(jobs))
+	for _, job := range jobs {
+		list = append(list, job.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// GetDownloadLink 为已完成的导出任务生成限时签名下载链接
+func (s *TransactionExportService) GetDownloadLink(owner *ExportOwner, jobID string) (*protocol.ExportDownloadLink, protocol.ErrorCode) {
+	job := models.GetExportJob(owner.UserID, owner.UserType, jobID)
+	if job == nil {
+		return nil, protocol.ExportJobNotFound
+	}
+	switch job.GetStatus() {
+	case protocol.StatusSuccess:
+	case protocol.StatusExpired:
+		return nil, protocol.ExportLinkExpired
+	default:
+		return nil, protocol.ExportJobNotReady
+	}
+	return s.signDownloadLink(job), protocol.Success
+}
+
+// ResolveDownload 校验签名下载参数，返回待下载的导出任务
+func (s *TransactionExportService) ResolveDownload(req *protocol.ExportDownloadRequest) (*models.ExportJob, protocol.ErrorCode) {
+	expected := s.sign(req.JobID, req.Expires)
+	if !hmac.Equal([]byte(expected), []byte(req.Sign)) {
+		return nil, protocol.ExportLinkInvalid
+	}
+	if req.Expires < time.Now().Unix() {
+		return nil, protocol.ExportLinkExpired
+	}
+	job := models.GetExportJob("", "", req.JobID)
+	if job == nil {
+		return nil, protocol.ExportJobNotFound
+	}
+	if job.GetStatus() != protocol.StatusSuccess {
+		return nil, protocol.ExportLinkExpired
+	}
+	if _, err := os.Stat(job.GetFilePath()); err != nil {
+		return nil, protocol.ExportLinkExpired
+	}
+	return job, protocol.Success
+}
+
+func (s *TransactionExportService) sign(jobID string, expires int64) string {
+	return utils.GetHmacSha256Hex(fmt.Sprintf("%s:%d", jobID, expires), config.Get().Export.SignSecret)
+}
+
+func (s *TransactionExportService) signDownloadLink(job *models.ExportJob) *protocol.ExportDownloadLink {
+	cfg := config.Get().Export
+	expires := time.Now().Add(time.Duration(cfg.LinkExpireMinutes) * time.Minute).Unix()
+	params := url.Values{}
+	params.Set("job_id", job.JobID)
+	params.Set("expires", fmt.Sprintf("%d", expires))
+	params.Set("sign", s.sign(job.JobID, expires))
+	return &protocol.ExportDownloadLink{
+		JobID:     job.JobID,
+		FileName:  job.GetFileName(),
+		URL:       fmt.Sprintf("%s?%s", cfg.DownloadURL, params.Encode()),
+		ExpiredAt: expires * 1000,
+	}
+}
+
+// Process 执行导出任务：抢占任务后分批读取交易并写入文件
+func (s *TransactionExportService) Process(job *models.ExportJob) {
+	started := &models.ExportJobValues{}
+	started.SetStatus(protocol.StatusProcessing).SetStartedAt(utils.TimeNowMilli())
+	ok, err := models.UpdateExportJobStatus(job, protocol.StatusPending, started)
+	if err != nil {
+		log.Get().Errorf("Claim export job %s failed: %v", job.JobID, err)
+		return
+	}
+	if !ok {
+		return // 已被其他实例处理
+	}
+
+	values := &models.ExportJobValues{}
+	filePath, fileName, rows, err := s.writeFile(job)
+	if err != nil {
+		log.Get().Errorf("Export job %s failed: %v", job.JobID, err)
+		_ = os.Remove(filePath)
+		values.SetStatus(protocol.StatusFailed).
+			SetError(err.Error()).
+			SetRowCount(rows).
+			SetCompletedAt(utils.TimeNowMilli())
+		if _err := models.SaveExportJobValues(models.WriteDB, job, values); _err != nil {
+			log.Get().Errorf("Save export job %s failed: %v", job.JobID, _err)
+		}
+		return
+	}
+
+	var size int64
+	if info, _err := os.Stat(filePath); _err == nil {
+		size = info.Size()
+	}
+	now := time.Now()
+	values.SetStatus(protocol.StatusSuccess).
+		SetFileName(fileName).
+		SetFilePath(filePath).
+		SetFileSize(size).
+		SetRowCount(rows).
+		SetCompletedAt(now.UnixMilli()).
+		SetExpiredAt(now.AddDate(0, 0, config.Get().Export.RetentionDays).UnixMilli())
+	if err := models.SaveExportJobValues(models.WriteDB, job, values); err != nil {
+		log.Get().Errorf("Save export job %s failed: %v", job.JobID, err)
+		return
+	}
+	s.notify(job)
+}
+
+// writeFile 按ID游标分批读取交易并写入导出文件，返回文件路径、文件名和行数
+func (s *TransactionExportService) writeFile(job *models.ExportJob) (filePath, fileName string, rows int64, err error) {
+	cfg := config.Get().Export
+	loc, err := time.LoadLocation(job.Timezone)
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(cfg.StorageDir, time.Now().Format("20060102"))
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		return
+	}
+	filePath = filepath.Join(dir, fmt.Sprintf("%s.%s", job.JobID, job.Format))
+	fileName = fmt.Sprintf("transactions_%s_%s.%s", job.TrxType, time.Now().In(loc).Format("20060102150405"), job.Format)
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	buf := bufio.NewWriter(file)
+
+	var writer exportRowWriter
+	if job.Format == protocol.ExportFormatXLSX {
+		if writer, err = utils.NewXLSXWriter(buf, job.TrxType); err != nil {
+			return
+		}
+	} else {
+		writer = &csvRowWriter{w: csv.NewWriter(buf)}
+	}
+	if err = writer.WriteRow(job.Columns); err != nil {
+		return
+	}
+
+	var lastID int64
+	row := make([]string, len(job.Columns))
+	for {
+		var batch []*models.Transaction
+		batch, err = models.ListTransactionBatchByQuery(job.Query, lastID, cfg.BatchSize)
+		if err != nil {
+			return
+		}
+		for _, trx := range batch {
+			info := trx.Protocol()
+			for i, column := range job.Columns {
+				row[i] = exportColumns[column](info, loc)
+			}
+			if err = writer.WriteRow(row); err != nil {
+				return
+			}
+			lastID = trx.ID
+		}
+		rows += int64(len(batch))
+		if rows > int64(cfg.MaxRows) {
+			err = fmt.Errorf("export exceeds %d rows, please narrow the filters", cfg.MaxRows)
+			return
+		}
+		if len(batch) < cfg.BatchSize {
+			break
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return
+	}
+	err = buf.Flush()
+	return
+}
+
+// notify 发送导出完成邮件
+func (s *TransactionExportService) notify(job *models.ExportJob) {
+	if job.Email == "" {
+		return
+	}
+	link := s.signDownloadLink(job)
+	msg := &Message{
+		Type:     protocol.MsgTypeExportReady,
+		To:       job.Email,
+		Language: job.Lang,
+		Params: map[string]any{
+			"to":             job.Email,
+			"file_name":      job.GetFileName(),
+			"row_count":      job.GetRowCount(),
+			"url":            link.URL,
+			"expire_minutes": config.Get().Export.LinkExpireMinutes,
+		},
+	}
+	if err := GetMessageService().SendEmailMessage(msg); err != nil {
+		log.Get().Errorf("Send export ready email for job %s failed: %v", job.JobID, err)
+	}
+}
+
+// cleanup 删除过期的导出文件
+func (s *TransactionExportService) cleanup() (int, error) {
+	jobs, err := models.ListExpiredExportJobs(utils.TimeNowMilli(), 500)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range jobs {
+		if err := os.Remove(job.GetFilePath()); err != nil && !os.IsNotExist(err) {
+			log.Get().Errorf("Remove export file %s failed: %v", job.GetFilePath(), err)
+			continue
+		}
+		values := &models.ExportJobValues{}
+		values.SetStatus(protocol.StatusExpired)
+		if _, err := models.UpdateExportJobStatus(job, protocol.StatusSuccess, values); err != nil {
+			log.Get().Errorf("Expire export job %s failed: %v", job.JobID, err)
+		}
+	}
+	return len(jobs), nil
+}
+
+// RegisterExportTasks 注册导出相关定时任务
+func RegisterExportTasks() {
+	log.Get().Info("注册导出任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "transaction_export_process",
+			Type:       protocol.TransactionExportProcess,
+			HandlerKey: protocol.TransactionExportProcess,
+			Name:       "交易导出补偿处理",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 1m"}[0], // 每分钟执行一次
+				Timeout: &[]int{1800}[0],          // 30分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+		{
+			TaskID:     "transaction_export_cleanup",
+			Type:       protocol.TransactionExportCleanup,
+			HandlerKey: protocol.TransactionExportCleanup,
+			Name:       "过期导出文件清理",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"0 3 * * *"}[0], // 每天凌晨3点执行
+				Timeout: &[]int{600}[0],            // 10分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("导出任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleTransactionExportProcess 处理创建后未被执行的导出任务（如服务重启导致）
+func HandleTransactionExportProcess(ctx context.Context, params protocol.MapData) error {
+	before := time.Now().Add(-time.Minute).UnixMilli()
+	jobs, err := models.ListExportJobsByStatus(protocol.StatusPending, before, 10)
+	if err != nil {
+		return fmt.Errorf("查询待处理导出任务失败: %v", err)
+	}
+	service := GetTransactionExportService()
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		service.Process(job)
+	}
+
+	// 长时间处于处理中的任务视为中断，置为失败以便用户重新发起
+	stale, err := models.ListExportJobsByStatus(protocol.StatusProcessing, time.Now().Add(-time.Hour).UnixMilli(), 100)
+	if err != nil {
+		return fmt.Errorf("查询中断导出任务失败: %v", err)
+	}
+	for _, job := range stale {
+		values := &models.ExportJobValues{}
+		values.SetStatus(protocol.StatusFailed).
+			SetError("export interrupted").
+			SetCompletedAt(utils.TimeNowMilli())
+		if _, err := models.UpdateExportJobStatus(job, protocol.StatusProcessing, values); err != nil {
+			log.Get().Errorf("Fail stale export job %s failed: %v", job.JobID, err)
+		}
+	}
+	return nil
+}
+
+// HandleTransactionExportCleanup 清理过期导出文件
+func HandleTransactionExportCleanup(ctx context.Context, params protocol.MapData) error {
+	count, err := GetTransactionExportService().cleanup()
+	if err != nil {
+		return fmt.Errorf("清理过期导出文件失败: %v", err)
+	}
+	log.Get().Infof("清理过期导出文件完成，共 %d 个", count)
+	return nil
+}
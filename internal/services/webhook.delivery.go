@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// ListDeliveries 分页查询通知接收方（商户或收银团队）的通知推送记录
+func (s *WebhookService) ListDeliveries(userType, userID string, req *protocol.WebhookListRequest) ([]*protocol.WebhookDelivery, int64, protocol.ErrorCode) {
+	webhooks, total, err := models.ListWebhookByQuery(&models.WebhookQuery{
+		UserType:       userType,
+		UserID:         userID,
+		WebhookID:      req.WebhookID,
+		TrxID:          req.TrxID,
+		Type:           req.Event,
+		NotifyStatus:   req.NotifyStatus,
+		CreatedAtStart: req.CreatedAtStart,
+		CreatedAtEnd:   req.CreatedAtEnd,
+		Page:           req.Page,
+		Size:           req.Size,
+		EndpointID:     req.EndpointID,
+	})
+	if err != nil {
+		log.Get().Errorf("List webhooks for %s %s failed: %v", userType, userID, err)
+		return nil, 0, protocol.DatabaseError
+	}
+	list := make([]*protocol.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		list = append(list, webhook.Protocol())
+	}
+	return list, total, protocol.Success
+}
+
+// GetDelivery 获取通知推送详情，包含每次推送的请求头与接收方响应
+func (s *WebhookService) GetDelivery(userType, userID, webhookID string) (*protocol.WebhookDelivery, protocol.ErrorCode) {
+	webhook := models.GetUserWebhook(userType, userID, webhookID)
+	if webhook == nil {
+		return nil, protocol.WebhookNotFound
+	}
+	attempts, err := models.ListWebhookAttempts(webhook.WebhookID)
+	if err != nil {
+		log.Get().Errorf("List attempts of webhook %s failed: %v", webhookID, err)
+		return nil, protocol.DatabaseError
+	}
+	delivery := webhook.Protocol()
+	delivery.Attempts = make([]*protocol.WebhookAttemptLog, 0, len(attempts))
+	for _, attempt := range attempts {
+		delivery.Attempts = append(delivery.Attempts, attempt.Protocol())
+	}
+	return delivery, protocol.Success
+}
+
+// Resend 手动重推通知：已结束的通知重新置为待推送并增加一次推送机会，仍在重试中的通知提前到当前时间推送
+func (s *WebhookService) Resend(userType, userID string, req *protocol.WebhookResendRequest) (*protocol.WebhookResendResult, protocol.ErrorCode) {
+	ids := slices.Compact(slices.Sorted(slices.Values(req.WebhookIDs)))
+	result := &protocol.WebhookResendResult{}
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		webhooks, err := models.ListUserWebhooksByIDs(tx, userType, userID, ids)
+		if err != nil {
+			return err
+		}
+		now := utils.TimeNowMilli()
+		found := make(map[string]bool, len(webhooks))
+		for _, webhook := range webhooks {
+			found[webhook.WebhookID] = true
+			values := &models.WebhookValues{}
+			values.SetNotifyStatus(protocol.StatusPending).
+				SetNextNotifyAt(now).
+				SetRemark("manual resend by " + userID)
+			if webhook.GetNotifyStatus() != protocol.StatusPending {
+				values.SetMaxRetryTimes(webhook.GetNotifyTimes() + 1)
+			}
+			if err := models.UpdateWebhookValues(tx, webhook, values); err != nil {
+				return err
+			}
+		}
+		result.Queued = len(webhooks)
+		for _, id := range ids {
+			if !found[id] {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Get().Errorf("Resend webhooks for %s %s failed: %v", userType, userID, err)
+		return nil, protocol.DatabaseError
+	}
+	log.Get().Infof("%s %s resend webhooks: queued=%d, not_found=%d", userType, userID, result.Queued, len(result.NotFound))
+	return result, protocol.Success
+}
+
+// Ping 向通知地址发送一条签名的测试通知，同步返回推送结果（不含响应体），不落库也不重试，endpointID为空时发送到默认通知地址
+func (s *WebhookService) Ping(ctx context.Context, userType, userID, endpointID string) (*protocol.WebhookPingResult, protocol.ErrorCode) {
+	receiver, code := getWebhookReceiver(userType, userID)
+	if code != protocol.Success {
+		return nil, code
+	}
+	webhook := newWebhook(userType, userID, receiver.NotifyURL)
+	webhook.SetType(protocol.WebhookTypePing).
+		SetAPIVersion(protocol.WebhookAPIVersionV1)
+	if endpointID != "" {
+		endpoint := models.GetUserWebhookEndpoint(models.ReadDB, userType, userID, endpointID)
+		if endpoint == nil {
+			return nil, protocol.WebhookEndpointNotFound
+		}
+		webhook.SetNotifyURL(endpoint.GetURL()).
+			SetEndpointID(endpointID).
+			SetAPIVersion(endpoint.GetAPIVersion())
+	}
+	if utils.ValidatePublicURL(webhook.GetNotifyURL()) != nil {
+		return nil, protocol.InvalidWebhookURL
+	}
+	now := utils.TimeNowMilli()
+	var payload string
+	if userType == protocol.UserTypeCashierTeam {
+		payload = utils.ToJsonString(&protocol.CashierTeamNotify{
+			WebhookID: webhook.WebhookID,
+			Event:     protocol.WebhookTypePing,
+			Tid:       userID,
+			CreatedAt: now,
+		})
+	} else {
+		payload = renderWebhookPayload(webhook, &protocol.WebhookNotify{
+			Event:     protocol.WebhookTypePing,
+			Mid:       userID,
+			CreatedAt: now,
+		})
+	}
+	attempt := s.send(ctx, &webhookRequest{
+		WebhookID:  webhook.WebhookID,
+		Event:      protocol.WebhookTypePing,
+		UserType:   userType,
+		UserID:     userID,
+		EndpointID: endpointID,
+		APIVersion: webhook.GetAPIVersion(),
+		NotifyURL:  webhook.GetNotifyURL(),
+		Payload:    payload,
+		Attempt:    1,
+	})
+	attempt.CreatedAt = now
+	// 只返回状态码与是否成功，不回显接收方响应体，避免被用来读取任意地址的内容
+	attempt.ResponseBody = ""
+	return &protocol.WebhookPingResult{
+		WebhookID:   webhook.WebhookID,
+		RequestBody: payload,
+		Result:      attempt.Protocol(),
+	}, protocol.Success
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEndpointService 通知地址配置服务，商户可按事件类型配置多个通知地址，与默认通知地址并行推送
+type WebhookEndpointService struct{}
+
+var (
+	webhookEndpointService     *WebhookEndpointService
+	webhookEndpointServiceOnce sync.Once
+)
+
+const maxWebhookEndpoints = 10 // 每个商户最多配置的通知地址数，含已停用
+
+func SetupWebhookEndpointService() {
+	webhookEndpointServiceOnce.Do(func() {
+		webhookEndpointService = &WebhookEndpointService{}
+	})
+}
+
+// GetWebhookEndpointService 获取通知地址配置服务单例
+func GetWebhookEndpointService() *WebhookEndpointService {
+	if webhookEndpointService == nil {
+		SetupWebhookEndpointService()
+	}
+	return webhookEndpointService
+}
+
+// List 获取商户的全部通知地址
+func (s *WebhookEndpointService) List(mid string) ([]*protocol.WebhookEndpoint, protocol.ErrorCode) {
+	endpoints, err := models.ListUserWebhookEndpoints(models.ReadDB, protocol.UserTypeMerchant, mid, "")
+	if err != nil {
+		log.Get().Errorf("List webhook endpoints for merchant %s failed: %v", mid, err)
+		return nil, protocol.DatabaseError
+	}
+	list := make([]*protocol.WebhookEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		list = append(list, endpoint.Protocol())
+	}
+	return list, protocol.Success
+}
+
+// Create 创建通知地址并生成其签名密钥
+func (s *WebhookEndpointService) Create(mid string, req *protocol.CreateWebhookEndpointRequest) (*protocol.WebhookEndpoint, protocol.ErrorCode) {
+	if utils.ValidatePublicURL(req.URL) != nil {
+		return nil, protocol.InvalidWebhookURL
+	}
+	endpoint := &models.WebhookEndpoint{
+		EndpointID:            utils.GenerateWebhookEndpointID(),
+		UserID:                mid,
+		UserType:              protocol.UserTypeMerchant,
+		WebhookEndpointValues: &models.WebhookEndpointValues{},
+	}
+	version := req.APIVersion
+	if version == "" {
+		version = protocol.WebhookAPIVersionV1
+	}
+	events := req.Events
+	if events == nil {
+		events = []string{}
+	}
+	endpoint.SetURL(req.URL).
+		SetEvents(events).
+		SetAPIVersion(version).
+		SetStatus(protocol.StatusActive).
+		SetDescription(req.Description).
+		SetUpdatedBy(mid)
+
+	code := protocol.Success
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		count, err := models.CountUserWebhookEndpoints(tx, protocol.UserTypeMerchant, mid)
+		if err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		if count >= maxWebhookEndpoints {
+			code = protocol.WebhookEndpointLimitExceeded
+			return protocol.NewServiceError(code, "webhook endpoint limit exceeded")
+		}
+		if err := tx.Create(endpoint).Error; err != nil {
+			code = protocol.DatabaseError
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if code == protocol.DatabaseError {
+			log.Get().Errorf("Create webhook endpoint for merchant %s failed: %v", mid, err)
+		}
+		return nil, code
+	}
+	log.Get().Infof("Merchant %s created webhook endpoint %s: %s", mid, endpoint.EndpointID, endpoint.GetURL())
+	return s.Detail(mid, endpoint.EndpointID)
+}
+
+// Detail 获取通知地址及其当前参与签名的密钥
+func (s *WebhookEndpointService) Detail(mid, endpointID string) (*protocol.WebhookEndpoint, protocol.ErrorCode) {
+	// 创建后立即查询，从主库读取
+	endpoint := models.GetUserWebhookEndpoint(models.WriteDB, protocol.UserTypeMerchant, mid, endpointID)
+	if endpoint == nil {
+		return nil, protocol.WebhookEndpointNotFound
+	}
+	secrets, code := GetWebhookSecretService().Info(protocol.UserTypeMerchant, mid, endpointID)
+	if code != protocol.Success {
+		return nil, code
+	}
+	result := endpoint.Protocol()
+	result.Secrets = secrets
+	return result, protocol.Success
+}
+
+// Update 修改通知地址、订阅事件、版本或启停状态，停用后未完成的推送在下次投递时置为失败
+func (s *WebhookEndpointService) Update(mid string, req *protocol.UpdateWebhookEndpointRequest) (*protocol.WebhookEndpoint, protocol.ErrorCode) {
+	endpoint := models.GetUserWebhookEndpoint(models.WriteDB, protocol.UserTypeMerchant, mid, req.EndpointID)
+	if endpoint == nil {
+		return nil, protocol.WebhookEndpointNotFound
+	}
+	values := &models.WebhookEndpointValues{}
+	if req.URL != nil {
+		if utils.ValidatePublicURL(*req.URL) != nil {
+			return nil, protocol.InvalidWebhookURL
+		}
+		values.SetURL(*req.URL)
+	}
+	if req.Events != nil {
+		values.SetEvents(*req.Events)
+	}
+	if req.APIVersion != nil {
+		values.SetAPIVersion(*req.APIVersion)
+	}
+	if req.Status != nil {
+		values.SetStatus(*req.Status)
+	}
+	if req.Description != nil {
+		values.SetDescription(*req.Description)
+	}
+	values.SetUpdatedBy(mid)
+	if err := models.UpdateWebhookEndpointValues(models.WriteDB, endpoint, values); err != nil {
+		log.Get().Errorf("Update webhook endpoint %s failed: %v", req.EndpointID, err)
+		return nil, protocol.DatabaseError
+	}
+	log.Get().Infof("Merchant %s updated webhook endpoint %s: url=%s, status=%s",
+		mid, endpoint.EndpointID, endpoint.GetURL(), endpoint.GetStatus())
+	return endpoint.Protocol(), protocol.Success
+}
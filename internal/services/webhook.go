@@ -0,0 +1,507 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/task"
+	"inpayos/internal/utils"
+	"inpayos/pkg/webhooksig"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookService 商户异步通知投递服务：按通知地址推送终态事件并签名，失败按指数退避重试并记录每次推送
+type WebhookService struct {
+	client *http.Client
+}
+
+var (
+	webhookService     *WebhookService
+	webhookServiceOnce sync.Once
+)
+
+const (
+	webhookResponseLimit = 2048  // 保存的商户响应体长度上限，单位：字节
+	webhookLeaseMillis   = 60000 // 推送抢占后额外顺延的时间，进程中断时到期后重新推送
+)
+
+func init() {
+	task.RegisterHandler(protocol.WebhookDispatch, HandleWebhookDispatch)
+}
+
+func SetupWebhookService() {
+	webhookServiceOnce.Do(func() {
+		webhookService = &WebhookService{
+			client: utils.NewPublicHttpClient(),
+		}
+	})
+}
+
+// GetWebhookService 获取通知投递服务单例
+func GetWebhookService() *WebhookService {
+	if webhookService == nil {
+		SetupWebhookService()
+	}
+	return webhookService
+}
+
+// webhookReceiver 通知接收方的默认通知地址与G2FA密钥
+type webhookReceiver struct {
+	NotifyURL string
+	G2FA      string
+}
+
+// getWebhookReceiver 获取通知接收方（商户或收银团队）
+func getWebhookReceiver(userType, userID string) (*webhookReceiver, protocol.ErrorCode) {
+	switch userType {
+	case protocol.UserTypeMerchant:
+		merchant := models.GetMerchantByMID(userID)
+		if merchant == nil {
+			return nil, protocol.MerchantNotFound
+		}
+		return &webhookReceiver{NotifyURL: merchant.GetNotifyURL(), G2FA: merchant.GetG2FA()}, protocol.Success
+	case protocol.UserTypeCashierTeam:
+		team := models.GetCashierTeamByTid(userID)
+		if team == nil {
+			return nil, protocol.CashierNotFound
+		}
+		return &webhookReceiver{NotifyURL: team.GetNotifyURL(), G2FA: team.GetG2FA()}, protocol.Success
+	}
+	return nil, protocol.InvalidParams
+}
+
+// newMerchantWebhook 生成待推送的商户通知
+func newMerchantWebhook(mid, notifyURL string) *models.Webhook {
+	return newWebhook(protocol.UserTypeMerchant, mid, notifyURL)
+}
+
+// newWebhook 生成待推送的通知
+func newWebhook(userType, userID, notifyURL string) *models.Webhook {
+	webhook := &models.Webhook{
+		WebhookID:     utils.GenerateWebhookID(),
+		WebhookValues: &models.WebhookValues{},
+	}
+	webhook.SetUserID(userID).
+		SetUserType(userType).
+		SetNotifyURL(notifyURL).
+		SetNotifyStatus(protocol.StatusPending).
+		SetNotifyTimes(0).
+		SetMaxRetryTimes(int32(config.Get().Webhook.MaxRetryTimes)).
+		SetNextNotifyAt(utils.TimeNowMilli())
+	return webhook
+}
+
+// newWebhookNotify 由通知记录生成推送内容
+func newWebhookNotify(webhook *models.Webhook) *protocol.WebhookNotify {
+	notify := &protocol.WebhookNotify{
+		WebhookID: webhook.WebhookID,
+		Event:     webhook.GetType(),
+		Mid:       webhook.GetUserID(),
+		TrxID:     webhook.GetTransactionID(),
+		BillID:    webhook.GetBillID(),
+		Status:    webhook.GetStatus(),
+		Amount:    webhook.GetAmount().String(),
+		Ccy:       webhook.GetCcy(),
+		LinkID:    webhook.GetLinkID(),
+		CreatedAt: utils.TimeNowMilli(),
+	}
+	if webhook.Fee != nil {
+		notify.Fee = webhook.GetFee().String()
+	}
+	if webhook.ReceivedAmount != nil && !webhook.ReceivedAmount.Equal(webhook.GetAmount()) {
+		notify.ReceivedAmount = webhook.ReceivedAmount.String()
+	}
+	return notify
+}
+
+// WebhookEvent 待推送的商户通知事件，由CreateWebhooks按默认通知地址及订阅该事件的通知地址分别生成通知记录
+type WebhookEvent struct {
+	Webhook *models.Webhook         // 默认通知地址的通知记录，NotifyURL为空表示未配置默认通知地址
+	Notify  *protocol.WebhookNotify // 推送内容，各通知记录按自身的通知ID与版本生成请求体
+}
+
+// NewTransactionWebhook 根据交易当前状态生成商户通知事件，默认通知地址为交易指定的notify_url
+func NewTransactionWebhook(trx *models.Transaction) *WebhookEvent {
+	webhook := newMerchantWebhook(trx.Mid, trx.GetNotifyURL())
+	webhook.SetTransactionID(trx.TrxID).
+		SetType(trx.TrxType).
+		SetStatus(trx.GetStatus()).
+		SetAmount(trx.GetAmount()).
+		SetFee(trx.GetFeeAmount()).
+		SetCcy(trx.Ccy)
+	if trx.TransactionValues != nil && trx.ReceivedAmount != nil {
+		webhook.SetReceivedAmount(*trx.ReceivedAmount)
+	}
+	if trx.LinkID != "" {
+		webhook.SetLinkID(trx.LinkID)
+	}
+	notify := newWebhookNotify(webhook)
+	notify.ReqID = trx.ReqID
+	notify.BeneficiaryID = trx.BeneficiaryID
+	notify.ResCode = trx.GetResCode()
+	notify.ResMsg = trx.GetResMsg()
+	return &WebhookEvent{Webhook: webhook, Notify: notify}
+}
+
+// NewDisputeWebhook 生成争议状态变更的商户通知事件
+func NewDisputeWebhook(dispute *models.Dispute, notifyURL string) *WebhookEvent {
+	webhook := newMerchantWebhook(dispute.Mid, notifyURL)
+	webhook.SetTransactionID(dispute.TrxID).
+		SetBillID(dispute.DisputeID).
+		SetType(protocol.WebhookTypeDispute).
+		SetStatus(dispute.GetStatus()).
+		SetAmount(dispute.GetAmount()).
+		SetCcy(dispute.Ccy)
+	return &WebhookEvent{Webhook: webhook, Notify: newWebhookNotify(webhook)}
+}
+
+// NewCheckoutWebhook 生成收银台会话终态的商户通知事件，会话未指定通知地址时使用商户配置
+func NewCheckoutWebhook(checkout *models.MerchantCheckout) *WebhookEvent {
+	notifyURL := checkout.GetNotifyURL()
+	if notifyURL == "" {
+		if merchant := models.GetMerchantByMID(checkout.Mid); merchant != nil {
+			notifyURL = merchant.GetNotifyURL()
+		}
+	}
+	webhook := newMerchantWebhook(checkout.Mid, notifyURL)
+	webhook.SetTransactionID(checkout.GetTrxID()).
+		SetBillID(checkout.CheckoutID).
+		SetType(protocol.WebhookTypeCheckout).
+		SetStatus(checkout.GetStatus()).
+		SetAmount(checkout.GetAmount()).
+		SetCcy(checkout.GetCcy())
+	if checkout.LinkID != "" {
+		webhook.SetLinkID(checkout.LinkID)
+	}
+	notify := newWebhookNotify(webhook)
+	notify.ReqID = checkout.ReqID
+	notify.ResCode = checkout.GetErrorCode()
+	notify.ResMsg = checkout.GetErrorMsg()
+	return &WebhookEvent{Webhook: webhook, Notify: notify}
+}
+
+// NewSettleWebhook 生成交易结算完成的商户通知事件，金额为结算金额
+func NewSettleWebhook(trx *models.Transaction, settle *models.MerchantSettleTransaction) *WebhookEvent {
+	webhook := newMerchantWebhook(trx.Mid, trx.GetNotifyURL())
+	webhook.SetTransactionID(trx.TrxID).
+		SetBillID(settle.GetSettleLogID()).
+		SetType(protocol.WebhookTypeSettle).
+		SetStatus(settle.GetStatus()).
+		SetAmount(settle.GetSettleAmount()).
+		SetFee(settle.GetFee()).
+		SetCcy(settle.SettleCcy)
+	notify := newWebhookNotify(webhook)
+	notify.ReqID = trx.ReqID
+	return &WebhookEvent{Webhook: webhook, Notify: notify}
+}
+
+// NewWithdrawWebhook 生成提现状态变更的商户通知事件，金额为提现到账金额
+func NewWithdrawWebhook(withdraw *models.MerchantWithdraw) *WebhookEvent {
+	webhook := newMerchantWebhook(withdraw.Mid, withdraw.GetNotifyURL())
+	webhook.SetTransactionID(withdraw.TrxID).
+		SetType(protocol.TrxTypeWithdraw).
+		SetStatus(withdraw.GetStatus()).
+		SetAmount(withdraw.GetAmount()).
+		SetFee(withdraw.GetFee()).
+		SetCcy(withdraw.GetCcy())
+	notify := newWebhookNotify(webhook)
+	notify.BeneficiaryID = withdraw.BeneficiaryID
+	notify.ResCode = withdraw.GetResCode()
+	notify.ResMsg = withdraw.GetResMsg()
+	return &WebhookEvent{Webhook: webhook, Notify: notify}
+}
+
+// CreateTransactionWebhook 交易进入终态时生成商户通知及收银团队通知，非终态时不生成
+func CreateTransactionWebhook(db *gorm.DB, trx *models.Transaction) error {
+	if !slices.Contains(protocol.TrxFinalStatusList, trx.GetStatus()) {
+		return nil
+	}
+	if err := CreateWebhooks(db, NewTransactionWebhook(trx)); err != nil {
+		return err
+	}
+	return CreateCashierCompletedWebhook(db, trx)
+}
+
+// CreateWebhooks 生成事件的通知记录：配置了默认通知地址时一条，订阅该事件的每个启用的通知地址各一条，各自独立推送与重试
+func CreateWebhooks(db *gorm.DB, event *WebhookEvent) error {
+	base := event.Webhook
+	if base.GetNotifyURL() != "" {
+		base.SetAPIVersion(protocol.WebhookAPIVersionV1).
+			SetRequestBody(renderWebhookPayload(base, event.Notify))
+		if err := models.CreateWebhook(db, base); err != nil {
+			return err
+		}
+	}
+	endpoints, err := models.ListUserWebhookEndpoints(db, base.GetUserType(), base.GetUserID(), protocol.StatusActive)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(base.GetType()) {
+			continue
+		}
+		values := *base.WebhookValues
+		webhook := &models.Webhook{
+			WebhookID:     utils.GenerateWebhookID(),
+			WebhookValues: &values,
+		}
+		webhook.SetNotifyURL(endpoint.GetURL()).
+			SetEndpointID(endpoint.EndpointID).
+			SetAPIVersion(endpoint.GetAPIVersion())
+		webhook.SetRequestBody(renderWebhookPayload(webhook, event.Notify))
+		if err := models.CreateWebhook(db, webhook); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderWebhookPayload 按通知记录的通知ID与版本生成请求体
+func renderWebhookPayload(webhook *models.Webhook, notify *protocol.WebhookNotify) string {
+	data := *notify
+	data.WebhookID = webhook.WebhookID
+	if webhook.GetAPIVersion() == protocol.WebhookAPIVersionV2 {
+		return utils.ToJsonString(&protocol.WebhookEnvelope{
+			ID:         webhook.WebhookID,
+			Event:      data.Event,
+			APIVersion: protocol.WebhookAPIVersionV2,
+			CreatedAt:  data.CreatedAt,
+			Data:       &data,
+		})
+	}
+	return utils.ToJsonString(&data)
+}
+
+// Dispatch 分批投递已到推送时间的通知，直到没有待推送记录或任务超时
+func (s *WebhookService) Dispatch(ctx context.Context) error {
+	cfg := config.Get().Webhook
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		webhooks, err := models.ListDueWebhooks(utils.TimeNowMilli(), cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("查询待推送通知失败: %v", err)
+		}
+		sem := make(chan struct{}, cfg.Workers)
+		var wg sync.WaitGroup
+		for _, webhook := range webhooks {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(webhook *models.Webhook) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				s.Deliver(ctx, webhook)
+			}(webhook)
+		}
+		wg.Wait()
+		if len(webhooks) < cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// Deliver 推送一次通知并记录结果，抢占失败说明已被其他进程处理
+func (s *WebhookService) Deliver(ctx context.Context, webhook *models.Webhook) {
+	cfg := config.Get().Webhook
+	now := utils.TimeNowMilli()
+	ok, err := models.ClaimWebhook(models.WriteDB, webhook, now+cfg.GetTimeout().Milliseconds()+webhookLeaseMillis)
+	if err != nil {
+		log.Get().Errorf("Claim webhook %s failed: %v", webhook.WebhookID, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	notifyURL := webhook.GetNotifyURL()
+	if endpointID := webhook.GetEndpointID(); endpointID != "" {
+		// 通知地址停用后不再推送，修改地址后的重试推送到新地址
+		endpoint := models.GetUserWebhookEndpoint(models.ReadDB, webhook.GetUserType(), webhook.GetUserID(), endpointID)
+		if endpoint == nil || endpoint.GetStatus() != protocol.StatusActive {
+			s.abandon(webhook, "webhook endpoint disabled")
+			return
+		}
+		notifyURL = endpoint.GetURL()
+	}
+	// 地址不符合推送要求时重试也不会成功，直接置为失败
+	if err := utils.ValidatePublicURL(notifyURL); err != nil {
+		s.abandon(webhook, "notify url not allowed: "+err.Error())
+		return
+	}
+
+	times := webhook.GetNotifyTimes() + 1
+	payload := webhook.GetRequestBody()
+	if payload == "" {
+		payload = utils.ToJsonString(newWebhookNotify(webhook))
+	}
+	attempt := s.send(ctx, &webhookRequest{
+		WebhookID:  webhook.WebhookID,
+		Event:      webhook.GetType(),
+		UserType:   webhook.GetUserType(),
+		UserID:     webhook.GetUserID(),
+		EndpointID: webhook.GetEndpointID(),
+		APIVersion: webhook.GetAPIVersion(),
+		NotifyURL:  notifyURL,
+		Payload:    payload,
+		Attempt:    times,
+	})
+
+	finished := utils.TimeNowMilli()
+	values := &models.WebhookValues{}
+	values.SetNotifyTimes(times).
+		SetLastNotifyAt(finished).
+		SetResponseCode(attempt.ResponseCode).
+		SetResponseBody(attempt.ResponseBody)
+	switch {
+	case attempt.Status == protocol.StatusSuccess:
+		values.SetNotifyStatus(protocol.StatusSuccess)
+	case times >= webhook.GetMaxRetryTimes():
+		values.SetNotifyStatus(protocol.StatusFailed).
+			SetRemark(fmt.Sprintf("exhausted after %d attempts", times))
+	default:
+		values.SetNextNotifyAt(finished + cfg.GetRetryDelay(times))
+	}
+	err = models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := models.CreateWebhookAttempt(tx, attempt); err != nil {
+			return err
+		}
+		return models.UpdateWebhookValues(tx, webhook, values)
+	})
+	if err != nil {
+		log.Get().Errorf("Save webhook %s attempt %d failed: %v", webhook.WebhookID, times, err)
+		return
+	}
+	log.Get().Infof("Webhook %s attempt %d: status=%s, code=%s, duration=%dms",
+		webhook.WebhookID, times, attempt.Status, attempt.ResponseCode, attempt.Duration)
+}
+
+// abandon 不再推送的通知直接置为失败
+func (s *WebhookService) abandon(webhook *models.Webhook, remark string) {
+	values := &models.WebhookValues{}
+	values.SetNotifyStatus(protocol.StatusFailed).
+		SetRemark(remark)
+	if err := models.UpdateWebhookValues(models.WriteDB, webhook, values); err != nil {
+		log.Get().Errorf("Abandon webhook %s failed: %v", webhook.WebhookID, err)
+		return
+	}
+	log.Get().Infof("Webhook %s abandoned: %s", webhook.WebhookID, remark)
+}
+
+// webhookRequest 单次推送请求
+type webhookRequest struct {
+	WebhookID  string
+	Event      string
+	UserType   string
+	UserID     string
+	EndpointID string // 为空表示默认通知地址，决定签名使用的密钥
+	APIVersion string
+	NotifyURL  string
+	Payload    string
+	Attempt    int32
+}
+
+// send 签名并发送通知，返回推送记录，商户返回HTTP 200且响应体为success时视为成功
+func (s *WebhookService) send(ctx context.Context, in *webhookRequest) *models.WebhookAttempt {
+	attempt := &models.WebhookAttempt{
+		WebhookID: in.WebhookID,
+		Attempt:   in.Attempt,
+		NotifyURL: in.NotifyURL,
+		Status:    protocol.StatusFailed,
+	}
+	start := time.Now()
+	statusCode, body, err := s.post(ctx, in, attempt)
+	attempt.Duration = time.Since(start).Milliseconds()
+	attempt.ResponseBody = body
+	if statusCode > 0 {
+		attempt.ResponseCode = strconv.Itoa(statusCode)
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		if len(attempt.Error) > 512 {
+			attempt.Error = attempt.Error[:512]
+		}
+	} else if statusCode == http.StatusOK && strings.EqualFold(strings.TrimSpace(body), protocol.WebhookSuccessResponse) {
+		attempt.Status = protocol.StatusSuccess
+	}
+	return attempt
+}
+
+// post 发送通知请求并记录请求头，返回HTTP状态码与截断后的响应体
+func (s *WebhookService) post(ctx context.Context, in *webhookRequest, attempt *models.WebhookAttempt) (int, string, error) {
+	// 每次推送按发送时间重新签名，避免商户因时间戳超出容差拒绝重试请求
+	signature, err := GetWebhookSecretService().SignatureHeader(in.UserType, in.UserID, in.EndpointID, []byte(in.Payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("sign webhook: %v", err)
+	}
+	// 通知地址由商户配置，只允许推送到公网https地址，连接时还会校验解析后的IP
+	if err := utils.ValidatePublicURL(in.NotifyURL); err != nil {
+		return 0, "", fmt.Errorf("notify url not allowed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Get().Webhook.GetTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, in.NotifyURL, bytes.NewBufferString(in.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	headers := map[string]string{
+		"Content-Type":             utils.JSON_HEADER,
+		"X-Webhook-Id":             in.WebhookID,
+		"X-Webhook-Event":          in.Event,
+		"X-Webhook-Attempt":        strconv.Itoa(int(in.Attempt)),
+		"X-Webhook-Version":        in.APIVersion,
+		webhooksig.HeaderSignature: signature,
+	}
+	attempt.RequestHeaders = protocol.MapData{}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+		attempt.RequestHeaders[key] = value
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	return resp.StatusCode, string(body), err
+}
+
+// RegisterWebhookTasks 注册通知投递任务
+func RegisterWebhookTasks() {
+	log.Get().Info("注册通知投递任务...")
+	tasks := []*models.Task{
+		{
+			TaskID:     "webhook_dispatch",
+			Type:       protocol.WebhookDispatch,
+			HandlerKey: protocol.WebhookDispatch,
+			Name:       "商户通知投递",
+			TaskValues: &models.TaskValues{
+				Cron:    &[]string{"every 10s"}[0], // 每10秒执行一次
+				Timeout: &[]int{300}[0],            // 5分钟超时
+				Status:  &[]string{protocol.StatusEnabled}[0],
+				Params:  map[string]any{},
+			},
+		},
+	}
+	task.InitTasks(tasks)
+	log.Get().Infof("通知投递任务注册完成，共 %d 个任务", len(tasks))
+}
+
+// HandleWebhookDispatch 投递已到推送时间的商户通知
+func HandleWebhookDispatch(ctx context.Context, params protocol.MapData) error {
+	return GetWebhookService().Dispatch(ctx)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"inpayos/internal/config"
+	"inpayos/internal/log"
+	"inpayos/internal/models"
+	"inpayos/internal/protocol"
+	"inpayos/internal/utils"
+	"inpayos/pkg/webhooksig"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookSecretService 通知签名密钥服务，每个通知接收方一套密钥，轮换过渡期内新旧密钥同时签名
+type WebhookSecretService struct {
+	mu sync.Mutex
+}
+
+var (
+	webhookSecretService     *WebhookSecretService
+	webhookSecretServiceOnce sync.Once
+)
+
+const webhookSecretPrefix = "whsec_"
+
+func SetupWebhookSecretService() {
+	webhookSecretServiceOnce.Do(func() {
+		webhookSecretService = &WebhookSecretService{}
+	})
+}
+
+// GetWebhookSecretService 获取通知签名密钥服务单例
+func GetWebhookSecretService() *WebhookSecretService {
+	if webhookSecretService == nil {
+		SetupWebhookSecretService()
+	}
+	return webhookSecretService
+}
+
+// Info 获取通知地址当前参与签名的密钥，首次查询时生成，endpointID为空表示默认通知地址
+func (s *WebhookSecretService) Info(userType, userID, endpointID string) (*protocol.WebhookSecretInfo, protocol.ErrorCode) {
+	secrets, err := s.SigningSecrets(userType, userID, endpointID)
+	if err != nil {
+		log.Get().Errorf("Get webhook secrets for %s %s %s failed: %v", userType, userID, endpointID, err)
+		return nil, protocol.DatabaseError
+	}
+	info := &protocol.WebhookSecretInfo{
+		Header:  webhooksig.HeaderSignature,
+		Secrets: make([]*protocol.WebhookSecret, 0, len(secrets)),
+	}
+	for _, secret := range secrets {
+		info.Secrets = append(info.Secrets, secret.Protocol())
+	}
+	return info, protocol.Success
+}
+
+// Rotate 生成新密钥，旧密钥在过渡期内继续签名，已绑定G2FA的接收方需校验验证码
+func (s *WebhookSecretService) Rotate(userType, userID string, req *protocol.RotateWebhookSecretRequest) (*protocol.WebhookSecretInfo, protocol.ErrorCode) {
+	receiver, code := getWebhookReceiver(userType, userID)
+	if code != protocol.Success {
+		return nil, code
+	}
+	if receiver.G2FA != "" && !VerifyG2FACode(receiver.G2FA, req.Code) {
+		return nil, protocol.InvalidTwoFactorCode
+	}
+	if req.EndpointID != "" && models.GetUserWebhookEndpoint(models.ReadDB, userType, userID, req.EndpointID) == nil {
+		return nil, protocol.WebhookEndpointNotFound
+	}
+	hours := config.Get().Webhook.RotationHours
+	if req.WindowHours != nil {
+		hours = *req.WindowHours
+	}
+	if code := s.rotate(userType, userID, req.EndpointID, userID, time.Duration(hours)*time.Hour); code != protocol.Success {
+		return nil, code
+	}
+	return s.Info(userType, userID, req.EndpointID)
+}
+
+func (s *WebhookSecretService) rotate(userType, userID, endpointID, operator string, window time.Duration) protocol.ErrorCode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt := utils.TimeNowMilli() + window.Milliseconds()
+	err := models.WriteDB.Transaction(func(tx *gorm.DB) error {
+		if err := models.ExpireWebhookSecrets(tx, userType, userID, endpointID, expiresAt); err != nil {
+			return err
+		}
+		return tx.Create(s.newSecret(userType, userID, endpointID, operator)).Error
+	})
+	if err != nil {
+		log.Get().Errorf("Rotate webhook secret for %s %s %s failed: %v", userType, userID, endpointID, err)
+		return protocol.DatabaseError
+	}
+	return protocol.Success
+}
+
+// SigningSecrets 获取通知地址当前参与签名的密钥，没有时生成一个
+func (s *WebhookSecretService) SigningSecrets(userType, userID, endpointID string) ([]*models.WebhookSecret, error) {
+	secrets, err := models.ListActiveWebhookSecrets(models.ReadDB, userType, userID, endpointID, utils.TimeNowMilli())
+	if err != nil || len(secrets) > 0 {
+		return secrets, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// 加锁后从主库再查一次，避免并发推送重复生成
+	secrets, err = models.ListActiveWebhookSecrets(models.WriteDB, userType, userID, endpointID, utils.TimeNowMilli())
+	if err != nil || len(secrets) > 0 {
+		return secrets, err
+	}
+	secret := s.newSecret(userType, userID, endpointID, protocol.System)
+	if err := models.WriteDB.Create(secret).Error; err != nil {
+		return nil, err
+	}
+	return []*models.WebhookSecret{secret}, nil
+}
+
+// SignatureHeader 以当前参与签名的全部密钥生成签名头
+func (s *WebhookSecretService) SignatureHeader(userType, userID, endpointID string, body []byte) (string, error) {
+	secrets, err := s.SigningSecrets(userType, userID, endpointID)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		keys = append(keys, secret.Secret)
+	}
+	return webhooksig.SignatureHeader(time.Now().Unix(), body, keys...), nil
+}
+
+func (s *WebhookSecretService) newSecret(userType, userID, endpointID, operator string) *models.WebhookSecret {
+	secret := &models.WebhookSecret{
+		SecretID:            utils.GenerateWebhookSecretID(),
+		UserID:              userID,
+		UserType:            userType,
+		Secret:              webhookSecretPrefix + utils.GenerateAPIKey(),
+		WebhookSecretValues: &models.WebhookSecretValues{},
+		EndpointID:          endpointID,
+	}
+	secret.SetStatus(protocol.StatusActive).
+		SetExpiresAt(0).
+		SetRotatedBy(operator)
+	return secret
+}
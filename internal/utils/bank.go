@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ifscPattern      = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	ibanPattern      = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	swiftBICPattern  = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	abaPattern       = regexp.MustCompile(`^[0-9]{9}$`)
+	sortCodePattern  = regexp.MustCompile(`^[0-9]{6}$`)
+	accountNoPattern = regexp.MustCompile(`^[A-Z0-9]{4,34}$`)
+
+	// 本地银行账号长度规则
+	indAccountPattern = regexp.MustCompile(`^[0-9]{9,18}$`)
+	usaAccountPattern = regexp.MustCompile(`^[0-9]{4,17}$`)
+	gbrAccountPattern = regexp.MustCompile(`^[0-9]{8}$`)
+)
+
+// ibanLengths 各国IBAN总长度，未列出的国家仅校验格式与校验位
+var ibanLengths = map[string]int{
+	"AE": 23, "AT": 20, "BE": 16, "BH": 22, "CH": 21, "CY": 28, "CZ": 24, "DE": 22,
+	"DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27, "GB": 22, "GR": 27, "HR": 21,
+	"HU": 28, "IE": 22, "IT": 27, "LT": 20, "LU": 20, "LV": 21, "MT": 31, "NL": 18,
+	"NO": 15, "PL": 28, "PT": 25, "QA": 29, "RO": 24, "SA": 24, "SE": 24, "SI": 19,
+	"SK": 24, "TR": 26,
+}
+
+var (
+	ErrInvalidAccountNo   = errors.New("invalid account number")
+	ErrInvalidBankCode    = errors.New("invalid bank code")
+	ErrInvalidIBAN        = errors.New("invalid iban")
+	ErrInvalidUpiVpa      = errors.New("invalid upi vpa")
+	ErrUnsupportedAccount = errors.New("unsupported account type")
+)
+
+// NormalizeBankField 去除空格、横线并转为大写，用于账号、IBAN与银行编码
+func NormalizeBankField(value string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(value)))
+}
+
+// ValidateIFSC 校验印度IFSC编码，4位银行代码+0+6位分行代码
+func ValidateIFSC(ifsc string) bool {
+	return ifscPattern.MatchString(ifsc)
+}
+
+// ValidateSwiftBIC 校验SWIFT/BIC编码，8位或11位
+func ValidateSwiftBIC(bic string) bool {
+	return swiftBICPattern.MatchString(bic)
+}
+
+// ValidateABARouting 校验美国ABA路由号，按3-7-1权重求和需被10整除
+func ValidateABARouting(routing string) bool {
+	if !abaPattern.MatchString(routing) {
+		return false
+	}
+	weights := []int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i, c := range routing {
+		sum += int(c-'0') * weights[i]
+	}
+	return sum%10 == 0
+}
+
+// ValidateIBAN 校验IBAN长度与ISO 7064 mod-97校验位
+func ValidateIBAN(iban string) bool {
+	if !ibanPattern.MatchString(iban) {
+		return false
+	}
+	if length, ok := ibanLengths[iban[:2]]; ok && len(iban) != length {
+		return false
+	}
+	// 前4位移至末尾，字母按A=10...Z=35展开后对97取模应为1
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, c := range rearranged {
+		if c >= 'A' && c <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(c-'A') + 10))
+		} else {
+			digits.WriteRune(c)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// ValidateBankAccount 按账户类型与国家校验收款账户，入参需已规范化
+// bank_account: IND 账号9-18位数字+IFSC，USA 账号4-17位数字+ABA，GBR 账号8位数字+6位Sort Code，
+// 其他国家账号为4-34位字母数字，银行编码填写时需为SWIFT/BIC
+func ValidateBankAccount(country, accountType, accountNo, bankCode string) error {
+	switch accountType {
+	case "upi":
+		if country != "IND" || !IsValidUpiVpa(accountNo) {
+			return ErrInvalidUpiVpa
+		}
+		return nil
+	case "iban":
+		if !ValidateIBAN(accountNo) {
+			return ErrInvalidIBAN
+		}
+		if bankCode != "" && !ValidateSwiftBIC(bankCode) {
+			return ErrInvalidBankCode
+		}
+		return nil
+	case "bank_account":
+	default:
+		return ErrUnsupportedAccount
+	}
+
+	switch country {
+	case "IND":
+		if !indAccountPattern.MatchString(accountNo) {
+			return ErrInvalidAccountNo
+		}
+		if !ValidateIFSC(bankCode) {
+			return ErrInvalidBankCode
+		}
+	case "USA":
+		if !usaAccountPattern.MatchString(accountNo) {
+			return ErrInvalidAccountNo
+		}
+		if !ValidateABARouting(bankCode) {
+			return ErrInvalidBankCode
+		}
+	case "GBR":
+		if !gbrAccountPattern.MatchString(accountNo) {
+			return ErrInvalidAccountNo
+		}
+		if !sortCodePattern.MatchString(bankCode) {
+			return ErrInvalidBankCode
+		}
+	default:
+		if !accountNoPattern.MatchString(accountNo) {
+			return ErrInvalidAccountNo
+		}
+		if bankCode != "" && !ValidateSwiftBIC(bankCode) {
+			return ErrInvalidBankCode
+		}
+	}
+	return nil
+}
+
+// BankAccountFingerprint 账户指纹，用于同一商户下识别重复的收款账户，代付请求不含国家，指纹按币种区分
+func BankAccountFingerprint(mid, ccy, accountType, accountNo, bankCode string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{mid, ccy, accountType, accountNo, bankCode}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// MaskAccountNo 账号掩码，仅保留末4位，UPI VPA保留首字符与@后缀
+func MaskAccountNo(accountNo string) string {
+	if at := strings.LastIndex(accountNo, "@"); at > 0 {
+		return accountNo[:1] + strings.Repeat("*", max(at-1, 1)) + accountNo[at:]
+	}
+	if len(accountNo) <= 4 {
+		return accountNo
+	}
+	return strings.Repeat("*", len(accountNo)-4) + accountNo[len(accountNo)-4:]
+}
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -84,6 +88,89 @@ func NewHttpClientWithProxy(proxy string) *http.Client {
 	return cli
 }
 
+var (
+	ErrURLNotHTTPS   = errors.New("url must use https")
+	ErrURLNotPublic  = errors.New("url host must be a public address")
+	ErrAddrNotPublic = errors.New("dial to non-public address is not allowed")
+)
+
+// nonPublicPrefixes 回环、内网、链路本地以外仍不允许访问的网段，含云厂商元数据地址
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // 运营商级NAT，部分云厂商元数据地址在此网段
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("fd00:ec2::254/128"),
+}
+
+// IsPublicAddr 判断IP是否为公网地址
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePublicURL 校验回调地址：仅允许https，主机为IP时必须为公网地址，连接时还会再校验解析后的地址
+func ValidatePublicURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" {
+		return ErrURLNotHTTPS
+	}
+	host := u.Hostname()
+	if host == "" || strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return ErrURLNotPublic
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !IsPublicAddr(addr) {
+		return ErrURLNotPublic
+	}
+	return nil
+}
+
+// publicDialControl 在建立连接时校验实际连接的IP，防止通过DNS重绑定访问内网
+func publicDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !IsPublicAddr(addr) {
+		return ErrAddrNotPublic
+	}
+	return nil
+}
+
+// NewPublicHttpClient 访问商户等外部地址的客户端：仅允许https，只能连接公网地址，不跟随重定向，不使用环境代理
+func NewPublicHttpClient() *http.Client {
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: ConnectionTimeout,
+			Control: publicDialControl,
+		}).DialContext,
+		TLSHandshakeTimeout: HandshakeTimeout,
+	}
+	cli := &http.Client{
+		Transport: transport,
+		Timeout:   ConnectionTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return cli
+}
+
 func PostWithHeader(url string, data []byte, headers map[string]string) (string, *http.Response, error) {
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(data))
 	if err != nil {
@@ -33,6 +33,23 @@ const (
 	ID_PREFIX_CASHIER_TEAM = "CT"
 	ID_PREFIX_DEPOSIT      = "DP"
 	ID_PREFIX_WITHDRAW     = "WD"
+	ID_PREFIX_MERCHANT_USR = "MU"
+	ID_PREFIX_APPROVAL     = "APV"
+	ID_PREFIX_EXPORT       = "EXP"
+	ID_PREFIX_ADJUSTMENT   = "ADJ"
+	ID_PREFIX_DISPUTE      = "DSP"
+	ID_PREFIX_PROOF        = "PRF"
+	ID_PREFIX_STATEMENT    = "BST"
+	ID_PREFIX_PAYMENT_LINK = "PL"
+	ID_PREFIX_BENEFICIARY  = "BNF"
+	ID_PREFIX_WEBHOOK_KEY  = "WHK"
+	ID_PREFIX_WEBHOOK_EP   = "WHE"
+	ID_PREFIX_LEDGER_ENTRY = "LE"
+	ID_PREFIX_LEDGER_CHECK = "LC"
+	ID_PREFIX_MARGIN_REL   = "MR"
+	ID_PREFIX_CONVERSION   = "CV"
+	ID_PREFIX_TRANSFER     = "TF"
+	ID_PREFIX_RESERVE      = "RSV"
 )
 
 func GenerateID() string {
@@ -130,6 +147,21 @@ func GenerateWithdrawID() string {
 	return fmt.Sprintf("%v%v", ID_PREFIX_WITHDRAW, GenerateID())
 }
 
+// GenerateMerchantUserID 生成商户子账号ID
+func GenerateMerchantUserID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_MERCHANT_USR, GenerateID())
+}
+
+// GenerateApprovalID 生成审批ID
+func GenerateApprovalID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_APPROVAL, GenerateID())
+}
+
+// GenerateExportJobID 生成导出任务ID
+func GenerateExportJobID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_EXPORT, GenerateID())
+}
+
 // GenerateSalt 生成加密盐值
 func GenerateSalt() string {
 	salt := make([]byte, 32)
@@ -177,3 +209,73 @@ func generateRandomNumber(length int) string {
 	}
 	return string(result)
 }
+
+// GenerateAdjustmentID 生成调账单号
+func GenerateAdjustmentID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_ADJUSTMENT, GenerateID())
+}
+
+// GenerateDisputeID 生成争议ID
+func GenerateDisputeID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_DISPUTE, GenerateID())
+}
+
+// GenerateProofID 生成支付凭证ID
+func GenerateProofID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_PROOF, GenerateID())
+}
+
+// GenerateStatementID 生成银行流水ID
+func GenerateStatementID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_STATEMENT, GenerateID())
+}
+
+// GeneratePaymentLinkID 生成支付链接ID
+func GeneratePaymentLinkID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_PAYMENT_LINK, GenerateID())
+}
+
+// GenerateBeneficiaryID 生成收款人ID
+func GenerateBeneficiaryID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_BENEFICIARY, GenerateID())
+}
+
+// GenerateWebhookSecretID 生成通知签名密钥ID
+func GenerateWebhookSecretID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_WEBHOOK_KEY, GenerateID())
+}
+
+// GenerateWebhookEndpointID 生成通知地址ID
+func GenerateWebhookEndpointID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_WEBHOOK_EP, GenerateID())
+}
+
+// GenerateLedgerEntryID 生成记账凭证ID
+func GenerateLedgerEntryID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_LEDGER_ENTRY, GenerateID())
+}
+
+// GenerateLedgerCheckID 生成账务核对ID
+func GenerateLedgerCheckID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_LEDGER_CHECK, GenerateID())
+}
+
+// GenerateMarginReleaseID 生成保证金释放ID
+func GenerateMarginReleaseID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_MARGIN_REL, GenerateID())
+}
+
+// GenerateConversionID 生成换汇ID
+func GenerateConversionID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_CONVERSION, GenerateID())
+}
+
+// GenerateTransferID 生成内部转账ID
+func GenerateTransferID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_TRANSFER, GenerateID())
+}
+
+// GenerateReserveID 生成结算准备金ID
+func GenerateReserveID() string {
+	return fmt.Sprintf("%v%v", ID_PREFIX_RESERVE, GenerateID())
+}
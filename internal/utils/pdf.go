@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDF页面参数：A4横向，等宽字体，便于按列对齐输出对账单等表格文本
+const (
+	pdfPageWidth    = 842
+	pdfPageHeight   = 595
+	pdfMargin       = 36
+	pdfFontSize     = 8
+	pdfLineHeight   = 10
+	PDFLinesPerPage = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+	PDFCharsPerLine = (pdfPageWidth - 2*pdfMargin) * 10 / (pdfFontSize * 6) // Courier字宽为字号的0.6倍
+)
+
+// 对象编号：1-目录 2-页面树 3-字体，页面及内容流从4开始顺序分配
+const (
+	pdfCatalogObj = 1
+	pdfPagesObj   = 2
+	pdfFontObj    = 3
+)
+
+// PDFWriter 流式纯文本PDF写入器，逐行写入，写满一页即输出，不在内存中保留整份文档
+// 仅支持ASCII字符，其他字符以?替代，适用于对账单等以数字和编号为主的报表
+type PDFWriter struct {
+	w       io.Writer
+	offset  int64
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	lines   []string
+}
+
+// NewPDFWriter 创建PDF写入器
+func NewPDFWriter(w io.Writer) (*PDFWriter, error) {
+	p := &PDFWriter{
+		w:       w,
+		offsets: map[int]int64{},
+		nextObj: pdfFontObj + 1,
+	}
+	if err := p.write("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// WriteLine 写入一行文本，超出行宽的部分截断
+func (p *PDFWriter) WriteLine(text string) error {
+	if len(text) > PDFCharsPerLine {
+		text = text[:PDFCharsPerLine]
+	}
+	p.lines = append(p.lines, text)
+	if len(p.lines) >= PDFLinesPerPage {
+		return p.flushPage()
+	}
+	return nil
+}
+
+// Close 输出剩余内容及文档结构，不关闭底层io.Writer
+func (p *PDFWriter) Close() error {
+	if len(p.lines) > 0 || len(p.pages) == 0 {
+		if err := p.flushPage(); err != nil {
+			return err
+		}
+	}
+	kids := make([]string, 0, len(p.pages))
+	for _, obj := range p.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", obj))
+	}
+	if err := p.writeObject(pdfPagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages))); err != nil {
+		return err
+	}
+	if err := p.writeObject(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"); err != nil {
+		return err
+	}
+	if err := p.writeObject(pdfCatalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObj)); err != nil {
+		return err
+	}
+
+	xref := p.offset
+	var b strings.Builder
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", p.nextObj)
+	for obj := 1; obj < p.nextObj; obj++ {
+		fmt.Fprintf(&b, "%010d 00000 n \n", p.offsets[obj])
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.nextObj, pdfCatalogObj, xref)
+	return p.write(b.String())
+}
+
+// flushPage 输出当前页的内容流和页面对象
+func (p *PDFWriter) flushPage() error {
+	// '操作符先换行再输出，起点设在顶边距处，首行即落在顶边距下一行
+	var content strings.Builder
+	fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", pdfFontSize, pdfLineHeight, pdfMargin, pdfPageHeight-pdfMargin)
+	for _, line := range p.lines {
+		fmt.Fprintf(&content, "(%s) '\n", pdfEscape(line))
+	}
+	content.WriteString("ET\n")
+	p.lines = p.lines[:0]
+
+	contentObj := p.allocObject()
+	if err := p.writeObject(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String())); err != nil {
+		return err
+	}
+	pageObj := p.allocObject()
+	page := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObj, pdfPageWidth, pdfPageHeight, pdfFontObj, contentObj)
+	if err := p.writeObject(pageObj, page); err != nil {
+		return err
+	}
+	p.pages = append(p.pages, pageObj)
+	return nil
+}
+
+func (p *PDFWriter) allocObject() int {
+	obj := p.nextObj
+	p.nextObj++
+	return obj
+}
+
+func (p *PDFWriter) writeObject(obj int, body string) error {
+	p.offsets[obj] = p.offset
+	return p.write(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", obj, body))
+}
+
+func (p *PDFWriter) write(s string) error {
+	n, err := io.WriteString(p.w, s)
+	p.offset += int64(n)
+	return err
+}
+
+// pdfEscape 转义PDF字符串中的特殊字符，非ASCII可见字符替换为?
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
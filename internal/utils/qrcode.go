@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// qrQuietZone 二维码四周留白的模块数，规范要求至少4个模块
+const qrQuietZone = 4
+
+var upiVpaPattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_]{2,256}@[a-zA-Z][a-zA-Z0-9]{1,63}$`)
+
+// qrMatrix 编码二维码并计算缩放参数，size为输出图片边长（像素）
+func qrMatrix(content string, size int) (code barcode.Barcode, scale, offset int, err error) {
+	code, err = qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	modules := code.Bounds().Dx() + 2*qrQuietZone
+	scale = size / modules
+	if scale < 1 {
+		return nil, 0, 0, fmt.Errorf("qr size %d is too small for %d modules", size, modules)
+	}
+	// 整数倍缩放后的剩余像素平均分配到四周
+	offset = (size-scale*modules)/2 + scale*qrQuietZone
+	return code, scale, offset, nil
+}
+
+// RenderQRCodePNG 将内容渲染为指定边长的PNG二维码
+func RenderQRCodePNG(content string, size int) ([]byte, error) {
+	code, scale, offset, err := qrMatrix(content, size)
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	bounds := code.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if code.At(x, y) != color.Black {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray(offset+(x-bounds.Min.X)*scale+dx, offset+(y-bounds.Min.Y)*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderQRCodeSVG 将内容渲染为指定边长的SVG二维码，每行连续的深色模块合并为一个矩形
+func RenderQRCodeSVG(content string, size int) ([]byte, error) {
+	code, scale, offset, err := qrMatrix(content, size)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#ffffff"/><path fill="#000000" d="`, size, size)
+	bounds := code.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; {
+			if code.At(x, y) != color.Black {
+				x++
+				continue
+			}
+			start := x
+			for x < bounds.Max.X && code.At(x, y) == color.Black {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz",
+				offset+(start-bounds.Min.X)*scale, offset+(y-bounds.Min.Y)*scale,
+				(x-start)*scale, scale, (x-start)*scale)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}
+
+// UpiIntent UPI收款意图参数
+type UpiIntent struct {
+	Vpa       string // 收款方VPA，必填
+	PayeeName string // 收款方名称
+	Amount    string // 金额，为空时由付款人输入
+	Ccy       string // 币种，默认INR
+	Ref       string // 交易参考号
+	Note      string // 交易备注
+}
+
+// IsValidUpiVpa 校验UPI VPA格式，如 name@bank
+func IsValidUpiVpa(vpa string) bool {
+	return upiVpaPattern.MatchString(vpa)
+}
+
+// BuildUpiIntent 按NPCI规范生成 upi://pay 意图串，参数按 pa、pn、am、cu、tr、tn 顺序输出
+func BuildUpiIntent(intent *UpiIntent) string {
+	ccy := intent.Ccy
+	if ccy == "" {
+		ccy = "INR"
+	}
+	params := [][2]string{
+		{"pa", intent.Vpa},
+		{"pn", intent.PayeeName},
+		{"am", intent.Amount},
+		{"cu", ccy},
+		{"tr", intent.Ref},
+		{"tn", intent.Note},
+	}
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		if p[1] == "" {
+			continue
+		}
+		// 部分UPI应用不识别+号空格及转义后的@，空格统一使用%20，@保持原样
+		value := strings.NewReplacer("+", "%20", "%40", "@").Replace(url.QueryEscape(p[1]))
+		parts = append(parts, p[0]+"="+value)
+	}
+	return "upi://pay?" + strings.Join(parts, "&")
+}
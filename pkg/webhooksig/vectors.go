@@ -0,0 +1,112 @@
+package webhooksig
+
+import (
+	"fmt"
+	"time"
+)
+
+// Vector 签名测试向量，商户可用于核对自己的验签实现
+type Vector struct {
+	Name         string   `json:"name"`
+	Secrets      []string `json:"secrets"` // 签名使用的密钥，轮换期间为新旧两个
+	Timestamp    int64    `json:"timestamp"`
+	Body         string   `json:"body"`   // 商户收到的原始请求体
+	Header       string   `json:"header"` // X-Webhook-Signature
+	VerifySecret string   `json:"verify_secret"`
+	Now          int64    `json:"now"` // 验签时间，Unix秒
+	Tolerance    int64    `json:"tolerance_seconds"`
+	Valid        bool     `json:"valid"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// vectorCase 测试用例输入，签名头与期望结果由签名代码生成
+type vectorCase struct {
+	name         string
+	secrets      []string
+	timestamp    int64
+	signedBody   string
+	body         string
+	header       string // 非空时使用给定的签名头，用于格式错误用例
+	verifySecret string
+	now          int64
+}
+
+const (
+	vectorSecretOld = "whsec_3f1c9a0d5e7b2846c1a9e0f4d2b8c6a1"
+	vectorSecretNew = "whsec_8b2e4d6f0a1c3e5b7d9f2a4c6e8b0d1f"
+	vectorTimestamp = 1735689600 // 2025-01-01T00:00:00Z
+	vectorBody      = `{"webhook_id":"WH1873650001","event":"payin","mid":"M10001","trx_id":"PI1873650001","req_id":"ORDER-1001","status":"success","amount":"1000","fee":"20","ccy":"INR","res_code":"0000","created_at":1735689599000}`
+)
+
+var vectorCases = []vectorCase{
+	{name: "single secret", secrets: []string{vectorSecretNew}, signedBody: vectorBody, verifySecret: vectorSecretNew},
+	{name: "rotation verified with new secret", secrets: []string{vectorSecretNew, vectorSecretOld}, signedBody: vectorBody, verifySecret: vectorSecretNew},
+	{name: "rotation verified with old secret", secrets: []string{vectorSecretNew, vectorSecretOld}, signedBody: vectorBody, verifySecret: vectorSecretOld},
+	{name: "old secret after rotation window", secrets: []string{vectorSecretNew}, signedBody: vectorBody, verifySecret: vectorSecretOld},
+	{name: "unicode body", secrets: []string{vectorSecretNew}, signedBody: `{"remark":"付款成功 ✓","amount":"12.50"}`, verifySecret: vectorSecretNew},
+	{name: "empty body", secrets: []string{vectorSecretNew}, signedBody: "", verifySecret: vectorSecretNew},
+	{name: "tampered body", secrets: []string{vectorSecretNew}, signedBody: vectorBody, body: vectorBody[:len(vectorBody)-1] + " }", verifySecret: vectorSecretNew},
+	{name: "timestamp too old", secrets: []string{vectorSecretNew}, signedBody: vectorBody, verifySecret: vectorSecretNew, now: vectorTimestamp + 301},
+	{name: "timestamp in future", secrets: []string{vectorSecretNew}, signedBody: vectorBody, verifySecret: vectorSecretNew, now: vectorTimestamp - 301},
+	{name: "missing timestamp", signedBody: vectorBody, header: "v1=0000", verifySecret: vectorSecretNew},
+	{name: "missing signature", signedBody: vectorBody, header: fmt.Sprintf("t=%d", vectorTimestamp), verifySecret: vectorSecretNew},
+}
+
+// Vectors 生成签名测试向量
+func Vectors() []*Vector {
+	vectors := make([]*Vector, 0, len(vectorCases))
+	for _, c := range vectorCases {
+		timestamp := c.timestamp
+		if timestamp == 0 {
+			timestamp = vectorTimestamp
+		}
+		now := c.now
+		if now == 0 {
+			now = timestamp + 30
+		}
+		body := c.body
+		if body == "" {
+			body = c.signedBody
+		}
+		header := c.header
+		if header == "" {
+			header = SignatureHeader(timestamp, []byte(c.signedBody), c.secrets...)
+		}
+		vector := &Vector{
+			Name:         c.name,
+			Secrets:      c.secrets,
+			Timestamp:    timestamp,
+			Body:         body,
+			Header:       header,
+			VerifySecret: c.verifySecret,
+			Now:          now,
+			Tolerance:    int64(DefaultTolerance / time.Second),
+		}
+		vector.Valid, vector.Error = vector.verify()
+		vectors = append(vectors, vector)
+	}
+	return vectors
+}
+
+// Check 使用当前签名代码重新验证测试向量，返回第一个结果不一致的向量
+func Check(vectors []*Vector) error {
+	for _, vector := range vectors {
+		valid, errMsg := vector.verify()
+		if valid != vector.Valid || errMsg != vector.Error {
+			return fmt.Errorf("vector %q: got valid=%v error=%q, want valid=%v error=%q",
+				vector.Name, valid, errMsg, vector.Valid, vector.Error)
+		}
+		if vector.Valid && len(vector.Secrets) > 0 && vector.Header != SignatureHeader(vector.Timestamp, []byte(vector.Body), vector.Secrets...) {
+			return fmt.Errorf("vector %q: header mismatch", vector.Name)
+		}
+	}
+	return nil
+}
+
+func (v *Vector) verify() (bool, string) {
+	err := VerifyAt(v.Header, []byte(v.Body), v.VerifySecret, time.Duration(v.Tolerance)*time.Second, time.Unix(v.Now, 0))
+	if err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
@@ -0,0 +1,42 @@
+package webhooksig
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// vectorsFile 提供给商户的签名测试向量，由 make webhook-vectors 生成
+const vectorsFile = "../../docs/webhook/signature_vectors.json"
+
+func TestVectorsCheck(t *testing.T) {
+	if err := Check(Vectors()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVectorsFileUpToDate(t *testing.T) {
+	data, err := os.ReadFile(vectorsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []*Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check(vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(Vectors()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatalf("%s is out of date, run make webhook-vectors", vectorsFile)
+	}
+}
@@ -0,0 +1,118 @@
+// Package webhooksig 商户异步通知签名与验签
+//
+// 每个通知请求携带签名头：
+//
+//	X-Webhook-Signature: t=1700000000,v1=5257a869...,v1=9f3c...
+//
+// t为发送时的Unix时间戳（秒），v1为HMAC-SHA256(webhook密钥, "{t}.{原始请求体}")的十六进制小写结果。
+// 密钥轮换期间同一请求会携带新旧两个密钥的签名，商户任一密钥验签通过即可。
+// 验签时须使用未经解析和重新序列化的原始请求体，并校验时间戳以防重放。
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderSignature  = "X-Webhook-Signature" // 签名请求头
+	SchemeV1         = "v1"                  // HMAC-SHA256签名方案
+	DefaultTolerance = 5 * time.Minute       // 建议的时间戳容差
+)
+
+var (
+	ErrInvalidHeader     = errors.New("webhooksig: invalid signature header")
+	ErrNoSignature       = errors.New("webhooksig: no v1 signature in header")
+	ErrTimestampExpired  = errors.New("webhooksig: timestamp outside tolerance")
+	ErrSignatureMismatch = errors.New("webhooksig: signature mismatch")
+)
+
+// Signature 解析后的签名头
+type Signature struct {
+	Timestamp  int64
+	Signatures []string // v1签名，轮换期间有多个
+}
+
+// ComputeSignature 计算HMAC-SHA256("{timestamp}.{body}")的十六进制签名
+func ComputeSignature(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader 生成签名头，每个密钥生成一个v1签名
+func SignatureHeader(timestamp int64, body []byte, secrets ...string) string {
+	parts := make([]string, 0, len(secrets)+1)
+	parts = append(parts, "t="+strconv.FormatInt(timestamp, 10))
+	for _, secret := range secrets {
+		parts = append(parts, SchemeV1+"="+ComputeSignature(secret, timestamp, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseHeader 解析签名头，忽略未知方案以便后续升级签名算法
+func ParseHeader(header string) (*Signature, error) {
+	sig := &Signature{}
+	hasTimestamp := false
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, ErrInvalidHeader
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || hasTimestamp {
+				return nil, ErrInvalidHeader
+			}
+			sig.Timestamp = ts
+			hasTimestamp = true
+		case SchemeV1:
+			sig.Signatures = append(sig.Signatures, value)
+		}
+	}
+	if !hasTimestamp {
+		return nil, ErrInvalidHeader
+	}
+	if len(sig.Signatures) == 0 {
+		return nil, ErrNoSignature
+	}
+	return sig, nil
+}
+
+// Verify 使用当前时间验签，tolerance为0时不校验时间戳
+func Verify(header string, body []byte, secret string, tolerance time.Duration) error {
+	return VerifyAt(header, body, secret, tolerance, time.Now())
+}
+
+// VerifyAt 以指定时间验签，任一v1签名与密钥匹配即通过
+func VerifyAt(header string, body []byte, secret string, tolerance time.Duration, now time.Time) error {
+	sig, err := ParseHeader(header)
+	if err != nil {
+		return err
+	}
+	if tolerance > 0 {
+		diff := now.Sub(time.Unix(sig.Timestamp, 0))
+		if diff > tolerance || diff < -tolerance {
+			return ErrTimestampExpired
+		}
+	}
+	expected, _ := hex.DecodeString(ComputeSignature(secret, sig.Timestamp, body))
+	for _, signature := range sig.Signatures {
+		actual, err := hex.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(expected, actual) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}